@@ -31,6 +31,9 @@ func main() {
 	// Initialize Database
 	db := config.ConnectDatabase()
 
+	// Reminders were deduplicated per notification before the reminder ledger existed.
+	remindersTracked := db.Migrator().HasTable(&models.DueReminder{})
+
 	// Auto Migrate
 	if err := db.AutoMigrate(
 		&models.User{},
@@ -47,6 +50,7 @@ func main() {
 		&models.Comment{},
 		&models.Attachment{},
 		&models.Notification{},
+		&models.DueReminder{},
 		&models.Subscription{},
 		&models.AutomationRule{},
 		&models.BoardTemplate{},
@@ -55,9 +59,39 @@ func main() {
 		&models.UserPreferences{},
 		&models.LoginAttempt{},
 		&models.EmailVerification{},
+		&models.TimeLog{},
+		&models.Sprint{},
+		&models.SprintScopeChange{},
+		&models.ChecklistTemplate{},
+		&models.ChecklistTemplateItem{},
+		&models.CommentEdit{},
+		&models.CommentReaction{},
+		&models.BoardInbox{},
+		&models.InboundEmailMessage{},
+		&models.BoardForm{},
+		&models.BoardFormField{},
+		&models.WorkspaceField{},
+		&models.WorkspaceLabel{},
+		&models.ColumnCriterion{},
+		&models.Swimlane{},
+		&models.CardMention{},
+		&models.SavedFilter{},
+		&models.CalendarFeed{},
+		&models.CardDependency{},
 	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+	if err := services.EnsureSearchIndexes(db); err != nil {
+		log.Fatal("Failed to create search indexes:", err)
+	}
+	if err := services.BackfillCompletedAt(db); err != nil {
+		log.Println("Failed to backfill card completion times:", err)
+	}
+	if !remindersTracked {
+		if err := services.BackfillDueReminders(db, time.Now().Add(-24*time.Hour)); err != nil {
+			log.Println("Failed to backfill due reminders:", err)
+		}
+	}
 
 	// Seed Data (Modified to check if ANY user exists)
 	// For v2, we might want to skip auto-seed or seed a default user
@@ -89,8 +123,8 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok", "version": "2.0-beta"})
 	})
 
-	// Static Files (Uploads)
-	r.Static("/uploads", "./uploads")
+	// Static Files (Uploads), served as downloads only
+	r.Group("/uploads", middleware.UploadsMiddleware()).Static("/", "./uploads")
 
 	// Auth Routes (Public)
 	authGroup := r.Group("/auth")
@@ -128,6 +162,9 @@ func main() {
 		api.PATCH("/columns/:id", columnHandler.UpdateColumn)
 		api.DELETE("/columns/:id", columnHandler.DeleteColumn)
 		api.PATCH("/columns/:id/move", columnHandler.MoveColumn)
+		columnCriteriaHandler := handlers.NewColumnCriteriaHandler(services.NewColumnCriteriaService(db), hub)
+		api.GET("/columns/:id/criteria", columnCriteriaHandler.GetCriteria)
+		api.PUT("/columns/:id/criteria", columnCriteriaHandler.ReplaceCriteria)
 
 		// Activity Service
 		activityService := services.NewActivityService(db)
@@ -136,6 +173,7 @@ func main() {
 		api.GET("/cards/:id/activity", activityHandler.GetCardActivity)
 
 		notificationService := services.NewNotificationService(db, hub, emailService)
+		notificationService.ReplyAddresses = services.NewReplyAddressSignerFromEnv()
 		notificationHandler := handlers.NewNotificationHandler(notificationService)
 		api.GET("/notifications", notificationHandler.GetNotifications)
 		api.PATCH("/notifications/:id/read", notificationHandler.MarkAsRead)
@@ -145,6 +183,7 @@ func main() {
 		autoRepo := repository.NewAutomationRepository(db)
 		automationService := services.NewAutomationService(autoRepo)
 		automationService.SetDependencies(db, notificationService)
+		automationService.Hub = hub
 		automationHandler := handlers.NewAutomationHandler(automationService)
 
 		// Subscriptions
@@ -174,11 +213,26 @@ func main() {
 		)
 		go dueReminderService.Start(bgCtx, time.Duration(reminderIntervalMins)*time.Minute)
 
-		adminHandler := handlers.NewAdminHandler(db, dueReminderService)
+		// Stale Card Digest (daily by default)
+		staleIntervalHours := 24
+		if v := os.Getenv("STALE_CARD_INTERVAL_HOURS"); v != "" {
+			if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+				staleIntervalHours = parsed
+			}
+		}
+		staleCardService := services.NewStaleCardService(db, notificationService, automationService)
+		go staleCardService.Start(bgCtx, time.Duration(staleIntervalHours)*time.Hour)
+
+		adminHandler := handlers.NewAdminHandler(db, dueReminderService, staleCardService)
+
+		// Formula fields reading today's date are refreshed when the day changes
+		formulaService := services.NewFormulaService(db)
+		go formulaService.Start(bgCtx, time.Hour)
 
 		// Cards
 		cardRepo := repository.NewCardRepository(db)
 		cardService := services.NewCardService(cardRepo, automationService)
+		cardService.Formulas = formulaService
 		automationService.SetExecutor(cardService)
 
 		cardHandler := handlers.NewCardHandler(cardService, activityService, notificationService, subService, hub) // Inject NotificationService and SubscriptionService
@@ -191,6 +245,7 @@ func main() {
 		api.POST("/cards/:id/archive", cardHandler.Archive)
 		api.POST("/cards/:id/restore", cardHandler.Restore)
 		api.POST("/cards/:id/copy", cardHandler.Copy)
+		api.POST("/boards/:id/cards/bulk", cardHandler.Bulk)
 		api.POST("/cards/:id/template", cardHandler.SaveAsTemplate)
 		api.GET("/cards/templates", cardHandler.GetTemplates)
 
@@ -206,6 +261,7 @@ func main() {
 
 		// Checklists
 		checklistHandler := handlers.NewChecklistHandler(db, hub, notificationService, subService, automationService)
+		checklistHandler.Formulas = formulaService
 
 		api.POST("/cards/:id/checklists", checklistHandler.CreateChecklist)
 		api.DELETE("/checklists/:id", checklistHandler.DeleteChecklist)
@@ -214,10 +270,49 @@ func main() {
 		api.PATCH("/checklist-items/:id", checklistHandler.ToggleItem)
 		api.PATCH("/checklist-items/:id/move", checklistHandler.MoveItem)
 		api.DELETE("/checklist-items/:id", checklistHandler.DeleteItem)
+		api.PUT("/checklist-items/:id/assignee", checklistHandler.AssignItem)
+		api.PUT("/checklist-items/:id/due-date", checklistHandler.SetItemDueDate)
+		api.GET("/users/me/checklist-items", checklistHandler.GetMyItems)
+		api.POST("/checklists/:id/copy", checklistHandler.CopyChecklist)
+
+		// Checklist Templates
+		api.GET("/boards/:id/checklist-templates", checklistHandler.ListBoardTemplates)
+		api.POST("/boards/:id/checklist-templates", checklistHandler.CreateBoardTemplate)
+		api.GET("/workspaces/:id/checklist-templates", checklistHandler.ListWorkspaceTemplates)
+		api.POST("/workspaces/:id/checklist-templates", checklistHandler.CreateWorkspaceTemplate)
+		api.GET("/checklist-templates/:id", checklistHandler.GetTemplate)
+		api.PATCH("/checklist-templates/:id", checklistHandler.UpdateTemplate)
+		api.DELETE("/checklist-templates/:id", checklistHandler.DeleteTemplate)
 
 		// Analytics
-		analyticsHandler := handlers.NewAnalyticsHandler(db)
+		timeTrackingService := services.NewTimeTrackingService(db, activityService)
+		timeTrackingService.Formulas = formulaService
+		timeTrackingHandler := handlers.NewTimeTrackingHandler(timeTrackingService, hub)
+		analyticsHandler := handlers.NewAnalyticsHandler(db, timeTrackingService)
 		api.GET("/boards/:id/analytics", analyticsHandler.GetBoardAnalytics)
+		api.GET("/boards/:id/analytics/time", analyticsHandler.GetBoardTimeAnalytics)
+
+		// Estimates & Time Tracking
+		api.PUT("/cards/:id/estimate", timeTrackingHandler.SetEstimate)
+		api.GET("/cards/:id/time", timeTrackingHandler.GetCardTime)
+		api.POST("/cards/:id/time-logs", timeTrackingHandler.CreateTimeLog)
+		api.PATCH("/time-logs/:id", timeTrackingHandler.UpdateTimeLog)
+		api.DELETE("/time-logs/:id", timeTrackingHandler.DeleteTimeLog)
+		api.GET("/boards/:id/time-logs/export", timeTrackingHandler.ExportTimeLogs)
+
+		// Sprints
+		sprintService := services.NewSprintService(db, activityService, automationService)
+		sprintHandler := handlers.NewSprintHandler(sprintService, hub)
+		api.GET("/boards/:id/sprints", sprintHandler.ListSprints)
+		api.POST("/boards/:id/sprints", sprintHandler.CreateSprint)
+		api.GET("/sprints/:id", sprintHandler.GetSprint)
+		api.PATCH("/sprints/:id", sprintHandler.UpdateSprint)
+		api.DELETE("/sprints/:id", sprintHandler.DeleteSprint)
+		api.POST("/sprints/:id/start", sprintHandler.StartSprint)
+		api.POST("/sprints/:id/close", sprintHandler.CloseSprint)
+		api.GET("/sprints/:id/report", sprintHandler.GetReport)
+		api.GET("/sprints/:id/burndown", sprintHandler.GetBurndown)
+		api.PUT("/cards/:id/sprint", sprintHandler.AssignCard)
 
 		// Labels
 		labelHandler := handlers.NewLabelHandler(db, hub)
@@ -230,7 +325,33 @@ func main() {
 		commentService := services.NewCommentService(db, activityService)
 		commentHandler := handlers.NewCommentHandler(commentService, notificationService, subService, db, hub)
 		api.POST("/cards/:id/comments", commentHandler.CreateComment)
+		api.PATCH("/comments/:id", commentHandler.UpdateComment)
 		api.DELETE("/comments/:id", commentHandler.DeleteComment)
+		api.GET("/comments/:id/history", commentHandler.GetCommentHistory)
+		api.POST("/comments/:id/reactions", commentHandler.ToggleReaction)
+
+		// Board inboxes (email-to-board)
+		inboundDomain := os.Getenv("INBOUND_EMAIL_DOMAIN")
+		boardInboxService := services.NewBoardInboxService(db, cardService, activityService, inboundDomain)
+		boardInboxHandler := handlers.NewBoardInboxHandler(boardInboxService, hub, notificationService, subService)
+		api.GET("/boards/:id/inbox", boardInboxHandler.GetInbox)
+		api.PUT("/boards/:id/inbox", boardInboxHandler.UpdateInbox)
+		api.POST("/boards/:id/inbox/rotate", boardInboxHandler.RotateInbox)
+		api.DELETE("/boards/:id/inbox", boardInboxHandler.DeleteInbox)
+		api.POST("/boards/:id/inbox/messages", boardInboxHandler.UploadMessage)
+
+		// Inbound mail: board inboxes and replies to card notifications (public; webhook secret or SMTP)
+		inboundEmailService := services.NewInboundEmailService(db, commentService, notificationService.ReplyAddresses)
+		inboundEmailHandler := handlers.NewInboundEmailHandler(inboundEmailService, commentHandler, boardInboxHandler, os.Getenv("INBOUND_EMAIL_WEBHOOK_SECRET"))
+		r.POST("/inbound/email", inboundEmailHandler.ReceiveEmail)
+		if addr := os.Getenv("INBOUND_SMTP_ADDR"); addr != "" && inboundDomain != "" {
+			smtpServer := services.NewInboundSMTPServer(addr, inboundDomain, inboundEmailHandler.Deliver)
+			go func() {
+				if err := smtpServer.ListenAndServe(bgCtx); err != nil {
+					log.Printf("[InboundSMTP] %v", err)
+				}
+			}()
+		}
 
 		// Card Metadata
 		api.POST("/cards/:id/labels/:labelId", cardHandler.AddLabel)
@@ -287,17 +408,105 @@ func main() {
 		api.GET("/users/me/activity", userHandler.GetUserActivity)
 		api.PATCH("/users/me/onboarding", userHandler.CompleteOnboarding)
 		api.POST("/admin/reminders/run", adminHandler.RunDueDateReminders)
+		api.POST("/admin/stale-cards/run", adminHandler.RunStaleCardDigest)
+		api.POST("/admin/positions/rebalance", adminHandler.RebalancePositions)
 
 		// Custom Fields
 		cfRepo := repository.NewCustomFieldRepository(db)
-		cfService := services.NewCustomFieldService(cfRepo)
+		cfService := services.NewCustomFieldService(cfRepo, notificationService, automationService)
 		cfHandler := handlers.NewCustomFieldHandler(cfService, db, hub)
 
 		api.POST("/boards/:id/fields", cfHandler.CreateField)
 		api.GET("/boards/:id/fields", cfHandler.GetFields)
+		api.PATCH("/fields/:id", cfHandler.UpdateField)
+		api.PATCH("/fields/:id/move", cfHandler.MoveField)
 		api.DELETE("/fields/:id", cfHandler.DeleteField)
 		api.POST("/cards/:id/fields/:field_id", cfHandler.SetValue)
 		api.GET("/cards/:id/fields", cfHandler.GetValues)
+
+		// Workspace-shared fields and labels
+		sharedDefinitionHandler := handlers.NewWorkspaceDefinitionHandler(services.NewSharedDefinitionService(db, cfService), hub)
+		api.GET("/workspaces/:id/fields", sharedDefinitionHandler.ListFields)
+		api.POST("/workspaces/:id/fields", sharedDefinitionHandler.CreateField)
+		api.PATCH("/workspace-fields/:id", sharedDefinitionHandler.UpdateField)
+		api.DELETE("/workspace-fields/:id", sharedDefinitionHandler.DeleteField)
+		api.GET("/workspace-fields/:id/analytics", sharedDefinitionHandler.FieldAnalytics)
+		api.POST("/boards/:id/shared-fields/:fieldId", sharedDefinitionHandler.LinkField)
+		api.GET("/workspaces/:id/labels", sharedDefinitionHandler.ListLabels)
+		api.POST("/workspaces/:id/labels", sharedDefinitionHandler.CreateLabel)
+		api.PATCH("/workspace-labels/:id", sharedDefinitionHandler.UpdateLabel)
+		api.DELETE("/workspace-labels/:id", sharedDefinitionHandler.DeleteLabel)
+		api.POST("/boards/:id/shared-labels/:labelId", sharedDefinitionHandler.LinkLabel)
+
+		// Swimlanes
+		swimlaneService := services.NewSwimlaneService(db, cfService)
+		boardHandler.Swimlanes = swimlaneService
+		cardHandler.Swimlanes = swimlaneService
+		swimlaneHandler := handlers.NewSwimlaneHandler(swimlaneService, hub)
+		api.GET("/boards/:id/swimlanes", swimlaneHandler.ListLanes)
+		api.POST("/boards/:id/swimlanes", swimlaneHandler.CreateSwimlane)
+		api.PATCH("/swimlanes/:id", swimlaneHandler.UpdateSwimlane)
+		api.DELETE("/swimlanes/:id", swimlaneHandler.DeleteSwimlane)
+
+		// Search
+		searchHandler := handlers.NewSearchHandler(services.NewSearchService(db))
+		api.GET("/search", searchHandler.Search)
+		cardQueryService := services.NewCardQueryService(db)
+		cardQueryHandler := handlers.NewCardQueryHandler(cardQueryService)
+		api.GET("/cards", cardQueryHandler.ListCards)
+
+		// Saved filters and My Work
+		savedFilterService := services.NewSavedFilterService(db, cardQueryService)
+		savedFilterHandler := handlers.NewSavedFilterHandler(
+			savedFilterService,
+			services.NewMyWorkService(db, services.NewChecklistService(db, notificationService)),
+		)
+		api.GET("/workspaces/:id/filters", savedFilterHandler.ListWorkspaceFilters)
+		api.POST("/workspaces/:id/filters", savedFilterHandler.CreateWorkspaceFilter)
+		api.GET("/boards/:id/filters", savedFilterHandler.ListBoardFilters)
+		api.POST("/boards/:id/filters", savedFilterHandler.CreateBoardFilter)
+		api.GET("/filters/:id", savedFilterHandler.GetFilter)
+		api.PATCH("/filters/:id", savedFilterHandler.UpdateFilter)
+		api.DELETE("/filters/:id", savedFilterHandler.DeleteFilter)
+		api.GET("/filters/:id/cards", savedFilterHandler.RunFilter)
+		api.GET("/users/me/work", savedFilterHandler.GetMyWork)
+
+		// Timeline
+		timelineHandler := handlers.NewTimelineHandler(services.NewTimelineService(db, cardQueryService, cardService, activityService), savedFilterService, hub)
+		api.GET("/boards/:id/timeline", timelineHandler.BoardTimeline)
+		api.GET("/filters/:id/timeline", timelineHandler.FilterTimeline)
+		api.PUT("/cards/:id/schedule", timelineHandler.Reschedule)
+		api.POST("/cards/:id/dependencies", timelineHandler.AddDependency)
+		api.DELETE("/cards/:id/dependencies/:dependsOnId", timelineHandler.RemoveDependency)
+
+		// Calendar
+		calendarHandler := handlers.NewCalendarHandler(services.NewCalendarService(db, cardQueryService, cardService, activityService), savedFilterService, hub)
+		api.GET("/boards/:id/calendar", calendarHandler.BoardCalendar)
+		api.GET("/filters/:id/calendar", calendarHandler.FilterCalendar)
+		api.PUT("/cards/:id/due-date", calendarHandler.RescheduleDue)
+
+		// Calendar feeds (the feed itself is public; its token is the credential)
+		calendarFeedHandler := handlers.NewCalendarFeedHandler(services.NewCalendarFeedService(db, os.Getenv("FRONTEND_URL")))
+		api.GET("/users/me/calendar-feed", calendarFeedHandler.GetMyFeed)
+		api.POST("/users/me/calendar-feed/regenerate", calendarFeedHandler.RegenerateMyFeed)
+		api.GET("/boards/:id/calendar-feed", calendarFeedHandler.GetBoardFeed)
+		api.POST("/boards/:id/calendar-feed/regenerate", calendarFeedHandler.RegenerateBoardFeed)
+		r.GET("/calendar/:token", middleware.RateLimitMiddleware(1, 10), calendarFeedHandler.ServeFeed)
+
+		// Intake forms (public submission routes are registered below, outside auth)
+		boardFormService := services.NewBoardFormService(db, cardService, cfService, activityService, services.NewCaptchaVerifierFromEnv())
+		boardFormHandler := handlers.NewBoardFormHandler(boardFormService, hub, notificationService, subService)
+		api.GET("/boards/:id/forms", boardFormHandler.ListForms)
+		api.POST("/boards/:id/forms", boardFormHandler.CreateForm)
+		api.GET("/forms/:id", boardFormHandler.GetForm)
+		api.PATCH("/forms/:id", boardFormHandler.UpdateForm)
+		api.POST("/forms/:id/rotate", boardFormHandler.RotateToken)
+		api.DELETE("/forms/:id", boardFormHandler.DeleteForm)
+
+		publicForms := r.Group("/forms")
+		publicForms.Use(middleware.RateLimitMiddleware(0.2, 5)) // ~12 submissions/minute per IP
+		publicForms.GET("/:token", boardFormHandler.GetPublicForm)
+		publicForms.POST("/:token/submissions", boardFormHandler.Submit)
 	}
 
 	r.GET("/ws", func(c *gin.Context) {
@@ -8,6 +8,7 @@ import (
 
 	"nexus-backend/internal/middleware"
 	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
 	"nexus-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -17,51 +18,104 @@ import (
 type AdminHandler struct {
 	DB                 *gorm.DB
 	DueReminderService *services.DueDateReminderService
+	StaleCardService   *services.StaleCardService
 }
 
-func NewAdminHandler(db *gorm.DB, dueReminderService *services.DueDateReminderService) *AdminHandler {
+func NewAdminHandler(db *gorm.DB, dueReminderService *services.DueDateReminderService, staleCardService *services.StaleCardService) *AdminHandler {
 	return &AdminHandler{
 		DB:                 db,
 		DueReminderService: dueReminderService,
+		StaleCardService:   staleCardService,
 	}
 }
 
 func (h *AdminHandler) RunDueDateReminders(c *gin.Context) {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	if !h.requireAdmin(c) {
 		return
 	}
 
-	var user models.User
-	if err := h.DB.Select("id, email").First(&user, "id = ?", userID).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+	if h.DueReminderService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Due reminder service unavailable"})
 		return
 	}
 
-	if !h.isAllowedAdminEmail(user.Email) {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Forbidden. Set ADMIN_USER_EMAILS to allow this account.",
-		})
+	started := time.Now()
+	stats := h.DueReminderService.RunOnce()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Due date reminder run completed",
+		"started_at":  started.UTC(),
+		"duration_ms": time.Since(started).Milliseconds(),
+		"stats":       stats,
+	})
+}
+
+func (h *AdminHandler) RunStaleCardDigest(c *gin.Context) {
+	if !h.requireAdmin(c) {
 		return
 	}
 
-	if h.DueReminderService == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Due reminder service unavailable"})
+	if h.StaleCardService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stale card service unavailable"})
 		return
 	}
 
 	started := time.Now()
-	stats := h.DueReminderService.RunOnce()
+	stats := h.StaleCardService.RunOnce()
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":     "Due date reminder run completed",
+		"message":     "Stale card digest run completed",
+		"started_at":  started.UTC(),
+		"duration_ms": time.Since(started).Milliseconds(),
+		"stats":       stats,
+	})
+}
+
+// RebalancePositions renumbers every float-ordered list (columns, cards, checklists,
+// checklist items and custom fields) with even gaps.
+func (h *AdminHandler) RebalancePositions(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	started := time.Now()
+	stats, err := repository.RebalanceAll(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rebalance positions", "stats": stats})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Position rebalance completed",
 		"started_at":  started.UTC(),
 		"duration_ms": time.Since(started).Milliseconds(),
 		"stats":       stats,
 	})
 }
 
+// requireAdmin writes a 401/403 response and returns false unless the caller is on the admin allowlist.
+func (h *AdminHandler) requireAdmin(c *gin.Context) bool {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return false
+	}
+
+	var user models.User
+	if err := h.DB.Select("id, email").First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return false
+	}
+
+	if !h.isAllowedAdminEmail(user.Email) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Forbidden. Set ADMIN_USER_EMAILS to allow this account.",
+		})
+		return false
+	}
+	return true
+}
+
 func (h *AdminHandler) isAllowedAdminEmail(email string) bool {
 	allowlist := strings.TrimSpace(os.Getenv("ADMIN_USER_EMAILS"))
 	if allowlist == "" {
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"time"
 
+	"nexus-backend/internal/middleware"
 	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,11 +15,12 @@ import (
 )
 
 type AnalyticsHandler struct {
-	DB *gorm.DB
+	DB           *gorm.DB
+	TimeTracking *services.TimeTrackingService
 }
 
-func NewAnalyticsHandler(db *gorm.DB) *AnalyticsHandler {
-	return &AnalyticsHandler{DB: db}
+func NewAnalyticsHandler(db *gorm.DB, timeTracking *services.TimeTrackingService) *AnalyticsHandler {
+	return &AnalyticsHandler{DB: db, TimeTracking: timeTracking}
 }
 
 type BoardAnalytics struct {
@@ -28,6 +31,8 @@ type BoardAnalytics struct {
 	CardsPerMember []StatItem     `json:"cards_per_member"`
 	DueDateStatus  DueDateStats   `json:"due_date_status"`
 	WeeklyActivity []ActivityStat `json:"weekly_activity"`
+
+	CardsPerField []services.FieldGroupStat `json:"cards_per_field,omitempty"` // with ?field_id=
 }
 
 type StatItem struct {
@@ -165,5 +170,209 @@ func (h *AnalyticsHandler) GetBoardAnalytics(c *gin.Context) {
 		Scan(&activityStats)
 	analytics.WeeklyActivity = activityStats
 
+	// Optional grouping by one of the board's custom fields
+	if raw := c.Query("field_id"); raw != "" {
+		fieldID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field ID"})
+			return
+		}
+		var field models.CustomField
+		if err := h.DB.First(&field, "id = ? AND board_id = ?", fieldID, boardID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Field not found"})
+			return
+		}
+		fieldStats, err := services.GroupCardsByField(h.DB, &field, []uuid.UUID{boardID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to group cards by field"})
+			return
+		}
+		analytics.CardsPerField = fieldStats
+	}
+
 	c.JSON(http.StatusOK, analytics)
 }
+
+type TimeAnalytics struct {
+	BoardID   uuid.UUID        `json:"board_id"`
+	From      string           `json:"from,omitempty"`
+	To        string           `json:"to,omitempty"`
+	Totals    TimeTotals       `json:"totals"`
+	PerColumn []ColumnTimeStat `json:"per_column"`
+	PerMember []MemberTimeStat `json:"per_member"`
+	PerDay    []DailyTimeStat  `json:"per_day"`
+}
+
+type TimeTotals struct {
+	EstimateMinutes  int     `json:"estimate_minutes"`
+	StoryPoints      float64 `json:"story_points"`
+	LoggedMinutes    int     `json:"logged_minutes"`
+	RemainingMinutes int     `json:"remaining_minutes"`
+	EstimatedCards   int     `json:"estimated_cards"`
+}
+
+type ColumnTimeStat struct {
+	ColumnID uuid.UUID `json:"column_id"`
+	Name     string    `json:"name"`
+	TimeTotals
+}
+
+type MemberTimeStat struct {
+	UserID        uuid.UUID `json:"user_id"`
+	Name          string    `json:"name"`
+	LoggedMinutes int       `json:"logged_minutes"`
+	Entries       int       `json:"entries"`
+}
+
+type DailyTimeStat struct {
+	Date          string `json:"date"`
+	LoggedMinutes int    `json:"logged_minutes"`
+}
+
+// parseTimeLogFilter reads from/to (YYYY-MM-DD) and user_id query parameters.
+func parseTimeLogFilter(c *gin.Context) (services.TimeLogFilter, bool) {
+	var filter services.TimeLogFilter
+	for _, key := range []string{"from", "to"} {
+		raw := c.Query(key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(services.TimeLogDateLayout, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + key + " date, expected YYYY-MM-DD"})
+			return filter, false
+		}
+		if key == "from" {
+			filter.From = &t
+		} else {
+			filter.To = &t
+		}
+	}
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return filter, false
+		}
+		filter.UserID = &id
+	}
+	return filter, true
+}
+
+// GetBoardTimeAnalytics aggregates estimates and logged time by column, member and day.
+// from/to/user_id narrow the logged time; estimates and remaining time reflect the board now.
+func (h *AnalyticsHandler) GetBoardTimeAnalytics(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.TimeTracking.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+	filter, ok := parseTimeLogFilter(c)
+	if !ok {
+		return
+	}
+
+	// Current estimates and all-time logged minutes per card drive remaining time.
+	var cards []struct {
+		ID              uuid.UUID
+		ColumnID        uuid.UUID
+		ColumnName      string
+		ColumnPosition  float64
+		EstimateMinutes *int
+		StoryPoints     *float64
+		LoggedMinutes   int
+	}
+	if err := h.DB.Table("cards").
+		Select(`cards.id, cards.column_id, columns.name AS column_name, columns.position AS column_position,
+			cards.estimate_minutes, cards.story_points,
+			(SELECT COALESCE(SUM(tl.duration_minutes), 0) FROM time_logs tl WHERE tl.card_id = cards.id) AS logged_minutes`).
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Where("columns.board_id = ? AND cards.is_archived = ? AND cards.is_template = ?", boardID, false, false).
+		Order("columns.position ASC").
+		Scan(&cards).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch estimates"})
+		return
+	}
+
+	logs, err := h.TimeTracking.BoardTimeLogs(boardID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time logs"})
+		return
+	}
+
+	report := TimeAnalytics{
+		BoardID:   boardID,
+		PerColumn: []ColumnTimeStat{},
+		PerMember: []MemberTimeStat{},
+		PerDay:    []DailyTimeStat{},
+	}
+	if filter.From != nil {
+		report.From = filter.From.Format(services.TimeLogDateLayout)
+	}
+	if filter.To != nil {
+		report.To = filter.To.Format(services.TimeLogDateLayout)
+	}
+
+	columnIndex := map[uuid.UUID]int{}
+	column := func(id uuid.UUID, name string) *ColumnTimeStat {
+		if i, ok := columnIndex[id]; ok {
+			return &report.PerColumn[i]
+		}
+		columnIndex[id] = len(report.PerColumn)
+		report.PerColumn = append(report.PerColumn, ColumnTimeStat{ColumnID: id, Name: name})
+		return &report.PerColumn[len(report.PerColumn)-1]
+	}
+
+	for _, card := range cards {
+		stat := column(card.ColumnID, card.ColumnName)
+		if card.EstimateMinutes != nil {
+			remaining := *services.RemainingMinutes(card.EstimateMinutes, card.LoggedMinutes)
+			stat.EstimateMinutes += *card.EstimateMinutes
+			stat.RemainingMinutes += remaining
+			stat.EstimatedCards++
+			report.Totals.EstimateMinutes += *card.EstimateMinutes
+			report.Totals.RemainingMinutes += remaining
+			report.Totals.EstimatedCards++
+		}
+		if card.StoryPoints != nil {
+			stat.StoryPoints += *card.StoryPoints
+			report.Totals.StoryPoints += *card.StoryPoints
+		}
+	}
+
+	memberIndex := map[uuid.UUID]int{}
+	dayIndex := map[string]int{}
+	for _, entry := range logs {
+		column(entry.ColumnID, entry.ColumnName).LoggedMinutes += entry.DurationMinutes
+		report.Totals.LoggedMinutes += entry.DurationMinutes
+
+		i, ok := memberIndex[entry.UserID]
+		if !ok {
+			i = len(report.PerMember)
+			memberIndex[entry.UserID] = i
+			report.PerMember = append(report.PerMember, MemberTimeStat{UserID: entry.UserID, Name: entry.UserName})
+		}
+		report.PerMember[i].LoggedMinutes += entry.DurationMinutes
+		report.PerMember[i].Entries++
+
+		day := entry.LoggedOn.Format(services.TimeLogDateLayout)
+		d, ok := dayIndex[day]
+		if !ok {
+			d = len(report.PerDay)
+			dayIndex[day] = d
+			report.PerDay = append(report.PerDay, DailyTimeStat{Date: day})
+		}
+		report.PerDay[d].LoggedMinutes += entry.DurationMinutes
+	}
+
+	c.JSON(http.StatusOK, report)
+}
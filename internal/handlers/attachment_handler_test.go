@@ -59,6 +59,10 @@ func setupAttachmentHandlerDB(t *testing.T) *gorm.DB {
 		background_image_url TEXT,
 		documentation_notes TEXT,
 		is_starred INTEGER DEFAULT 0,
+		stale_after_days INTEGER DEFAULT 0,
+		swimlane_mode TEXT DEFAULT 'none',
+		swimlane_field_id TEXT,
+		version INTEGER DEFAULT 1,
 		created_at DATETIME,
 		updated_at DATETIME,
 		deleted_at DATETIME
@@ -68,8 +72,12 @@ func setupAttachmentHandlerDB(t *testing.T) *gorm.DB {
 		board_id TEXT,
 		name TEXT NOT NULL,
 		position REAL NOT NULL,
+		version INTEGER DEFAULT 1,
 		created_at DATETIME,
-		updated_at DATETIME
+		updated_at DATETIME,
+		wip_limit INTEGER,
+		wip_scope TEXT DEFAULT 'cards',
+		wip_mode TEXT DEFAULT 'warn'
 	)`).Error)
 	require.NoError(t, db.Exec(`CREATE TABLE cards (
 		id TEXT PRIMARY KEY,
@@ -83,8 +91,18 @@ func setupAttachmentHandlerDB(t *testing.T) *gorm.DB {
 		archived_at DATETIME,
 		is_template INTEGER DEFAULT 0,
 		template_name TEXT,
+		version INTEGER DEFAULT 1,
+		start_date DATETIME,
 		due_date DATETIME,
 		is_complete INTEGER DEFAULT 0,
+		estimate_minutes INTEGER,
+		story_points REAL,
+		priority TEXT DEFAULT 'none',
+		last_activity_at DATETIME,
+		stale_notified_at DATETIME,
+		completed_at DATETIME,
+		sprint_id TEXT,
+		swimlane_id TEXT,
 		cover_attachment_id TEXT
 	)`).Error)
 	require.NoError(t, db.Exec(`CREATE TABLE attachments (
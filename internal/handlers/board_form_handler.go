@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BoardFormHandler struct {
+	Service             *services.BoardFormService
+	Hub                 *realtime.Hub
+	NotificationService *services.NotificationService
+	SubscriptionService *services.SubscriptionService
+}
+
+func NewBoardFormHandler(service *services.BoardFormService, hub *realtime.Hub, notificationService *services.NotificationService, subService *services.SubscriptionService) *BoardFormHandler {
+	return &BoardFormHandler{
+		Service:             service,
+		Hub:                 hub,
+		NotificationService: notificationService,
+		SubscriptionService: subService,
+	}
+}
+
+// FormSubmissionRequest is the public submission body. Website is the honeypot input: it is
+// hidden from people, so anything in it marks the submission as automated.
+type FormSubmissionRequest struct {
+	Values       map[string]interface{} `json:"values"`
+	Website      string                 `json:"website"`
+	CaptchaToken string                 `json:"captcha_token"`
+}
+
+func respondBoardFormError(c *gin.Context, err error, fallback string) {
+	var invalid *services.FormValidationError
+	if respondWIPLimit(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrInvalidForm):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR", "fields": invalid.Fields})
+	case errors.Is(err, services.ErrFormClosed):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error(), "code": "FORM_CLOSED"})
+	case errors.Is(err, services.ErrCaptchaFailed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "CAPTCHA_FAILED"})
+	case errors.Is(err, services.ErrCaptchaUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func (h *BoardFormHandler) boardAccess(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return boardID, userID, true
+}
+
+func (h *BoardFormHandler) ListForms(c *gin.Context) {
+	boardID, _, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+	forms, err := h.Service.ListForms(boardID)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to fetch forms")
+		return
+	}
+	c.JSON(http.StatusOK, forms)
+}
+
+func (h *BoardFormHandler) CreateForm(c *gin.Context) {
+	boardID, userID, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+	var req services.BoardFormInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	form, err := h.Service.CreateForm(boardID, userID, req)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to create form")
+		return
+	}
+	c.JSON(http.StatusCreated, form)
+}
+
+func (h *BoardFormHandler) GetForm(c *gin.Context) {
+	formID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	form, err := h.Service.GetFormForUser(formID, userID)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to fetch form")
+		return
+	}
+	c.JSON(http.StatusOK, form)
+}
+
+func (h *BoardFormHandler) UpdateForm(c *gin.Context) {
+	formID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var req services.BoardFormInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	form, err := h.Service.GetFormForUser(formID, userID)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to update form")
+		return
+	}
+	form, err = h.Service.UpdateForm(form, req)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to update form")
+		return
+	}
+	c.JSON(http.StatusOK, form)
+}
+
+// RotateToken issues a new public link; the old one stops working.
+func (h *BoardFormHandler) RotateToken(c *gin.Context) {
+	formID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	form, err := h.Service.GetFormForUser(formID, userID)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to rotate form link")
+		return
+	}
+	form, err = h.Service.RotateToken(form)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to rotate form link")
+		return
+	}
+	c.JSON(http.StatusOK, form)
+}
+
+func (h *BoardFormHandler) DeleteForm(c *gin.Context) {
+	formID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	form, err := h.Service.GetFormForUser(formID, userID)
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to delete form")
+		return
+	}
+	if err := h.Service.DeleteForm(form); err != nil {
+		respondBoardFormError(c, err, "Failed to delete form")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetPublicForm serves the form definition to anonymous visitors.
+func (h *BoardFormHandler) GetPublicForm(c *gin.Context) {
+	form, err := h.Service.GetPublicForm(c.Param("token"))
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to fetch form")
+		return
+	}
+	c.JSON(http.StatusOK, form)
+}
+
+// Submit accepts a JSON FormSubmissionRequest, or multipart with the same JSON in "payload"
+// and uploads in "files". The response never reveals the created card.
+func (h *BoardFormHandler) Submit(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxFormFiles*services.MaxFormFileBytes+(1<<20))
+
+	var req FormSubmissionRequest
+	var files []services.FormFile
+	if form, err := c.MultipartForm(); err == nil {
+		if err := json.Unmarshal([]byte(c.PostForm("payload")), &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "payload must be JSON", "code": "VALIDATION_ERROR"})
+			return
+		}
+		if len(form.File["files"]) > services.MaxFormFiles {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Too many files", "code": "VALIDATION_ERROR"})
+			return
+		}
+		for _, fh := range form.File["files"] {
+			f, err := fh.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+				return
+			}
+			data, err := io.ReadAll(io.LimitReader(f, services.MaxFormFileBytes+1))
+			f.Close()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+				return
+			}
+			files = append(files, services.FormFile{Filename: fh.Filename, ContentType: fh.Header.Get("Content-Type"), Data: data})
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	result, err := h.Service.Submit(c.Param("token"), services.FormSubmission{
+		Values:       req.Values,
+		Honeypot:     req.Website,
+		CaptchaToken: req.CaptchaToken,
+		RemoteIP:     c.ClientIP(),
+		Files:        files,
+	})
+	if err != nil {
+		respondBoardFormError(c, err, "Failed to submit form")
+		return
+	}
+	if !result.Dropped {
+		announceCreatedCard(h.Hub, h.NotificationService, h.SubscriptionService, result.Card, result.Form.CreatedBy,
+			"A new card was submitted through "+result.Form.Title+": "+result.Card.Title)
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "received"})
+}
@@ -9,6 +9,7 @@ import (
 	"nexus-backend/internal/models"
 	"nexus-backend/internal/realtime"
 	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,9 +22,10 @@ import (
 )
 
 type BoardHandler struct {
-	Repo *repository.BoardRepository
-	DB   *gorm.DB // Kept for legacy compatibility if needed
-	Hub  *realtime.Hub
+	Repo      *repository.BoardRepository
+	DB        *gorm.DB // Kept for legacy compatibility if needed
+	Hub       *realtime.Hub
+	Swimlanes *services.SwimlaneService // optional; groups cards by lane in GetBoardByID
 }
 
 func NewBoardHandler(repo *repository.BoardRepository, db *gorm.DB, hub *realtime.Hub) *BoardHandler {
@@ -69,12 +71,33 @@ func (h *BoardHandler) GetBoardByID(c *gin.Context) {
 		return
 	}
 
+	// Optional card filters: ?priority=high,urgent and ?sort=position|priority|last_activity
+	var priorities []models.CardPriority
+	if raw := strings.TrimSpace(c.Query("priority")); raw != "" {
+		for _, p := range strings.Split(raw, ",") {
+			priority := models.CardPriority(strings.ToLower(strings.TrimSpace(p)))
+			if !priority.Valid() {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority filter", "code": "VALIDATION_ERROR"})
+				return
+			}
+			priorities = append(priorities, priority)
+		}
+	}
+	cardOrder, ok := boardCardOrder(c.Query("sort"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort, expected position, priority or last_activity", "code": "VALIDATION_ERROR"})
+		return
+	}
+
 	// Fetch Columns & Cards (Legacy Logic Refactored)
 	var columns []models.Column
 	if err := h.DB.Where("board_id = ?", board.ID).
 		Preload("Cards", func(db *gorm.DB) *gorm.DB {
-			return db.Where("is_archived = ?", false).
-				Order("position ASC").
+			db = db.Where("is_archived = ?", false)
+			if len(priorities) > 0 {
+				db = db.Where("priority IN ?", priorities)
+			}
+			return db.Order(cardOrder).
 				Preload("Checklists").
 				Preload("Labels").
 				Preload("Members").
@@ -84,12 +107,30 @@ func (h *BoardHandler) GetBoardByID(c *gin.Context) {
 		return
 	}
 
-	// Compute CardCount
+	// Compute CardCount and staleness
+	now := time.Now()
+	staleCount := 0
 	for i := range columns {
 		columns[i].CardCount = len(columns[i].Cards)
 		if columns[i].Cards == nil {
 			columns[i].Cards = []models.Card{}
 		}
+		for j := range columns[i].Cards {
+			card := &columns[i].Cards[j]
+			card.IsStale = card.StaleAt(now, board.StaleAfterDays)
+			if card.IsStale {
+				staleCount++
+			}
+		}
+	}
+
+	// Group each column's cards by swimlane
+	var lanes []models.BoardLane
+	if h.Swimlanes != nil {
+		if lanes, err = h.Swimlanes.GroupColumns(board, columns); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to group swimlanes"})
+			return
+		}
 	}
 
 	// Fetch user role in the workspace
@@ -111,11 +152,31 @@ func (h *BoardHandler) GetBoardByID(c *gin.Context) {
 		"board":     board,
 		"columns":   columns,
 		"user_role": userRole,
+		// Staleness per card is exposed as cards[].is_stale
+		"stale_card_count": staleCount,
+	}
+	if lanes != nil {
+		response["swimlanes"] = lanes
 	}
 
+	setVersionETag(c, board.Version)
 	c.JSON(http.StatusOK, response)
 }
 
+// boardCardOrder maps the ?sort query to an ORDER BY for a column's cards.
+func boardCardOrder(sort string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(sort)) {
+	case "", "position":
+		return "position ASC", true
+	case "priority":
+		return "CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END DESC, position ASC", true
+	case "last_activity":
+		// Least recently touched first, so stale cards surface at the top.
+		return "last_activity_at ASC, position ASC", true
+	}
+	return "", false
+}
+
 // CreateBoard creates a new board in a specific workspace or default
 func (h *BoardHandler) CreateBoard(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
@@ -354,10 +415,14 @@ func (h *BoardHandler) ToggleStar(c *gin.Context) {
 	}
 
 	board.IsStarred = !board.IsStarred
-	if err := h.DB.Save(&board).Error; err != nil {
+	if err := h.DB.Model(&board).Updates(map[string]interface{}{
+		"is_starred": board.IsStarred,
+		"version":    gorm.Expr("version + 1"),
+	}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle star"})
 		return
 	}
+	board.Version++
 
 	if h.Hub != nil {
 		h.Hub.BroadcastToRoom(board.ID.String(), "BOARD_UPDATED", map[string]interface{}{
@@ -389,6 +454,18 @@ func (h *BoardHandler) UpdateBoard(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	if expectedVersion == 0 {
+		expectedVersion = board.Version
+	}
+	if board.Version != expectedVersion {
+		respondVersionConflict(c, board.Version, board)
+		return
+	}
+
 	var req map[string]interface{}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
@@ -408,12 +485,53 @@ func (h *BoardHandler) UpdateBoard(c *gin.Context) {
 	if documentationNotes, ok := req["documentation_notes"].(string); ok {
 		updates["documentation_notes"] = documentationNotes
 	}
+	if raw, present := req["stale_after_days"]; present {
+		days, ok := raw.(float64)
+		if !ok || days < 0 || days > 365 || days != float64(int(days)) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "stale_after_days must be a whole number between 0 and 365",
+				"code":  "VALIDATION_ERROR",
+				"field": "stale_after_days",
+			})
+			return
+		}
+		updates["stale_after_days"] = int(days)
+	}
+	if raw, present := req["swimlane_mode"]; present && h.Swimlanes != nil {
+		mode, _ := raw.(string)
+		var fieldID *uuid.UUID
+		if rawID, ok := req["swimlane_field_id"].(string); ok && rawID != "" {
+			id, err := uuid.Parse(rawID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid swimlane_field_id", "code": "VALIDATION_ERROR", "field": "swimlane_field_id"})
+				return
+			}
+			fieldID = &id
+		}
+		if err := h.Swimlanes.CheckMode(board.ID, models.SwimlaneMode(mode), fieldID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR", "field": "swimlane_mode"})
+			return
+		}
+		updates["swimlane_mode"] = mode
+		updates["swimlane_field_id"] = fieldID
+	}
 
 	if len(updates) > 0 {
-		if err := h.DB.Model(&board).Updates(updates).Error; err != nil {
+		updates["version"] = gorm.Expr("version + 1")
+		result := h.DB.Model(&board).Where("version = ?", expectedVersion).Updates(updates)
+		if result.Error != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update board"})
 			return
 		}
+		if result.RowsAffected == 0 {
+			if current, err := h.Repo.GetBoardByID(boardID, userID); err == nil {
+				respondVersionConflict(c, current.Version, current)
+				return
+			}
+			c.JSON(http.StatusNotFound, gin.H{"error": "Board not found or access denied"})
+			return
+		}
+		board.Version = expectedVersion + 1
 	}
 
 	if h.Hub != nil {
@@ -422,6 +540,7 @@ func (h *BoardHandler) UpdateBoard(c *gin.Context) {
 		})
 	}
 
+	setVersionETag(c, board.Version)
 	c.JSON(http.StatusOK, board)
 }
 
@@ -509,6 +628,7 @@ func (h *BoardHandler) UploadBoardBackground(c *gin.Context) {
 	if err := h.DB.Model(&board).Updates(map[string]interface{}{
 		"background_image_url": bgURL,
 		"background_color":     "",
+		"version":              gorm.Expr("version + 1"),
 	}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update board background"})
 		return
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BoardInboxHandler struct {
+	Service             *services.BoardInboxService
+	Hub                 *realtime.Hub
+	NotificationService *services.NotificationService
+	SubscriptionService *services.SubscriptionService
+}
+
+func NewBoardInboxHandler(service *services.BoardInboxService, hub *realtime.Hub, notificationService *services.NotificationService, subService *services.SubscriptionService) *BoardInboxHandler {
+	return &BoardInboxHandler{
+		Service:             service,
+		Hub:                 hub,
+		NotificationService: notificationService,
+		SubscriptionService: subService,
+	}
+}
+
+type UpdateBoardInboxRequest struct {
+	ColumnID uuid.UUID `json:"column_id" binding:"required"`
+	Enabled  *bool     `json:"enabled"`
+}
+
+func respondBoardInboxError(c *gin.Context, err error, fallback string) {
+	if respondWIPLimit(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Board inbox not configured"})
+	case errors.Is(err, services.ErrInboxColumnNotOnBoard):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	case errors.Is(err, services.ErrDuplicateInboundEmail):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "DUPLICATE_MESSAGE"})
+	case errors.Is(err, services.ErrInboundEmailTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMalformedEmail), errors.Is(err, services.ErrInboundAutoReply):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "code": "EMAIL_REJECTED"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// boardAccess parses :id and checks the caller can use the board, writing the error response on failure.
+func (h *BoardInboxHandler) boardAccess(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return boardID, userID, true
+}
+
+func (h *BoardInboxHandler) GetInbox(c *gin.Context) {
+	boardID, _, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+	inbox, err := h.Service.GetInbox(boardID)
+	if err != nil {
+		respondBoardInboxError(c, err, "Failed to fetch board inbox")
+		return
+	}
+	c.JSON(http.StatusOK, inbox)
+}
+
+// UpdateInbox creates the inbox on first call; later calls change its column or enabled flag.
+func (h *BoardInboxHandler) UpdateInbox(c *gin.Context) {
+	boardID, userID, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+	var req UpdateBoardInboxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "column_id is required", "code": "VALIDATION_ERROR"})
+		return
+	}
+	inbox, err := h.Service.ConfigureInbox(boardID, userID, req.ColumnID, req.Enabled)
+	if err != nil {
+		respondBoardInboxError(c, err, "Failed to update board inbox")
+		return
+	}
+	c.JSON(http.StatusOK, inbox)
+}
+
+func (h *BoardInboxHandler) RotateInbox(c *gin.Context) {
+	boardID, _, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+	inbox, err := h.Service.RotateInbox(boardID)
+	if err != nil {
+		respondBoardInboxError(c, err, "Failed to rotate board inbox address")
+		return
+	}
+	c.JSON(http.StatusOK, inbox)
+}
+
+func (h *BoardInboxHandler) DeleteInbox(c *gin.Context) {
+	boardID, _, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteInbox(boardID); err != nil {
+		respondBoardInboxError(c, err, "Failed to delete board inbox")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UploadMessage imports a raw .eml file, sent as multipart field "file" or as the request body.
+func (h *BoardInboxHandler) UploadMessage(c *gin.Context) {
+	boardID, _, ok := h.boardAccess(c)
+	if !ok {
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read message"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+	raw, err := io.ReadAll(io.LimitReader(reader, services.MaxInboundEmailBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read message"})
+		return
+	}
+
+	result, err := h.Service.ImportForBoard(boardID, raw)
+	if err != nil {
+		respondBoardInboxError(c, err, "Failed to import message")
+		return
+	}
+	h.afterEmailCard(result)
+	c.JSON(http.StatusCreated, result.Card)
+}
+
+// Deliver imports mail addressed to a board inbox; it matches the InboundSMTPServer callback.
+func (h *BoardInboxHandler) Deliver(from string, to []string, raw []byte) error {
+	result, err := h.Service.ProcessMessage(raw, to)
+	if err != nil {
+		return err
+	}
+	h.afterEmailCard(result)
+	return nil
+}
+
+// afterEmailCard mirrors CardHandler.Create: broadcast the new card and tell board watchers.
+func (h *BoardInboxHandler) afterEmailCard(result *services.InboundCardResult) {
+	announceCreatedCard(h.Hub, h.NotificationService, h.SubscriptionService, result.Card, result.ActorID,
+		"A new card was created from email: "+result.Card.Title)
+}
+
+// announceCreatedCard broadcasts a card created outside CardHandler and notifies the board's
+// subscribers. card.Column.BoardID must be set.
+func announceCreatedCard(hub *realtime.Hub, notifications *services.NotificationService, subs *services.SubscriptionService, card *models.Card, actorID uuid.UUID, message string) {
+	if hub != nil {
+		hub.BroadcastToRoom(card.Column.BoardID.String(), "CARD_CREATED", map[string]interface{}{
+			"board_id":     card.Column.BoardID.String(),
+			"column_id":    card.ColumnID.String(),
+			"card":         card,
+			"wip_exceeded": card.WIPExceeded,
+		})
+		broadcastWIPExceeded(hub, card.Column.BoardID, card.ID, card.WIPExceeded)
+	}
+	if subs == nil || notifications == nil {
+		return
+	}
+	subscribers, err := subs.GetSubscribers(card.Column.BoardID)
+	if err != nil {
+		log.Printf("[Cards] failed loading subscribers for board %s: %v", card.Column.BoardID, err)
+		return
+	}
+	notifications.NotifySubscribers(subscribers, actorID, models.NotificationMention,
+		"Card Created", message, card.ID, "CARD", services.PrefNotifyCardCreated)
+}
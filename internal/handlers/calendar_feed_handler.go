@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedHandler serves secret iCalendar feed URLs and the feeds themselves.
+type CalendarFeedHandler struct {
+	Service *services.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(service *services.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{Service: service}
+}
+
+// withFeedURL fills in the public feed address, built from the request's host so it matches how
+// the API was reached (honouring a TLS-terminating proxy).
+func withFeedURL(c *gin.Context, feed *models.CalendarFeed) *models.CalendarFeed {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	feed.URL = scheme + "://" + c.Request.Host + "/calendar/" + feed.Token + ".ics"
+	return feed
+}
+
+// feedFor resolves the feed a request is about: the caller's own, or theirs for board :id.
+func (h *CalendarFeedHandler) feedFor(c *gin.Context, board, regenerate bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var boardID *uuid.UUID
+	if board {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+			return
+		}
+		if _, err := h.Service.GetBoard(id, userID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		boardID = &id
+	}
+	var feed *models.CalendarFeed
+	if regenerate {
+		feed, err = h.Service.RegenerateFeed(userID, boardID)
+	} else {
+		feed, err = h.Service.Feed(userID, boardID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch calendar feed"})
+		return
+	}
+	c.JSON(http.StatusOK, withFeedURL(c, feed))
+}
+
+// GetMyFeed: GET /users/me/calendar-feed
+// Returns the caller's feed of assigned cards, creating its token on first use.
+func (h *CalendarFeedHandler) GetMyFeed(c *gin.Context) { h.feedFor(c, false, false) }
+
+// RegenerateMyFeed: POST /users/me/calendar-feed/regenerate
+func (h *CalendarFeedHandler) RegenerateMyFeed(c *gin.Context) { h.feedFor(c, false, true) }
+
+// GetBoardFeed: GET /boards/:id/calendar-feed
+// Returns the caller's feed of the board's dated cards, creating its token on first use.
+func (h *CalendarFeedHandler) GetBoardFeed(c *gin.Context) { h.feedFor(c, true, false) }
+
+// RegenerateBoardFeed: POST /boards/:id/calendar-feed/regenerate
+func (h *CalendarFeedHandler) RegenerateBoardFeed(c *gin.Context) { h.feedFor(c, true, true) }
+
+// ServeFeed: GET /calendar/:token (public; the token is the credential, ".ics" is optional)
+func (h *CalendarFeedHandler) ServeFeed(c *gin.Context) {
+	body, err := h.Service.Render(strings.TrimSuffix(c.Param("token"), ".ics"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render calendar feed"})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler serves board and saved-filter calendars and due-date rescheduling.
+type CalendarHandler struct {
+	Service *services.CalendarService
+	Filters *services.SavedFilterService
+	Hub     *realtime.Hub
+}
+
+func NewCalendarHandler(service *services.CalendarService, filters *services.SavedFilterService, hub *realtime.Hub) *CalendarHandler {
+	return &CalendarHandler{Service: service, Filters: filters, Hub: hub}
+}
+
+func respondCalendarError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, services.ErrInvalidCalendar) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	}
+	respondTimelineError(c, err, fallback)
+}
+
+// calendarOptions reads tz (IANA name, default UTC), from and to (YYYY-MM-DD) and q.
+func calendarOptions(c *gin.Context) (services.CalendarOptions, bool) {
+	opts := services.CalendarOptions{Location: time.UTC, Query: c.Query("q")}
+	if tz := c.Query("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone", "code": "VALIDATION_ERROR"})
+			return opts, false
+		}
+		opts.Location = loc
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &opts.From}, {"to", &opts.To}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", raw, opts.Location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name + " date", "code": "VALIDATION_ERROR"})
+			return opts, false
+		}
+		*p.dst = t
+	}
+	return opts, true
+}
+
+// BoardCalendar: GET /boards/:id/calendar?from=&to=&tz=&q=
+func (h *CalendarHandler) BoardCalendar(c *gin.Context) {
+	boardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	opts, ok := calendarOptions(c)
+	if !ok {
+		return
+	}
+	board, err := h.Service.GetBoard(boardID, userID)
+	if err != nil {
+		respondCalendarError(c, err, "Failed to fetch board")
+		return
+	}
+	calendar, err := h.Service.BoardCalendar(board, userID, opts)
+	if err != nil {
+		respondCalendarError(c, err, "Failed to build calendar")
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+// FilterCalendar: GET /filters/:id/calendar?from=&to=&tz=&q=
+func (h *CalendarHandler) FilterCalendar(c *gin.Context) {
+	filterID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	opts, ok := calendarOptions(c)
+	if !ok {
+		return
+	}
+	filter, err := h.Filters.GetFilter(filterID, userID)
+	if err != nil {
+		respondCalendarError(c, err, "Failed to fetch filter")
+		return
+	}
+	calendar, err := h.Service.FilterCalendar(filter, userID, opts)
+	if err != nil {
+		respondCalendarError(c, err, "Failed to build calendar")
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+// RescheduleDue: PUT /cards/:id/due-date
+// Moves the card's due date and re-arms its reminders.
+func (h *CalendarHandler) RescheduleDue(c *gin.Context) {
+	cardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		DueDate *time.Time `json:"due_date" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "due_date is required", "code": "VALIDATION_ERROR", "field": "due_date"})
+		return
+	}
+	card, err := h.Service.CardForUser(cardID, userID)
+	if err != nil {
+		respondCalendarError(c, err, "Failed to fetch card")
+		return
+	}
+	updated, err := h.Service.Reschedule(card, userID, *req.DueDate, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		respondVersionConflict(c, updated.Version, updated)
+		return
+	}
+	if errors.Is(err, services.ErrStartAfterDue) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR", "field": "due_date"})
+		return
+	}
+	if err != nil {
+		respondCalendarError(c, err, "Failed to reschedule card")
+		return
+	}
+	if h.Hub != nil {
+		h.Hub.BroadcastToRoom(card.Column.BoardID.String(), "CARD_UPDATED", map[string]interface{}{
+			"card_id":  card.ID.String(),
+			"board_id": card.Column.BoardID.String(),
+		})
+	}
+	setVersionETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
+}
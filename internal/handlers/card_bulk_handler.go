@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BulkCardRequest struct {
+	CardIDs   []uuid.UUID `json:"card_ids" binding:"required,min=1"`
+	Operation string      `json:"operation" binding:"required"`
+	ColumnID  *uuid.UUID  `json:"column_id"`
+	LabelID   *uuid.UUID  `json:"label_id"`
+	UserID    *uuid.UUID  `json:"user_id"`
+	DueDate   *time.Time  `json:"due_date"`
+	FieldID   *uuid.UUID  `json:"field_id"`
+	Value     interface{} `json:"value"`
+}
+
+// Bulk applies one operation to many cards of a board in a single transaction.
+func (h *CardHandler) Bulk(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req BulkCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "card_ids and operation are required",
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	op := services.BulkCardOperation{
+		Type:     services.BulkCardOperationType(strings.ToLower(strings.TrimSpace(req.Operation))),
+		ColumnID: req.ColumnID,
+		LabelID:  req.LabelID,
+		UserID:   req.UserID,
+		DueDate:  req.DueDate,
+		FieldID:  req.FieldID,
+		Value:    req.Value,
+	}
+
+	result, err := h.Service.BulkUpdateCards(userID, boardID, req.CardIDs, op)
+	var bulkErr *services.BulkCardError
+	switch {
+	case errors.As(err, &bulkErr):
+		status := http.StatusUnprocessableEntity
+		if bulkErr.Forbidden() {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":    "Bulk operation rejected; no cards were changed",
+			"code":     "BULK_REJECTED",
+			"failures": bulkErr.Failures,
+		})
+		return
+	case errors.Is(err, services.ErrInvalidBulkOperation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bulk operation failed; no cards were changed"})
+		return
+	}
+
+	// Newly assigned members watch their cards, as with single assignment.
+	if op.Type == services.BulkAddMember && h.SubscriptionService != nil {
+		for _, cardID := range result.CardIDs {
+			if ok, _ := h.SubscriptionService.IsSubscribed(*op.UserID, cardID); !ok {
+				_ = h.SubscriptionService.Subscribe(*op.UserID, cardID, models.SubscriptionCard)
+			}
+		}
+	}
+
+	if h.Hub != nil {
+		h.Hub.BroadcastToRoom(boardID.String(), realtime.MessageTypeCardsBulkUpdated, result)
+		if result.TargetBoardID != nil {
+			h.Hub.BroadcastToRoom(result.TargetBoardID.String(), realtime.MessageTypeCardsBulkUpdated, result)
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,27 +1,27 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	models "nexus-backend/internal/models"
 	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
 	"nexus-backend/internal/services"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-var mentionIDPattern = regexp.MustCompile(`mention:([0-9a-fA-F-]{36})`)
-var mentionTextPattern = regexp.MustCompile(`(?:^|\s)@([a-zA-Z0-9._-]{2,50})`)
-
 type CardHandler struct {
 	Service             *services.CardService
 	ActivityService     *services.ActivityService
 	NotificationService *services.NotificationService
 	SubscriptionService *services.SubscriptionService
 	Hub                 *realtime.Hub
+	Swimlanes           *services.SwimlaneService // optional; needed for moves with a lane
 }
 
 func NewCardHandler(service *services.CardService, activityService *services.ActivityService, notificationService *services.NotificationService, subService *services.SubscriptionService, hub *realtime.Hub) *CardHandler {
@@ -63,13 +63,20 @@ func (h *CardHandler) Create(c *gin.Context) {
 		// Create from Template
 		card, err = h.Service.CopyCard(*req.TemplateID, columnID)
 		if err == nil && req.Title != "" {
+			exceeded := card.WIPExceeded
 			card, err = h.Service.UpdateCard(card.ID, req.Title, "", nil, nil)
+			if err == nil {
+				card.WIPExceeded = exceeded
+			}
 		}
 	} else {
 		// Standard creation
 		card, err = h.Service.CreateCard(req.Title, req.Description, columnID)
 	}
 
+	if respondWIPLimit(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create card"})
 		return
@@ -78,16 +85,19 @@ func (h *CardHandler) Create(c *gin.Context) {
 	// Re-fetch with preloaded relations so Column.BoardID is available
 	fullCard, fetchErr := h.Service.GetCardByID(card.ID)
 	if fetchErr == nil {
+		fullCard.WIPExceeded = card.WIPExceeded
 		card = fullCard
 	}
 
 	// Broadcast to target board
 	if h.Hub != nil {
 		h.Hub.BroadcastToRoom(card.Column.BoardID.String(), "CARD_CREATED", map[string]interface{}{
-			"board_id":  card.Column.BoardID.String(),
-			"column_id": columnID.String(),
-			"card":      card,
+			"board_id":     card.Column.BoardID.String(),
+			"column_id":    columnID.String(),
+			"card":         card,
+			"wip_exceeded": card.WIPExceeded,
 		})
+		broadcastWIPExceeded(h.Hub, card.Column.BoardID, card.ID, card.WIPExceeded)
 	}
 
 	// Log Activity
@@ -134,14 +144,17 @@ func (h *CardHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	setVersionETag(c, card.Version)
 	c.JSON(http.StatusOK, card)
 }
 
 type UpdateCardRequest struct {
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	DueDate     *time.Time `json:"due_date"`
-	IsComplete  *bool      `json:"is_complete"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	StartDate   *time.Time           `json:"start_date"`
+	DueDate     *time.Time           `json:"due_date"`
+	IsComplete  *bool                `json:"is_complete"`
+	Priority    *models.CardPriority `json:"priority"`
 }
 
 // Helper to broadcast update
@@ -193,111 +206,16 @@ func (h *CardHandler) notifyWatchersExcept(cardID uuid.UUID, actorID uuid.UUID,
 	h.NotificationService.NotifySubscribers(filtered, actorID, models.NotificationMention, title, message, cardID, "CARD", pref)
 }
 
-func extractMentionUserIDs(description string) []uuid.UUID {
-	matches := mentionIDPattern.FindAllStringSubmatch(description, -1)
-	seen := map[uuid.UUID]struct{}{}
-	out := make([]uuid.UUID, 0, len(matches))
-	for _, m := range matches {
-		if len(m) < 2 {
-			continue
-		}
-		id, err := uuid.Parse(m[1])
-		if err != nil {
-			continue
-		}
-		if _, ok := seen[id]; ok {
-			continue
-		}
-		seen[id] = struct{}{}
-		out = append(out, id)
-	}
-	return out
-}
-
-func extractMentionTokens(text string) []string {
-	matches := mentionTextPattern.FindAllStringSubmatch(text, -1)
-	seen := map[string]struct{}{}
-	out := make([]string, 0, len(matches))
-	for _, m := range matches {
-		if len(m) < 2 {
-			continue
-		}
-		token := strings.ToLower(strings.TrimSpace(m[1]))
-		if token == "" {
-			continue
-		}
-		if _, ok := seen[token]; ok {
-			continue
-		}
-		seen[token] = struct{}{}
-		out = append(out, token)
-	}
-	return out
-}
-
 func (h *CardHandler) notifyDescriptionMentions(card *models.Card, actorID uuid.UUID, description string) map[uuid.UUID]struct{} {
 	notified := map[uuid.UUID]struct{}{}
-	if h.NotificationService == nil || card == nil || strings.TrimSpace(description) == "" {
+	if card == nil || strings.TrimSpace(description) == "" {
 		return notified
 	}
 
 	db := h.Service.Repo.DB
-	var board models.Board
-	if err := db.Select("id", "workspace_id").First(&board, "id = ?", card.Column.BoardID).Error; err != nil {
-		return notified
-	}
-
-	var workspaceMembers []models.WorkspaceMember
-	if err := db.Where("workspace_id = ? AND status <> ?", board.WorkspaceID, "declined").Find(&workspaceMembers).Error; err != nil {
-		return notified
-	}
-
-	allowed := map[uuid.UUID]struct{}{}
-	for _, m := range workspaceMembers {
-		allowed[m.UserID] = struct{}{}
-	}
-
-	mentionedMap := map[uuid.UUID]struct{}{}
-	for _, id := range extractMentionUserIDs(description) {
-		mentionedMap[id] = struct{}{}
-	}
-
-	tokens := extractMentionTokens(description)
-	if len(tokens) > 0 {
-		memberIDs := make([]uuid.UUID, 0, len(workspaceMembers))
-		for _, m := range workspaceMembers {
-			memberIDs = append(memberIDs, m.UserID)
-		}
-		if len(memberIDs) > 0 {
-			var users []models.User
-			if err := db.Select("id", "name", "username", "email").Where("id IN ?", memberIDs).Find(&users).Error; err == nil {
-				tokenSet := map[string]struct{}{}
-				for _, t := range tokens {
-					tokenSet[t] = struct{}{}
-				}
-				for _, u := range users {
-					candidates := []string{
-						strings.ToLower(strings.TrimSpace(u.Username)),
-						strings.ToLower(strings.ReplaceAll(strings.TrimSpace(u.Name), " ", "")),
-					}
-					if parts := strings.Split(strings.ToLower(strings.TrimSpace(u.Email)), "@"); len(parts) > 0 {
-						candidates = append(candidates, parts[0])
-					}
-					for _, c := range candidates {
-						if c == "" {
-							continue
-						}
-						if _, ok := tokenSet[c]; ok {
-							mentionedMap[u.ID] = struct{}{}
-							break
-						}
-					}
-				}
-			}
-		}
-	}
-
-	if len(mentionedMap) == 0 {
+	mentionedMap := services.ResolveBoardMentions(db, card.Column.BoardID, description)
+	services.RecordMentions(db, card.ID, actorID, mentionedMap)
+	if h.NotificationService == nil || len(mentionedMap) == 0 {
 		return notified
 	}
 
@@ -311,9 +229,6 @@ func (h *CardHandler) notifyDescriptionMentions(card *models.Card, actorID uuid.
 		if recipientID == actorID {
 			continue
 		}
-		if _, ok := allowed[recipientID]; !ok {
-			continue
-		}
 		_, _ = h.NotificationService.CreateNotification(
 			recipientID,
 			actorID,
@@ -337,13 +252,35 @@ func (h *CardHandler) Update(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateCardRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
 
-	card, err := h.Service.UpdateCard(id, req.Title, req.Description, req.DueDate, req.IsComplete)
+	if req.Priority != nil && !req.Priority.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Priority must be one of none, low, medium, high, urgent",
+			"code":  "VALIDATION_ERROR",
+			"field": "priority",
+		})
+		return
+	}
+
+	card, err := h.Service.UpdateCardIfVersion(id, expectedVersion, req.Title, req.Description, req.StartDate, req.DueDate, req.IsComplete, req.Priority)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		h.respondCardConflict(c, id)
+		return
+	}
+	if errors.Is(err, services.ErrStartAfterDue) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR", "field": "start_date"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found"})
 		return
@@ -355,9 +292,13 @@ func (h *CardHandler) Update(c *gin.Context) {
 	if userIDStr, exists := c.Get("userID"); exists {
 		userID := userIDStr.(uuid.UUID)
 		// Fetch boardID again or pass it down? fetching it via card is safer
-		h.ActivityService.LogActivity(userID, card.Column.BoardID, "updated_card", card.ID, map[string]interface{}{
+		metadata := map[string]interface{}{
 			"card_title": card.Title,
-		})
+		}
+		if req.Priority != nil {
+			metadata["priority"] = *req.Priority
+		}
+		h.ActivityService.LogActivity(userID, card.Column.BoardID, "updated_card", card.ID, metadata)
 
 		// Notify explicitly mentioned members in description markdown: [@Name](mention:<user-id>) or @username
 		mentionedRecipients := h.notifyDescriptionMentions(card, userID, req.Description)
@@ -370,9 +311,20 @@ func (h *CardHandler) Update(c *gin.Context) {
 		}
 	}
 
+	setVersionETag(c, card.Version)
 	c.JSON(http.StatusOK, card)
 }
 
+// respondCardConflict returns 412 with the card as it currently stands.
+func (h *CardHandler) respondCardConflict(c *gin.Context, cardID uuid.UUID) {
+	current, err := h.Service.GetCardByID(cardID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found"})
+		return
+	}
+	respondVersionConflict(c, current.Version, current)
+}
+
 // Metadata Endpoints
 
 func (h *CardHandler) AddLabel(c *gin.Context) {
@@ -557,8 +509,10 @@ func (h *CardHandler) Delete(c *gin.Context) {
 }
 
 type MoveCardRequest struct {
-	ColumnID uuid.UUID `json:"column_id" binding:"required"`
-	Position float64   `json:"position" binding:"min=0"`
+	ColumnID         uuid.UUID `json:"column_id" binding:"required"`
+	Position         float64   `json:"position" binding:"min=0"`
+	OverrideCriteria bool      `json:"override_criteria"` // workspace admins only; recorded in the activity log
+	Lane             *string   `json:"lane"`              // target swimlane key on the column's board; "" is the catch-all lane
 }
 
 func (h *CardHandler) Move(c *gin.Context) {
@@ -569,13 +523,90 @@ func (h *CardHandler) Move(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req MoveCardRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
 		return
 	}
 
-	card, err := h.Service.MoveCard(id, req.ColumnID, req.Position)
+	// The target lane is checked before anything moves.
+	var laneBoard *models.Board
+	if req.Lane != nil {
+		if h.Swimlanes == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Swimlanes are not available", "code": "VALIDATION_ERROR", "field": "lane"})
+			return
+		}
+		laneBoard, err = h.Swimlanes.BoardForColumn(req.ColumnID)
+		if err == nil {
+			err = h.Swimlanes.CheckLane(laneBoard, *req.Lane)
+		}
+		if errors.Is(err, services.ErrInvalidSwimlane) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR", "field": "lane"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Move failed (Concurrent modification or invalid target)", "code": "CONFLICT", "retry": true})
+			return
+		}
+	}
+
+	// Column criteria are checked up front so an admin override can be authorized and
+	// audited; without an override the service enforces them again.
+	unmet, err := h.Service.CheckMoveCriteria(id, req.ColumnID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check column criteria"})
+		return
+	}
+	override := req.OverrideCriteria && len(unmet) > 0
+	if override {
+		userID, ok := c.Get("userID")
+		if !ok || !h.Service.CanOverrideCriteria(userID.(uuid.UUID), unmet) {
+			c.JSON(http.StatusForbidden, gin.H{"error": services.ErrCriteriaOverrideDenied.Error(), "code": "OVERRIDE_FORBIDDEN", "unmet": unmet})
+			return
+		}
+	}
+
+	// Dragging across lanes changes what the lane is derived from (swimlane, assignee,
+	// label, priority or field value), in the move's transaction so both land or neither.
+	opts := repository.MoveCardOptions{
+		ExpectedVersion:  expectedVersion,
+		OverrideCriteria: override,
+	}
+	var fromLane string
+	laneChanged := false
+	if req.Lane != nil {
+		opts.InTransaction = func(tx *gorm.DB) (err error) {
+			fromLane, laneChanged, err = h.Swimlanes.MoveToLaneTx(tx, laneBoard, id, *req.Lane)
+			return err
+		}
+	}
+
+	result, err := h.Service.MoveCardWithOptions(id, req.ColumnID, req.Position, opts)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		h.respondCardConflict(c, id)
+		return
+	}
+	var criteriaErr *services.CriteriaError
+	if errors.As(err, &criteriaErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Card does not meet the column criteria",
+			"code":  "CRITERIA_NOT_MET",
+			"unmet": criteriaErr.Unmet,
+		})
+		return
+	}
+	if respondWIPLimit(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrInvalidSwimlane) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR", "field": "lane"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusConflict, gin.H{
 			"error": "Move failed (Concurrent modification or invalid target)",
@@ -585,18 +616,26 @@ func (h *CardHandler) Move(c *gin.Context) {
 		return
 	}
 
+	card := result.Card
+
 	// Re-fetch to ensure relationships (Column/BoardID) are correct in response/broadcast
 	updatedCard, fetchErr := h.Service.GetCardByID(card.ID)
 	if fetchErr == nil {
+		updatedCard.WIPExceeded = card.WIPExceeded
 		card = updatedCard
 	}
 
 	if h.Hub != nil {
 		h.Hub.BroadcastToRoom(card.Column.BoardID.String(), "CARD_MOVED", map[string]interface{}{
-			"card_id":   card.ID.String(),
-			"column_id": req.ColumnID.String(),
-			"position":  req.Position,
+			"card_id":      card.ID.String(),
+			"column_id":    req.ColumnID.String(),
+			"position":     card.Position,
+			"version":      card.Version,
+			"wip_exceeded": card.WIPExceeded,
+			"lane":         req.Lane,
 		})
+		broadcastRebalance(h.Hub, card.Column.BoardID, repository.CardPositionList(req.ColumnID), result.Rebalanced)
+		broadcastWIPExceeded(h.Hub, card.Column.BoardID, card.ID, card.WIPExceeded)
 	}
 
 	// Log Activity (only if column changed, or maybe even position?)
@@ -607,14 +646,31 @@ func (h *CardHandler) Move(c *gin.Context) {
 			"column_id": req.ColumnID,
 			"position":  req.Position,
 		})
+		if laneChanged {
+			h.ActivityService.LogActivity(userID, card.Column.BoardID, "changed_swimlane", card.ID, map[string]interface{}{
+				"card_title":    card.Title,
+				"swimlane_mode": laneBoard.SwimlaneMode,
+				"from_lane":     fromLane,
+				"to_lane":       *req.Lane,
+			})
+		}
+		if override {
+			h.ActivityService.LogActivity(userID, card.Column.BoardID, "overrode_column_criteria", card.ID, map[string]interface{}{
+				"card_title": card.Title,
+				"column_id":  req.ColumnID,
+				"unmet":      unmet,
+			})
+		}
 
 		// Notify Subscribers
 		h.notifyWatchers(card.ID, userID, "Card Moved", "A card you are watching was moved to another list", services.PrefNotifyCardMoved)
 	}
 
+	setVersionETag(c, card.Version)
 	c.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"moved_card": card,
+		"success":      true,
+		"moved_card":   card,
+		"wip_exceeded": card.WIPExceeded,
 	})
 }
 
@@ -667,23 +723,31 @@ func (h *CardHandler) Restore(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.RestoreCard(id, req.ColumnID); err != nil {
+	exceeded, err := h.Service.RestoreCard(id, req.ColumnID)
+	if respondWIPLimit(c, err) {
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore card"})
 		return
 	}
 
 	h.broadcastCardUpdate(id)
 
+	card, fetchErr := h.Service.GetCardByID(id)
+	if fetchErr == nil {
+		broadcastWIPExceeded(h.Hub, card.Column.BoardID, id, exceeded)
+	}
+
 	// Log Activity
-	if userIDStr, exists := c.Get("userID"); exists {
+	if userIDStr, exists := c.Get("userID"); exists && fetchErr == nil {
 		userID := userIDStr.(uuid.UUID)
-		card, _ := h.Service.GetCardByID(id)
 		h.ActivityService.LogActivity(userID, card.Column.BoardID, "restored_card", card.ID, map[string]interface{}{
 			"card_title": card.Title,
 		})
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, gin.H{"success": true, "wip_exceeded": exceeded})
 }
 
 func (h *CardHandler) Copy(c *gin.Context) {
@@ -703,6 +767,9 @@ func (h *CardHandler) Copy(c *gin.Context) {
 	}
 
 	newCard, err := h.Service.CopyCard(originalCardID, req.TargetColumnID)
+	if respondWIPLimit(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to copy card"})
 		return
@@ -711,10 +778,12 @@ func (h *CardHandler) Copy(c *gin.Context) {
 	// Broadcast to target board
 	if h.Hub != nil {
 		h.Hub.BroadcastToRoom(newCard.Column.BoardID.String(), "CARD_CREATED", map[string]interface{}{
-			"board_id":  newCard.Column.BoardID.String(),
-			"column_id": newCard.ColumnID.String(),
-			"card":      newCard,
+			"board_id":     newCard.Column.BoardID.String(),
+			"column_id":    newCard.ColumnID.String(),
+			"card":         newCard,
+			"wip_exceeded": newCard.WIPExceeded,
 		})
+		broadcastWIPExceeded(h.Hub, newCard.Column.BoardID, newCard.ID, newCard.WIPExceeded)
 	}
 
 	// Log Activity
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CardQueryHandler lists cards across boards matching a card query.
+type CardQueryHandler struct {
+	Service *services.CardQueryService
+}
+
+func NewCardQueryHandler(service *services.CardQueryService) *CardQueryHandler {
+	return &CardQueryHandler{Service: service}
+}
+
+// respondCardQueryError answers parse errors with the position of the problem.
+func respondCardQueryError(c *gin.Context, err error, fallback string) {
+	var parseErr *services.CardQueryError
+	switch {
+	case errors.As(err, &parseErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    parseErr.Error(),
+			"code":     "QUERY_PARSE_ERROR",
+			"message":  parseErr.Message,
+			"position": parseErr.Position,
+		})
+	case errors.Is(err, services.ErrInvalidCardQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// ListCards: GET /cards?q=&sort=&order=&cursor=&limit=
+func (h *CardQueryHandler) ListCards(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	page := services.CardQueryPage{Sort: c.Query("sort"), Order: c.Query("order"), Cursor: c.Query("cursor")}
+	if raw := c.Query("limit"); raw != "" {
+		if page.Limit, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit", "code": "VALIDATION_ERROR"})
+			return
+		}
+	}
+	result, err := h.Service.QueryCards(userID, c.Query("q"), page)
+	if err != nil {
+		respondCardQueryError(c, err, "Failed to query cards")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
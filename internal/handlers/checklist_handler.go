@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"nexus-backend/internal/middleware"
 	"nexus-backend/internal/models"
 	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
 	"nexus-backend/internal/services"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,6 +22,8 @@ type ChecklistHandler struct {
 	NotificationService *services.NotificationService
 	SubscriptionService *services.SubscriptionService
 	AutomationService   *services.AutomationService
+	Service             *services.ChecklistService
+	Formulas            *services.FormulaService // optional; checklist progress feeds formula fields
 }
 
 func NewChecklistHandler(db *gorm.DB, hub *realtime.Hub, notificationService *services.NotificationService, subService *services.SubscriptionService, automationService *services.AutomationService) *ChecklistHandler {
@@ -26,6 +33,24 @@ func NewChecklistHandler(db *gorm.DB, hub *realtime.Hub, notificationService *se
 		NotificationService: notificationService,
 		SubscriptionService: subService,
 		AutomationService:   automationService,
+		Service:             services.NewChecklistService(db, notificationService),
+	}
+}
+
+// respondChecklistError maps checklist service errors to HTTP responses.
+func respondChecklistError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrAssigneeNotMember),
+		errors.Is(err, services.ErrTemplateNotAvailable),
+		errors.Is(err, services.ErrInvalidTemplateScope),
+		errors.Is(err, services.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
 	}
 }
 
@@ -43,8 +68,9 @@ func (h *ChecklistHandler) notifyWatchers(cardID uuid.UUID, actorID uuid.UUID, t
 	h.NotificationService.NotifySubscribers(subscribers, actorID, models.NotificationMention, title, message, cardID, "CARD", "")
 }
 
-// Helper to broadcast update
+// Helper to refresh formula fields and broadcast update
 func (h *ChecklistHandler) broadcastCardUpdate(cardID uuid.UUID) {
+	h.Formulas.RecomputeCard(cardID)
 	if h.Hub == nil {
 		return
 	}
@@ -58,6 +84,17 @@ func (h *ChecklistHandler) broadcastCardUpdate(cardID uuid.UUID) {
 	}
 }
 
+// broadcastRebalance resolves the card's board and forwards renumbered positions to it.
+func (h *ChecklistHandler) broadcastRebalance(cardID uuid.UUID, list repository.PositionList, updates []repository.PositionUpdate) {
+	if h.Hub == nil || len(updates) == 0 {
+		return
+	}
+	var card models.Card
+	if err := h.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err == nil {
+		broadcastRebalance(h.Hub, card.Column.BoardID, list, updates)
+	}
+}
+
 // CreateChecklist adds a new checklist to a card
 func (h *ChecklistHandler) CreateChecklist(c *gin.Context) {
 	cardID, err := uuid.Parse(c.Param("id"))
@@ -67,13 +104,19 @@ func (h *ChecklistHandler) CreateChecklist(c *gin.Context) {
 	}
 
 	var req struct {
-		Title string `json:"title" binding:"required,min=1,max=200"`
+		Title      string     `json:"title" binding:"max=200"`
+		TemplateID *uuid.UUID `json:"template_id"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Title == "" && req.TemplateID == nil) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
 		return
 	}
 
+	if req.TemplateID != nil {
+		h.createFromTemplate(c, cardID, *req.TemplateID, req.Title)
+		return
+	}
+
 	// Calculate position using midpoint algorithm
 	var maxPos float64
 	h.DB.Model(&models.Checklist{}).Where("card_id = ?", cardID).
@@ -101,6 +144,67 @@ func (h *ChecklistHandler) CreateChecklist(c *gin.Context) {
 	c.JSON(http.StatusCreated, checklist)
 }
 
+// createFromTemplate handles CreateChecklist with a template_id; the title overrides the template name.
+func (h *ChecklistHandler) createFromTemplate(c *gin.Context, cardID, templateID uuid.UUID, title string) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var card models.Card
+	if err := h.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found"})
+		return
+	}
+	if !h.Service.CanAccessBoard(userID, card.Column.BoardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	checklist, err := h.Service.ApplyTemplate(cardID, templateID, title)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to create checklist")
+		return
+	}
+
+	h.broadcastCardUpdate(cardID)
+	h.notifyWatchers(cardID, userID, "Checklist Added", "A new checklist was added to a card you are watching")
+	c.JSON(http.StatusCreated, checklist)
+}
+
+// CopyChecklist copies a checklist and its items onto another card.
+func (h *ChecklistHandler) CopyChecklist(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid checklist ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		CardID         uuid.UUID `json:"card_id" binding:"required"`
+		KeepCompletion bool      `json:"keep_completion"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "card_id is required"})
+		return
+	}
+
+	checklist, target, err := h.Service.CopyChecklist(id, req.CardID, userID, req.KeepCompletion)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to copy checklist")
+		return
+	}
+
+	h.broadcastCardUpdate(target.ID)
+	h.notifyWatchers(target.ID, userID, "Checklist Added", "A new checklist was added to a card you are watching")
+	c.JSON(http.StatusCreated, checklist)
+}
+
 // DeleteChecklist removes a checklist and its items
 func (h *ChecklistHandler) DeleteChecklist(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -133,6 +237,11 @@ func (h *ChecklistHandler) MoveChecklist(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req struct {
 		Position float64 `json:"position" binding:"required"`
 	}
@@ -146,14 +255,54 @@ func (h *ChecklistHandler) MoveChecklist(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Checklist not found"})
 		return
 	}
+	if expectedVersion == 0 {
+		expectedVersion = checklist.Version
+	}
+	if checklist.Version != expectedVersion {
+		respondVersionConflict(c, checklist.Version, checklist)
+		return
+	}
 
-	checklist.Position = req.Position
-	if err := h.DB.Save(&checklist).Error; err != nil {
+	var rebalanced []repository.PositionUpdate
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&checklist).Where("version = ?", expectedVersion).Updates(map[string]interface{}{
+			"position": req.Position,
+			"version":  gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrVersionConflict
+		}
+		updates, err := repository.RebalanceIfCrowded(tx, repository.ChecklistPositionList(checklist.CardID), checklist.ID, req.Position)
+		rebalanced = updates
+		return err
+	})
+	if errors.Is(err, repository.ErrVersionConflict) {
+		var current models.Checklist
+		if err := h.DB.First(&current, "id = ?", id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Checklist not found"})
+			return
+		}
+		respondVersionConflict(c, current.Version, current)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move checklist"})
 		return
 	}
+	checklist.Position = req.Position
+	checklist.Version = expectedVersion + 1
+	for _, u := range rebalanced {
+		if u.ID == checklist.ID {
+			checklist.Position = u.Position
+		}
+	}
 
 	h.broadcastCardUpdate(checklist.CardID)
+	h.broadcastRebalance(checklist.CardID, repository.ChecklistPositionList(checklist.CardID), rebalanced)
+	setVersionETag(c, checklist.Version)
 	c.JSON(http.StatusOK, checklist)
 }
 
@@ -166,13 +315,27 @@ func (h *ChecklistHandler) CreateItem(c *gin.Context) {
 	}
 
 	var req struct {
-		Title string `json:"title" binding:"required,min=1,max=500"`
+		Title      string     `json:"title" binding:"required,min=1,max=500"`
+		AssigneeID *uuid.UUID `json:"assignee_id"`
+		DueDate    *time.Time `json:"due_date"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
 		return
 	}
 
+	var card models.Card
+	if err := h.DB.Preload("Column").
+		Where("id = (SELECT card_id FROM checklists WHERE id = ? AND deleted_at IS NULL)", checklistID).
+		First(&card).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checklist not found"})
+		return
+	}
+	if req.AssigneeID != nil && !h.Service.CanAccessBoard(*req.AssigneeID, card.Column.BoardID) {
+		respondChecklistError(c, services.ErrAssigneeNotMember, "")
+		return
+	}
+
 	// Calculate position
 	var maxPos float64
 	h.DB.Model(&models.ChecklistItem{}).Where("checklist_id = ?", checklistID).
@@ -183,6 +346,8 @@ func (h *ChecklistHandler) CreateItem(c *gin.Context) {
 		Title:       req.Title,
 		Position:    maxPos + 16384.0,
 		IsCompleted: false,
+		AssigneeID:  req.AssigneeID,
+		DueDate:     req.DueDate,
 	}
 
 	if err := h.DB.Create(&item).Error; err != nil {
@@ -190,15 +355,19 @@ func (h *ChecklistHandler) CreateItem(c *gin.Context) {
 		return
 	}
 
-	// Get CardID
-	var checklist models.Checklist
-	h.DB.First(&checklist, "id = ?", checklistID)
-	h.broadcastCardUpdate(checklist.CardID)
+	h.broadcastCardUpdate(card.ID)
 
 	// Notify Subscribers
 	if userIDStr, exists := c.Get("userID"); exists {
 		userID := userIDStr.(uuid.UUID)
-		h.notifyWatchers(checklist.CardID, userID, "Checklist Item Added", "A new item was added to a checklist on a card you are watching")
+		h.notifyWatchers(card.ID, userID, "Checklist Item Added", "A new item was added to a checklist on a card you are watching")
+
+		skip := map[uuid.UUID]struct{}{}
+		if item.AssigneeID != nil {
+			h.Service.NotifyAssigned(&item, &card, userID)
+			skip[*item.AssigneeID] = struct{}{}
+		}
+		h.Service.NotifyTitleMentions(&item, &card, userID, skip)
 	}
 
 	c.JSON(http.StatusCreated, item)
@@ -213,7 +382,8 @@ func (h *ChecklistHandler) ToggleItem(c *gin.Context) {
 	}
 
 	var req struct {
-		IsCompleted bool `json:"is_completed"`
+		IsCompleted *bool   `json:"is_completed"`
+		Title       *string `json:"title" binding:"omitempty,min=1,max=500"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
@@ -226,7 +396,14 @@ func (h *ChecklistHandler) ToggleItem(c *gin.Context) {
 		return
 	}
 
-	item.IsCompleted = req.IsCompleted
+	oldTitle := item.Title
+	completing := req.IsCompleted != nil && *req.IsCompleted
+	if req.IsCompleted != nil {
+		item.IsCompleted = *req.IsCompleted
+	}
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
 	if err := h.DB.Save(&item).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
 		return
@@ -240,7 +417,7 @@ func (h *ChecklistHandler) ToggleItem(c *gin.Context) {
 	}
 	h.broadcastCardUpdate(checklist.CardID)
 
-	if req.IsCompleted && h.AutomationService != nil {
+	if completing && h.AutomationService != nil {
 		var total int64
 		var incomplete int64
 		h.DB.Model(&models.ChecklistItem{}).Where("checklist_id = ?", item.ChecklistID).Count(&total)
@@ -261,11 +438,22 @@ func (h *ChecklistHandler) ToggleItem(c *gin.Context) {
 	// Notify Subscribers
 	if userIDStr, exists := c.Get("userID"); exists {
 		userID := userIDStr.(uuid.UUID)
-		status := "completed"
-		if !item.IsCompleted {
-			status = "uncompleted"
+		if req.IsCompleted != nil {
+			status := "completed"
+			if !item.IsCompleted {
+				status = "uncompleted"
+			}
+			h.notifyWatchers(checklist.CardID, userID, "Checklist Item Updated", "An item was marked as "+status+" on a card you are watching")
+		}
+
+		// Only people newly mentioned by a title edit hear about it.
+		if req.Title != nil && item.Title != oldTitle {
+			var card models.Card
+			if err := h.DB.Preload("Column").First(&card, "id = ?", checklist.CardID).Error; err == nil {
+				already := services.ResolveBoardMentions(h.DB, card.Column.BoardID, oldTitle)
+				h.Service.NotifyTitleMentions(&item, &card, userID, already)
+			}
 		}
-		h.notifyWatchers(checklist.CardID, userID, "Checklist Item Updated", "An item was marked as "+status+" on a card you are watching")
 	}
 
 	c.JSON(http.StatusOK, item)
@@ -300,15 +488,35 @@ func (h *ChecklistHandler) MoveItem(c *gin.Context) {
 		item.ChecklistID = req.ChecklistID
 	}
 
-	if err := h.DB.Save(&item).Error; err != nil {
+	var rebalanced []repository.PositionUpdate
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&item).Error; err != nil {
+			return err
+		}
+		updates, err := repository.RebalanceIfCrowded(tx, repository.ChecklistItemPositionList(item.ChecklistID), item.ID, item.Position)
+		rebalanced = updates
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move item"})
 		return
 	}
+	for _, u := range rebalanced {
+		if u.ID == item.ID {
+			item.Position = u.Position
+		}
+	}
 
 	// Get CardID of old checklist
 	var oldChecklist models.Checklist
 	h.DB.First(&oldChecklist, "id = ?", oldChecklistID)
 	h.broadcastCardUpdate(oldChecklist.CardID)
+	if len(rebalanced) > 0 {
+		var targetChecklist models.Checklist
+		if err := h.DB.First(&targetChecklist, "id = ?", item.ChecklistID).Error; err == nil {
+			h.broadcastRebalance(targetChecklist.CardID, repository.ChecklistItemPositionList(item.ChecklistID), rebalanced)
+		}
+	}
 
 	// If moved to a different checklist, broadcast update for that card too
 	if req.ChecklistID != uuid.Nil && req.ChecklistID != oldChecklistID {
@@ -349,3 +557,83 @@ func (h *ChecklistHandler) DeleteItem(c *gin.Context) {
 	h.broadcastCardUpdate(cardID)
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// AssignItem sets or clears a checklist item's assignee.
+func (h *ChecklistHandler) AssignItem(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		AssigneeID *uuid.UUID `json:"assignee_id"` // null unassigns
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	item, card, err := h.Service.AssignItem(id, req.AssigneeID, userID)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to assign item")
+		return
+	}
+
+	h.broadcastCardUpdate(card.ID)
+	c.JSON(http.StatusOK, item)
+}
+
+// SetItemDueDate sets or clears a checklist item's due date.
+func (h *ChecklistHandler) SetItemDueDate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		DueDate *time.Time `json:"due_date"` // null clears
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	item, card, err := h.Service.SetItemDueDate(id, req.DueDate, userID)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to set due date")
+		return
+	}
+
+	h.broadcastCardUpdate(card.ID)
+	c.JSON(http.StatusOK, item)
+}
+
+// GetMyItems lists checklist items assigned to the caller across all accessible boards.
+// Completed items are left out unless include_completed=true.
+func (h *ChecklistHandler) GetMyItems(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	includeCompleted, _ := strconv.ParseBool(c.Query("include_completed"))
+
+	items, err := h.Service.AssignedItems(userID, includeCompleted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklist items"})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
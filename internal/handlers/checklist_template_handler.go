@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+
+	"nexus-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ChecklistTemplateRequest struct {
+	Name  string   `json:"name" binding:"required,max=200"`
+	Items []string `json:"items"`
+}
+
+type UpdateChecklistTemplateRequest struct {
+	Name  *string   `json:"name"`
+	Items *[]string `json:"items"` // replaces all items when present
+}
+
+// ListBoardTemplates returns checklist templates usable on a board: its own and its workspace's.
+func (h *ChecklistHandler) ListBoardTemplates(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	templates, err := h.Service.ListBoardTemplates(boardID)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to fetch checklist templates")
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *ChecklistHandler) CreateBoardTemplate(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	h.createTemplate(c, &boardID, nil)
+}
+
+func (h *ChecklistHandler) ListWorkspaceTemplates(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.Service.CanAccessWorkspace(userID, workspaceID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	templates, err := h.Service.ListWorkspaceTemplates(workspaceID)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to fetch checklist templates")
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *ChecklistHandler) CreateWorkspaceTemplate(c *gin.Context) {
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+	h.createTemplate(c, nil, &workspaceID)
+}
+
+func (h *ChecklistHandler) createTemplate(c *gin.Context, boardID, workspaceID *uuid.UUID) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ChecklistTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	tmpl, err := h.Service.CreateTemplate(userID, boardID, workspaceID, req.Name, req.Items)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to create checklist template")
+		return
+	}
+	c.JSON(http.StatusCreated, tmpl)
+}
+
+func (h *ChecklistHandler) GetTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tmpl, err := h.Service.GetTemplate(id, userID)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to fetch checklist template")
+		return
+	}
+	c.JSON(http.StatusOK, tmpl)
+}
+
+func (h *ChecklistHandler) UpdateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req UpdateChecklistTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	tmpl, err := h.Service.UpdateTemplate(id, userID, req.Name, req.Items)
+	if err != nil {
+		respondChecklistError(c, err, "Failed to update checklist template")
+		return
+	}
+	c.JSON(http.StatusOK, tmpl)
+}
+
+func (h *ChecklistHandler) DeleteTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.Service.DeleteTemplate(id, userID); err != nil {
+		respondChecklistError(c, err, "Failed to delete checklist template")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
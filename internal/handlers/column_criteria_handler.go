@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ColumnCriteriaHandler serves the entry and exit criteria of columns.
+type ColumnCriteriaHandler struct {
+	Service *services.ColumnCriteriaService
+	Hub     *realtime.Hub
+}
+
+func NewColumnCriteriaHandler(service *services.ColumnCriteriaService, hub *realtime.Hub) *ColumnCriteriaHandler {
+	return &ColumnCriteriaHandler{Service: service, Hub: hub}
+}
+
+// columnAccess loads :id and checks the caller can use its board, writing the error response on failure.
+func (h *ColumnCriteriaHandler) columnAccess(c *gin.Context) (*models.Column, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column ID"})
+		return nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	column, err := h.Service.GetColumn(id, userID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Column not found", "code": "NOT_FOUND"})
+		return nil, false
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch column"})
+		return nil, false
+	}
+	return column, true
+}
+
+// GetCriteria: GET /columns/:id/criteria
+func (h *ColumnCriteriaHandler) GetCriteria(c *gin.Context) {
+	column, ok := h.columnAccess(c)
+	if !ok {
+		return
+	}
+	criteria, err := h.Service.ListCriteria(column.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch criteria"})
+		return
+	}
+	c.JSON(http.StatusOK, criteria)
+}
+
+// ReplaceCriteria: PUT /columns/:id/criteria
+// The body's criteria replace all of the column's criteria; an empty list removes them.
+func (h *ColumnCriteriaHandler) ReplaceCriteria(c *gin.Context) {
+	column, ok := h.columnAccess(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Criteria []services.ColumnCriterionInput `json:"criteria"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	criteria, err := h.Service.ReplaceCriteria(column, req.Criteria)
+	if errors.Is(err, services.ErrInvalidCriterion) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save criteria"})
+		return
+	}
+
+	if h.Hub != nil {
+		h.Hub.BroadcastToRoom(column.BoardID.String(), "COLUMN_UPDATED", map[string]interface{}{
+			"column_id": column.ID.String(),
+			"board_id":  column.BoardID.String(),
+		})
+	}
+	c.JSON(http.StatusOK, criteria)
+}
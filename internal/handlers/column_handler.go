@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"nexus-backend/internal/models"
 	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,7 +27,10 @@ type CreateColumnRequest struct {
 }
 
 type UpdateColumnRequest struct {
-	Name string `json:"name" binding:"max=100"`
+	Name     string           `json:"name" binding:"max=100"`
+	WIPLimit *int             `json:"wip_limit"` // 0 removes the limit
+	WIPScope *models.WIPScope `json:"wip_scope"`
+	WIPMode  *models.WIPMode  `json:"wip_mode"`
 }
 
 func (h *ColumnHandler) CreateColumn(c *gin.Context) {
@@ -76,6 +81,11 @@ func (h *ColumnHandler) CreateColumn(c *gin.Context) {
 
 func (h *ColumnHandler) UpdateColumn(c *gin.Context) {
 	id := c.Param("id")
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateColumnRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -94,14 +104,60 @@ func (h *ColumnHandler) UpdateColumn(c *gin.Context) {
 		return
 	}
 
+	if expectedVersion == 0 {
+		expectedVersion = column.Version
+	}
+	if column.Version != expectedVersion {
+		respondVersionConflict(c, column.Version, column)
+		return
+	}
+
+	if req.WIPLimit != nil && *req.WIPLimit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "wip_limit cannot be negative", "code": "VALIDATION_ERROR", "field": "wip_limit"})
+		return
+	}
+	if req.WIPScope != nil && !req.WIPScope.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "wip_scope must be cards or member", "code": "VALIDATION_ERROR", "field": "wip_scope"})
+		return
+	}
+	if req.WIPMode != nil && !req.WIPMode.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "wip_mode must be warn or block", "code": "VALIDATION_ERROR", "field": "wip_mode"})
+		return
+	}
+
 	if req.Name != "" {
 		column.Name = req.Name
 	}
+	updates := map[string]interface{}{
+		"name":    column.Name,
+		"version": gorm.Expr("version + 1"),
+	}
+	if req.WIPLimit != nil {
+		column.WIPLimit = req.WIPLimit
+		if *req.WIPLimit == 0 {
+			column.WIPLimit = nil
+		}
+		updates["wip_limit"] = column.WIPLimit
+	}
+	if req.WIPScope != nil {
+		column.WIPScope = *req.WIPScope
+		updates["wip_scope"] = column.WIPScope
+	}
+	if req.WIPMode != nil {
+		column.WIPMode = *req.WIPMode
+		updates["wip_mode"] = column.WIPMode
+	}
 
-	if err := h.DB.Save(&column).Error; err != nil {
+	result := h.DB.Model(&column).Where("version = ?", expectedVersion).Updates(updates)
+	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update column"})
 		return
 	}
+	if result.RowsAffected == 0 {
+		h.respondColumnConflict(c, column.ID)
+		return
+	}
+	column.Version = expectedVersion + 1
 
 	if h.Hub != nil {
 		h.Hub.BroadcastToRoom(column.BoardID.String(), "COLUMN_UPDATED", map[string]interface{}{
@@ -110,9 +166,20 @@ func (h *ColumnHandler) UpdateColumn(c *gin.Context) {
 		})
 	}
 
+	setVersionETag(c, column.Version)
 	c.JSON(http.StatusOK, column)
 }
 
+// respondColumnConflict returns 412 with the column as it currently stands.
+func (h *ColumnHandler) respondColumnConflict(c *gin.Context, columnID uuid.UUID) {
+	var current models.Column
+	if err := h.DB.First(&current, "id = ?", columnID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Column not found", "code": "NOT_FOUND"})
+		return
+	}
+	respondVersionConflict(c, current.Version, current)
+}
+
 func (h *ColumnHandler) DeleteColumn(c *gin.Context) {
 	id := c.Param("id")
 
@@ -155,6 +222,11 @@ type MoveColumnRequest struct {
 
 func (h *ColumnHandler) MoveColumn(c *gin.Context) {
 	id := c.Param("id")
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req MoveColumnRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position"})
@@ -162,21 +234,51 @@ func (h *ColumnHandler) MoveColumn(c *gin.Context) {
 	}
 
 	var column models.Column
+	var rebalanced []repository.PositionUpdate
 
 	// Transaction for safety
 	err := h.DB.Transaction(func(tx *gorm.DB) error {
 		if err := tx.First(&column, "id = ?", id).Error; err != nil {
 			return err
 		}
+		if expectedVersion != 0 && column.Version != expectedVersion {
+			return repository.ErrVersionConflict
+		}
 
 		if column.Position == req.Position {
 			return nil
 		}
 
+		result := tx.Model(&column).Where("version = ?", column.Version).Updates(map[string]interface{}{
+			"position": req.Position,
+			"version":  gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrVersionConflict
+		}
 		column.Position = req.Position
-		return tx.Save(&column).Error
+		column.Version++
+
+		updates, err := repository.RebalanceIfCrowded(tx, repository.ColumnPositionList(column.BoardID), column.ID, req.Position)
+		if err != nil {
+			return err
+		}
+		rebalanced = updates
+		for _, u := range updates {
+			if u.ID == column.ID {
+				column.Position = u.Position
+			}
+		}
+		return nil
 	})
 
+	if errors.Is(err, repository.ErrVersionConflict) {
+		h.respondColumnConflict(c, column.ID)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move column", "details": err.Error()})
 		return
@@ -186,9 +288,11 @@ func (h *ColumnHandler) MoveColumn(c *gin.Context) {
 	if h.Hub != nil {
 		h.Hub.BroadcastToRoom(column.BoardID.String(), "COLUMN_MOVED", map[string]interface{}{
 			"column_id": column.ID.String(),
-			"position":  req.Position,
+			"position":  column.Position,
 		})
+		broadcastRebalance(h.Hub, column.BoardID, repository.ColumnPositionList(column.BoardID), rebalanced)
 	}
 
-	c.Status(http.StatusOK)
+	setVersionETag(c, column.Version)
+	c.JSON(http.StatusOK, column)
 }
@@ -0,0 +1,113 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nexus-backend/internal/handlers"
+	"nexus-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupColumnHandlerDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE columns (
+		id TEXT PRIMARY KEY,
+		board_id TEXT,
+		name TEXT NOT NULL,
+		position REAL NOT NULL,
+		version INTEGER DEFAULT 1,
+		created_at DATETIME,
+		updated_at DATETIME,
+		wip_limit INTEGER,
+		wip_scope TEXT DEFAULT 'cards',
+		wip_mode TEXT DEFAULT 'warn'
+	)`).Error)
+	return db
+}
+
+func TestColumnHandler_UpdateColumn_RequiresCurrentVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupColumnHandlerDB(t)
+
+	columnID := uuid.New()
+	require.NoError(t, db.Create(&models.Column{ID: columnID, BoardID: uuid.New(), Name: "To Do", Position: 16384}).Error)
+
+	handler := handlers.NewColumnHandler(db, nil)
+	router := gin.New()
+	router.PATCH("/columns/:id", handler.UpdateColumn)
+
+	patch := func(ifMatch, name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/columns/"+columnID.String(), strings.NewReader(`{"name":"`+name+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := patch("", "Backlog")
+	require.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+	rec = patch(`"1"`, "Backlog")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	// A second writer still holding version 1 must not overwrite the rename.
+	rec = patch(`"1"`, "Icebox")
+	require.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	require.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	require.Contains(t, rec.Body.String(), "Backlog")
+
+	var stored models.Column
+	require.NoError(t, db.First(&stored, "id = ?", columnID).Error)
+	require.Equal(t, "Backlog", stored.Name)
+	require.Equal(t, 2, stored.Version)
+}
+
+func TestColumnHandler_MoveColumn_RequiresCurrentVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupColumnHandlerDB(t)
+
+	columnID := uuid.New()
+	require.NoError(t, db.Create(&models.Column{ID: columnID, BoardID: uuid.New(), Name: "To Do", Position: 16384}).Error)
+
+	handler := handlers.NewColumnHandler(db, nil)
+	router := gin.New()
+	router.PATCH("/columns/:id/move", handler.MoveColumn)
+
+	move := func(ifMatch string, position int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/columns/"+columnID.String()+"/move", strings.NewReader(fmt.Sprintf(`{"position":%d}`, position)))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusPreconditionRequired, move("", 32768).Code)
+	rec := move(`"1"`, 32768)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	require.Contains(t, rec.Body.String(), `"version":2`)
+	require.Equal(t, http.StatusPreconditionFailed, move(`"1"`, 49152).Code)
+
+	var stored models.Column
+	require.NoError(t, db.First(&stored, "id = ?", columnID).Error)
+	require.Equal(t, float64(32768), stored.Position)
+}
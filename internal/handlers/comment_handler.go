@@ -1,12 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"nexus-backend/internal/middleware"
 	"nexus-backend/internal/models"
 	"nexus-backend/internal/realtime"
 	"nexus-backend/internal/services"
-	"regexp"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,9 +15,6 @@ import (
 	"gorm.io/gorm"
 )
 
-var commentMentionPattern = regexp.MustCompile(`mention:([0-9a-fA-F-]{36})`)
-var commentMentionTextPattern = regexp.MustCompile(`(?:^|\s)@([a-zA-Z0-9._-]{2,50})`)
-
 type CommentHandler struct {
 	Service             *services.CommentService
 	NotificationService *services.NotificationService
@@ -72,112 +70,22 @@ func (h *CommentHandler) notifyWatchersExcept(cardID uuid.UUID, actorID uuid.UUI
 	h.NotificationService.NotifySubscribers(filtered, actorID, models.NotificationMention, title, message, cardID, "CARD", pref)
 }
 
-func extractCommentMentionIDs(content string) []uuid.UUID {
-	matches := commentMentionPattern.FindAllStringSubmatch(content, -1)
-	seen := map[uuid.UUID]struct{}{}
-	out := make([]uuid.UUID, 0, len(matches))
-	for _, m := range matches {
-		if len(m) < 2 {
-			continue
-		}
-		id, err := uuid.Parse(m[1])
-		if err != nil {
-			continue
-		}
-		if _, ok := seen[id]; ok {
-			continue
-		}
-		seen[id] = struct{}{}
-		out = append(out, id)
-	}
-	return out
-}
-
-func extractCommentMentionTokens(content string) []string {
-	matches := commentMentionTextPattern.FindAllStringSubmatch(content, -1)
-	seen := map[string]struct{}{}
-	out := make([]string, 0, len(matches))
-	for _, m := range matches {
-		if len(m) < 2 {
-			continue
-		}
-		token := strings.ToLower(strings.TrimSpace(m[1]))
-		if token == "" {
-			continue
-		}
-		if _, ok := seen[token]; ok {
-			continue
-		}
-		seen[token] = struct{}{}
-		out = append(out, token)
-	}
-	return out
-}
-
-func (h *CommentHandler) notifyCommentMentions(cardID, actorID uuid.UUID, content string) map[uuid.UUID]struct{} {
+// notifyCommentMentions notifies workspace members mentioned in content, except those in skip.
+func (h *CommentHandler) notifyCommentMentions(cardID, actorID uuid.UUID, content string, skip map[uuid.UUID]struct{}) map[uuid.UUID]struct{} {
 	notified := map[uuid.UUID]struct{}{}
-	if h.NotificationService == nil || strings.TrimSpace(content) == "" {
+	if strings.TrimSpace(content) == "" {
 		return notified
 	}
 	var card models.Card
 	if err := h.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
 		return notified
 	}
-	var board models.Board
-	if err := h.DB.Select("id", "workspace_id").First(&board, "id = ?", card.Column.BoardID).Error; err != nil {
-		return notified
-	}
-
-	var workspaceMembers []models.WorkspaceMember
-	if err := h.DB.Where("workspace_id = ? AND status <> ?", board.WorkspaceID, "declined").Find(&workspaceMembers).Error; err != nil {
-		return notified
-	}
-	allowed := map[uuid.UUID]struct{}{}
-	for _, wm := range workspaceMembers {
-		allowed[wm.UserID] = struct{}{}
-	}
-
-	mentionedMap := map[uuid.UUID]struct{}{}
-	for _, id := range extractCommentMentionIDs(content) {
-		mentionedMap[id] = struct{}{}
-	}
-
-	tokens := extractCommentMentionTokens(content)
-	if len(tokens) > 0 {
-		memberIDs := make([]uuid.UUID, 0, len(workspaceMembers))
-		for _, wm := range workspaceMembers {
-			memberIDs = append(memberIDs, wm.UserID)
-		}
-		if len(memberIDs) > 0 {
-			var users []models.User
-			if err := h.DB.Select("id", "name", "username", "email").Where("id IN ?", memberIDs).Find(&users).Error; err == nil {
-				tokenSet := map[string]struct{}{}
-				for _, t := range tokens {
-					tokenSet[t] = struct{}{}
-				}
-				for _, u := range users {
-					candidates := []string{
-						strings.ToLower(strings.TrimSpace(u.Username)),
-						strings.ToLower(strings.ReplaceAll(strings.TrimSpace(u.Name), " ", "")),
-					}
-					if parts := strings.Split(strings.ToLower(strings.TrimSpace(u.Email)), "@"); len(parts) > 0 {
-						candidates = append(candidates, parts[0])
-					}
-					for _, c := range candidates {
-						if c == "" {
-							continue
-						}
-						if _, ok := tokenSet[c]; ok {
-							mentionedMap[u.ID] = struct{}{}
-							break
-						}
-					}
-				}
-			}
-		}
+	mentionedMap := services.ResolveBoardMentions(h.DB, card.Column.BoardID, content)
+	services.RecordMentions(h.DB, cardID, actorID, mentionedMap)
+	for id := range skip {
+		delete(mentionedMap, id)
 	}
-
-	if len(mentionedMap) == 0 {
+	if h.NotificationService == nil || len(mentionedMap) == 0 {
 		return notified
 	}
 
@@ -191,9 +99,6 @@ func (h *CommentHandler) notifyCommentMentions(cardID, actorID uuid.UUID, conten
 		if recipientID == actorID {
 			continue
 		}
-		if _, ok := allowed[recipientID]; !ok {
-			continue
-		}
 		_, _ = h.NotificationService.CreateNotification(
 			recipientID,
 			actorID,
@@ -217,7 +122,8 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	}
 
 	var req struct {
-		Content string `json:"content" binding:"required"`
+		Content  string     `json:"content" binding:"required"`
+		ParentID *uuid.UUID `json:"parent_id"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -227,21 +133,38 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
 	log.Printf("[CommentHandler] Creating comment for card %s", cardID)
-	comment, err := h.Service.CreateComment(cardID, userID, req.Content)
+	comment, err := h.Service.CreateComment(cardID, userID, req.Content, req.ParentID)
+	if errors.Is(err, services.ErrInvalidParentComment) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	}
 	if err != nil {
 		log.Printf("[CommentHandler] Error creating comment: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
 		return
 	}
 
-	// Notify explicit mentions first, then notify watchers except mentioned users.
-	mentionedRecipients := h.notifyCommentMentions(cardID, userID, req.Content)
+	h.afterCommentCreated(cardID, userID, req.Content, req.ParentID)
+	c.JSON(http.StatusCreated, comment)
+}
+
+// afterCommentCreated sends notifications and the board update for a new comment, whether it
+// came from the API or an email reply.
+func (h *CommentHandler) afterCommentCreated(cardID, userID uuid.UUID, content string, parentID *uuid.UUID) {
+	// Notify the author being replied to, then explicit mentions, then remaining watchers.
+	mentionedRecipients := map[uuid.UUID]struct{}{}
+	if parentID != nil {
+		if authorID, ok := h.notifyReplyTarget(*parentID, userID, cardID); ok {
+			mentionedRecipients[authorID] = struct{}{}
+		}
+	}
+	for id := range h.notifyCommentMentions(cardID, userID, content, mentionedRecipients) {
+		mentionedRecipients[id] = struct{}{}
+	}
 	h.notifyWatchersExcept(cardID, userID, "New Comment", "A new comment was added to a card you are watching", services.PrefNotifyComments, mentionedRecipients)
 
 	log.Printf("[CommentHandler] Broadcasting CARD_UPDATED for card %s", cardID)
 	h.broadcastCardUpdate(cardID)
-
-	c.JSON(http.StatusCreated, comment)
 }
 
 func (h *CommentHandler) DeleteComment(c *gin.Context) {
@@ -274,3 +197,154 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	h.broadcastCardUpdate(comment.CardID)
 	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
 }
+
+// notifyReplyTarget tells the author of the comment being replied to about the reply.
+// It reports the author so later notifications can skip them.
+func (h *CommentHandler) notifyReplyTarget(parentID, actorID, cardID uuid.UUID) (uuid.UUID, bool) {
+	if h.NotificationService == nil {
+		return uuid.Nil, false
+	}
+	var parent models.Comment
+	if err := h.DB.Select("id", "user_id").First(&parent, "id = ?", parentID).Error; err != nil || parent.UserID == actorID {
+		return uuid.Nil, false
+	}
+	var card models.Card
+	if err := h.DB.Select("id", "title").First(&card, "id = ?", cardID).Error; err != nil {
+		return uuid.Nil, false
+	}
+
+	actorName := "Someone"
+	var actor models.User
+	if err := h.DB.Select("id", "name").First(&actor, "id = ?", actorID).Error; err == nil && strings.TrimSpace(actor.Name) != "" {
+		actorName = actor.Name
+	}
+
+	_, _ = h.NotificationService.CreateNotification(
+		parent.UserID,
+		actorID,
+		models.NotificationReply,
+		"New reply to your comment",
+		actorName+" replied to your comment on card: "+card.Title,
+		cardID,
+		"CARD",
+		services.PrefNotifyComments,
+	)
+	return parent.UserID, true
+}
+
+// loadAccessibleComment parses :id and checks the caller can see the comment's board.
+func (h *CommentHandler) loadAccessibleComment(c *gin.Context) (*models.Comment, uuid.UUID, uuid.UUID, bool) {
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	comment, boardID, err := h.Service.CommentBoard(commentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, uuid.Nil, uuid.Nil, false
+	}
+	return comment, boardID, userID, true
+}
+
+// UpdateComment edits a comment's content. Only the author may edit; the previous
+// content is kept in the edit history and newly mentioned users are notified.
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	existing, _, userID, ok := h.loadAccessibleComment(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+		return
+	}
+
+	previous := existing.Content
+	comment, err := h.Service.UpdateComment(existing.ID, userID, req.Content)
+	if errors.Is(err, gorm.ErrInvalidData) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own comments"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	if comment.Content != previous {
+		var card models.Card
+		if err := h.DB.Preload("Column").First(&card, "id = ?", comment.CardID).Error; err == nil {
+			already := services.ResolveBoardMentions(h.DB, card.Column.BoardID, previous)
+			h.notifyCommentMentions(comment.CardID, userID, comment.Content, already)
+		}
+		h.broadcastCardUpdate(comment.CardID)
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// GetCommentHistory returns a comment's previous versions, newest first.
+func (h *CommentHandler) GetCommentHistory(c *gin.Context) {
+	comment, _, _, ok := h.loadAccessibleComment(c)
+	if !ok {
+		return
+	}
+	edits, err := h.Service.EditHistory(comment.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch edit history"})
+		return
+	}
+	c.JSON(http.StatusOK, edits)
+}
+
+// ToggleReaction adds or removes the caller's emoji on a comment. Reactions are broadcast
+// to the board but never notify or email anyone.
+func (h *CommentHandler) ToggleReaction(c *gin.Context) {
+	comment, boardID, userID, ok := h.loadAccessibleComment(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Emoji string `json:"emoji" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Emoji is required"})
+		return
+	}
+
+	added, reactions, err := h.Service.ToggleReaction(comment.ID, userID, req.Emoji)
+	if errors.Is(err, services.ErrInvalidEmoji) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reaction"})
+		return
+	}
+
+	payload := gin.H{
+		"comment_id": comment.ID,
+		"card_id":    comment.CardID,
+		"board_id":   boardID,
+		"user_id":    userID,
+		"emoji":      strings.TrimSpace(req.Emoji),
+		"added":      added,
+		"reactions":  reactions,
+	}
+	if h.Hub != nil {
+		h.Hub.BroadcastToRoom(boardID.String(), realtime.MessageTypeCommentReaction, payload)
+	}
+	c.JSON(http.StatusOK, payload)
+}
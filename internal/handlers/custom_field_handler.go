@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"nexus-backend/internal/middleware"
 	"nexus-backend/internal/models"
 	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
 	"nexus-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -39,6 +42,7 @@ func (h *CustomFieldHandler) CreateField(c *gin.Context) {
 		Name    string                 `json:"name" binding:"required"`
 		Type    models.CustomFieldType `json:"type" binding:"required"`
 		Options []string               `json:"options"`
+		Formula string                 `json:"formula"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -46,7 +50,16 @@ func (h *CustomFieldHandler) CreateField(c *gin.Context) {
 		return
 	}
 
-	field, err := h.Service.CreateField(boardID, req.Name, req.Type, req.Options)
+	var field *models.CustomField
+	if req.Type == models.FieldTypeFormula {
+		field, err = h.Service.CreateFormulaField(boardID, req.Name, req.Formula)
+	} else {
+		field, err = h.Service.CreateField(boardID, req.Name, req.Type, req.Options)
+	}
+	if errors.Is(err, services.ErrInvalidCustomField) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	}
 	if err != nil {
 		fmt.Printf("[CustomFieldHandler] CreateField error: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create field: " + err.Error()})
@@ -106,6 +119,99 @@ func (h *CustomFieldHandler) DeleteField(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// fieldAccess loads :id and checks the caller can use its board, writing the error response on failure.
+func (h *CustomFieldHandler) fieldAccess(c *gin.Context) (*models.CustomField, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field ID"})
+		return nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	field, err := h.Service.Repo.GetFieldByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Field not found"})
+		return nil, false
+	}
+	if !h.Service.CanAccessBoard(userID, field.BoardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+	return field, true
+}
+
+// UpdateField: PATCH /fields/:id
+// Renames the field, edits its options or formula or changes its type. Existing card values are
+// migrated; values that cannot be converted are cleared and listed in "failures".
+func (h *CustomFieldHandler) UpdateField(c *gin.Context) {
+	field, ok := h.fieldAccess(c)
+	if !ok {
+		return
+	}
+
+	var req services.FieldUpdateInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	result, err := h.Service.UpdateField(field, req)
+	if errors.Is(err, services.ErrInvalidCustomField) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	}
+	if errors.Is(err, services.ErrSharedDefinition) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "SHARED_DEFINITION"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update field"})
+		return
+	}
+
+	if h.Hub != nil && !result.DryRun {
+		h.Hub.BroadcastToRoom(field.BoardID.String(), "BOARD_UPDATED", map[string]interface{}{
+			"board_id": field.BoardID.String(),
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// MoveField: PATCH /fields/:id/move
+func (h *CustomFieldHandler) MoveField(c *gin.Context) {
+	field, ok := h.fieldAccess(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Position float64 `json:"position" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Position is required"})
+		return
+	}
+
+	rebalanced, err := h.Service.MoveField(field, req.Position)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move field"})
+		return
+	}
+
+	if h.Hub != nil {
+		broadcastRebalance(h.Hub, field.BoardID, repository.CustomFieldPositionList(field.BoardID), rebalanced)
+		h.Hub.BroadcastToRoom(field.BoardID.String(), "BOARD_UPDATED", map[string]interface{}{
+			"board_id": field.BoardID.String(),
+		})
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
 // SetValue: POST /cards/:id/fields/:field_id
 func (h *CustomFieldHandler) SetValue(c *gin.Context) {
 	cardID, err := uuid.Parse(c.Param("id"))
@@ -129,7 +235,13 @@ func (h *CustomFieldHandler) SetValue(c *gin.Context) {
 		return
 	}
 
-	val, err := h.Service.SetCardValue(cardID, fieldID, req.Value)
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	val, err := h.Service.SetCardValue(cardID, fieldID, req.Value, userID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
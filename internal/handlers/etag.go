@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag formats a record version as a strong entity tag.
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func setVersionETag(c *gin.Context, version int) {
+	c.Header("ETag", versionETag(version))
+}
+
+// requireIfMatch reads the version the client last saw from the If-Match header.
+// "*" matches any version and is returned as 0. When the header is missing or
+// malformed a 428/400 response is written and ok is false.
+func requireIfMatch(c *gin.Context) (version int, ok bool) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error": "If-Match header with the current ETag is required",
+			"code":  "PRECONDITION_REQUIRED",
+		})
+		return 0, false
+	}
+	if raw == "*" {
+		return 0, true
+	}
+
+	// Only a single tag is meaningful for a versioned record; take the first.
+	if idx := strings.Index(raw, ","); idx >= 0 {
+		raw = strings.TrimSpace(raw[:idx])
+	}
+	raw = strings.TrimPrefix(raw, "W/")
+	raw = strings.Trim(raw, `"`)
+
+	version, err := strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid If-Match header",
+			"code":  "VALIDATION_ERROR",
+		})
+		return 0, false
+	}
+	return version, true
+}
+
+// respondVersionConflict answers a stale write with 412 and the record's current state.
+func respondVersionConflict(c *gin.Context, version int, current interface{}) {
+	setVersionETag(c, version)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "Resource was modified by someone else",
+		"code":    "VERSION_CONFLICT",
+		"current": current,
+	})
+}
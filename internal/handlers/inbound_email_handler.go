@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// InboundEmailHandler receives mail from a provider's raw-MIME webhook or the embedded SMTP
+// listener. Mail to a board inbox becomes a card; replies to notifications become comments.
+type InboundEmailHandler struct {
+	Service  *services.InboundEmailService
+	Comments *CommentHandler
+	Boards   *BoardInboxHandler
+	// WebhookSecret must be sent as X-Inbound-Secret; the webhook is closed while it is empty.
+	WebhookSecret string
+}
+
+func NewInboundEmailHandler(service *services.InboundEmailService, comments *CommentHandler, boards *BoardInboxHandler, webhookSecret string) *InboundEmailHandler {
+	return &InboundEmailHandler{Service: service, Comments: comments, Boards: boards, WebhookSecret: webhookSecret}
+}
+
+// Deliver processes one raw message and runs the usual card or comment notifications.
+// It matches the InboundSMTPServer delivery callback.
+func (h *InboundEmailHandler) Deliver(from string, to []string, raw []byte) error {
+	if h.Boards != nil {
+		err := h.Boards.Deliver(from, to, raw)
+		if !errors.Is(err, services.ErrNoBoardInbox) {
+			if err != nil {
+				log.Printf("[InboundEmail] rejected board mail from %s: %v", from, err)
+			}
+			return err
+		}
+		if h.Service.Signer == nil {
+			return err
+		}
+	}
+
+	comment, err := h.Service.ProcessMessage(raw, to)
+	if err != nil {
+		log.Printf("[InboundEmail] rejected message from %s: %v", from, err)
+		return err
+	}
+	h.Comments.afterCommentCreated(comment.CardID, comment.UserID, comment.Content, nil)
+	return nil
+}
+
+// ReceiveEmail accepts a raw MIME message as the request body (POST /inbound/email).
+// Envelope recipients may be passed as repeated "recipient" query parameters.
+func (h *InboundEmailHandler) ReceiveEmail(c *gin.Context) {
+	provided := c.GetHeader("X-Inbound-Secret")
+	if h.WebhookSecret == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(h.WebhookSecret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid inbound secret"})
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, services.MaxInboundEmailBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read message"})
+		return
+	}
+	if len(raw) > services.MaxInboundEmailBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrInboundEmailTooLarge.Error()})
+		return
+	}
+
+	recipients := c.QueryArray("recipient")
+	if err := h.Deliver(c.Query("sender"), recipients, raw); err != nil {
+		respondInboundEmailError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "accepted"})
+}
+
+// respondInboundEmailError answers rejected mail with 422 so providers don't retry it;
+// only unexpected failures return 5xx.
+func respondInboundEmailError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInboundAutoReply), errors.Is(err, services.ErrDuplicateInboundEmail):
+		c.JSON(http.StatusAccepted, gin.H{"status": "ignored", "reason": err.Error()})
+	case errors.Is(err, services.ErrInboundEmailTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInboundEmailDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidReplyToken),
+		errors.Is(err, services.ErrInboundSenderMismatch),
+		errors.Is(err, services.ErrBoardAccessDenied),
+		errors.Is(err, services.ErrEmptyEmailReply),
+		errors.Is(err, services.ErrMalformedEmail),
+		errors.Is(err, services.ErrNoBoardInbox),
+		errors.Is(err, services.ErrBoardInboxDisabled),
+		errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": strings.TrimSpace(err.Error()), "code": "EMAIL_REJECTED"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process email"})
+	}
+}
@@ -87,6 +87,10 @@ func (h *LabelHandler) UpdateLabel(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
 		return
 	}
+	if label.WorkspaceLabelID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "shared definitions are edited on the workspace", "code": "SHARED_DEFINITION"})
+		return
+	}
 
 	var req struct {
 		Name  string `json:"name"`
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// broadcastRebalance tells board clients that a list was renumbered so they can
+// replace their cached positions. It is a no-op when nothing was renumbered.
+func broadcastRebalance(hub *realtime.Hub, boardID uuid.UUID, list repository.PositionList, updates []repository.PositionUpdate) {
+	if hub == nil || len(updates) == 0 {
+		return
+	}
+	hub.BroadcastToRoom(boardID.String(), realtime.MessageTypePositionsRebalanced, map[string]interface{}{
+		"board_id":  boardID.String(),
+		"list":      list.Name,
+		"scope_id":  list.ScopeID.String(),
+		"positions": updates,
+	})
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SavedFilterHandler serves named card queries and the caller's My Work dashboard.
+type SavedFilterHandler struct {
+	Service *services.SavedFilterService
+	MyWork  *services.MyWorkService
+}
+
+func NewSavedFilterHandler(service *services.SavedFilterService, myWork *services.MyWorkService) *SavedFilterHandler {
+	return &SavedFilterHandler{Service: service, MyWork: myWork}
+}
+
+func respondSavedFilterError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Filter not found", "code": "NOT_FOUND"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrNotFilterOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSavedFilter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		respondCardQueryError(c, err, fallback)
+	}
+}
+
+type savedFilterRequest struct {
+	Name   *string `json:"name"`
+	Query  *string `json:"query"`
+	Shared *bool   `json:"shared"`
+}
+
+func (r savedFilterRequest) input() services.SavedFilterInput {
+	return services.SavedFilterInput{Name: r.Name, Query: r.Query, Shared: r.Shared}
+}
+
+// ListWorkspaceFilters: GET /workspaces/:id/filters
+// Returns the caller's own filters and those shared with the workspace, board filters included.
+func (h *SavedFilterHandler) ListWorkspaceFilters(c *gin.Context) {
+	workspaceID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	if !h.Service.CanAccessWorkspace(userID, workspaceID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+	filters, err := h.Service.ListWorkspaceFilters(workspaceID, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch filters")
+		return
+	}
+	c.JSON(http.StatusOK, filters)
+}
+
+// CreateWorkspaceFilter: POST /workspaces/:id/filters
+func (h *SavedFilterHandler) CreateWorkspaceFilter(c *gin.Context) {
+	workspaceID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	if !h.Service.CanAccessWorkspace(userID, workspaceID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+	h.create(c, func(in services.SavedFilterInput) (*models.SavedFilter, error) {
+		return h.Service.CreateFilter(userID, workspaceID, nil, in)
+	})
+}
+
+// ListBoardFilters: GET /boards/:id/filters
+// Returns the board's filters followed by the workspace-wide ones the caller can see.
+func (h *SavedFilterHandler) ListBoardFilters(c *gin.Context) {
+	boardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	board, err := h.Service.GetBoard(boardID, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch board")
+		return
+	}
+	filters, err := h.Service.ListBoardFilters(board, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch filters")
+		return
+	}
+	c.JSON(http.StatusOK, filters)
+}
+
+// CreateBoardFilter: POST /boards/:id/filters
+func (h *SavedFilterHandler) CreateBoardFilter(c *gin.Context) {
+	boardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	board, err := h.Service.GetBoard(boardID, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch board")
+		return
+	}
+	h.create(c, func(in services.SavedFilterInput) (*models.SavedFilter, error) {
+		return h.Service.CreateFilter(userID, board.WorkspaceID, &board.ID, in)
+	})
+}
+
+func (h *SavedFilterHandler) create(c *gin.Context, save func(services.SavedFilterInput) (*models.SavedFilter, error)) {
+	var req savedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	filter, err := save(req.input())
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to create filter")
+		return
+	}
+	c.JSON(http.StatusCreated, filter)
+}
+
+// GetFilter: GET /filters/:id
+func (h *SavedFilterHandler) GetFilter(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	filter, err := h.Service.GetFilter(id, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch filter")
+		return
+	}
+	c.JSON(http.StatusOK, filter)
+}
+
+// UpdateFilter: PATCH /filters/:id
+func (h *SavedFilterHandler) UpdateFilter(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	filter, err := h.Service.GetFilter(id, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch filter")
+		return
+	}
+	var req savedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	if err := h.Service.UpdateFilter(filter, userID, req.input()); err != nil {
+		respondSavedFilterError(c, err, "Failed to update filter")
+		return
+	}
+	c.JSON(http.StatusOK, filter)
+}
+
+// DeleteFilter: DELETE /filters/:id
+func (h *SavedFilterHandler) DeleteFilter(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	filter, err := h.Service.GetFilter(id, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch filter")
+		return
+	}
+	if err := h.Service.DeleteFilter(filter, userID); err != nil {
+		respondSavedFilterError(c, err, "Failed to delete filter")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Filter deleted"})
+}
+
+// RunFilter: GET /filters/:id/cards?sort=&order=&cursor=&limit=
+// Pages through the cards matching the filter within its board or workspace.
+func (h *SavedFilterHandler) RunFilter(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	filter, err := h.Service.GetFilter(id, userID)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to fetch filter")
+		return
+	}
+	page := services.CardQueryPage{Sort: c.Query("sort"), Order: c.Query("order"), Cursor: c.Query("cursor")}
+	if raw := c.Query("limit"); raw != "" {
+		if page.Limit, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit", "code": "VALIDATION_ERROR"})
+			return
+		}
+	}
+	result, err := h.Service.RunFilter(filter, userID, page)
+	if err != nil {
+		respondSavedFilterError(c, err, "Failed to query cards")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMyWork: GET /users/me/work?tz=
+// Buckets the caller's open cards and checklist items by due date; tz is an IANA zone
+// deciding where "today" ends and defaults to UTC.
+func (h *SavedFilterHandler) GetMyWork(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone", "code": "VALIDATION_ERROR"})
+			return
+		}
+	}
+	work, err := h.MyWork.MyWork(userID, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch work"})
+		return
+	}
+	c.JSON(http.StatusOK, work)
+}
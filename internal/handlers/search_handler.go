@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SearchHandler serves full-text search over the boards a user can access.
+type SearchHandler struct {
+	Service *services.SearchService
+}
+
+func NewSearchHandler(service *services.SearchService) *SearchHandler {
+	return &SearchHandler{Service: service}
+}
+
+// Search: GET /search?q=&board_id=&type=&include_archived=&limit=
+// type is a comma-separated list of card, comment, checklist_item and attachment.
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	opts := services.SearchOptions{Query: c.Query("q")}
+	if raw := c.Query("board_id"); raw != "" {
+		boardID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+			return
+		}
+		opts.BoardID = &boardID
+	}
+	if raw := c.Query("type"); raw != "" {
+		for _, kind := range strings.Split(raw, ",") {
+			opts.Kinds = append(opts.Kinds, services.SearchKind(strings.TrimSpace(kind)))
+		}
+	}
+	opts.IncludeArchived, _ = strconv.ParseBool(c.Query("include_archived"))
+	if raw := c.Query("limit"); raw != "" {
+		if opts.Limit, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit", "code": "VALIDATION_ERROR"})
+			return
+		}
+	}
+
+	hits, err := h.Service.Search(userID, opts)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"query": opts.Query, "results": hits})
+}
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SprintHandler struct {
+	Service *services.SprintService
+	Hub     *realtime.Hub
+}
+
+func NewSprintHandler(service *services.SprintService, hub *realtime.Hub) *SprintHandler {
+	return &SprintHandler{Service: service, Hub: hub}
+}
+
+// respondSprintError maps sprint service errors to HTTP responses.
+func respondSprintError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrSprintAlreadyActive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "SPRINT_ALREADY_ACTIVE"})
+	case errors.Is(err, services.ErrSprintNotPlanned),
+		errors.Is(err, services.ErrSprintNotActive),
+		errors.Is(err, services.ErrSprintClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "INVALID_SPRINT_STATE"})
+	case errors.Is(err, services.ErrSprintInvalidDates),
+		errors.Is(err, services.ErrSprintDatesImmutable),
+		errors.Is(err, services.ErrSprintWrongBoard),
+		errors.Is(err, services.ErrInvalidCarryOver):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func (h *SprintHandler) broadcast(boardID uuid.UUID, sprintID uuid.UUID, action string) {
+	if h.Hub == nil {
+		return
+	}
+	h.Hub.BroadcastToRoom(boardID.String(), realtime.MessageTypeSprintUpdated, map[string]interface{}{
+		"board_id":  boardID.String(),
+		"sprint_id": sprintID.String(),
+		"action":    action,
+	})
+}
+
+// loadSprint parses :id and loads a sprint the caller can access, writing the error response on failure.
+func (h *SprintHandler) loadSprint(c *gin.Context) (*models.Sprint, uuid.UUID, bool) {
+	sprintID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return nil, uuid.Nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, uuid.Nil, false
+	}
+	sprint, err := h.Service.GetSprintForUser(sprintID, userID)
+	if err != nil {
+		respondSprintError(c, err, "Failed to load sprint")
+		return nil, uuid.Nil, false
+	}
+	return sprint, userID, true
+}
+
+func (h *SprintHandler) ListSprints(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	sprints, err := h.Service.ListSprints(boardID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sprints"})
+		return
+	}
+	c.JSON(http.StatusOK, sprints)
+}
+
+type CreateSprintRequest struct {
+	Name      string    `json:"name" binding:"required,max=100"`
+	Goal      string    `json:"goal"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"`
+}
+
+func (h *SprintHandler) CreateSprint(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	var req CreateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	sprint, err := h.Service.CreateSprint(boardID, userID, req.Name, req.Goal, req.StartDate, req.EndDate)
+	if err != nil {
+		respondSprintError(c, err, "Failed to create sprint")
+		return
+	}
+	h.broadcast(boardID, sprint.ID, "created")
+	c.JSON(http.StatusCreated, sprint)
+}
+
+// GetSprint returns the sprint with its cards.
+func (h *SprintHandler) GetSprint(c *gin.Context) {
+	sprint, _, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+	full, err := h.Service.GetSprintWithCards(sprint.ID)
+	if err != nil {
+		respondSprintError(c, err, "Failed to load sprint")
+		return
+	}
+	c.JSON(http.StatusOK, full)
+}
+
+type UpdateSprintRequest struct {
+	Name      *string    `json:"name" binding:"omitempty,max=100"`
+	Goal      *string    `json:"goal"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+func (h *SprintHandler) UpdateSprint(c *gin.Context) {
+	sprint, _, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	if err := h.Service.UpdateSprint(sprint, req.Name, req.Goal, req.StartDate, req.EndDate); err != nil {
+		respondSprintError(c, err, "Failed to update sprint")
+		return
+	}
+	h.broadcast(sprint.BoardID, sprint.ID, "updated")
+	c.JSON(http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) DeleteSprint(c *gin.Context) {
+	sprint, _, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteSprint(sprint); err != nil {
+		respondSprintError(c, err, "Failed to delete sprint")
+		return
+	}
+	h.broadcast(sprint.BoardID, sprint.ID, "deleted")
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SprintHandler) StartSprint(c *gin.Context) {
+	sprint, userID, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.StartSprint(sprint, userID); err != nil {
+		respondSprintError(c, err, "Failed to start sprint")
+		return
+	}
+	h.broadcast(sprint.BoardID, sprint.ID, "started")
+	c.JSON(http.StatusOK, sprint)
+}
+
+type CloseSprintRequest struct {
+	CarryOver       string     `json:"carry_over"` // next_sprint, backlog or none (default)
+	NextSprintID    *uuid.UUID `json:"next_sprint_id"`
+	BacklogColumnID *uuid.UUID `json:"backlog_column_id"`
+}
+
+// CloseSprint closes an active sprint. Unfinished cards move to the next sprint,
+// to a backlog column, or stay with the closed sprint.
+func (h *SprintHandler) CloseSprint(c *gin.Context) {
+	sprint, userID, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+
+	var req CloseSprintRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "code": "VALIDATION_ERROR"})
+			return
+		}
+	}
+
+	result, err := h.Service.CloseSprint(sprint, userID, services.CloseSprintOptions{
+		CarryOver:       req.CarryOver,
+		NextSprintID:    req.NextSprintID,
+		BacklogColumnID: req.BacklogColumnID,
+	})
+	if err != nil {
+		respondSprintError(c, err, "Failed to close sprint")
+		return
+	}
+
+	h.broadcast(sprint.BoardID, sprint.ID, "closed")
+	if h.Hub != nil && len(result.CarriedOver) > 0 {
+		h.Hub.BroadcastToRoom(sprint.BoardID.String(), realtime.MessageTypeCardsBulkUpdated, map[string]interface{}{
+			"board_id":  sprint.BoardID.String(),
+			"operation": "sprint_carry_over",
+			"card_ids":  result.CarriedOver,
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+type AssignCardSprintRequest struct {
+	SprintID *uuid.UUID `json:"sprint_id"` // null removes the card from its sprint
+}
+
+// AssignCard puts a card into a sprint or takes it out.
+func (h *SprintHandler) AssignCard(c *gin.Context) {
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid card ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req AssignCardSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	card, err := h.Service.AssignCard(cardID, req.SprintID, userID)
+	if err != nil {
+		respondSprintError(c, err, "Failed to assign sprint")
+		return
+	}
+
+	if h.Hub != nil {
+		h.Hub.BroadcastToRoom(card.Column.BoardID.String(), realtime.MessageTypeCardUpdated, map[string]interface{}{
+			"card_id":   card.ID.String(),
+			"board_id":  card.Column.BoardID.String(),
+			"sprint_id": card.SprintID,
+		})
+	}
+	setVersionETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// GetReport returns committed vs completed totals, carry-over and the scope change log.
+func (h *SprintHandler) GetReport(c *gin.Context) {
+	sprint, _, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+	report, err := h.Service.Report(sprint)
+	if err != nil {
+		respondSprintError(c, err, "Failed to build sprint report")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetBurndown returns daily scope and remaining work from sprint start to end (or today).
+func (h *SprintHandler) GetBurndown(c *gin.Context) {
+	sprint, _, ok := h.loadSprint(c)
+	if !ok {
+		return
+	}
+	points, err := h.Service.Burndown(sprint)
+	if err != nil {
+		respondSprintError(c, err, "Failed to build burndown")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"sprint_id":  sprint.ID,
+		"start_date": sprint.StartDate,
+		"end_date":   sprint.EndDate,
+		"points":     points,
+	})
+}
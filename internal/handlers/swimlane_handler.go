@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SwimlaneHandler serves a board's lanes and its explicit swimlanes.
+type SwimlaneHandler struct {
+	Service *services.SwimlaneService
+	Hub     *realtime.Hub
+}
+
+func NewSwimlaneHandler(service *services.SwimlaneService, hub *realtime.Hub) *SwimlaneHandler {
+	return &SwimlaneHandler{Service: service, Hub: hub}
+}
+
+func respondSwimlaneError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Swimlane not found", "code": "NOT_FOUND"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrInvalidSwimlane):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func (h *SwimlaneHandler) broadcastBoard(boardID uuid.UUID) {
+	if h.Hub == nil {
+		return
+	}
+	h.Hub.BroadcastToRoom(boardID.String(), "BOARD_UPDATED", map[string]interface{}{
+		"board_id": boardID.String(),
+	})
+}
+
+// ListLanes: GET /boards/:id/swimlanes
+// Returns the board's swimlane mode, its lanes in display order and, in explicit mode, the
+// swimlanes themselves.
+func (h *SwimlaneHandler) ListLanes(c *gin.Context) {
+	boardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	board, err := h.Service.GetBoard(boardID, userID)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to fetch board")
+		return
+	}
+	lanes, err := h.Service.Lanes(board)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to fetch lanes")
+		return
+	}
+	swimlanes, err := h.Service.ListSwimlanes(board.ID)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to fetch swimlanes")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"mode":      board.SwimlaneMode,
+		"field_id":  board.SwimlaneFieldID,
+		"lanes":     lanes,
+		"swimlanes": swimlanes,
+	})
+}
+
+// CreateSwimlane: POST /boards/:id/swimlanes
+func (h *SwimlaneHandler) CreateSwimlane(c *gin.Context) {
+	boardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	if _, err := h.Service.GetBoard(boardID, userID); err != nil {
+		respondSwimlaneError(c, err, "Failed to fetch board")
+		return
+	}
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required", "code": "VALIDATION_ERROR"})
+		return
+	}
+	lane, err := h.Service.CreateSwimlane(boardID, req.Name)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to create swimlane")
+		return
+	}
+	h.broadcastBoard(boardID)
+	c.JSON(http.StatusCreated, lane)
+}
+
+// UpdateSwimlane: PATCH /swimlanes/:id
+func (h *SwimlaneHandler) UpdateSwimlane(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	lane, err := h.Service.GetSwimlane(id, userID)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to fetch swimlane")
+		return
+	}
+	var req struct {
+		Name     *string  `json:"name"`
+		Position *float64 `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	rebalanced, err := h.Service.UpdateSwimlane(lane, req.Name, req.Position)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to update swimlane")
+		return
+	}
+	h.broadcastBoard(lane.BoardID)
+	broadcastRebalance(h.Hub, lane.BoardID, repository.SwimlanePositionList(lane.BoardID), rebalanced)
+	c.JSON(http.StatusOK, lane)
+}
+
+// DeleteSwimlane: DELETE /swimlanes/:id
+// The lane's cards move to the catch-all lane.
+func (h *SwimlaneHandler) DeleteSwimlane(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	lane, err := h.Service.GetSwimlane(id, userID)
+	if err != nil {
+		respondSwimlaneError(c, err, "Failed to fetch swimlane")
+		return
+	}
+	if err := h.Service.DeleteSwimlane(lane); err != nil {
+		respondSwimlaneError(c, err, "Failed to delete swimlane")
+		return
+	}
+	h.broadcastBoard(lane.BoardID)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TimeTrackingHandler struct {
+	Service *services.TimeTrackingService
+	Hub     *realtime.Hub
+}
+
+func NewTimeTrackingHandler(service *services.TimeTrackingService, hub *realtime.Hub) *TimeTrackingHandler {
+	return &TimeTrackingHandler{Service: service, Hub: hub}
+}
+
+// respondTimeTrackingError maps service errors to HTTP responses.
+func respondTimeTrackingError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrTimeLogNotAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author can change this time log"})
+	case errors.Is(err, services.ErrInvalidDuration), errors.Is(err, services.ErrInvalidEstimate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func (h *TimeTrackingHandler) broadcastCardTime(boardID, cardID uuid.UUID) {
+	if h.Hub == nil {
+		return
+	}
+	h.Hub.BroadcastToRoom(boardID.String(), "CARD_UPDATED", map[string]interface{}{
+		"card_id":  cardID.String(),
+		"board_id": boardID.String(),
+	})
+}
+
+type SetEstimateRequest struct {
+	EstimateMinutes *int     `json:"estimate_minutes"`
+	StoryPoints     *float64 `json:"story_points"`
+}
+
+// SetEstimate replaces a card's estimate. Omitted or null fields are cleared.
+func (h *TimeTrackingHandler) SetEstimate(c *gin.Context) {
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid card ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req SetEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	card, err := h.Service.SetEstimate(cardID, userID, req.EstimateMinutes, req.StoryPoints)
+	if err != nil {
+		respondTimeTrackingError(c, err, "Failed to update estimate")
+		return
+	}
+	h.broadcastCardTime(card.Column.BoardID, card.ID)
+
+	summary, err := h.Service.GetCardSummary(cardID, userID)
+	if err != nil {
+		respondTimeTrackingError(c, err, "Failed to load time summary")
+		return
+	}
+	setVersionETag(c, card.Version)
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetCardTime returns the card's estimate, logged and remaining time with its logs.
+func (h *TimeTrackingHandler) GetCardTime(c *gin.Context) {
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid card ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	summary, err := h.Service.GetCardSummary(cardID, userID)
+	if err != nil {
+		respondTimeTrackingError(c, err, "Failed to load time summary")
+		return
+	}
+	logs, err := h.Service.ListTimeLogs(cardID, userID)
+	if err != nil {
+		respondTimeTrackingError(c, err, "Failed to load time logs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary":   summary,
+		"time_logs": logs,
+	})
+}
+
+type TimeLogRequest struct {
+	DurationMinutes *int    `json:"duration_minutes"`
+	LoggedOn        *string `json:"logged_on"` // YYYY-MM-DD, defaults to today
+	Note            *string `json:"note"`
+}
+
+func (r TimeLogRequest) loggedOn() (*time.Time, error) {
+	if r.LoggedOn == nil || *r.LoggedOn == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(services.TimeLogDateLayout, *r.LoggedOn)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (h *TimeTrackingHandler) CreateTimeLog(c *gin.Context) {
+	cardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid card ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TimeLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.DurationMinutes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "duration_minutes is required", "code": "VALIDATION_ERROR"})
+		return
+	}
+	loggedOn, err := req.loggedOn()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid logged_on date, expected YYYY-MM-DD", "code": "VALIDATION_ERROR"})
+		return
+	}
+	day := time.Now()
+	if loggedOn != nil {
+		day = *loggedOn
+	}
+	note := ""
+	if req.Note != nil {
+		note = *req.Note
+	}
+
+	log, err := h.Service.LogTime(cardID, userID, *req.DurationMinutes, day, note)
+	if err != nil {
+		respondTimeTrackingError(c, err, "Failed to log time")
+		return
+	}
+	if boardID, ok := h.cardBoardID(cardID); ok {
+		h.broadcastCardTime(boardID, cardID)
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func (h *TimeTrackingHandler) UpdateTimeLog(c *gin.Context) {
+	logID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time log ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TimeLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	loggedOn, err := req.loggedOn()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid logged_on date, expected YYYY-MM-DD", "code": "VALIDATION_ERROR"})
+		return
+	}
+
+	log, err := h.Service.UpdateTimeLog(logID, userID, req.DurationMinutes, loggedOn, req.Note)
+	if err != nil {
+		respondTimeTrackingError(c, err, "Failed to update time log")
+		return
+	}
+	if boardID, ok := h.cardBoardID(log.CardID); ok {
+		h.broadcastCardTime(boardID, log.CardID)
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+func (h *TimeTrackingHandler) DeleteTimeLog(c *gin.Context) {
+	logID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time log ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.Service.DeleteTimeLog(logID, userID); err != nil {
+		respondTimeTrackingError(c, err, "Failed to delete time log")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExportTimeLogs streams a board's time logs as a timesheet CSV.
+// Accepts the same from/to/user_id filters as the time analytics endpoint.
+func (h *TimeTrackingHandler) ExportTimeLogs(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.Service.CanAccessBoard(userID, boardID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+	filter, ok := parseTimeLogFilter(c)
+	if !ok {
+		return
+	}
+
+	rows, err := h.Service.BoardTimeLogs(boardID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time logs"})
+		return
+	}
+
+	filename := fmt.Sprintf("timesheet-%s-%s.csv", boardID.String()[:8], time.Now().Format(services.TimeLogDateLayout))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"date", "user", "email", "card", "column", "minutes", "hours", "note"})
+	for _, row := range rows {
+		_ = w.Write([]string{
+			row.LoggedOn.Format(services.TimeLogDateLayout),
+			csvSafe(row.UserName),
+			csvSafe(row.UserEmail),
+			csvSafe(row.CardTitle),
+			csvSafe(row.ColumnName),
+			strconv.Itoa(row.DurationMinutes),
+			strconv.FormatFloat(float64(row.DurationMinutes)/60, 'f', 2, 64),
+			csvSafe(row.Note),
+		})
+	}
+	w.Flush()
+}
+
+func (h *TimeTrackingHandler) cardBoardID(cardID uuid.UUID) (uuid.UUID, bool) {
+	var boardID uuid.UUID
+	err := h.Service.DB.Table("cards").
+		Select("columns.board_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Where("cards.id = ?", cardID).
+		Scan(&boardID).Error
+	return boardID, err == nil && boardID != uuid.Nil
+}
+
+// csvSafe stops spreadsheet apps from evaluating user text as a formula.
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimelineHandler serves board and saved-filter timelines, card dependencies and rescheduling.
+type TimelineHandler struct {
+	Service *services.TimelineService
+	Filters *services.SavedFilterService
+	Hub     *realtime.Hub
+}
+
+func NewTimelineHandler(service *services.TimelineService, filters *services.SavedFilterService, hub *realtime.Hub) *TimelineHandler {
+	return &TimelineHandler{Service: service, Filters: filters, Hub: hub}
+}
+
+func respondTimelineError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found", "code": "NOT_FOUND"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrInvalidTimeline),
+		errors.Is(err, services.ErrInvalidDependency),
+		errors.Is(err, services.ErrStartAfterDue):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		respondCardQueryError(c, err, fallback)
+	}
+}
+
+func (h *TimelineHandler) broadcastCards(cards ...services.TimelineCard) {
+	if h.Hub == nil {
+		return
+	}
+	for _, card := range cards {
+		h.Hub.BroadcastToRoom(card.BoardID.String(), "CARD_UPDATED", map[string]interface{}{
+			"card_id":  card.ID.String(),
+			"board_id": card.BoardID.String(),
+		})
+	}
+}
+
+// timelineOptions reads from, to (YYYY-MM-DD or RFC 3339) and group_by.
+func timelineOptions(c *gin.Context) (services.TimelineOptions, bool) {
+	opts := services.TimelineOptions{GroupBy: c.Query("group_by")}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &opts.From}, {"to", &opts.To}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			t, err = time.Parse(time.RFC3339, raw)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name + " date", "code": "VALIDATION_ERROR"})
+			return opts, false
+		}
+		*p.dst = t
+	}
+	return opts, true
+}
+
+// BoardTimeline: GET /boards/:id/timeline?from=&to=&group_by=
+func (h *TimelineHandler) BoardTimeline(c *gin.Context) {
+	boardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	opts, ok := timelineOptions(c)
+	if !ok {
+		return
+	}
+	board, err := h.Service.GetBoard(boardID, userID)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to fetch board")
+		return
+	}
+	timeline, err := h.Service.BoardTimeline(board, userID, opts)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to build timeline")
+		return
+	}
+	c.JSON(http.StatusOK, timeline)
+}
+
+// FilterTimeline: GET /filters/:id/timeline?from=&to=&group_by=
+func (h *TimelineHandler) FilterTimeline(c *gin.Context) {
+	filterID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	opts, ok := timelineOptions(c)
+	if !ok {
+		return
+	}
+	filter, err := h.Filters.GetFilter(filterID, userID)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to fetch filter")
+		return
+	}
+	timeline, err := h.Service.FilterTimeline(filter, userID, opts)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to build timeline")
+		return
+	}
+	c.JSON(http.StatusOK, timeline)
+}
+
+// Reschedule: PUT /cards/:id/schedule
+// Replaces the card's start and due dates (omitted or null clears one); cascade shifts the
+// cards depending on it by as much as its end moved.
+func (h *TimelineHandler) Reschedule(c *gin.Context) {
+	cardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		StartDate *time.Time `json:"start_date"`
+		DueDate   *time.Time `json:"due_date"`
+		Cascade   bool       `json:"cascade"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "code": "VALIDATION_ERROR"})
+		return
+	}
+	card, err := h.Service.CardForUser(cardID, userID)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to fetch card")
+		return
+	}
+	result, err := h.Service.Reschedule(card, userID, req.StartDate, req.DueDate, req.Cascade, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) && result != nil {
+		respondVersionConflict(c, result.Card.Version, result.Card)
+		return
+	}
+	if err != nil {
+		respondTimelineError(c, err, "Failed to reschedule card")
+		return
+	}
+	h.broadcastCards(append([]services.TimelineCard{result.Card}, result.Shifted...)...)
+	setVersionETag(c, result.Card.Version)
+	c.JSON(http.StatusOK, result)
+}
+
+// AddDependency: POST /cards/:id/dependencies
+// Records that the card depends on depends_on_id finishing first.
+func (h *TimelineHandler) AddDependency(c *gin.Context) {
+	cardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		DependsOnID uuid.UUID `json:"depends_on_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "depends_on_id is required", "code": "VALIDATION_ERROR"})
+		return
+	}
+	card, err := h.Service.CardForUser(cardID, userID)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to fetch card")
+		return
+	}
+	dep, err := h.Service.AddDependency(card, req.DependsOnID, userID)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to add dependency")
+		return
+	}
+	h.broadcastCards(services.TimelineCard{ID: card.ID, BoardID: card.Column.BoardID})
+	c.JSON(http.StatusCreated, dep)
+}
+
+// RemoveDependency: DELETE /cards/:id/dependencies/:dependsOnId
+func (h *TimelineHandler) RemoveDependency(c *gin.Context) {
+	cardID, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	dependsOnID, err := uuid.Parse(c.Param("dependsOnId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	card, err := h.Service.CardForUser(cardID, userID)
+	if err != nil {
+		respondTimelineError(c, err, "Failed to fetch card")
+		return
+	}
+	if err := h.Service.RemoveDependency(card, dependsOnID, userID); err != nil {
+		respondTimelineError(c, err, "Failed to remove dependency")
+		return
+	}
+	h.broadcastCards(services.TimelineCard{ID: card.ID, BoardID: card.Column.BoardID})
+	c.JSON(http.StatusOK, gin.H{"message": "Dependency removed"})
+}
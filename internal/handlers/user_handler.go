@@ -213,6 +213,8 @@ func (h *UserHandler) GetPreferences(c *gin.Context) {
 			NotifyCardCreated:         true,
 			NotifyCardMoved:           true,
 			NotifyCardArchived:        true,
+			NotifyStaleCards:          true,
+			NotifyAssignments:         true,
 			AllowDesktopNotifications: false,
 			ColorBlindMode:            false,
 			DisableKeyboardShortcuts:  false,
@@ -242,6 +244,8 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 		NotifyCardCreated         *bool `json:"notify_card_created"`
 		NotifyCardMoved           *bool `json:"notify_card_moved"`
 		NotifyCardArchived        *bool `json:"notify_card_archived"`
+		NotifyStaleCards          *bool `json:"notify_stale_cards"`
+		NotifyAssignments         *bool `json:"notify_assignments"`
 		AllowDesktopNotifications *bool `json:"allow_desktop_notifications"`
 		ColorBlindMode            *bool `json:"color_blind_mode"`
 		DisableKeyboardShortcuts  *bool `json:"disable_keyboard_shortcuts"`
@@ -267,6 +271,8 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 			NotifyCardCreated:         true,
 			NotifyCardMoved:           true,
 			NotifyCardArchived:        true,
+			NotifyStaleCards:          true,
+			NotifyAssignments:         true,
 			AllowDesktopNotifications: false,
 			ColorBlindMode:            false,
 			DisableKeyboardShortcuts:  false,
@@ -302,6 +308,12 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 	if req.NotifyCardArchived != nil {
 		updates["notify_card_archived"] = *req.NotifyCardArchived
 	}
+	if req.NotifyStaleCards != nil {
+		updates["notify_stale_cards"] = *req.NotifyStaleCards
+	}
+	if req.NotifyAssignments != nil {
+		updates["notify_assignments"] = *req.NotifyAssignments
+	}
 	if req.AllowDesktopNotifications != nil {
 		updates["allow_desktop_notifications"] = *req.AllowDesktopNotifications
 	}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// respondWIPLimit writes a 409 when err is a block-mode WIP limit and reports whether it did.
+func respondWIPLimit(c *gin.Context, err error) bool {
+	var wipErr *repository.WIPLimitError
+	if !errors.As(err, &wipErr) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    wipErr.Error(),
+		"code":     "WIP_LIMIT_EXCEEDED",
+		"exceeded": wipErr.Exceeded,
+	})
+	return true
+}
+
+// broadcastWIPExceeded tells the board that cardID took a warn-mode column over its limit.
+func broadcastWIPExceeded(hub *realtime.Hub, boardID, cardID uuid.UUID, exceeded []models.WIPExceeded) {
+	if hub == nil || len(exceeded) == 0 {
+		return
+	}
+	hub.BroadcastToRoom(boardID.String(), realtime.MessageTypeWIPLimitExceeded, map[string]interface{}{
+		"board_id":  boardID.String(),
+		"column_id": exceeded[0].ColumnID.String(),
+		"card_id":   cardID.String(),
+		"exceeded":  exceeded,
+	})
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"nexus-backend/internal/middleware"
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
+	"nexus-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WorkspaceDefinitionHandler serves workspace-shared custom fields and labels.
+type WorkspaceDefinitionHandler struct {
+	Service *services.SharedDefinitionService
+	Hub     *realtime.Hub
+}
+
+func NewWorkspaceDefinitionHandler(service *services.SharedDefinitionService, hub *realtime.Hub) *WorkspaceDefinitionHandler {
+	return &WorkspaceDefinitionHandler{Service: service, Hub: hub}
+}
+
+func respondSharedDefinitionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, services.ErrBoardAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, services.ErrInvalidCustomField),
+		errors.Is(err, services.ErrInvalidSharedLabel),
+		errors.Is(err, services.ErrNotInWorkspace):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func (h *WorkspaceDefinitionHandler) broadcastBoards(boardIDs []uuid.UUID) {
+	if h.Hub == nil {
+		return
+	}
+	for _, id := range boardIDs {
+		h.Hub.BroadcastToRoom(id.String(), "BOARD_UPDATED", map[string]interface{}{
+			"board_id": id.String(),
+		})
+	}
+}
+
+// workspaceAccess parses :id as a workspace the caller belongs to.
+func (h *WorkspaceDefinitionHandler) workspaceAccess(c *gin.Context) (uuid.UUID, bool) {
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return uuid.Nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	if !h.Service.CanAccessWorkspace(userID, workspaceID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return uuid.Nil, false
+	}
+	return workspaceID, true
+}
+
+// definitionID parses the named path parameter and the caller's user ID.
+func definitionID(c *gin.Context, param string) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, userID, true
+}
+
+// --- Fields ---
+
+// ListFields: GET /workspaces/:id/fields
+func (h *WorkspaceDefinitionHandler) ListFields(c *gin.Context) {
+	workspaceID, ok := h.workspaceAccess(c)
+	if !ok {
+		return
+	}
+	defs, err := h.Service.ListFields(workspaceID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch fields")
+		return
+	}
+	c.JSON(http.StatusOK, defs)
+}
+
+// CreateField: POST /workspaces/:id/fields
+func (h *WorkspaceDefinitionHandler) CreateField(c *gin.Context) {
+	workspaceID, ok := h.workspaceAccess(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Name    string                 `json:"name" binding:"required"`
+		Type    models.CustomFieldType `json:"type" binding:"required"`
+		Options []string               `json:"options"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name and type are required", "code": "VALIDATION_ERROR"})
+		return
+	}
+	def, err := h.Service.CreateField(workspaceID, req.Name, req.Type, req.Options)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to create field")
+		return
+	}
+	c.JSON(http.StatusCreated, def)
+}
+
+// UpdateField: PATCH /workspace-fields/:id
+// Takes the same body as PATCH /fields/:id and applies it to every linked board.
+func (h *WorkspaceDefinitionHandler) UpdateField(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetField(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch field")
+		return
+	}
+	var req services.FieldUpdateInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	result, err := h.Service.UpdateField(def, req)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to update field")
+		return
+	}
+	if !result.DryRun {
+		h.broadcastBoards(result.BoardIDs)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteField: DELETE /workspace-fields/:id
+func (h *WorkspaceDefinitionHandler) DeleteField(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetField(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch field")
+		return
+	}
+	boardIDs, err := h.Service.DeleteField(def)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to delete field")
+		return
+	}
+	h.broadcastBoards(boardIDs)
+	c.Status(http.StatusNoContent)
+}
+
+// LinkField: POST /boards/:id/shared-fields/:fieldId
+func (h *WorkspaceDefinitionHandler) LinkField(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	id, userID, ok := definitionID(c, "fieldId")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetField(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch field")
+		return
+	}
+	field, err := h.Service.LinkField(boardID, def)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to link field")
+		return
+	}
+	h.broadcastBoards([]uuid.UUID{boardID})
+	c.JSON(http.StatusOK, field)
+}
+
+// FieldAnalytics: GET /workspace-fields/:id/analytics
+// Groups the active cards of every board in the workspace by the shared field's value.
+func (h *WorkspaceDefinitionHandler) FieldAnalytics(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetField(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch field")
+		return
+	}
+	stats, err := h.Service.GroupCardsByWorkspaceField(def)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to group cards by field")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"field": def, "cards_per_field": stats})
+}
+
+// --- Labels ---
+
+// ListLabels: GET /workspaces/:id/labels
+func (h *WorkspaceDefinitionHandler) ListLabels(c *gin.Context) {
+	workspaceID, ok := h.workspaceAccess(c)
+	if !ok {
+		return
+	}
+	defs, err := h.Service.ListLabels(workspaceID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch labels")
+		return
+	}
+	c.JSON(http.StatusOK, defs)
+}
+
+// CreateLabel: POST /workspaces/:id/labels
+func (h *WorkspaceDefinitionHandler) CreateLabel(c *gin.Context) {
+	workspaceID, ok := h.workspaceAccess(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Name  string `json:"name" binding:"required"`
+		Color string `json:"color" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name and color are required", "code": "VALIDATION_ERROR"})
+		return
+	}
+	def, err := h.Service.CreateLabel(workspaceID, req.Name, req.Color)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to create label")
+		return
+	}
+	c.JSON(http.StatusCreated, def)
+}
+
+// UpdateLabel: PATCH /workspace-labels/:id
+func (h *WorkspaceDefinitionHandler) UpdateLabel(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetLabel(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch label")
+		return
+	}
+	var req struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "VALIDATION_ERROR"})
+		return
+	}
+	boardIDs, err := h.Service.UpdateLabel(def, req.Name, req.Color)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to update label")
+		return
+	}
+	h.broadcastBoards(boardIDs)
+	c.JSON(http.StatusOK, def)
+}
+
+// DeleteLabel: DELETE /workspace-labels/:id
+func (h *WorkspaceDefinitionHandler) DeleteLabel(c *gin.Context) {
+	id, userID, ok := definitionID(c, "id")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetLabel(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch label")
+		return
+	}
+	boardIDs, err := h.Service.DeleteLabel(def)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to delete label")
+		return
+	}
+	h.broadcastBoards(boardIDs)
+	c.Status(http.StatusNoContent)
+}
+
+// LinkLabel: POST /boards/:id/shared-labels/:labelId
+func (h *WorkspaceDefinitionHandler) LinkLabel(c *gin.Context) {
+	boardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	id, userID, ok := definitionID(c, "labelId")
+	if !ok {
+		return
+	}
+	def, err := h.Service.GetLabel(id, userID)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to fetch label")
+		return
+	}
+	label, err := h.Service.LinkLabel(boardID, def)
+	if err != nil {
+		respondSharedDefinitionError(c, err, "Failed to link label")
+		return
+	}
+	h.broadcastBoards([]uuid.UUID{boardID})
+	c.JSON(http.StatusOK, label)
+}
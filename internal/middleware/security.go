@@ -15,3 +15,14 @@ func SecurityMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// UploadsMiddleware makes stored uploads download-only so a file can never render as a page
+// on the app's origin.
+func UploadsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Disposition", "attachment")
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Header("Content-Security-Policy", "default-src 'none'; sandbox")
+		c.Next()
+	}
+}
@@ -19,6 +19,8 @@ const (
 	TriggerDueDateSet     TriggerType = "DUE_DATE_SET"
 	TriggerCalendarSet    TriggerType = "CALENDAR_DATE_SET"
 	TriggerDueDateOverdue TriggerType = "DUE_DATE_OVERDUE"
+	TriggerCardStale      TriggerType = "CARD_STALE"
+	TriggerUserFieldSet   TriggerType = "USER_FIELD_SET" // context: card_id, field_id, user_id ("" when cleared)
 )
 
 type ActionType string
@@ -32,6 +34,7 @@ const (
 	ActionSetDueDate      ActionType = "SET_DUE_DATE"
 	ActionNotifyAssignees ActionType = "NOTIFY_ASSIGNEES"
 	ActionNotifyAdmins    ActionType = "NOTIFY_ADMINS"
+	ActionAddChecklist    ActionType = "ADD_CHECKLIST"
 )
 
 type AutomationRule struct {
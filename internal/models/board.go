@@ -15,6 +15,10 @@ type Board struct {
 	BackgroundImageURL string         `gorm:"type:text" json:"background_image_url"`
 	DocumentationNotes string         `gorm:"type:text" json:"documentation_notes"`
 	IsStarred          bool           `gorm:"default:false" json:"is_starred"`
+	StaleAfterDays     int            `gorm:"not null;default:0" json:"stale_after_days"` // 0 disables stale detection
+	SwimlaneMode       SwimlaneMode   `gorm:"type:varchar(20);not null;default:'none'" json:"swimlane_mode"`
+	SwimlaneFieldID    *uuid.UUID     `gorm:"type:uuid" json:"swimlane_field_id,omitempty"` // dropdown field for custom_field mode
+	Version            int            `gorm:"not null;default:1" json:"version"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// FormFieldTarget says which part of the new card a form field fills in.
+type FormFieldTarget string
+
+const (
+	FormTargetTitle       FormFieldTarget = "title"
+	FormTargetDescription FormFieldTarget = "description"
+	FormTargetLabels      FormFieldTarget = "labels"       // choose from LabelIDs
+	FormTargetCustomField FormFieldTarget = "custom_field" // value for CustomFieldID
+)
+
+func (t FormFieldTarget) Valid() bool {
+	switch t {
+	case FormTargetTitle, FormTargetDescription, FormTargetLabels, FormTargetCustomField:
+		return true
+	}
+	return false
+}
+
+// BoardForm is a public intake form; each submission becomes a card in ColumnID.
+// Token is the unguessable part of the public URL.
+type BoardForm struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID           uuid.UUID `gorm:"type:uuid;not null;index" json:"board_id"`
+	ColumnID          uuid.UUID `gorm:"type:uuid;not null" json:"column_id"`
+	Title             string    `gorm:"type:varchar(200);not null" json:"title"`
+	Description       string    `gorm:"type:text" json:"description"`
+	Token             string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"token"`
+	Enabled           bool      `gorm:"not null" json:"enabled"`
+	AllowAttachments  bool      `gorm:"not null" json:"allow_attachments"`
+	RequireCaptcha    bool      `gorm:"not null" json:"require_captcha"`
+	TriggerAutomation bool      `gorm:"not null" json:"trigger_automation"` // run CARD_CREATED/LABEL_ADDED rules for submissions
+	CreatedBy         uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	Fields []BoardFormField `gorm:"foreignKey:FormID;constraint:OnDelete:CASCADE" json:"fields"`
+}
+
+func (f *BoardForm) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
+
+type BoardFormField struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	FormID        uuid.UUID       `gorm:"type:uuid;not null;index" json:"form_id"`
+	Label         string          `gorm:"type:varchar(200);not null" json:"label"`
+	Target        FormFieldTarget `gorm:"type:varchar(20);not null" json:"target"`
+	CustomFieldID *uuid.UUID      `gorm:"type:uuid" json:"custom_field_id,omitempty"`
+	LabelIDs      pq.StringArray  `gorm:"type:text[]" json:"label_ids,omitempty"`
+	Required      bool            `gorm:"not null" json:"required"`
+	Position      float64         `gorm:"not null" json:"position"`
+}
+
+func (f *BoardFormField) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BoardInbox is a board's secret email address; mail sent to it becomes a card in ColumnID.
+type BoardInbox struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"board_id"`
+	ColumnID  uuid.UUID `gorm:"type:uuid;not null" json:"column_id"`
+	Token     string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"` // acts for senders who aren't board members
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Address string `gorm:"-" json:"address"`
+}
+
+func (b *BoardInbox) BeforeCreate(tx *gorm.DB) (err error) {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return
+}
+
+// InboundEmailMessage records a processed Message-ID so redelivered mail is not imported twice.
+type InboundEmailMessage struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_inbound_board_message" json:"board_id"`
+	MessageID string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_inbound_board_message" json:"message_id"`
+	CardID    *uuid.UUID `gorm:"type:uuid" json:"card_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (m *InboundEmailMessage) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return
+}
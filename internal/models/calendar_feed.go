@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CalendarFeed is a user's secret iCalendar subscription: their assigned cards, or every dated
+// card on BoardID when set. The feed stops serving once the user loses access to the cards.
+type CalendarFeed struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	BoardID   *uuid.UUID `gorm:"type:uuid;index" json:"board_id"`
+	Token     string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	URL string `gorm:"-" json:"url"`
+}
+
+func (f *CalendarFeed) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
@@ -7,6 +7,34 @@ import (
 	"gorm.io/gorm"
 )
 
+type CardPriority string
+
+const (
+	PriorityNone   CardPriority = "none"
+	PriorityLow    CardPriority = "low"
+	PriorityMedium CardPriority = "medium"
+	PriorityHigh   CardPriority = "high"
+	PriorityUrgent CardPriority = "urgent"
+)
+
+var cardPriorityRank = map[CardPriority]int{
+	PriorityNone:   0,
+	PriorityLow:    1,
+	PriorityMedium: 2,
+	PriorityHigh:   3,
+	PriorityUrgent: 4,
+}
+
+func (p CardPriority) Valid() bool {
+	_, ok := cardPriorityRank[p]
+	return ok
+}
+
+// Rank orders priorities from none (0) to urgent (4).
+func (p CardPriority) Rank() int {
+	return cardPriorityRank[p]
+}
+
 type Card struct {
 	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
 	Title        string     `gorm:"type:varchar(200);not null" json:"title"`
@@ -19,6 +47,7 @@ type Card struct {
 	ArchivedAt   *time.Time `json:"archived_at"`
 	IsTemplate   bool       `json:"is_template" gorm:"default:false"`
 	TemplateName string     `json:"template_name" gorm:"type:varchar(100)"`
+	Version      int        `gorm:"not null;default:1" json:"version"` // Optimistic concurrency counter, exposed as ETag
 
 	// Relations
 	Checklists        []Checklist            `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"checklists"`
@@ -27,8 +56,28 @@ type Card struct {
 	Column            Column                 `gorm:"foreignKey:ColumnID" json:"column,omitempty"` // Belongs to Column (for BoardID access)
 
 	// Metadata
-	DueDate    *time.Time `json:"due_date"` // Optional
-	IsComplete bool       `json:"is_complete" gorm:"default:false"`
+	StartDate   *time.Time `gorm:"index" json:"start_date"` // Optional; with DueDate it spans a date range
+	DueDate     *time.Time `json:"due_date"`                // Optional
+	IsComplete  bool       `json:"is_complete" gorm:"default:false"`
+	CompletedAt *time.Time `json:"completed_at"` // Set when IsComplete flips to true
+
+	// Triage
+	Priority        CardPriority `gorm:"type:varchar(10);not null;default:'none';index" json:"priority"`
+	LastActivityAt  *time.Time   `gorm:"index" json:"last_activity_at"` // Bumped by every Activity targeting the card
+	StaleNotifiedAt *time.Time   `json:"-"`                             // Set when CARD_STALE fired; new activity clears it
+	IsStale         bool         `gorm:"-" json:"is_stale,omitempty"`   // Computed from the board's StaleAfterDays
+
+	// Set on the card returned by a create, copy, move or restore that took its column
+	// over a warn-mode WIP limit.
+	WIPExceeded []WIPExceeded `gorm:"-" json:"wip_exceeded,omitempty"`
+
+	// Planning
+	SprintID   *uuid.UUID `gorm:"type:uuid;index" json:"sprint_id"`
+	SwimlaneID *uuid.UUID `gorm:"type:uuid;index" json:"swimlane_id"` // explicit swimlane on the card's board
+
+	// Estimates (time tracking lives in TimeLog)
+	EstimateMinutes *int     `json:"estimate_minutes"`
+	StoryPoints     *float64 `json:"story_points"`
 
 	// Many-to-Many
 	Labels  []Label `gorm:"many2many:card_labels;" json:"labels"`
@@ -39,10 +88,55 @@ type Card struct {
 	Attachments       []Attachment `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"attachments"`
 }
 
+// StaleAt reports whether the card has gone staleAfterDays without activity as of now.
+// Completed cards and boards with stale detection disabled (0) never report stale.
+func (c *Card) StaleAt(now time.Time, staleAfterDays int) bool {
+	if staleAfterDays <= 0 || c.IsComplete || c.IsArchived {
+		return false
+	}
+	last := c.CreatedAt
+	if c.LastActivityAt != nil {
+		last = *c.LastActivityAt
+	}
+	return now.Sub(last) >= time.Duration(staleAfterDays)*24*time.Hour
+}
+
 // BeforeCreate hook to generate UUID if not present
 func (c *Card) BeforeCreate(tx *gorm.DB) (err error) {
 	if c.ID == uuid.Nil {
 		c.ID = uuid.New()
 	}
+	if c.Priority == "" {
+		c.Priority = PriorityNone
+	}
+	if c.LastActivityAt == nil {
+		now := time.Now()
+		c.LastActivityAt = &now
+	}
+	return
+}
+
+// CardMention records that a user was @mentioned on a card, in its description, a comment or
+// a checklist item.
+type CardMention struct {
+	CardID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"card_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CardDependency says CardID depends on DependsOnID finishing first (finish-to-start). Both
+// cards are on boards of the same workspace.
+type CardDependency struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CardID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_card_dependency" json:"card_id"`
+	DependsOnID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_card_dependency;index" json:"depends_on_id"`
+	CreatedBy   uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (d *CardDependency) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
 	return
 }
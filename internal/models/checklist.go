@@ -12,6 +12,7 @@ type Checklist struct {
 	CardID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"card_id"`
 	Title     string         `gorm:"type:varchar(200);not null" json:"title"`
 	Position  float64        `gorm:"not null" json:"position"`
+	Version   int            `gorm:"not null;default:1" json:"version"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -26,9 +27,16 @@ type ChecklistItem struct {
 	Title       string         `gorm:"type:varchar(500);not null" json:"title"`
 	IsCompleted bool           `gorm:"default:false" json:"is_completed"`
 	Position    float64        `gorm:"not null" json:"position"`
+	AssigneeID  *uuid.UUID     `gorm:"type:uuid;index" json:"assignee_id"`
+	DueDate     *time.Time     `gorm:"index" json:"due_date"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// DueReminderSentAt is set once the assignee has been reminded and cleared when the due date changes.
+	DueReminderSentAt *time.Time `json:"-"`
+
+	Assignee *User `gorm:"foreignKey:AssigneeID" json:"assignee,omitempty"`
 }
 
 // BeforeCreate hooks
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChecklistTemplate is a reusable checklist. Exactly one of BoardID or WorkspaceID is set:
+// board templates are offered on that board only, workspace templates on every board in it.
+type ChecklistTemplate struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID     *uuid.UUID `gorm:"type:uuid;index" json:"board_id"`
+	WorkspaceID *uuid.UUID `gorm:"type:uuid;index" json:"workspace_id"`
+	Name        string     `gorm:"type:varchar(200);not null" json:"name"`
+	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	Items []ChecklistTemplateItem `gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE" json:"items"`
+}
+
+type ChecklistTemplateItem struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TemplateID uuid.UUID `gorm:"type:uuid;not null;index" json:"template_id"`
+	Title      string    `gorm:"type:varchar(500);not null" json:"title"`
+	Position   float64   `gorm:"not null" json:"position"`
+}
+
+func (t *ChecklistTemplate) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
+
+func (i *ChecklistTemplateItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -7,15 +7,57 @@ import (
 	"gorm.io/gorm"
 )
 
+// WIPScope says what a column's WIP limit counts.
+type WIPScope string
+
+const (
+	WIPScopeCards  WIPScope = "cards"  // active cards in the column
+	WIPScopeMember WIPScope = "member" // active cards per assigned member
+)
+
+func (s WIPScope) Valid() bool {
+	return s == WIPScopeCards || s == WIPScopeMember
+}
+
+// WIPMode says what happens when a change would exceed the WIP limit.
+type WIPMode string
+
+const (
+	WIPModeWarn  WIPMode = "warn"  // the change goes through and is flagged
+	WIPModeBlock WIPMode = "block" // the change is rejected
+)
+
+func (m WIPMode) Valid() bool {
+	return m == WIPModeWarn || m == WIPModeBlock
+}
+
 type Column struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	BoardID   uuid.UUID `gorm:"type:uuid;index" json:"board_id"` // Link to Board
-	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
-	Position  float64   `gorm:"not null" json:"position"`
-	Cards     []Card    `gorm:"foreignKey:ColumnID;constraint:OnDelete:CASCADE" json:"cards"`
-	CardCount int       `gorm:"-" json:"card_count"` // Computed field
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID   uuid.UUID    `gorm:"type:uuid;index" json:"board_id"` // Link to Board
+	Name      string       `gorm:"type:varchar(100);not null" json:"name"`
+	Position  float64      `gorm:"not null" json:"position"`
+	Cards     []Card       `gorm:"foreignKey:ColumnID;constraint:OnDelete:CASCADE" json:"cards"`
+	CardCount int          `gorm:"-" json:"card_count"`      // Computed field
+	Lanes     []ColumnLane `gorm:"-" json:"lanes,omitempty"` // Cards grouped by the board's swimlanes
+	Version   int          `gorm:"not null;default:1" json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	// Work-in-progress limit; nil means unlimited.
+	WIPLimit *int     `gorm:"column:wip_limit" json:"wip_limit"`
+	WIPScope WIPScope `gorm:"column:wip_scope;type:varchar(10);not null;default:'cards'" json:"wip_scope"`
+	WIPMode  WIPMode  `gorm:"column:wip_mode;type:varchar(10);not null;default:'warn'" json:"wip_mode"`
+}
+
+// WIPExceeded reports a column that is over its WIP limit after a change. UserID is set
+// for per-member limits.
+type WIPExceeded struct {
+	ColumnID uuid.UUID  `json:"column_id"`
+	Limit    int        `json:"limit"`
+	Count    int64      `json:"count"`
+	Scope    WIPScope   `json:"scope"`
+	Mode     WIPMode    `json:"mode"`
+	UserID   *uuid.UUID `json:"user_id,omitempty"`
 }
 
 // BeforeCreate hook to generate UUID if not present
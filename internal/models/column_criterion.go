@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CriterionPhase says whether a criterion guards moving cards into or out of a column.
+type CriterionPhase string
+
+const (
+	CriterionEntry CriterionPhase = "entry"
+	CriterionExit  CriterionPhase = "exit"
+)
+
+func (p CriterionPhase) Valid() bool {
+	return p == CriterionEntry || p == CriterionExit
+}
+
+// CriterionKind is the condition a card must satisfy.
+type CriterionKind string
+
+const (
+	CriterionAssignee           CriterionKind = "assignee"            // at least one member
+	CriterionDueDate            CriterionKind = "due_date"            // a due date is set
+	CriterionEstimate           CriterionKind = "estimate"            // estimate minutes or story points are set
+	CriterionChecklistsComplete CriterionKind = "checklists_complete" // every checklist item is done
+	CriterionDescription        CriterionKind = "description"         // the description is not blank
+	CriterionPriority           CriterionKind = "priority"            // priority is not "none"
+	CriterionLabel              CriterionKind = "label"               // LabelID is on the card
+	CriterionCustomField        CriterionKind = "custom_field"        // CustomFieldID has a value
+)
+
+var criterionKinds = map[CriterionKind]bool{
+	CriterionAssignee: true, CriterionDueDate: true, CriterionEstimate: true, CriterionChecklistsComplete: true,
+	CriterionDescription: true, CriterionPriority: true, CriterionLabel: true, CriterionCustomField: true,
+}
+
+func (k CriterionKind) Valid() bool {
+	return criterionKinds[k]
+}
+
+// ColumnCriterion is one rule a card must meet to enter or leave a column. Moves that
+// break a rule are rejected unless a workspace admin overrides them.
+type ColumnCriterion struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	ColumnID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"column_id"`
+	Phase         CriterionPhase `gorm:"type:varchar(10);not null" json:"phase"`
+	Kind          CriterionKind  `gorm:"type:varchar(30);not null" json:"kind"`
+	LabelID       *uuid.UUID     `gorm:"type:uuid" json:"label_id,omitempty"`
+	CustomFieldID *uuid.UUID     `gorm:"type:uuid" json:"custom_field_id,omitempty"`
+	Position      int            `gorm:"not null;default:0" json:"position"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+func (ColumnCriterion) TableName() string {
+	return "column_criteria"
+}
+
+func (c *ColumnCriterion) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -1,17 +1,97 @@
 package models
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Comment struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	CardID    uuid.UUID `gorm:"type:uuid;not null;index" json:"card_id"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CardID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"card_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	User      User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ParentID  *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"` // set on replies; threads are one level deep
+	Content   string     `gorm:"type:text;not null" json:"content"`
+	IsEdited  bool       `gorm:"default:false" json:"is_edited"`
+	EditedAt  *time.Time `json:"edited_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Reactions       []CommentReaction `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"-"`
+	ReactionSummary []ReactionCount   `gorm:"-" json:"reactions"`
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+// AfterFind aggregates preloaded reactions into per-emoji counts.
+func (c *Comment) AfterFind(tx *gorm.DB) (err error) {
+	c.ReactionSummary = SummarizeReactions(c.Reactions)
+	return
+}
+
+// CommentEdit keeps the content a comment had before an edit.
+type CommentEdit struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CommentID       uuid.UUID `gorm:"type:uuid;not null;index" json:"comment_id"`
+	EditedBy        uuid.UUID `gorm:"type:uuid;not null" json:"edited_by"`
+	PreviousContent string    `gorm:"type:text;not null" json:"previous_content"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (e *CommentEdit) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
+
+// CommentReaction is one user's emoji on a comment; a user can add each emoji once.
+type CommentReaction struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CommentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_comment_reaction" json:"comment_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_comment_reaction" json:"user_id"`
+	Emoji     string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_comment_reaction" json:"emoji"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *CommentReaction) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
+
+type ReactionCount struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// SummarizeReactions groups reactions by emoji in order of first use.
+func SummarizeReactions(reactions []CommentReaction) []ReactionCount {
+	sorted := make([]CommentReaction, len(reactions))
+	copy(sorted, reactions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	out := []ReactionCount{}
+	index := map[string]int{}
+	for _, r := range sorted {
+		i, ok := index[r.Emoji]
+		if !ok {
+			i = len(out)
+			index[r.Emoji] = i
+			out = append(out, ReactionCount{Emoji: r.Emoji, UserIDs: []uuid.UUID{}})
+		}
+		out[i].Count++
+		out[i].UserIDs = append(out[i].UserIDs, r.UserID)
+	}
+	return out
 }
@@ -16,17 +16,60 @@ const (
 	FieldTypeDate     CustomFieldType = "date"
 	FieldTypeDropdown CustomFieldType = "dropdown"
 	FieldTypeCheckbox CustomFieldType = "checkbox"
+
+	FieldTypeUser        CustomFieldType = "user"         // a board member, stored in ValueUserID
+	FieldTypeMultiSelect CustomFieldType = "multi_select" // any of Options, stored in ValueList
+	FieldTypeURL         CustomFieldType = "url"
+	FieldTypeEmail       CustomFieldType = "email"
+	FieldTypeCurrency    CustomFieldType = "currency" // ValueNumber plus ISO 4217 code in ValueCurrency
+	FieldTypeRating      CustomFieldType = "rating"   // whole number 1..MaxFieldRating in ValueNumber
+	FieldTypeFormula     CustomFieldType = "formula"  // computed from Formula; stored like ResultType
 )
 
+const MaxFieldRating = 5
+
+func (t CustomFieldType) Valid() bool {
+	switch t {
+	case FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeDropdown, FieldTypeCheckbox,
+		FieldTypeUser, FieldTypeMultiSelect, FieldTypeURL, FieldTypeEmail, FieldTypeCurrency, FieldTypeRating, FieldTypeFormula:
+		return true
+	}
+	return false
+}
+
+// HasOptions reports whether values are chosen from the field's Options.
+func (t CustomFieldType) HasOptions() bool {
+	return t == FieldTypeDropdown || t == FieldTypeMultiSelect
+}
+
 type CustomField struct {
-	ID        uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	BoardID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"board_id"`
-	Name      string          `gorm:"type:varchar(100);not null" json:"name"`
-	Type      CustomFieldType `gorm:"type:varchar(20);not null" json:"type"`
-	Options   pq.StringArray  `gorm:"type:text[]" json:"options,omitempty"` // For dropdowns
-	Position  float64         `gorm:"type:double precision;not null;default:0;index" json:"position"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID       uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BoardID  uuid.UUID       `gorm:"type:uuid;not null;index" json:"board_id"`
+	Name     string          `gorm:"type:varchar(100);not null" json:"name"`
+	Type     CustomFieldType `gorm:"type:varchar(20);not null" json:"type"`
+	Options  pq.StringArray  `gorm:"type:text[]" json:"options,omitempty"` // For dropdown and multi_select
+	Position float64         `gorm:"type:double precision;not null;default:0;index" json:"position"`
+
+	// WorkspaceFieldID links the field to a shared WorkspaceField; its definition is then
+	// edited on the workspace.
+	WorkspaceFieldID *uuid.UUID `gorm:"type:uuid;index" json:"workspace_field_id,omitempty"`
+
+	// Formula fields only. ResultType (number, text, date or checkbox) is inferred when the
+	// formula is saved and decides which value column holds the cached result.
+	Formula    string          `gorm:"type:text" json:"formula,omitempty"`
+	ResultType CustomFieldType `gorm:"type:varchar(20)" json:"result_type,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ValueType is the type whose value column holds the field's values: ResultType for
+// formulas, otherwise Type.
+func (f *CustomField) ValueType() CustomFieldType {
+	if f.Type == FieldTypeFormula {
+		return f.ResultType
+	}
+	return f.Type
 }
 
 type CardCustomFieldValue struct {
@@ -41,6 +84,15 @@ type CardCustomFieldValue struct {
 	ValueDate   *time.Time `gorm:"type:timestamp" json:"value_date,omitempty"`
 	ValueBool   bool       `gorm:"type:boolean" json:"value_bool,omitempty"`
 
+	ValueUserID   *uuid.UUID     `gorm:"type:uuid;index" json:"value_user_id,omitempty"`
+	ValueUser     *User          `gorm:"foreignKey:ValueUserID" json:"value_user,omitempty"`
+	ValueList     pq.StringArray `gorm:"type:text[]" json:"value_list,omitempty"`
+	ValueCurrency string         `gorm:"type:varchar(3)" json:"value_currency,omitempty"`
+
+	// FormulaError explains why a formula could not be computed for this card; the value
+	// columns are empty when it is set.
+	FormulaError string `gorm:"type:varchar(255)" json:"formula_error,omitempty"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -9,9 +9,12 @@ import (
 type NotificationType string
 
 const (
-	NotificationAssignment NotificationType = "ASSIGNMENT"
-	NotificationMention    NotificationType = "MENTION"
-	NotificationDueSoon    NotificationType = "DUE_SOON"
+	NotificationAssignment  NotificationType = "ASSIGNMENT"
+	NotificationMention     NotificationType = "MENTION"
+	NotificationDueSoon     NotificationType = "DUE_SOON"
+	NotificationStaleCards  NotificationType = "STALE_CARDS"
+	NotificationItemDueSoon NotificationType = "ITEM_DUE_SOON"
+	NotificationReply       NotificationType = "REPLY"
 )
 
 type Notification struct {
@@ -28,3 +31,12 @@ type Notification struct {
 	IsRead     bool             `json:"is_read" gorm:"default:false"`
 	CreatedAt  time.Time        `json:"created_at" gorm:"autoCreateTime"`
 }
+
+// DueReminder records that a user was reminded of a card's due date. It is keyed by the date,
+// so moving the card to a new date re-arms its reminders.
+type DueReminder struct {
+	CardID    uuid.UUID `json:"card_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	DueDate   time.Time `json:"due_date" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SavedFilter is a named card query. A board filter runs on that board only; a workspace
+// filter (BoardID nil) runs across every board of the workspace. Filters are private to their
+// owner until Shared, which shows them read-only to every workspace member.
+type SavedFilter struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	WorkspaceID uuid.UUID  `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	BoardID     *uuid.UUID `gorm:"type:uuid;index" json:"board_id"`
+	OwnerID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	Query       string     `gorm:"type:text;not null" json:"query"`
+	Shared      bool       `gorm:"not null;default:false" json:"shared"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (f *SavedFilter) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
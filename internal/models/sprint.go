@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SprintState string
+
+const (
+	SprintPlanned SprintState = "planned"
+	SprintActive  SprintState = "active"
+	SprintClosed  SprintState = "closed"
+)
+
+// Sprint is a time-boxed iteration on a board. Cards join it through Card.SprintID.
+type Sprint struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"board_id"`
+	Name      string      `gorm:"type:varchar(100);not null" json:"name"`
+	Goal      string      `gorm:"type:text" json:"goal"`
+	StartDate time.Time   `gorm:"not null" json:"start_date"`
+	EndDate   time.Time   `gorm:"not null" json:"end_date"`
+	State     SprintState `gorm:"type:varchar(10);not null;default:'planned';index" json:"state"`
+	StartedAt *time.Time  `json:"started_at"`
+	ClosedAt  *time.Time  `json:"closed_at"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	Cards []Card `gorm:"foreignKey:SprintID" json:"cards,omitempty"`
+}
+
+func (s *Sprint) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.State == "" {
+		s.State = SprintPlanned
+	}
+	return
+}
+
+type SprintChangeType string
+
+const (
+	SprintChangeCommitted   SprintChangeType = "committed"    // in scope when the sprint started
+	SprintChangeAdded       SprintChangeType = "added"        // joined an active sprint
+	SprintChangeRemoved     SprintChangeType = "removed"      // left an active sprint
+	SprintChangeEstimate    SprintChangeType = "estimate"     // story points changed while in an active sprint
+	SprintChangeCarriedOver SprintChangeType = "carried_over" // unfinished at close-out
+)
+
+// SprintScopeChange is one entry in a sprint's scope log. Points holds the card's
+// story points after the change so burndown can be replayed from the log alone.
+type SprintScopeChange struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	SprintID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"sprint_id"`
+	CardID         uuid.UUID        `gorm:"type:uuid;not null;index" json:"card_id"`
+	ActorID        *uuid.UUID       `gorm:"type:uuid" json:"actor_id"`
+	Type           SprintChangeType `gorm:"type:varchar(20);not null" json:"type"`
+	Points         *float64         `json:"points"`
+	PreviousPoints *float64         `json:"previous_points,omitempty"`
+	Detail         string           `gorm:"type:text" json:"detail,omitempty"` // e.g. where a carried-over card went
+	CreatedAt      time.Time        `gorm:"index" json:"created_at"`
+}
+
+func (c *SprintScopeChange) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SwimlaneMode says how a board splits its cards into horizontal lanes.
+type SwimlaneMode string
+
+const (
+	SwimlaneNone        SwimlaneMode = "none"
+	SwimlaneExplicit    SwimlaneMode = "explicit"     // Card.SwimlaneID picks one of the board's Swimlanes
+	SwimlaneMember      SwimlaneMode = "member"       // one lane per workspace member
+	SwimlaneLabel       SwimlaneMode = "label"        // one lane per board label
+	SwimlanePriority    SwimlaneMode = "priority"     // one lane per priority
+	SwimlaneCustomField SwimlaneMode = "custom_field" // one lane per option of Board.SwimlaneFieldID (dropdown only)
+)
+
+var swimlaneModes = map[SwimlaneMode]bool{
+	SwimlaneNone: true, SwimlaneExplicit: true, SwimlaneMember: true,
+	SwimlaneLabel: true, SwimlanePriority: true, SwimlaneCustomField: true,
+}
+
+func (m SwimlaneMode) Valid() bool {
+	return swimlaneModes[m]
+}
+
+// Swimlane is a named row on a board in explicit swimlane mode.
+type Swimlane struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	BoardID   uuid.UUID `gorm:"type:uuid;not null;index" json:"board_id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	Position  float64   `gorm:"not null" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *Swimlane) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// BoardLane is one lane as the board renders it, whatever the mode. Key is the lane's
+// swimlane, user or label ID, priority or option; the empty key is the catch-all lane for
+// cards with no lane, assignee, label or value.
+type BoardLane struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// ColumnLane holds a column's cards that fall in one lane.
+type ColumnLane struct {
+	Key   string `json:"key"`
+	Cards []Card `json:"cards"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeLog is time a user spent on a card on a given day.
+type TimeLog struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CardID          uuid.UUID `gorm:"type:uuid;not null;index" json:"card_id"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	User            User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+	LoggedOn        time.Time `gorm:"type:date;not null;index" json:"logged_on"` // Day the work was done
+	Note            string    `gorm:"type:text" json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (t *TimeLog) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
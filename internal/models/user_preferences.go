@@ -14,6 +14,8 @@ type UserPreferences struct {
 	NotifyCardCreated     bool `gorm:"default:true" json:"notify_card_created"`
 	NotifyCardMoved       bool `gorm:"default:true" json:"notify_card_moved"`
 	NotifyCardArchived    bool `gorm:"default:true" json:"notify_card_archived"`
+	NotifyStaleCards      bool `gorm:"default:true" json:"notify_stale_cards"`
+	NotifyAssignments     bool `gorm:"default:true" json:"notify_assignments"`
 
 	// Desktop Notifications
 	AllowDesktopNotifications bool `gorm:"default:false" json:"allow_desktop_notifications"`
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// WorkspaceField is a custom field defined once for a workspace. Boards opt in by linking
+// it, which adds a board CustomField with WorkspaceFieldID set; the board copy follows the
+// shared name, type and options, so values on every linked board can be reported together.
+type WorkspaceField struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	WorkspaceID uuid.UUID       `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	Name        string          `gorm:"type:varchar(100);not null" json:"name"`
+	Type        CustomFieldType `gorm:"type:varchar(20);not null" json:"type"`
+	Options     pq.StringArray  `gorm:"type:text[]" json:"options,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (f *WorkspaceField) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return
+}
+
+// WorkspaceLabel is a label defined once for a workspace; linked board labels carry its
+// name and color and keep their meaning when cards move between boards.
+type WorkspaceLabel struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+	Name        string    `gorm:"not null" json:"name"`
+	Color       string    `gorm:"not null" json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (l *WorkspaceLabel) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return
+}
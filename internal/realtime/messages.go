@@ -8,12 +8,17 @@ type Message struct {
 }
 
 const (
-	MessageTypeConnect            = "CONNECT"
-	MessageTypeDisconnect         = "DISCONNECT"
-	MessageTypeCardMoved          = "CARD_MOVED"
-	MessageTypeCardUpdated        = "CARD_UPDATED"
-	MessageTypeColumnMoved        = "COLUMN_MOVED"
-	MessageTypePresenceUpdate     = "PRESENCE_UPDATE"
-	MessageTypeInvitationReceived = "INVITATION_RECEIVED"
-	MessageTypeRoleUpdated        = "ROLE_UPDATED"
+	MessageTypeConnect             = "CONNECT"
+	MessageTypeDisconnect          = "DISCONNECT"
+	MessageTypeCardMoved           = "CARD_MOVED"
+	MessageTypeCardUpdated         = "CARD_UPDATED"
+	MessageTypeColumnMoved         = "COLUMN_MOVED"
+	MessageTypePresenceUpdate      = "PRESENCE_UPDATE"
+	MessageTypeInvitationReceived  = "INVITATION_RECEIVED"
+	MessageTypeRoleUpdated         = "ROLE_UPDATED"
+	MessageTypePositionsRebalanced = "POSITIONS_REBALANCED"
+	MessageTypeCardsBulkUpdated    = "CARDS_BULK_UPDATED"
+	MessageTypeSprintUpdated       = "SPRINT_UPDATED"
+	MessageTypeCommentReaction     = "COMMENT_REACTION"
+	MessageTypeWIPLimitExceeded    = "WIP_LIMIT_EXCEEDED"
 )
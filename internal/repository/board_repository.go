@@ -15,16 +15,19 @@ func NewBoardRepository(db *gorm.DB) *BoardRepository {
 	return &BoardRepository{DB: db}
 }
 
+// AccessibleWorkspaceIDs selects the IDs of workspaces the user owns or has accepted an
+// invitation to. It is the access rule for boards and everything on them.
+func AccessibleWorkspaceIDs(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Table("workspaces").Select("id").
+		Where("owner_id = ?", userID).
+		Or("id IN (?)", db.Table("workspace_members").Select("workspace_id").Where("user_id = ? AND status = 'accepted'", userID))
+}
+
 // GetBoardsByUserID fetches all boards for a user across all their workspaces (owned and shared)
 func (r *BoardRepository) GetBoardsByUserID(userID uuid.UUID) ([]models.Board, error) {
 	var boards []models.Board
 
-	// Subquery: Workspaces owned by user OR shared with user (accepted only)
-	subQuery := r.DB.Table("workspaces").Select("id").
-		Where("owner_id = ?", userID).
-		Or("id IN (?)", r.DB.Table("workspace_members").Select("workspace_id").Where("user_id = ? AND status = 'accepted'", userID))
-
-	err := r.DB.Where("workspace_id IN (?)", subQuery).
+	err := r.DB.Where("workspace_id IN (?)", AccessibleWorkspaceIDs(r.DB, userID)).
 		Order("updated_at DESC").
 		Find(&boards).Error
 
@@ -35,12 +38,7 @@ func (r *BoardRepository) GetBoardsByUserID(userID uuid.UUID) ([]models.Board, e
 func (r *BoardRepository) GetBoardByID(boardID uuid.UUID, userID uuid.UUID) (*models.Board, error) {
 	var board models.Board
 
-	// Subquery: Workspaces owned by user OR shared with user (accepted only)
-	subQuery := r.DB.Table("workspaces").Select("id").
-		Where("owner_id = ?", userID).
-		Or("id IN (?)", r.DB.Table("workspace_members").Select("workspace_id").Where("user_id = ? AND status = 'accepted'", userID))
-
-	err := r.DB.Where("id = ? AND workspace_id IN (?)", boardID, subQuery).
+	err := r.DB.Where("id = ? AND workspace_id IN (?)", boardID, AccessibleWorkspaceIDs(r.DB, userID)).
 		First(&board).Error
 	return &board, err
 }
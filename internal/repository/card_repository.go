@@ -8,8 +8,12 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrVersionConflict is returned when a write was made against a stale version of a record.
+var ErrVersionConflict = errors.New("version conflict")
+
 type CardRepository struct {
 	DB *gorm.DB
 }
@@ -18,9 +22,37 @@ func NewCardRepository(db *gorm.DB) *CardRepository {
 	return &CardRepository{DB: db}
 }
 
+// MoveCardOptions carries optional guards for a card move.
+type MoveCardOptions struct {
+	ExpectedVersion  int  // 0 skips the optimistic concurrency check
+	OverrideCriteria bool // CardService skips column entry/exit criteria; callers check who may
+	// InTransaction runs after the card is written, in the same transaction; an error rolls
+	// the whole move back.
+	InTransaction func(tx *gorm.DB) error
+}
+
+// MoveCardResult is the outcome of a move, including any list renumbering it caused.
+type MoveCardResult struct {
+	Card       *models.Card
+	Rebalanced []PositionUpdate
+}
+
 // MoveCardTransaction handles the move logic by setting the new float position.
 func (r *CardRepository) MoveCardTransaction(cardID uuid.UUID, newColumnID uuid.UUID, newPosition float64) (*models.Card, error) {
+	result, err := r.MoveCardWithOptions(cardID, newColumnID, newPosition, MoveCardOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Card, nil
+}
+
+// MoveCardWithOptions moves a card inside a transaction. If the card lands too close to a
+// neighbour the target column is renumbered in the same transaction.
+func (r *CardRepository) MoveCardWithOptions(cardID uuid.UUID, newColumnID uuid.UUID, newPosition float64, opts MoveCardOptions) (*MoveCardResult, error) {
 	var movedCard models.Card
+	var rebalanced []PositionUpdate
+	var wipExceeded []models.WIPExceeded
+	expectedVersion := opts.ExpectedVersion
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		// 1. Get the card to move (Preload Column to check current board)
@@ -28,6 +60,9 @@ func (r *CardRepository) MoveCardTransaction(cardID uuid.UUID, newColumnID uuid.
 		if err := tx.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
 			return err
 		}
+		if expectedVersion != 0 && card.Version != expectedVersion {
+			return ErrVersionConflict
+		}
 
 		// 2. Validate Target Column exists
 		var targetCol models.Column
@@ -37,26 +72,67 @@ func (r *CardRepository) MoveCardTransaction(cardID uuid.UUID, newColumnID uuid.
 
 		// 3. Simple Check for same position/column - do nothing
 		if card.ColumnID == newColumnID && card.Position == newPosition {
-			movedCard = card
-			return nil
+			if opts.InTransaction == nil {
+				movedCard = card
+				return nil
+			}
+			if err := opts.InTransaction(tx); err != nil {
+				return err
+			}
+			return tx.Preload("Column").First(&movedCard, "id = ?", cardID).Error
+		}
+		if card.ColumnID != newColumnID || card.IsArchived {
+			exceeded, err := checkWIPLimit(tx, newColumnID, cardID, nil)
+			if err != nil {
+				return err
+			}
+			wipExceeded = exceeded
 		}
 
-		// 4. Handle Cross-Board Move: Clear Labels
-		// Labels are board-specific. If moving to a new board, original labels are invalid.
+		// 4. Handle Cross-Board Move: board labels are invalid on the new board, but
+		// shared workspace labels and field values travel with the card.
 		if card.Column.BoardID != targetCol.BoardID {
-			if err := tx.Model(&card).Association("Labels").Clear(); err != nil {
-				return fmt.Errorf("failed to clear labels during cross-board move: %w", err)
+			if err := carrySharedDefinitions(tx, &card, card.Column.BoardID, targetCol.BoardID); err != nil {
+				return fmt.Errorf("failed to carry labels during cross-board move: %w", err)
 			}
 		}
 
-		// 5. Update the Card
-		if err := tx.Model(&models.Card{}).Where("id = ?", cardID).Updates(map[string]interface{}{
+		// 5. Update the Card, guarded by the version read above so a concurrent
+		// writer between the read and this statement is detected.
+		fields := map[string]interface{}{
 			"column_id":   newColumnID,
 			"position":    newPosition,
 			"is_archived": false,
 			"archived_at": nil,
-		}).Error; err != nil {
-			return err
+			"version":     gorm.Expr("version + 1"),
+		}
+		if card.Column.BoardID != targetCol.BoardID {
+			fields["swimlane_id"] = nil // swimlanes and sprints belong to the old board
+			if card.SprintID != nil {
+				fields["sprint_id"] = nil
+				if err := leaveActiveSprint(tx, &card, "moved to another board"); err != nil {
+					return err
+				}
+			}
+		}
+		result := tx.Model(&models.Card{}).Where("id = ? AND version = ?", cardID, card.Version).Updates(fields)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		updates, err := RebalanceIfCrowded(tx, CardPositionList(newColumnID), cardID, newPosition)
+		if err != nil {
+			return fmt.Errorf("failed to rebalance column: %w", err)
+		}
+		rebalanced = updates
+
+		if opts.InTransaction != nil {
+			if err := opts.InTransaction(tx); err != nil {
+				return err
+			}
 		}
 
 		// Reload to ensure fresh relations
@@ -70,16 +146,81 @@ func (r *CardRepository) MoveCardTransaction(cardID uuid.UUID, newColumnID uuid.
 		return nil, fmt.Errorf("transaction failed: %w", err)
 	}
 
-	return &movedCard, nil
+	movedCard.WIPExceeded = wipExceeded
+	return &MoveCardResult{Card: &movedCard, Rebalanced: rebalanced}, nil
 }
 
-// Basic CRUD methods
+// leaveActiveSprint logs the card leaving its sprint's scope when that sprint is running.
+func leaveActiveSprint(tx *gorm.DB, card *models.Card, detail string) error {
+	var count int64
+	if err := tx.Model(&models.Sprint{}).Where("id = ? AND state = ?", *card.SprintID, models.SprintActive).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+	return tx.Create(&models.SprintScopeChange{
+		SprintID: *card.SprintID,
+		CardID:   card.ID,
+		Type:     models.SprintChangeRemoved,
+		Points:   card.StoryPoints,
+		Detail:   detail,
+	}).Error
+}
+
+// Create inserts the card, checking its column's WIP limit first. Templates are not
+// counted against the limit.
 func (r *CardRepository) Create(card *models.Card) error {
-	return r.DB.Create(card).Error
+	if card.IsTemplate {
+		return r.DB.Create(card).Error
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		exceeded, err := checkWIPLimit(tx, card.ColumnID, card.ID, []uuid.UUID{})
+		if err != nil {
+			return err
+		}
+		card.WIPExceeded = exceeded
+		return tx.Create(card).Error
+	})
 }
 
 func (r *CardRepository) Update(card *models.Card) error {
-	return r.DB.Save(card).Error
+	return r.UpdateIfVersion(card, card.Version)
+}
+
+// UpdateIfVersion writes the card only if its stored version still equals
+// expectedVersion, then bumps the version.
+func (r *CardRepository) UpdateIfVersion(card *models.Card, expectedVersion int) error {
+	card.Version = expectedVersion + 1
+	result := r.DB.Model(card).
+		Where("version = ?", expectedVersion).
+		Select("*").
+		Omit(clause.Associations, "id", "created_at").
+		Updates(card)
+	if result.Error != nil {
+		card.Version = expectedVersion
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		card.Version = expectedVersion
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// SetDueDateIfVersion writes only the card's due date if its stored version still equals
+// expectedVersion, then bumps the version.
+func (r *CardRepository) SetDueDateIfVersion(card *models.Card, due time.Time, expectedVersion int) error {
+	result := r.DB.Model(&models.Card{}).Where("id = ? AND version = ?", card.ID, expectedVersion).
+		Updates(map[string]interface{}{"due_date": due, "version": expectedVersion + 1})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	card.DueDate, card.Version = &due, expectedVersion+1
+	return nil
 }
 
 func (r *CardRepository) Archive(id uuid.UUID) error {
@@ -98,12 +239,16 @@ func (r *CardRepository) Archive(id uuid.UUID) error {
 			"is_archived": true,
 			"archived_at": &now,
 			"position":    -1,
+			"version":     gorm.Expr("version + 1"),
 		}).Error
 	})
 }
 
-func (r *CardRepository) Restore(id uuid.UUID, columnID uuid.UUID) error {
-	return r.DB.Transaction(func(tx *gorm.DB) error {
+// Restore unarchives the card at the bottom of columnID, reporting any warn-mode WIP
+// limit it takes the column over.
+func (r *CardRepository) Restore(id uuid.UUID, columnID uuid.UUID) ([]models.WIPExceeded, error) {
+	var exceeded []models.WIPExceeded
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		var card models.Card
 		if err := tx.First(&card, "id = ?", id).Error; err != nil {
 			return err
@@ -113,6 +258,11 @@ func (r *CardRepository) Restore(id uuid.UUID, columnID uuid.UUID) error {
 			return nil // Not archived
 		}
 
+		var err error
+		if exceeded, err = checkWIPLimit(tx, columnID, id, nil); err != nil {
+			return err
+		}
+
 		// Get max position in target column
 		var result struct{ Max float64 }
 		var count int64
@@ -128,8 +278,10 @@ func (r *CardRepository) Restore(id uuid.UUID, columnID uuid.UUID) error {
 			"archived_at": nil,
 			"column_id":   columnID,
 			"position":    pos,
+			"version":     gorm.Expr("version + 1"),
 		}).Error
 	})
+	return exceeded, err
 }
 
 func (r *CardRepository) Delete(id uuid.UUID) error {
@@ -167,9 +319,9 @@ func (r *CardRepository) FindByIDWithChecklists(id uuid.UUID) (*models.Card, err
 		return db.Order("position ASC")
 	}).Preload("Checklists.Items", func(db *gorm.DB) *gorm.DB {
 		return db.Order("position ASC")
-	}).Preload("Comments", func(db *gorm.DB) *gorm.DB {
+	}).Preload("Checklists.Items.Assignee").Preload("Comments", func(db *gorm.DB) *gorm.DB {
 		return db.Order("created_at ASC")
-	}).Preload("Comments.User").Preload("Labels").Preload("Members").Preload("Column").Preload("Attachments").First(&card, "id = ?", id).Error
+	}).Preload("Comments.User").Preload("Comments.Reactions").Preload("Labels").Preload("Members").Preload("Column").Preload("Attachments").First(&card, "id = ?", id).Error
 	return &card, err
 }
 
@@ -233,9 +385,19 @@ func (r *CardRepository) CopyCard(originalCardID, targetColumnID uuid.UUID, targ
 	}
 	if originalCard.IsComplete {
 		newCard.IsComplete = true
+		newCard.CompletedAt = originalCard.CompletedAt
 	}
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		memberIDs := make([]uuid.UUID, len(originalCard.Members))
+		for i, m := range originalCard.Members {
+			memberIDs[i] = m.ID
+		}
+		exceeded, err := checkWIPLimit(tx, targetColumnID, newCard.ID, memberIDs)
+		if err != nil {
+			return err
+		}
+		newCard.WIPExceeded = exceeded
 		if err := tx.Create(&newCard).Error; err != nil {
 			return err
 		}
@@ -260,6 +422,8 @@ func (r *CardRepository) CopyCard(originalCardID, targetColumnID uuid.UUID, targ
 					Title:       item.Title,
 					IsCompleted: item.IsCompleted,
 					Position:    item.Position,
+					AssigneeID:  item.AssigneeID,
+					DueDate:     item.DueDate,
 				}
 				if err := tx.Create(&newItem).Error; err != nil {
 					return err
@@ -313,7 +477,12 @@ func (r *CardRepository) CopyCard(originalCardID, targetColumnID uuid.UUID, targ
 	}
 
 	// Reload to return full object
-	return r.FindByIDWithChecklists(newCard.ID)
+	copied, err := r.FindByIDWithChecklists(newCard.ID)
+	if err != nil {
+		return nil, err
+	}
+	copied.WIPExceeded = newCard.WIPExceeded
+	return copied, nil
 }
 
 func (r *CardRepository) FindTemplatesByBoardID(boardID uuid.UUID) ([]models.Card, error) {
@@ -70,9 +70,15 @@ func (r *CustomFieldRepository) DeleteValue(id uuid.UUID) error {
 	return r.DB.Delete(&models.CardCustomFieldValue{}, id).Error
 }
 
+func (r *CustomFieldRepository) GetValuesByFieldID(fieldID uuid.UUID) ([]models.CardCustomFieldValue, error) {
+	var values []models.CardCustomFieldValue
+	err := r.DB.Where("custom_field_id = ?", fieldID).Order("card_id ASC").Find(&values).Error
+	return values, err
+}
+
 func (r *CustomFieldRepository) GetValuesByCardID(cardID uuid.UUID) ([]models.CardCustomFieldValue, error) {
 	var values []models.CardCustomFieldValue
 	// Preload definition to get Name/Type
-	err := r.DB.Preload("CustomField").Where("card_id = ?", cardID).Find(&values).Error
+	err := r.DB.Preload("CustomField").Preload("ValueUser").Where("card_id = ?", cardID).Find(&values).Error
 	return values, err
 }
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PositionGap is the spacing used when appending to or renumbering a float-ordered list.
+const PositionGap = 16384.0
+
+// MinPositionGap is the smallest distance tolerated between neighbouring positions.
+// Repeated midpoint insertion between the same neighbours halves the gap each time;
+// once it drops below this the list is renumbered before float precision runs out.
+const MinPositionGap = 1e-6
+
+// PositionList identifies one float-ordered list, e.g. the cards of a column.
+type PositionList struct {
+	Name    string    `json:"list"` // "cards", "columns", "checklists", "checklist_items", "custom_fields", "swimlanes"
+	ScopeID uuid.UUID `json:"scope_id"`
+
+	scopeColumn string
+	filter      string
+}
+
+// PositionUpdate is one renumbered row, broadcast to clients after a rebalance.
+type PositionUpdate struct {
+	ID       uuid.UUID `json:"id"`
+	Position float64   `json:"position"`
+}
+
+func CardPositionList(columnID uuid.UUID) PositionList {
+	return PositionList{Name: "cards", ScopeID: columnID, scopeColumn: "column_id", filter: "is_archived = false"}
+}
+
+func ColumnPositionList(boardID uuid.UUID) PositionList {
+	return PositionList{Name: "columns", ScopeID: boardID, scopeColumn: "board_id"}
+}
+
+func ChecklistPositionList(cardID uuid.UUID) PositionList {
+	return PositionList{Name: "checklists", ScopeID: cardID, scopeColumn: "card_id", filter: "deleted_at IS NULL"}
+}
+
+func ChecklistItemPositionList(checklistID uuid.UUID) PositionList {
+	return PositionList{Name: "checklist_items", ScopeID: checklistID, scopeColumn: "checklist_id", filter: "deleted_at IS NULL"}
+}
+
+func CustomFieldPositionList(boardID uuid.UUID) PositionList {
+	return PositionList{Name: "custom_fields", ScopeID: boardID, scopeColumn: "board_id"}
+}
+
+func SwimlanePositionList(boardID uuid.UUID) PositionList {
+	return PositionList{Name: "swimlanes", ScopeID: boardID, scopeColumn: "board_id"}
+}
+
+func (l PositionList) query(tx *gorm.DB) *gorm.DB {
+	q := tx.Table(l.Name).Where(l.scopeColumn+" = ?", l.ScopeID)
+	if l.filter != "" {
+		q = q.Where(l.filter)
+	}
+	return q
+}
+
+// NeedsRebalance reports whether the row at position sits too close to a neighbour.
+func NeedsRebalance(tx *gorm.DB, list PositionList, id uuid.UUID, position float64) (bool, error) {
+	var prev, next []float64
+	if err := list.query(tx).Where("id <> ? AND position <= ?", id, position).
+		Order("position DESC").Limit(1).Pluck("position", &prev).Error; err != nil {
+		return false, err
+	}
+	if err := list.query(tx).Where("id <> ? AND position >= ?", id, position).
+		Order("position ASC").Limit(1).Pluck("position", &next).Error; err != nil {
+		return false, err
+	}
+
+	if len(prev) > 0 && position-prev[0] < MinPositionGap {
+		return true, nil
+	}
+	if len(next) > 0 && next[0]-position < MinPositionGap {
+		return true, nil
+	}
+	return false, nil
+}
+
+// RebalanceList renumbers a list evenly, keeping its current order.
+// Versions are left untouched: a renumber never changes relative order.
+func RebalanceList(tx *gorm.DB, list PositionList) ([]PositionUpdate, error) {
+	var ids []uuid.UUID
+	if err := list.query(tx).Order("position ASC, id ASC").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	updates := make([]PositionUpdate, 0, len(ids))
+	for i, id := range ids {
+		pos := float64(i+1) * PositionGap
+		if err := tx.Table(list.Name).Where("id = ?", id).Update("position", pos).Error; err != nil {
+			return nil, err
+		}
+		updates = append(updates, PositionUpdate{ID: id, Position: pos})
+	}
+	return updates, nil
+}
+
+// RebalanceIfCrowded renumbers the list when the given row ended up too close to a neighbour.
+// It returns nil when no renumbering was needed.
+func RebalanceIfCrowded(tx *gorm.DB, list PositionList, id uuid.UUID, position float64) ([]PositionUpdate, error) {
+	crowded, err := NeedsRebalance(tx, list, id, position)
+	if err != nil || !crowded {
+		return nil, err
+	}
+	return RebalanceList(tx, list)
+}
+
+type RebalanceStats struct {
+	ListsRebalanced int `json:"lists_rebalanced"`
+	RowsUpdated     int `json:"rows_updated"`
+}
+
+// RebalanceAll renumbers every float-ordered list in the database, one transaction per list.
+func RebalanceAll(db *gorm.DB) (RebalanceStats, error) {
+	stats := RebalanceStats{}
+
+	scopes := []struct {
+		table  string
+		column string
+		build  func(uuid.UUID) PositionList
+	}{
+		{"columns", "board_id", ColumnPositionList},
+		{"cards", "column_id", CardPositionList},
+		{"checklists", "card_id", ChecklistPositionList},
+		{"checklist_items", "checklist_id", ChecklistItemPositionList},
+		{"custom_fields", "board_id", CustomFieldPositionList},
+		{"swimlanes", "board_id", SwimlanePositionList},
+	}
+
+	for _, scope := range scopes {
+		var scopeIDs []uuid.UUID
+		if err := db.Table(scope.table).Distinct(scope.column).Pluck(scope.column, &scopeIDs).Error; err != nil {
+			return stats, err
+		}
+		for _, scopeID := range scopeIDs {
+			list := scope.build(scopeID)
+			err := db.Transaction(func(tx *gorm.DB) error {
+				updates, err := RebalanceList(tx, list)
+				if err != nil {
+					return err
+				}
+				stats.RowsUpdated += len(updates)
+				return nil
+			})
+			if err != nil {
+				return stats, err
+			}
+			stats.ListsRebalanced++
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,66 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+
+	"nexus-backend/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupPositionDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE columns (
+		id TEXT PRIMARY KEY,
+		board_id TEXT,
+		name TEXT,
+		position REAL NOT NULL
+	)`).Error)
+	return db
+}
+
+func TestRebalanceIfCrowded_RenumbersKeepingOrder(t *testing.T) {
+	db := setupPositionDB(t)
+	boardID := uuid.New()
+	list := repository.ColumnPositionList(boardID)
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	positions := []float64{1000, 1000.0000001, 2000}
+	for i, id := range ids {
+		require.NoError(t, db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, ?, ?)`,
+			id, boardID, fmt.Sprintf("col-%d", i), positions[i]).Error)
+	}
+
+	// The last column has plenty of room, so nothing changes.
+	updates, err := repository.RebalanceIfCrowded(db, list, ids[2], positions[2])
+	require.NoError(t, err)
+	require.Nil(t, updates)
+
+	updates, err = repository.RebalanceIfCrowded(db, list, ids[1], positions[1])
+	require.NoError(t, err)
+	require.Len(t, updates, 3)
+	for i, u := range updates {
+		require.Equal(t, ids[i], u.ID)
+		require.Equal(t, float64(i+1)*repository.PositionGap, u.Position)
+	}
+
+	var stored []float64
+	require.NoError(t, db.Table("columns").Order("position ASC").Pluck("position", &stored).Error)
+	require.Equal(t, []float64{repository.PositionGap, 2 * repository.PositionGap, 3 * repository.PositionGap}, stored)
+}
+
+func TestRebalanceIfCrowded_ReportsQueryErrors(t *testing.T) {
+	db := setupPositionDB(t)
+	// Cards have no table here, so the neighbour lookup fails.
+	_, err := repository.NeedsRebalance(db, repository.CardPositionList(uuid.New()), uuid.New(), 1000)
+	require.Error(t, err)
+	_, err = repository.RebalanceIfCrowded(db, repository.CardPositionList(uuid.New()), uuid.New(), 1000)
+	require.Error(t, err)
+}
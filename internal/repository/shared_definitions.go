@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"errors"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LinkWorkspaceLabel returns the board's label for a shared workspace label, adding it to
+// the board with the shared name and color when the board has not linked it yet.
+func LinkWorkspaceLabel(tx *gorm.DB, boardID uuid.UUID, shared *models.WorkspaceLabel) (*models.Label, error) {
+	var label models.Label
+	err := tx.Where("board_id = ? AND workspace_label_id = ?", boardID, shared.ID).First(&label).Error
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &label, err
+	}
+	label = models.Label{ID: uuid.New(), BoardID: boardID, Name: shared.Name, Color: shared.Color, WorkspaceLabelID: &shared.ID}
+	return &label, tx.Create(&label).Error
+}
+
+// LinkWorkspaceField returns the board's field for a shared workspace field, appending it
+// to the board's fields when the board has not linked it yet.
+func LinkWorkspaceField(tx *gorm.DB, boardID uuid.UUID, shared *models.WorkspaceField) (*models.CustomField, error) {
+	var field models.CustomField
+	err := tx.Where("board_id = ? AND workspace_field_id = ?", boardID, shared.ID).First(&field).Error
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &field, err
+	}
+	var last struct{ Max float64 }
+	tx.Model(&models.CustomField{}).Where("board_id = ?", boardID).Select("COALESCE(MAX(position), 0) AS max").Scan(&last)
+	field = models.CustomField{
+		ID:               uuid.New(),
+		BoardID:          boardID,
+		Name:             shared.Name,
+		Type:             shared.Type,
+		Options:          shared.Options,
+		Position:         last.Max + PositionGap,
+		WorkspaceFieldID: &shared.ID,
+	}
+	return &field, tx.Create(&field).Error
+}
+
+// carrySharedDefinitions runs when a card moves to another board. Board labels are
+// dropped, but within one workspace shared labels and shared field values are moved onto
+// the target board's copies of the definitions, linking them there if needed.
+func carrySharedDefinitions(tx *gorm.DB, card *models.Card, fromBoardID, toBoardID uuid.UUID) error {
+	var boards []models.Board
+	if err := tx.Select("id", "workspace_id").Where("id IN ?", []uuid.UUID{fromBoardID, toBoardID}).Find(&boards).Error; err != nil {
+		return err
+	}
+	sameWorkspace := len(boards) == 2 && boards[0].WorkspaceID == boards[1].WorkspaceID
+
+	var kept []models.Label
+	if sameWorkspace {
+		var labels []models.Label
+		if err := tx.Joins("JOIN card_labels ON card_labels.label_id = labels.id").
+			Where("card_labels.card_id = ? AND labels.workspace_label_id IS NOT NULL", card.ID).
+			Find(&labels).Error; err != nil {
+			return err
+		}
+		for _, l := range labels {
+			var shared models.WorkspaceLabel
+			if err := tx.First(&shared, "id = ?", *l.WorkspaceLabelID).Error; err != nil {
+				continue // the shared label was deleted
+			}
+			target, err := LinkWorkspaceLabel(tx, toBoardID, &shared)
+			if err != nil {
+				return err
+			}
+			kept = append(kept, *target)
+		}
+	}
+	if err := tx.Model(card).Association("Labels").Clear(); err != nil {
+		return err
+	}
+	if len(kept) > 0 {
+		if err := tx.Model(card).Association("Labels").Append(kept); err != nil {
+			return err
+		}
+	}
+	if !sameWorkspace {
+		return nil
+	}
+
+	var values []struct {
+		CustomFieldID    uuid.UUID
+		WorkspaceFieldID uuid.UUID
+	}
+	if err := tx.Table("card_custom_field_values").
+		Select("card_custom_field_values.custom_field_id, custom_fields.workspace_field_id").
+		Joins("JOIN custom_fields ON custom_fields.id = card_custom_field_values.custom_field_id").
+		Where("card_custom_field_values.card_id = ? AND card_custom_field_values.deleted_at IS NULL AND custom_fields.workspace_field_id IS NOT NULL", card.ID).
+		Scan(&values).Error; err != nil {
+		return err
+	}
+	for _, v := range values {
+		var shared models.WorkspaceField
+		if err := tx.First(&shared, "id = ?", v.WorkspaceFieldID).Error; err != nil {
+			continue
+		}
+		target, err := LinkWorkspaceField(tx, toBoardID, &shared)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.CardCustomFieldValue{}).Where("card_id = ? AND custom_field_id = ?", card.ID, v.CustomFieldID).Update("custom_field_id", target.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
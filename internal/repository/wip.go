@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"fmt"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WIPLimitError is returned when a change would take a block-mode column over its WIP
+// limit; nothing is written.
+type WIPLimitError struct {
+	Exceeded []models.WIPExceeded
+}
+
+func (e *WIPLimitError) Error() string {
+	return fmt.Sprintf("column %s is at its WIP limit of %d", e.Exceeded[0].ColumnID, e.Exceeded[0].Limit)
+}
+
+// checkWIPLimit runs before cardID is added to columnID inside tx. memberIDs are the
+// card's members once added; nil means the members it has now. The column row is locked
+// so concurrent writers count one at a time. Limits the change would exceed are returned
+// as a *WIPLimitError in block mode, or as warnings in warn mode.
+func checkWIPLimit(tx *gorm.DB, columnID, cardID uuid.UUID, memberIDs []uuid.UUID) ([]models.WIPExceeded, error) {
+	var column models.Column
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&column, "id = ?", columnID).Error; err != nil {
+		return nil, err
+	}
+	if column.WIPLimit == nil {
+		return nil, nil
+	}
+	scope, mode := column.WIPScope, column.WIPMode
+	if scope == "" {
+		scope = models.WIPScopeCards
+	}
+	if mode == "" {
+		mode = models.WIPModeWarn
+	}
+
+	active := func() *gorm.DB {
+		return tx.Model(&models.Card{}).
+			Where("cards.column_id = ? AND cards.id <> ? AND cards.is_archived = ? AND cards.is_template = ?", columnID, cardID, false, false)
+	}
+	var exceeded []models.WIPExceeded
+	over := func(count int64, userID *uuid.UUID) {
+		if count+1 > int64(*column.WIPLimit) {
+			exceeded = append(exceeded, models.WIPExceeded{
+				ColumnID: columnID, Limit: *column.WIPLimit, Count: count + 1, Scope: scope, Mode: mode, UserID: userID,
+			})
+		}
+	}
+
+	switch scope {
+	case models.WIPScopeMember:
+		if memberIDs == nil {
+			if err := tx.Table("card_members").Where("card_id = ?", cardID).Pluck("user_id", &memberIDs).Error; err != nil {
+				return nil, err
+			}
+		}
+		for i := range memberIDs {
+			var count int64
+			if err := active().Joins("JOIN card_members ON card_members.card_id = cards.id").
+				Where("card_members.user_id = ?", memberIDs[i]).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			over(count, &memberIDs[i])
+		}
+	default:
+		var count int64
+		if err := active().Count(&count).Error; err != nil {
+			return nil, err
+		}
+		over(count, nil)
+	}
+
+	if len(exceeded) > 0 && mode == models.WIPModeBlock {
+		return nil, &WIPLimitError{Exceeded: exceeded}
+	}
+	return exceeded, nil
+}
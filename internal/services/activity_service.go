@@ -29,7 +29,14 @@ func (s *ActivityService) LogActivity(userID uuid.UUID, boardID uuid.UUID, actio
 		Metadata: datatypes.JSON(metaJSON),
 	}
 
-	return s.DB.Create(&activity).Error
+	if err := s.DB.Create(&activity).Error; err != nil {
+		return err
+	}
+
+	// Card aging: any activity on a card refreshes its last-activity time and lets it go
+	// stale again later. UpdateColumns skips hooks and updated_at; a non-card target matches no row.
+	return s.DB.Model(&models.Card{}).Where("id = ?", targetID).
+		UpdateColumns(map[string]interface{}{"last_activity_at": activity.CreatedAt, "stale_notified_at": nil}).Error
 }
 
 // GetBoardActivity returns recent activity for a board
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrUploadTypeNotAllowed rejects an anonymous upload outside safeUploadTypes.
+var ErrUploadTypeNotAllowed = errors.New("file type is not allowed")
+
+// safeUploadTypes are the files stored under their own extension: the type recorded for them
+// and the prefix http.DetectContentType must report for their content. Office documents sniff
+// as zip archives.
+var safeUploadTypes = map[string]struct{ mime, sniffed string }{
+	".png":  {"image/png", "image/png"},
+	".jpg":  {"image/jpeg", "image/jpeg"},
+	".jpeg": {"image/jpeg", "image/jpeg"},
+	".gif":  {"image/gif", "image/gif"},
+	".webp": {"image/webp", "image/webp"},
+	".mp4":  {"video/mp4", "video/mp4"},
+	".pdf":  {"application/pdf", "application/pdf"},
+	".txt":  {"text/plain", "text/plain"},
+	".log":  {"text/plain", "text/plain"},
+	".csv":  {"text/csv", "text/plain"},
+	".zip":  {"application/zip", "application/zip"},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+	".xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip"},
+}
+
+// safeUploadType returns the extension and type to store a file under when its name and
+// content agree on one of safeUploadTypes.
+func safeUploadType(filename string, data []byte) (string, string, bool) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	t, ok := safeUploadTypes[ext]
+	if !ok || !strings.HasPrefix(http.DetectContentType(data), t.sniffed) {
+		return "", "", false
+	}
+	return ext, t.mime, true
+}
+
+// storeAttachment writes data under uploadDir and records it the same way the upload endpoint
+// does. Only the base name of filename is kept for display; the file itself gets a random name,
+// and anything outside safeUploadTypes is stored as opaque .bin data.
+func storeAttachment(db *gorm.DB, uploadDir string, cardID, userID uuid.UUID, filename string, data []byte) (*models.Attachment, error) {
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return nil, err
+	}
+	name := filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	ext, contentType, ok := safeUploadType(name, data)
+	if !ok {
+		ext, contentType = ".bin", "application/octet-stream"
+	}
+	stored := fmt.Sprintf("%d-%s%s", time.Now().UnixNano(), uuid.New().String(), ext)
+	dst := filepath.Join(uploadDir, stored)
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return nil, err
+	}
+
+	attachment := models.Attachment{
+		CardID:   cardID,
+		UserID:   userID,
+		Filename: name,
+		FilePath: "/uploads/" + stored,
+		FileType: contentType,
+		Size:     int64(len(data)),
+	}
+	if err := db.Create(&attachment).Error; err != nil {
+		os.Remove(dst)
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"nexus-backend/internal/models"
+	"nexus-backend/internal/realtime"
 	"nexus-backend/internal/repository"
 	"time"
 
@@ -26,6 +27,7 @@ type AutomationService struct {
 	Executor            ActionExecutor
 	DB                  *gorm.DB
 	NotificationService *NotificationService
+	Hub                 *realtime.Hub // optional; tells open boards about changes actions make directly
 }
 
 func NewAutomationService(repo repository.AutomationRepository) *AutomationService {
@@ -122,7 +124,10 @@ func (s *AutomationService) executeAction(rule models.AutomationRule, context ma
 			targetColumnID, _ := uuid.Parse(targetColumnIDStr)
 			// Calculate position: Bottom of column
 			pos := s.Executor.GetMaxPosition(targetColumnID) + 65536 // Add gap
-			s.Executor.MoveCard(cardID, targetColumnID, pos)
+			// Automation cannot override column criteria; a blocked move is skipped.
+			if _, err := s.Executor.MoveCard(cardID, targetColumnID, pos); err != nil {
+				log.Printf("[Automation] Rule %s could not move card %s: %v", rule.Name, cardID, err)
+			}
 		}
 
 	case models.ActionAddLabel:
@@ -164,6 +169,33 @@ func (s *AutomationService) executeAction(rule models.AutomationRule, context ma
 		s.notifyAssignees(cardID, params)
 	case models.ActionNotifyAdmins:
 		s.notifyWorkspaceAdmins(cardID, params)
+
+	case models.ActionAddChecklist:
+		// Params: { "template_id": "...", "title": "optional override" }
+		templateIDStr, ok := params["template_id"].(string)
+		if ok && s.DB != nil {
+			templateID, _ := uuid.Parse(templateIDStr)
+			title, _ := params["title"].(string)
+			if _, err := NewChecklistService(s.DB, nil).ApplyTemplate(cardID, templateID, title); err != nil {
+				log.Printf("[Automation] Failed to add checklist from template %s: %v", templateIDStr, err)
+			} else {
+				s.broadcastCardUpdate(cardID)
+			}
+		}
+	}
+}
+
+// broadcastCardUpdate announces a card changed by an action that bypasses the executor.
+func (s *AutomationService) broadcastCardUpdate(cardID uuid.UUID) {
+	if s.Hub == nil || s.DB == nil {
+		return
+	}
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err == nil {
+		s.Hub.BroadcastToRoom(card.Column.BoardID.String(), "CARD_UPDATED", map[string]interface{}{
+			"card_id":  card.ID.String(),
+			"board_id": card.Column.BoardID.String(),
+		})
 	}
 }
 
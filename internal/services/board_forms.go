@@ -0,0 +1,648 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidForm        = errors.New("invalid form")
+	ErrFormClosed         = errors.New("form is not accepting submissions")
+	ErrCaptchaFailed      = errors.New("captcha verification failed")
+	ErrCaptchaUnavailable = errors.New("captcha verification is not configured")
+)
+
+const (
+	MaxFormFields     = 30
+	MaxFormFiles      = 5
+	MaxFormFileBytes  = 5 << 20 // same limit as card attachment uploads
+	maxFormTextLength = 10000
+)
+
+// CaptchaVerifier checks a captcha response token; forms with RequireCaptcha use it.
+type CaptchaVerifier interface {
+	Verify(token, remoteIP string) (bool, error)
+}
+
+// SiteVerifyCaptcha talks to a reCAPTCHA/hCaptcha/Turnstile style "siteverify" endpoint.
+type SiteVerifyCaptcha struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewCaptchaVerifierFromEnv returns nil unless FORM_CAPTCHA_VERIFY_URL and FORM_CAPTCHA_SECRET are set.
+func NewCaptchaVerifierFromEnv() CaptchaVerifier {
+	verifyURL := os.Getenv("FORM_CAPTCHA_VERIFY_URL")
+	secret := os.Getenv("FORM_CAPTCHA_SECRET")
+	if verifyURL == "" || secret == "" {
+		return nil
+	}
+	return &SiteVerifyCaptcha{URL: verifyURL, Secret: secret, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (c *SiteVerifyCaptcha) Verify(token, remoteIP string) (bool, error) {
+	resp, err := c.Client.PostForm(c.URL, url.Values{"secret": {c.Secret}, "response": {token}, "remoteip": {remoteIP}})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, err
+	}
+	return body.Success, nil
+}
+
+// BoardFormService manages public intake forms and turns submissions into cards.
+type BoardFormService struct {
+	DB              *gorm.DB
+	Cards           *CardService
+	Fields          *CustomFieldService
+	ActivityService *ActivityService
+	Captcha         CaptchaVerifier
+	UploadDir       string
+}
+
+func NewBoardFormService(db *gorm.DB, cards *CardService, fields *CustomFieldService, activityService *ActivityService, captcha CaptchaVerifier) *BoardFormService {
+	return &BoardFormService{
+		DB:              db,
+		Cards:           cards,
+		Fields:          fields,
+		ActivityService: activityService,
+		Captcha:         captcha,
+		UploadDir:       "./uploads",
+	}
+}
+
+type BoardFormFieldInput struct {
+	Label         string                 `json:"label"`
+	Target        models.FormFieldTarget `json:"target"`
+	CustomFieldID *uuid.UUID             `json:"custom_field_id"`
+	LabelIDs      []uuid.UUID            `json:"label_ids"`
+	Required      bool                   `json:"required"`
+}
+
+// BoardFormInput creates or patches a form; nil fields are left unchanged, and Fields replaces
+// all fields when present.
+type BoardFormInput struct {
+	Title             *string                `json:"title"`
+	Description       *string                `json:"description"`
+	ColumnID          *uuid.UUID             `json:"column_id"`
+	Enabled           *bool                  `json:"enabled"`
+	AllowAttachments  *bool                  `json:"allow_attachments"`
+	RequireCaptcha    *bool                  `json:"require_captcha"`
+	TriggerAutomation *bool                  `json:"trigger_automation"`
+	Fields            *[]BoardFormFieldInput `json:"fields"`
+}
+
+// FormFieldError explains why one submitted value was rejected.
+type FormFieldError struct {
+	FieldID string `json:"field_id"`
+	Message string `json:"message"`
+}
+
+type FormValidationError struct {
+	Fields []FormFieldError
+}
+
+func (e *FormValidationError) Error() string {
+	return "submission has invalid fields"
+}
+
+func (s *BoardFormService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+func (s *BoardFormService) loadForm(query string, arg interface{}) (*models.BoardForm, error) {
+	var form models.BoardForm
+	err := s.DB.Preload("Fields", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where(query, arg).First(&form).Error
+	if err != nil {
+		return nil, err
+	}
+	return &form, nil
+}
+
+// GetFormForUser loads a form the user can manage.
+func (s *BoardFormService) GetFormForUser(formID, userID uuid.UUID) (*models.BoardForm, error) {
+	form, err := s.loadForm("id = ?", formID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanAccessBoard(userID, form.BoardID) {
+		return nil, ErrBoardAccessDenied
+	}
+	return form, nil
+}
+
+func (s *BoardFormService) ListForms(boardID uuid.UUID) ([]models.BoardForm, error) {
+	var forms []models.BoardForm
+	err := s.DB.Preload("Fields", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("board_id = ?", boardID).Order("created_at ASC").Find(&forms).Error
+	return forms, err
+}
+
+// CreateForm saves a new form. Forms start enabled and fire automation unless told otherwise.
+func (s *BoardFormService) CreateForm(boardID, actorID uuid.UUID, in BoardFormInput) (*models.BoardForm, error) {
+	if in.Title == nil || in.ColumnID == nil {
+		return nil, fmt.Errorf("%w: title and column_id are required", ErrInvalidForm)
+	}
+	form := models.BoardForm{
+		BoardID:           boardID,
+		Token:             newInboxToken(),
+		Enabled:           true,
+		TriggerAutomation: true,
+		CreatedBy:         actorID,
+	}
+	fields := []models.BoardFormField{}
+	if err := s.applyFormInput(&form, &fields, in); err != nil {
+		return nil, err
+	}
+	form.Fields = fields
+	if err := s.DB.Create(&form).Error; err != nil {
+		return nil, err
+	}
+	return &form, nil
+}
+
+func (s *BoardFormService) UpdateForm(form *models.BoardForm, in BoardFormInput) (*models.BoardForm, error) {
+	var fields []models.BoardFormField
+	if err := s.applyFormInput(form, &fields, in); err != nil {
+		return nil, err
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(form).Select("column_id", "title", "description", "enabled", "allow_attachments", "require_captcha", "trigger_automation").
+			Updates(form).Error; err != nil {
+			return err
+		}
+		if in.Fields == nil {
+			return nil
+		}
+		if err := tx.Where("form_id = ?", form.ID).Delete(&models.BoardFormField{}).Error; err != nil {
+			return err
+		}
+		for i := range fields {
+			fields[i].FormID = form.ID
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return tx.Create(&fields).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.loadForm("id = ?", form.ID)
+}
+
+func (s *BoardFormService) DeleteForm(form *models.BoardForm) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("form_id = ?", form.ID).Delete(&models.BoardFormField{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(form).Error
+	})
+}
+
+// RotateToken replaces the public token so the old link stops working.
+func (s *BoardFormService) RotateToken(form *models.BoardForm) (*models.BoardForm, error) {
+	if err := s.DB.Model(form).Update("token", newInboxToken()).Error; err != nil {
+		return nil, err
+	}
+	return s.loadForm("id = ?", form.ID)
+}
+
+// applyFormInput validates in against the form's board and copies it onto form. When
+// in.Fields is set, the validated fields are written to fields.
+func (s *BoardFormService) applyFormInput(form *models.BoardForm, fields *[]models.BoardFormField, in BoardFormInput) error {
+	if in.Title != nil {
+		title := strings.TrimSpace(*in.Title)
+		if title == "" || len(title) > 200 {
+			return fmt.Errorf("%w: title must be 1-200 characters", ErrInvalidForm)
+		}
+		form.Title = title
+	}
+	if in.Description != nil {
+		form.Description = strings.TrimSpace(*in.Description)
+	}
+	if in.ColumnID != nil {
+		var count int64
+		s.DB.Model(&models.Column{}).Where("id = ? AND board_id = ?", *in.ColumnID, form.BoardID).Count(&count)
+		if count == 0 {
+			return fmt.Errorf("%w: column does not belong to this board", ErrInvalidForm)
+		}
+		form.ColumnID = *in.ColumnID
+	}
+	for _, flag := range []struct {
+		in  *bool
+		out *bool
+	}{
+		{in.Enabled, &form.Enabled},
+		{in.AllowAttachments, &form.AllowAttachments},
+		{in.RequireCaptcha, &form.RequireCaptcha},
+		{in.TriggerAutomation, &form.TriggerAutomation},
+	} {
+		if flag.in != nil {
+			*flag.out = *flag.in
+		}
+	}
+	if in.Fields == nil {
+		return nil
+	}
+
+	if len(*in.Fields) > MaxFormFields {
+		return fmt.Errorf("%w: at most %d fields", ErrInvalidForm, MaxFormFields)
+	}
+	titleFields := 0
+	for i, f := range *in.Fields {
+		label := strings.TrimSpace(f.Label)
+		if label == "" || len(label) > 200 {
+			return fmt.Errorf("%w: field %d needs a label of 1-200 characters", ErrInvalidForm, i+1)
+		}
+		field := models.BoardFormField{
+			Label:    label,
+			Target:   f.Target,
+			Required: f.Required,
+			Position: float64(i+1) * repository.PositionGap,
+		}
+		switch f.Target {
+		case models.FormTargetTitle:
+			titleFields++
+		case models.FormTargetDescription:
+		case models.FormTargetCustomField:
+			var count int64
+			if f.CustomFieldID != nil {
+				s.DB.Model(&models.CustomField{}).Where("id = ? AND board_id = ?", *f.CustomFieldID, form.BoardID).Count(&count)
+			}
+			if count == 0 {
+				return fmt.Errorf("%w: field %d must reference a custom field on this board", ErrInvalidForm, i+1)
+			}
+			// Offering a member picker would list the workspace's people to anyone with the link.
+			var cf models.CustomField
+			if err := s.DB.Select("type").First(&cf, "id = ?", *f.CustomFieldID).Error; err == nil {
+				switch cf.Type {
+				case models.FieldTypeUser:
+					return fmt.Errorf("%w: field %d: user fields cannot be used on public forms", ErrInvalidForm, i+1)
+				case models.FieldTypeFormula:
+					return fmt.Errorf("%w: field %d: formula fields are computed and cannot be filled in", ErrInvalidForm, i+1)
+				}
+			}
+			field.CustomFieldID = f.CustomFieldID
+		case models.FormTargetLabels:
+			ids := uniqueUUIDs(f.LabelIDs)
+			var count int64
+			if len(ids) > 0 {
+				s.DB.Model(&models.Label{}).Where("id IN ? AND board_id = ?", ids, form.BoardID).Count(&count)
+			}
+			if len(ids) == 0 || int(count) != len(ids) {
+				return fmt.Errorf("%w: field %d must list labels from this board", ErrInvalidForm, i+1)
+			}
+			for _, id := range ids {
+				field.LabelIDs = append(field.LabelIDs, id.String())
+			}
+		default:
+			return fmt.Errorf("%w: field %d has unknown target %q", ErrInvalidForm, i+1, f.Target)
+		}
+		*fields = append(*fields, field)
+	}
+	if titleFields > 1 {
+		return fmt.Errorf("%w: only one field can map to the title", ErrInvalidForm)
+	}
+	return nil
+}
+
+// PublicForm is what anonymous visitors see: no board, column or token details.
+type PublicForm struct {
+	Title            string            `json:"title"`
+	Description      string            `json:"description"`
+	AllowAttachments bool              `json:"allow_attachments"`
+	RequireCaptcha   bool              `json:"require_captcha"`
+	Fields           []PublicFormField `json:"fields"`
+}
+
+type PublicFormField struct {
+	ID       uuid.UUID          `json:"id"`
+	Label    string             `json:"label"`
+	Kind     string             `json:"kind"` // text, textarea, labels, or the custom field type
+	Required bool               `json:"required"`
+	Options  []PublicFormOption `json:"options,omitempty"`
+}
+
+type PublicFormOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// openForm loads an enabled form by its public token.
+func (s *BoardFormService) openForm(token string) (*models.BoardForm, error) {
+	form, err := s.loadForm("token = ?", strings.ToLower(strings.TrimSpace(token)))
+	if err != nil {
+		return nil, err
+	}
+	if !form.Enabled {
+		return nil, ErrFormClosed
+	}
+	return form, nil
+}
+
+func (s *BoardFormService) GetPublicForm(token string) (*PublicForm, error) {
+	form, err := s.openForm(token)
+	if err != nil {
+		return nil, err
+	}
+	public := PublicForm{
+		Title:            form.Title,
+		Description:      form.Description,
+		AllowAttachments: form.AllowAttachments,
+		RequireCaptcha:   form.RequireCaptcha,
+		Fields:           []PublicFormField{},
+	}
+	for _, f := range form.Fields {
+		pf := PublicFormField{ID: f.ID, Label: f.Label, Required: f.Required}
+		switch f.Target {
+		case models.FormTargetTitle:
+			pf.Kind = "text"
+		case models.FormTargetDescription:
+			pf.Kind = "textarea"
+		case models.FormTargetLabels:
+			pf.Kind = "labels"
+			var labels []models.Label
+			s.DB.Where("id IN ?", []string(f.LabelIDs)).Order("name ASC").Find(&labels)
+			for _, l := range labels {
+				pf.Options = append(pf.Options, PublicFormOption{Value: l.ID.String(), Label: l.Name})
+			}
+		case models.FormTargetCustomField:
+			cf, err := s.Fields.Repo.GetFieldByID(*f.CustomFieldID)
+			if err != nil {
+				continue
+			}
+			pf.Kind = string(cf.Type)
+			for _, opt := range cf.Options {
+				pf.Options = append(pf.Options, PublicFormOption{Value: opt, Label: opt})
+			}
+		}
+		public.Fields = append(public.Fields, pf)
+	}
+	return &public, nil
+}
+
+// FormSubmission is one anonymous submission. Values are keyed by form field ID.
+type FormSubmission struct {
+	Values       map[string]interface{}
+	Honeypot     string // must stay empty; bots that fill every input are silently dropped
+	CaptchaToken string
+	RemoteIP     string
+	Files        []FormFile
+}
+
+type FormFile struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type FormSubmissionResult struct {
+	Form    *models.BoardForm
+	Card    *models.Card
+	Dropped bool // honeypot triggered; nothing was created
+}
+
+// Submit validates a submission and creates the card with its labels, custom field values and
+// files. Activity and attachments are credited to the form's creator.
+func (s *BoardFormService) Submit(token string, sub FormSubmission) (*FormSubmissionResult, error) {
+	form, err := s.openForm(token)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(sub.Honeypot) != "" {
+		return &FormSubmissionResult{Form: form, Dropped: true}, nil
+	}
+	if form.RequireCaptcha {
+		if s.Captcha == nil {
+			return nil, ErrCaptchaUnavailable
+		}
+		ok, err := s.Captcha.Verify(sub.CaptchaToken, sub.RemoteIP)
+		if err != nil {
+			log.Printf("[BoardForms] captcha verification error: %v", err)
+		}
+		if err != nil || !ok {
+			return nil, ErrCaptchaFailed
+		}
+	}
+
+	title, description, labelIDs, values, verr := s.mapSubmission(form, sub)
+	if verr != nil {
+		return nil, verr
+	}
+
+	card := &models.Card{
+		Title:       title,
+		Description: description,
+		ColumnID:    form.ColumnID,
+		Position:    s.Cards.GetMaxPosition(form.ColumnID),
+	}
+	if err := s.Cards.Repo.Create(card); err != nil {
+		return nil, err
+	}
+	for _, labelID := range labelIDs {
+		if form.TriggerAutomation {
+			err = s.Cards.AddLabel(card.ID, labelID)
+		} else {
+			err = s.Cards.Repo.AddLabel(card.ID, labelID)
+		}
+		if err != nil {
+			log.Printf("[BoardForms] failed adding label %s to card %s: %v", labelID, card.ID, err)
+		}
+	}
+	for _, v := range values {
+		v.CardID = card.ID
+		if err := s.Fields.Repo.SetValue(v); err != nil {
+			log.Printf("[BoardForms] failed setting field %s on card %s: %v", v.CustomFieldID, card.ID, err)
+		}
+	}
+	for _, f := range sub.Files {
+		if _, err := storeAttachment(s.DB, s.UploadDir, card.ID, form.CreatedBy, f.Filename, f.Data); err != nil {
+			log.Printf("[BoardForms] failed saving attachment %q for card %s: %v", f.Filename, card.ID, err)
+		}
+	}
+
+	if s.ActivityService != nil {
+		s.ActivityService.LogActivity(form.CreatedBy, form.BoardID, "created_card", card.ID, map[string]interface{}{
+			"card_title": card.Title,
+			"column_id":  form.ColumnID,
+			"source":     "form",
+			"form_id":    form.ID,
+		})
+	}
+	if form.TriggerAutomation && s.Cards.AutomationService != nil {
+		s.Cards.AutomationService.EvaluateRules(form.BoardID, models.TriggerCardCreated, map[string]interface{}{
+			"card_id":   card.ID.String(),
+			"column_id": form.ColumnID.String(),
+			"form_id":   form.ID.String(),
+		})
+	}
+
+	if full, err := s.Cards.GetCardByID(card.ID); err == nil {
+		full.WIPExceeded = card.WIPExceeded
+		card = full
+	} else {
+		card.Column = models.Column{ID: form.ColumnID, BoardID: form.BoardID}
+	}
+	return &FormSubmissionResult{Form: form, Card: card}, nil
+}
+
+// mapSubmission checks every form field against the submitted values and works out the card
+// title, description, labels and custom field values. All problems are reported together.
+func (s *BoardFormService) mapSubmission(form *models.BoardForm, sub FormSubmission) (string, string, []uuid.UUID, []*models.CardCustomFieldValue, error) {
+	var (
+		problems     []FormFieldError
+		title        string
+		descriptions []string
+		labelIDs     []uuid.UUID
+		values       []*models.CardCustomFieldValue
+	)
+	fail := func(id string, msg string) {
+		problems = append(problems, FormFieldError{FieldID: id, Message: msg})
+	}
+
+	for _, f := range form.Fields {
+		id := f.ID.String()
+		raw, present := sub.Values[id]
+		if text, ok := raw.(string); ok && strings.TrimSpace(text) == "" {
+			present = false
+		}
+		if !present || raw == nil {
+			if f.Required {
+				fail(id, "is required")
+			}
+			continue
+		}
+
+		switch f.Target {
+		case models.FormTargetTitle, models.FormTargetDescription:
+			text, ok := raw.(string)
+			text = strings.TrimSpace(text)
+			limit := maxFormTextLength
+			if f.Target == models.FormTargetTitle {
+				limit = 200
+			}
+			if !ok || len([]rune(text)) > limit {
+				fail(id, fmt.Sprintf("must be text of at most %d characters", limit))
+				continue
+			}
+			if f.Target == models.FormTargetTitle {
+				title = text
+			} else {
+				descriptions = append(descriptions, "**"+f.Label+"**\n\n"+text)
+			}
+		case models.FormTargetLabels:
+			chosen, ok := stringList(raw)
+			allowed := map[string]bool{}
+			for _, l := range f.LabelIDs {
+				allowed[l] = true
+			}
+			for _, c := range chosen {
+				if !allowed[c] {
+					ok = false
+				}
+			}
+			if !ok {
+				fail(id, "must be one or more of the offered labels")
+				continue
+			}
+			for _, c := range chosen {
+				labelIDs = append(labelIDs, uuid.MustParse(c))
+			}
+		case models.FormTargetCustomField:
+			cf, err := s.Fields.Repo.GetFieldByID(*f.CustomFieldID)
+			if err != nil {
+				fail(id, "is no longer available")
+				continue
+			}
+			val, err := buildCardFieldValue(cf, uuid.Nil, raw)
+			if err == nil && cf.Type == models.FieldTypeDropdown && !containsString(cf.Options, val.ValueText) {
+				err = errors.New("must be one of the offered options")
+			}
+			if err != nil {
+				fail(id, err.Error())
+				continue
+			}
+			values = append(values, val)
+		}
+	}
+
+	if len(sub.Files) > 0 && !form.AllowAttachments {
+		fail("files", "this form does not accept files")
+	}
+	if len(sub.Files) > MaxFormFiles {
+		fail("files", fmt.Sprintf("at most %d files", MaxFormFiles))
+	}
+	for _, f := range sub.Files {
+		if len(f.Data) > MaxFormFileBytes {
+			fail("files", fmt.Sprintf("%s exceeds the 5MB limit", f.Filename))
+		}
+		if _, _, ok := safeUploadType(f.Filename, f.Data); !ok {
+			fail("files", fmt.Sprintf("%s: %s", f.Filename, ErrUploadTypeNotAllowed))
+		}
+	}
+	if len(problems) > 0 {
+		return "", "", nil, nil, &FormValidationError{Fields: problems}
+	}
+
+	if title == "" {
+		title = form.Title + " submission"
+		if runes := []rune(title); len(runes) > 200 {
+			title = string(runes[:200])
+		}
+	}
+	return title, strings.Join(descriptions, "\n\n"), uniqueUUIDs(labelIDs), values, nil
+}
+
+// stringList accepts a single string or a JSON array of strings.
+func stringList(raw interface{}) ([]string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, len(out) > 0
+	case []string:
+		return v, len(v) > 0
+	}
+	return nil, false
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type stubCaptcha struct{ ok bool }
+
+func (s stubCaptcha) Verify(token, remoteIP string) (bool, error) { return s.ok && token != "", nil }
+
+func TestBoardForms_SubmissionsBecomeCards(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT, PRIMARY KEY (card_id, label_id))`,
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (
+			id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+			value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+		`CREATE TABLE attachments (
+			id TEXT PRIMARY KEY, card_id TEXT NOT NULL, user_id TEXT NOT NULL, filename TEXT NOT NULL, file_path TEXT NOT NULL,
+			file_type TEXT NOT NULL, size INTEGER NOT NULL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+		`CREATE TABLE board_forms (
+			id TEXT PRIMARY KEY, board_id TEXT NOT NULL, column_id TEXT NOT NULL, title TEXT NOT NULL, description TEXT,
+			token TEXT NOT NULL UNIQUE, enabled INTEGER, allow_attachments INTEGER, require_captcha INTEGER,
+			trigger_automation INTEGER, created_by TEXT NOT NULL, created_at DATETIME, updated_at DATETIME
+		)`,
+		`CREATE TABLE board_form_fields (
+			id TEXT PRIMARY KEY, form_id TEXT NOT NULL, label TEXT NOT NULL, target TEXT NOT NULL, custom_field_id TEXT,
+			label_ids TEXT, required INTEGER, position REAL
+		)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner := uuid.New()
+	wsID, boardID, colID := uuid.New(), uuid.New(), uuid.New()
+	bugLabel, otherBoardLabel := uuid.New(), uuid.New()
+	severity, foreignField := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Support')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Triage', 1)`, colID, boardID)
+	db.Exec(`INSERT INTO labels (id, board_id, name, color) VALUES (?, ?, 'bug', '#f00')`, bugLabel, boardID)
+	db.Exec(`INSERT INTO labels (id, board_id, name, color) VALUES (?, ?, 'elsewhere', '#0f0')`, otherBoardLabel, uuid.New())
+	db.Exec(`INSERT INTO custom_fields (id, board_id, name, type, options) VALUES (?, ?, 'Severity', 'dropdown', '{low,high}')`, severity, boardID)
+	db.Exec(`INSERT INTO custom_fields (id, board_id, name, type) VALUES (?, ?, 'Foreign', 'text')`, foreignField, uuid.New())
+
+	cards := services.NewCardService(repository.NewCardRepository(db), nil)
+	fields := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	svc := services.NewBoardFormService(db, cards, fields, services.NewActivityService(db), nil)
+	svc.UploadDir = t.TempDir()
+
+	title, col := "Report a bug", colID
+	_, err := svc.CreateForm(boardID, owner, services.BoardFormInput{
+		Title: &title, ColumnID: &col,
+		Fields: &[]services.BoardFormFieldInput{{Label: "Labels", Target: models.FormTargetLabels, LabelIDs: []uuid.UUID{otherBoardLabel}}},
+	})
+	require.ErrorIs(t, err, services.ErrInvalidForm, "labels must belong to the board")
+	_, err = svc.CreateForm(boardID, owner, services.BoardFormInput{
+		Title: &title, ColumnID: &col,
+		Fields: &[]services.BoardFormFieldInput{{Label: "X", Target: models.FormTargetCustomField, CustomFieldID: &foreignField}},
+	})
+	require.ErrorIs(t, err, services.ErrInvalidForm, "custom fields must belong to the board")
+
+	allowFiles := true
+	form, err := svc.CreateForm(boardID, owner, services.BoardFormInput{
+		Title: &title, ColumnID: &col, AllowAttachments: &allowFiles,
+		Fields: &[]services.BoardFormFieldInput{
+			{Label: "Summary", Target: models.FormTargetTitle, Required: true},
+			{Label: "Steps", Target: models.FormTargetDescription},
+			{Label: "Kind", Target: models.FormTargetLabels, LabelIDs: []uuid.UUID{bugLabel}},
+			{Label: "Severity", Target: models.FormTargetCustomField, CustomFieldID: &severity, Required: true},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, form.Enabled)
+	require.Len(t, form.Fields, 4)
+	summaryID, stepsID, kindID, severityID := form.Fields[0].ID.String(), form.Fields[1].ID.String(), form.Fields[2].ID.String(), form.Fields[3].ID.String()
+
+	public, err := svc.GetPublicForm(form.Token)
+	require.NoError(t, err)
+	require.Equal(t, "dropdown", public.Fields[3].Kind)
+	require.Equal(t, []services.PublicFormOption{{Value: bugLabel.String(), Label: "bug"}}, public.Fields[2].Options)
+
+	_, err = svc.Submit(form.Token, services.FormSubmission{Values: map[string]interface{}{severityID: "urgent"}})
+	var invalid *services.FormValidationError
+	require.ErrorAs(t, err, &invalid)
+	require.ElementsMatch(t, []services.FormFieldError{
+		{FieldID: summaryID, Message: "is required"},
+		{FieldID: severityID, Message: "must be one of the offered options"},
+	}, invalid.Fields)
+
+	// Anything that could render as a page on the app's origin is refused.
+	values := map[string]interface{}{summaryID: "XSS", severityID: "high"}
+	for _, f := range []services.FormFile{
+		{Filename: "page.html", ContentType: "text/html", Data: []byte("<script>alert(1)</script>")},
+		{Filename: "logo.svg", ContentType: "image/svg+xml", Data: []byte("<svg onload=alert(1)></svg>")},
+		{Filename: "fake.png", ContentType: "image/png", Data: []byte("<html><script>alert(1)</script>")},
+	} {
+		_, err = svc.Submit(form.Token, services.FormSubmission{Values: values, Files: []services.FormFile{f}})
+		require.ErrorAs(t, err, &invalid, f.Filename)
+		require.Equal(t, "files", invalid.Fields[0].FieldID)
+	}
+
+	result, err := svc.Submit(form.Token, services.FormSubmission{
+		Values: map[string]interface{}{
+			summaryID: "Login button broken", stepsID: "Click it", kindID: []interface{}{bugLabel.String()}, severityID: "high",
+		},
+		Files: []services.FormFile{{Filename: "screen.png", ContentType: "image/png", Data: []byte("\x89PNG\r\n\x1a\n")}},
+	})
+	require.NoError(t, err)
+	require.False(t, result.Dropped)
+	require.Equal(t, "Login button broken", result.Card.Title)
+	require.Equal(t, "**Steps**\n\nClick it", result.Card.Description)
+	require.Equal(t, colID, result.Card.ColumnID)
+	var labelCount int64
+	db.Table("card_labels").Where("card_id = ? AND label_id = ?", result.Card.ID, bugLabel).Count(&labelCount)
+	require.Equal(t, int64(1), labelCount)
+
+	var value models.CardCustomFieldValue
+	require.NoError(t, db.Where("card_id = ? AND custom_field_id = ?", result.Card.ID, severity).First(&value).Error)
+	require.Equal(t, "high", value.ValueText)
+	var attachment models.Attachment
+	require.NoError(t, db.First(&attachment, "card_id = ?", result.Card.ID).Error)
+	require.Equal(t, owner, attachment.UserID)
+	require.Equal(t, "screen.png", attachment.Filename)
+	require.Equal(t, "image/png", attachment.FileType)
+	require.NotContains(t, attachment.FilePath, "screen")
+	require.True(t, strings.HasSuffix(attachment.FilePath, ".png"))
+
+	// Honeypot submissions are accepted silently but create nothing.
+	var before, after int64
+	db.Model(&models.Card{}).Count(&before)
+	dropped, err := svc.Submit(form.Token, services.FormSubmission{Values: map[string]interface{}{summaryID: "spam"}, Honeypot: "http://spam.test"})
+	require.NoError(t, err)
+	require.True(t, dropped.Dropped)
+	db.Model(&models.Card{}).Count(&after)
+	require.Equal(t, before, after)
+
+	captcha := true
+	_, err = svc.UpdateForm(form, services.BoardFormInput{RequireCaptcha: &captcha})
+	require.NoError(t, err)
+	ok := map[string]interface{}{summaryID: "Another", severityID: "low"}
+	_, err = svc.Submit(form.Token, services.FormSubmission{Values: ok})
+	require.ErrorIs(t, err, services.ErrCaptchaUnavailable)
+	svc.Captcha = stubCaptcha{ok: true}
+	_, err = svc.Submit(form.Token, services.FormSubmission{Values: ok})
+	require.ErrorIs(t, err, services.ErrCaptchaFailed)
+	_, err = svc.Submit(form.Token, services.FormSubmission{Values: ok, CaptchaToken: "solved"})
+	require.NoError(t, err)
+
+	disabled := false
+	_, err = svc.UpdateForm(form, services.BoardFormInput{Enabled: &disabled})
+	require.NoError(t, err)
+	_, err = svc.Submit(form.Token, services.FormSubmission{Values: ok, CaptchaToken: "solved"})
+	require.ErrorIs(t, err, services.ErrFormClosed)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoBoardInbox          = errors.New("no board inbox address in recipients")
+	ErrBoardInboxDisabled    = errors.New("board inbox is disabled")
+	ErrInboxColumnNotOnBoard = errors.New("column does not belong to this board")
+	ErrDuplicateInboundEmail = errors.New("message was already imported")
+)
+
+const boardInboxPrefix = "board+"
+
+// BoardInboxService turns mail sent to a board's secret address into cards.
+type BoardInboxService struct {
+	DB              *gorm.DB
+	Cards           *CardService
+	ActivityService *ActivityService
+	Domain          string // INBOUND_EMAIL_DOMAIN; addresses are board+<token>@Domain
+	UploadDir       string // where email attachments are written, served under /uploads
+}
+
+func NewBoardInboxService(db *gorm.DB, cards *CardService, activityService *ActivityService, domain string) *BoardInboxService {
+	return &BoardInboxService{
+		DB:              db,
+		Cards:           cards,
+		ActivityService: activityService,
+		Domain:          strings.ToLower(strings.TrimSpace(domain)),
+		UploadDir:       "./uploads",
+	}
+}
+
+// InboundCardResult describes a card created from an email.
+type InboundCardResult struct {
+	Card        *models.Card
+	ActorID     uuid.UUID // the sender if they are a board member, otherwise the inbox creator
+	KnownSender bool
+}
+
+func (s *BoardInboxService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+func newInboxToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return strings.ToLower(replyTokenEncoding.EncodeToString(b))
+}
+
+func (s *BoardInboxService) withAddress(inbox *models.BoardInbox) *models.BoardInbox {
+	if s.Domain != "" {
+		inbox.Address = boardInboxPrefix + inbox.Token + "@" + s.Domain
+	}
+	return inbox
+}
+
+func (s *BoardInboxService) GetInbox(boardID uuid.UUID) (*models.BoardInbox, error) {
+	var inbox models.BoardInbox
+	if err := s.DB.First(&inbox, "board_id = ?", boardID).Error; err != nil {
+		return nil, err
+	}
+	return s.withAddress(&inbox), nil
+}
+
+// ConfigureInbox creates the board's inbox on first use or updates its column and enabled flag.
+func (s *BoardInboxService) ConfigureInbox(boardID, actorID, columnID uuid.UUID, enabled *bool) (*models.BoardInbox, error) {
+	var count int64
+	s.DB.Model(&models.Column{}).Where("id = ? AND board_id = ?", columnID, boardID).Count(&count)
+	if count == 0 {
+		return nil, ErrInboxColumnNotOnBoard
+	}
+
+	inbox, err := s.GetInbox(boardID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		created := models.BoardInbox{BoardID: boardID, ColumnID: columnID, Token: newInboxToken(), Enabled: true, CreatedBy: actorID}
+		if enabled != nil {
+			created.Enabled = *enabled
+		}
+		if err := s.DB.Create(&created).Error; err != nil {
+			return nil, err
+		}
+		return s.withAddress(&created), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"column_id": columnID}
+	if enabled != nil {
+		updates["enabled"] = *enabled
+	}
+	if err := s.DB.Model(inbox).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return s.GetInbox(boardID)
+}
+
+// RotateInbox replaces the secret token; the old address stops working immediately.
+func (s *BoardInboxService) RotateInbox(boardID uuid.UUID) (*models.BoardInbox, error) {
+	inbox, err := s.GetInbox(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.Model(inbox).Update("token", newInboxToken()).Error; err != nil {
+		return nil, err
+	}
+	return s.GetInbox(boardID)
+}
+
+func (s *BoardInboxService) DeleteInbox(boardID uuid.UUID) error {
+	result := s.DB.Where("board_id = ?", boardID).Delete(&models.BoardInbox{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ProcessMessage imports mail addressed to a board inbox. It returns ErrNoBoardInbox when
+// no recipient is a board address, so callers can try other inbound handlers.
+func (s *BoardInboxService) ProcessMessage(raw []byte, envelopeRecipients []string) (*InboundCardResult, error) {
+	msg, err := parseInboundEmail(raw)
+	if err != nil {
+		return nil, err
+	}
+	if s.Domain == "" {
+		return nil, ErrNoBoardInbox
+	}
+
+	var inbox *models.BoardInbox
+	for _, addr := range emailRecipients(msg.Header, envelopeRecipients) {
+		at := strings.LastIndex(addr, "@")
+		local := strings.ToLower(addr[:max(at, 0)])
+		if at < 0 || !strings.EqualFold(addr[at+1:], s.Domain) || !strings.HasPrefix(local, boardInboxPrefix) {
+			continue
+		}
+		var found models.BoardInbox
+		if err := s.DB.First(&found, "token = ?", strings.TrimPrefix(local, boardInboxPrefix)).Error; err == nil {
+			inbox = &found
+			break
+		}
+	}
+	if inbox == nil {
+		return nil, ErrNoBoardInbox
+	}
+	if !inbox.Enabled {
+		return nil, ErrBoardInboxDisabled
+	}
+	return s.importMessage(inbox, msg, raw)
+}
+
+// ImportForBoard imports an uploaded message into the board's inbox column regardless of
+// its recipients or whether the address is enabled; the uploader has already been authorized.
+func (s *BoardInboxService) ImportForBoard(boardID uuid.UUID, raw []byte) (*InboundCardResult, error) {
+	inbox, err := s.GetInbox(boardID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := parseInboundEmail(raw)
+	if err != nil {
+		return nil, err
+	}
+	return s.importMessage(inbox, msg, raw)
+}
+
+func parseInboundEmail(raw []byte) (*mail.Message, error) {
+	if len(raw) > MaxInboundEmailBytes {
+		return nil, ErrInboundEmailTooLarge
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEmail, err)
+	}
+	if isAutoGeneratedEmail(msg.Header) {
+		return nil, ErrInboundAutoReply
+	}
+	return msg, nil
+}
+
+func (s *BoardInboxService) importMessage(inbox *models.BoardInbox, msg *mail.Message, raw []byte) (*InboundCardResult, error) {
+	var content emailContent
+	if err := readEmailContent(textproto.MIMEHeader(msg.Header), msg.Body, &content); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEmail, err)
+	}
+
+	// Claim the Message-ID first; the unique index settles concurrent redeliveries.
+	messageID := strings.TrimSpace(msg.Header.Get("Message-ID"))
+	if messageID == "" {
+		sum := sha256.Sum256(raw)
+		messageID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	if len(messageID) > 255 {
+		messageID = messageID[:255]
+	}
+	var seen int64
+	s.DB.Model(&models.InboundEmailMessage{}).Where("board_id = ? AND message_id = ?", inbox.BoardID, messageID).Count(&seen)
+	if seen > 0 {
+		return nil, ErrDuplicateInboundEmail
+	}
+	record := models.InboundEmailMessage{BoardID: inbox.BoardID, MessageID: messageID}
+	if err := s.DB.Create(&record).Error; err != nil {
+		return nil, ErrDuplicateInboundEmail
+	}
+
+	result := InboundCardResult{ActorID: inbox.CreatedBy}
+	senderLine := ""
+	if from, err := mail.ParseAddress(msg.Header.Get("From")); err == nil {
+		var sender models.User
+		if err := s.DB.Select("id").Where("LOWER(email) = ?", strings.ToLower(from.Address)).First(&sender).Error; err == nil &&
+			s.CanAccessBoard(sender.ID, inbox.BoardID) {
+			result.ActorID, result.KnownSender = sender.ID, true
+		} else {
+			senderLine = "From: " + from.String() + "\n\n"
+		}
+	}
+
+	title, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		title = msg.Header.Get("Subject")
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "(no subject)"
+	}
+	if runes := []rune(title); len(runes) > 200 {
+		title = string(runes[:200])
+	}
+
+	card, err := s.Cards.CreateCard(title, senderLine+strings.TrimSpace(content.text()), inbox.ColumnID)
+	if err != nil {
+		s.DB.Delete(&record)
+		return nil, err
+	}
+	s.DB.Model(&record).Update("card_id", card.ID)
+	if result.KnownSender {
+		if err := s.Cards.AddMember(card.ID, result.ActorID); err != nil {
+			log.Printf("[BoardInbox] failed adding sender to card %s: %v", card.ID, err)
+		}
+	}
+	for _, a := range content.Attachments {
+		if _, err := storeAttachment(s.DB, s.UploadDir, card.ID, result.ActorID, a.Filename, a.Data); err != nil {
+			log.Printf("[BoardInbox] failed saving attachment %q for card %s: %v", a.Filename, card.ID, err)
+		}
+	}
+
+	if s.ActivityService != nil {
+		s.ActivityService.LogActivity(result.ActorID, inbox.BoardID, "created_card", card.ID, map[string]interface{}{
+			"card_title": card.Title,
+			"column_id":  inbox.ColumnID,
+			"source":     "email",
+		})
+	}
+
+	if full, err := s.Cards.GetCardByID(card.ID); err == nil {
+		full.WIPExceeded = card.WIPExceeded
+		card = full
+	} else {
+		card.Column = models.Column{ID: inbox.ColumnID, BoardID: inbox.BoardID}
+	}
+	result.Card = card
+	return &result, nil
+}
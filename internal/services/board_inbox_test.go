@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardInbox_ImportsMailAsCards(t *testing.T) {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE users (
+		id TEXT PRIMARY KEY, email TEXT, password TEXT, name TEXT, username TEXT, bio TEXT, avatar_url TEXT, language TEXT,
+		has_completed_onboarding INTEGER, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE card_members (card_id TEXT, user_id TEXT, PRIMARY KEY (card_id, user_id))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE attachments (
+		id TEXT PRIMARY KEY, card_id TEXT NOT NULL, user_id TEXT NOT NULL, filename TEXT NOT NULL, file_path TEXT NOT NULL,
+		file_type TEXT NOT NULL, size INTEGER NOT NULL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE board_inboxes (
+		id TEXT PRIMARY KEY, board_id TEXT NOT NULL UNIQUE, column_id TEXT NOT NULL, token TEXT NOT NULL UNIQUE,
+		enabled INTEGER DEFAULT 1, created_by TEXT NOT NULL, created_at DATETIME, updated_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE inbound_email_messages (
+		id TEXT PRIMARY KEY, board_id TEXT NOT NULL, message_id TEXT NOT NULL, card_id TEXT, created_at DATETIME,
+		UNIQUE (board_id, message_id)
+	)`).Error)
+
+	owner, member := uuid.New(), uuid.New()
+	wsID, boardID, colID, otherColID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Owner', 'owner@example.com')`, owner)
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Member', 'Member@Example.com')`, member)
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, member)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Support')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Inbox', 1)`, colID, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Elsewhere', 1)`, otherColID, uuid.New())
+
+	cards := services.NewCardService(repository.NewCardRepository(db), nil)
+	svc := services.NewBoardInboxService(db, cards, services.NewActivityService(db), "in.nexus.test")
+	svc.UploadDir = t.TempDir()
+
+	_, err := svc.ConfigureInbox(boardID, owner, otherColID, nil)
+	require.ErrorIs(t, err, services.ErrInboxColumnNotOnBoard)
+	inbox, err := svc.ConfigureInbox(boardID, owner, colID, nil)
+	require.NoError(t, err)
+	require.True(t, inbox.Enabled)
+	require.True(t, strings.HasPrefix(inbox.Address, "board+") && strings.HasSuffix(inbox.Address, "@in.nexus.test"))
+
+	mixed := "From: Member <member@example.com>\r\n" +
+		"To: Support <" + inbox.Address + ">\r\n" +
+		"Subject: =?utf-8?q?Printer_on_fire_=F0=9F=94=A5?=\r\n" +
+		"Message-ID: <abc@mail.example.com>\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=XX\r\n\r\n" +
+		"--XX\r\nContent-Type: text/plain; charset=utf-8\r\n\r\nIt is on fire.\r\n" +
+		"--XX\r\nContent-Type: image/png\r\nContent-Disposition: attachment; filename=\"../fire.png\"\r\nContent-Transfer-Encoding: base64\r\n\r\niVBORw0KGgo=\r\n" +
+		"--XX\r\nContent-Type: text/html\r\nContent-Disposition: attachment; filename=\"invoice.html\"\r\n\r\n<script>alert(1)</script>\r\n" +
+		"--XX--\r\n"
+
+	result, err := svc.ProcessMessage([]byte(mixed), nil)
+	require.NoError(t, err)
+	require.True(t, result.KnownSender)
+	require.Equal(t, member, result.ActorID)
+	require.Equal(t, "Printer on fire 🔥", result.Card.Title)
+	require.Equal(t, "It is on fire.", result.Card.Description)
+	require.Equal(t, colID, result.Card.ColumnID)
+
+	var memberCount int64
+	db.Table("card_members").Where("card_id = ? AND user_id = ?", result.Card.ID, member).Count(&memberCount)
+	require.Equal(t, int64(1), memberCount)
+	var attachment models.Attachment
+	require.NoError(t, db.First(&attachment, "card_id = ? AND filename = ?", result.Card.ID, "fire.png").Error)
+	require.Equal(t, "image/png", attachment.FileType)
+	require.Equal(t, int64(8), attachment.Size)
+	_, err = os.Stat(filepath.Join(svc.UploadDir, strings.TrimPrefix(attachment.FilePath, "/uploads/")))
+	require.NoError(t, err)
+	// Markup is kept, but only as an opaque download.
+	require.NoError(t, db.First(&attachment, "card_id = ? AND filename = ?", result.Card.ID, "invoice.html").Error)
+	require.Equal(t, "application/octet-stream", attachment.FileType)
+	require.True(t, strings.HasSuffix(attachment.FilePath, ".bin"))
+
+	_, err = svc.ProcessMessage([]byte(mixed), nil)
+	require.ErrorIs(t, err, services.ErrDuplicateInboundEmail)
+
+	// Unknown senders are credited to the inbox creator and named in the description.
+	stranger := "From: Pat <pat@customer.test>\r\nSubject: Hello\r\n\r\nCan you help?"
+	result, err = svc.ProcessMessage([]byte(stranger), []string{"<" + strings.ToUpper(inbox.Address) + ">"})
+	require.NoError(t, err)
+	require.False(t, result.KnownSender)
+	require.Equal(t, owner, result.ActorID)
+	require.Equal(t, "From: \"Pat\" <pat@customer.test>\n\nCan you help?", result.Card.Description)
+
+	_, err = svc.ProcessMessage([]byte("From: a@b.test\r\nTo: reply+x@in.nexus.test\r\n\r\nhi"), nil)
+	require.ErrorIs(t, err, services.ErrNoBoardInbox)
+
+	rotated, err := svc.RotateInbox(boardID)
+	require.NoError(t, err)
+	require.NotEqual(t, inbox.Address, rotated.Address)
+	_, err = svc.ProcessMessage([]byte("From: a@b.test\r\nTo: "+inbox.Address+"\r\nSubject: Old\r\n\r\nhi"), nil)
+	require.ErrorIs(t, err, services.ErrNoBoardInbox, "the old address stops working")
+
+	disabled := false
+	_, err = svc.ConfigureInbox(boardID, owner, colID, &disabled)
+	require.NoError(t, err)
+	_, err = svc.ProcessMessage([]byte("From: a@b.test\r\nTo: "+rotated.Address+"\r\nSubject: Off\r\n\r\nhi"), nil)
+	require.ErrorIs(t, err, services.ErrBoardInboxDisabled)
+
+	_, err = svc.ImportForBoard(boardID, []byte(strings.Repeat("x", services.MaxInboundEmailBytes+1)))
+	require.ErrorIs(t, err, services.ErrInboundEmailTooLarge)
+	uploaded, err := svc.ImportForBoard(boardID, []byte("From: a@b.test\r\nSubject: Uploaded\r\n\r\nfrom an .eml file"))
+	require.NoError(t, err, "uploads work while the address is disabled")
+	require.Equal(t, "Uploaded", uploaded.Card.Title)
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCalendar = errors.New("invalid calendar request")
+
+const (
+	MaxCalendarWindowDays = 62   // two months, enough for a month grid padded to whole weeks
+	MaxCalendarCards      = 1000 // per window; narrow the window when truncated
+)
+
+// CalendarDay holds what falls due on one date, in the caller's time zone.
+type CalendarDay struct {
+	Date           string                  `json:"date"` // YYYY-MM-DD
+	Cards          []TimelineCard          `json:"cards"`
+	ChecklistItems []AssignedChecklistItem `json:"checklist_items"`
+}
+
+type Calendar struct {
+	From      string        `json:"from"` // first day, inclusive
+	To        string        `json:"to"`   // last day, exclusive
+	Timezone  string        `json:"timezone"`
+	Days      []CalendarDay `json:"days"`
+	Truncated bool          `json:"truncated"`
+}
+
+// CalendarOptions picks the days [From, To) in Location; only their dates count. A zero From
+// starts the current month and a zero To ends the month From falls in. Query narrows the cards
+// with the card query language; checklist items follow the cards they belong to.
+type CalendarOptions struct {
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+	Query    string
+}
+
+// CalendarService lays cards and checklist items out by the day they are due.
+type CalendarService struct {
+	DB              *gorm.DB
+	Cards           *CardQueryService
+	CardService     *CardService
+	ActivityService *ActivityService
+	Now             func() time.Time
+}
+
+func NewCalendarService(db *gorm.DB, cards *CardQueryService, cardService *CardService, activityService *ActivityService) *CalendarService {
+	return &CalendarService{DB: db, Cards: cards, CardService: cardService, ActivityService: activityService, Now: time.Now}
+}
+
+// GetBoard loads a board the user can access.
+func (s *CalendarService) GetBoard(boardID, userID uuid.UUID) (*models.Board, error) {
+	board, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	if err != nil {
+		return nil, ErrBoardAccessDenied
+	}
+	return board, nil
+}
+
+// CardForUser loads a card, with its column, on a board the user can access.
+func (s *CalendarService) CardForUser(cardID, userID uuid.UUID) (*models.Card, error) {
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	if _, err := s.GetBoard(card.Column.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// BoardCalendar lists what falls due on the board in the window.
+func (s *CalendarService) BoardCalendar(board *models.Board, userID uuid.UUID, opts CalendarOptions) (*Calendar, error) {
+	return s.calendar(userID, CardQueryScope{BoardID: &board.ID}, "", opts)
+}
+
+// FilterCalendar lists what falls due in the window on the cards matching a saved filter; a
+// query in opts further narrows them.
+func (s *CalendarService) FilterCalendar(filter *models.SavedFilter, userID uuid.UUID, opts CalendarOptions) (*Calendar, error) {
+	return s.calendar(userID, CardQueryScope{WorkspaceID: &filter.WorkspaceID, BoardID: filter.BoardID}, filter.Query, opts)
+}
+
+func (s *CalendarService) window(opts CalendarOptions) (time.Time, time.Time, error) {
+	loc := opts.Location
+	day := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc) }
+	from, to := day(opts.From), day(opts.To)
+	if opts.From.IsZero() {
+		now := s.Now().In(loc)
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	}
+	if opts.To.IsZero() {
+		to = time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, loc)
+	}
+	if !to.After(from) {
+		return from, to, fmt.Errorf("%w: to must be after from", ErrInvalidCalendar)
+	}
+	if from.AddDate(0, 0, MaxCalendarWindowDays).Before(to) {
+		return from, to, fmt.Errorf("%w: the window may span at most %d days", ErrInvalidCalendar, MaxCalendarWindowDays)
+	}
+	return from, to, nil
+}
+
+// calendar returns one entry per day of the window, empty days included. Terms of the filter
+// query and the ad-hoc query must all match.
+func (s *CalendarService) calendar(userID uuid.UUID, scope CardQueryScope, filterQuery string, opts CalendarOptions) (*Calendar, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	from, to, err := s.window(opts)
+	if err != nil {
+		return nil, err
+	}
+	query, err := ParseCardQuery(strings.TrimSpace(filterQuery + " " + opts.Query))
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []models.Card
+	if err := s.Cards.Scoped(userID, query, scope).
+		Where("cards.due_date >= ? AND cards.due_date < ?", from.UTC(), to.UTC()).
+		Order("cards.due_date ASC, cards.id ASC").
+		Limit(MaxCalendarCards + 1).
+		Preload("Column").
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	var items []AssignedChecklistItem
+	if err := s.DB.Table("checklist_items").
+		Select(`checklist_items.*, checklists.title AS checklist_title, cards.id AS card_id, cards.title AS card_title,
+			boards.id AS board_id, boards.title AS board_title`).
+		Joins("JOIN checklists ON checklists.id = checklist_items.checklist_id AND checklists.deleted_at IS NULL").
+		Joins("JOIN cards ON cards.id = checklists.card_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("checklist_items.deleted_at IS NULL").
+		Where("checklist_items.due_date >= ? AND checklist_items.due_date < ?", from.UTC(), to.UTC()).
+		Where("cards.id IN (?)", s.Cards.Scoped(userID, query, scope).Select("cards.id")).
+		Order("checklist_items.due_date ASC, checklist_items.id ASC").
+		Limit(MaxCalendarCards + 1).
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{From: from.Format("2006-01-02"), To: to.Format("2006-01-02"), Timezone: opts.Location.String()}
+	if len(cards) > MaxCalendarCards {
+		cards, cal.Truncated = cards[:MaxCalendarCards], true
+	}
+	if len(items) > MaxCalendarCards {
+		items, cal.Truncated = items[:MaxCalendarCards], true
+	}
+	byDate := map[string]*CalendarDay{}
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		cal.Days = append(cal.Days, CalendarDay{Date: d.Format("2006-01-02"), Cards: []TimelineCard{}, ChecklistItems: []AssignedChecklistItem{}})
+	}
+	for i := range cal.Days {
+		byDate[cal.Days[i].Date] = &cal.Days[i]
+	}
+	for i := range cards {
+		day, ok := byDate[cards[i].DueDate.In(opts.Location).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		day.Cards = append(day.Cards, toTimelineCard(&cards[i]))
+	}
+	for _, item := range items {
+		day, ok := byDate[item.DueDate.In(opts.Location).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		day.ChecklistItems = append(day.ChecklistItems, item)
+	}
+	return cal, nil
+}
+
+// Reschedule moves the card's due date, re-arming its due reminders and firing the due-date
+// automations. Only the due date is written; a non-zero expectedVersion guards against
+// overwriting a concurrent edit, and on a conflict the card's current state is returned.
+func (s *CalendarService) Reschedule(card *models.Card, userID uuid.UUID, due time.Time, expectedVersion int) (*models.Card, error) {
+	repo := s.CardService.Repo
+	current, err := repo.FindByID(card.ID)
+	if err != nil {
+		return nil, err
+	}
+	if expectedVersion == 0 {
+		expectedVersion = current.Version
+	} else if current.Version != expectedVersion {
+		return current, repository.ErrVersionConflict
+	}
+	if current.StartDate != nil && current.StartDate.After(due) {
+		return current, ErrStartAfterDue
+	}
+	previous := current.DueDate
+	if err := repo.SetDueDateIfVersion(current, due, expectedVersion); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			if latest, findErr := repo.FindByID(card.ID); findErr == nil {
+				return latest, err
+			}
+		}
+		return current, err
+	}
+	s.CardService.cardUpdated(card.ID, true)
+	s.ActivityService.LogActivity(userID, card.Column.BoardID, "rescheduled_card", card.ID, map[string]interface{}{
+		"card_title":        card.Title,
+		"due_date":          due,
+		"previous_due_date": previous,
+	})
+	return current, nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const calendarProdID = "-//Nexus//Card Calendar//EN"
+
+// CalendarFeedService issues secret iCalendar (RFC 5545) feed tokens and renders the feeds.
+type CalendarFeedService struct {
+	DB          *gorm.DB
+	FrontendURL string // card links point at FrontendURL/board/<board>/card/<card>
+	Now         func() time.Time
+}
+
+func NewCalendarFeedService(db *gorm.DB, frontendURL string) *CalendarFeedService {
+	frontendURL = strings.TrimRight(strings.TrimSpace(frontendURL), "/")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:4200"
+	}
+	return &CalendarFeedService{DB: db, FrontendURL: frontendURL, Now: time.Now}
+}
+
+// GetBoard loads a board the user can access.
+func (s *CalendarFeedService) GetBoard(boardID, userID uuid.UUID) (*models.Board, error) {
+	board, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	if err != nil {
+		return nil, ErrBoardAccessDenied
+	}
+	return board, nil
+}
+
+// Feed returns the user's personal feed, or their feed for boardID, creating it on first use.
+func (s *CalendarFeedService) Feed(userID uuid.UUID, boardID *uuid.UUID) (*models.CalendarFeed, error) {
+	q := s.DB.Where("user_id = ?", userID)
+	if boardID == nil {
+		q = q.Where("board_id IS NULL")
+	} else {
+		q = q.Where("board_id = ?", *boardID)
+	}
+	var feed models.CalendarFeed
+	err := q.First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		feed = models.CalendarFeed{UserID: userID, BoardID: boardID, Token: newInboxToken()}
+		err = s.DB.Create(&feed).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// RegenerateFeed replaces the feed's token; calendars subscribed to the old URL stop updating.
+func (s *CalendarFeedService) RegenerateFeed(userID uuid.UUID, boardID *uuid.UUID) (*models.CalendarFeed, error) {
+	feed, err := s.Feed(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+	token := newInboxToken()
+	if err := s.DB.Model(feed).Update("token", token).Error; err != nil {
+		return nil, err
+	}
+	feed.Token = token
+	return feed, nil
+}
+
+// Render builds the calendar served for token: the user's assigned cards, or every card on the
+// feed's board, that have a due date. Unknown tokens and feeds whose user has lost access to the
+// board return gorm.ErrRecordNotFound.
+func (s *CalendarFeedService) Render(token string) ([]byte, error) {
+	var feed models.CalendarFeed
+	if err := s.DB.First(&feed, "token = ?", strings.ToLower(strings.TrimSpace(token))).Error; err != nil {
+		return nil, err
+	}
+
+	q := s.DB.Select("cards.*").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("boards.deleted_at IS NULL AND boards.workspace_id IN (?)", repository.AccessibleWorkspaceIDs(s.DB, feed.UserID)).
+		Where("cards.is_archived = ? AND cards.is_template = ? AND cards.due_date IS NOT NULL", false, false)
+	name := "Nexus: my cards"
+	if feed.BoardID != nil {
+		board, err := s.GetBoard(*feed.BoardID, feed.UserID)
+		if err != nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+		q = q.Where("boards.id = ?", board.ID)
+		name = "Nexus: " + board.Title
+	} else {
+		q = q.Where("cards.id IN (?)", s.DB.Table("card_members").Select("card_id").Where("user_id = ?", feed.UserID))
+	}
+	var cards []models.Card
+	if err := q.Order("cards.due_date ASC, cards.id ASC").Preload("Column").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	boardTitles := map[uuid.UUID]string{}
+	if feed.BoardID == nil && len(cards) > 0 {
+		ids := make([]uuid.UUID, 0, len(cards))
+		for _, card := range cards {
+			ids = append(ids, card.Column.BoardID)
+		}
+		var boards []models.Board
+		if err := s.DB.Select("id", "title").Where("id IN ?", ids).Find(&boards).Error; err != nil {
+			return nil, err
+		}
+		for _, b := range boards {
+			boardTitles[b.ID] = b.Title
+		}
+	}
+
+	w := &icsWriter{}
+	w.prop("BEGIN", "VCALENDAR")
+	w.prop("VERSION", "2.0")
+	w.prop("PRODID", calendarProdID)
+	w.prop("CALSCALE", "GREGORIAN")
+	w.prop("METHOD", "PUBLISH")
+	w.prop("X-WR-CALNAME", icsText(name))
+	stamp := s.Now()
+	for _, card := range cards {
+		s.writeEvent(w, card, boardTitles[card.Column.BoardID], stamp)
+	}
+	w.prop("END", "VCALENDAR")
+	return []byte(w.b.String()), nil
+}
+
+// writeEvent emits one VEVENT per card. The UID is derived from the card ID so an edited card
+// replaces its earlier event. A card with a start date on or before its due date becomes an
+// all-day event spanning those UTC dates; otherwise the event sits at the due time.
+func (s *CalendarFeedService) writeEvent(w *icsWriter, card models.Card, boardTitle string, stamp time.Time) {
+	link := fmt.Sprintf("%s/board/%s/card/%s", s.FrontendURL, card.Column.BoardID, card.ID)
+	w.prop("BEGIN", "VEVENT")
+	w.prop("UID", "card-"+card.ID.String()+"@nexus")
+	w.prop("DTSTAMP", icsUTC(stamp))
+	w.prop("LAST-MODIFIED", icsUTC(card.UpdatedAt))
+	w.prop("SEQUENCE", strconv.Itoa(card.Version))
+	if card.StartDate != nil && !card.StartDate.After(*card.DueDate) {
+		w.prop("DTSTART;VALUE=DATE", card.StartDate.UTC().Format("20060102"))
+		w.prop("DTEND;VALUE=DATE", card.DueDate.UTC().AddDate(0, 0, 1).Format("20060102"))
+	} else {
+		w.prop("DTSTART", icsUTC(*card.DueDate))
+	}
+
+	summary, status := card.Title, "Open"
+	if card.IsComplete {
+		summary, status = "[Done] "+card.Title, "Complete"
+	}
+	w.prop("SUMMARY", icsText(summary))
+	description := "Status: " + status
+	if boardTitle != "" {
+		description += "\nBoard: " + boardTitle
+	}
+	if d := strings.TrimSpace(card.Description); d != "" {
+		description += "\n\n" + d
+	}
+	w.prop("DESCRIPTION", icsText(description+"\n\n"+link))
+	w.prop("URL", link)
+	w.prop("END", "VEVENT")
+}
+
+// icsWriter writes CRLF-terminated content lines folded at 75 octets (RFC 5545 §3.1).
+type icsWriter struct {
+	b strings.Builder
+}
+
+func (w *icsWriter) prop(name, value string) {
+	line, limit := name+":"+value, 75
+	for len(line) > limit {
+		cut := limit
+		for !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line, limit = line[cut:], 74 // the leading space counts towards the limit
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icsText escapes a TEXT property value.
+func icsText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
+
+func icsUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCalendarFeeds_RenderRegenerateAndRevoke(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE calendar_feeds (id TEXT PRIMARY KEY, user_id TEXT, board_id TEXT, token TEXT UNIQUE, created_at DATETIME, updated_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	me, owner := uuid.New(), uuid.New()
+	wsID, boardID, otherBoard, colID, otherCol := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, me)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Launch'), (?, ?, 'Ops')`, boardID, wsID, otherBoard, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1), (?, ?, 'Doing', 1)`, colID, boardID, otherCol, otherBoard)
+
+	due := time.Date(2024, 5, 10, 17, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	release := models.Card{ID: uuid.New(), Title: "Release, v2; final", Description: "Ship it\nthen celebrate", ColumnID: colID, Position: 1,
+		StartDate: &start, DueDate: &due}
+	review := models.Card{ID: uuid.New(), Title: "Review " + strings.Repeat("é", 40), ColumnID: otherCol, Position: 1, DueDate: &due, IsComplete: true}
+	unassigned := models.Card{ID: uuid.New(), Title: "Unassigned", ColumnID: colID, Position: 2, DueDate: &due}
+	undated := models.Card{ID: uuid.New(), Title: "Undated", ColumnID: colID, Position: 3}
+	for _, c := range []*models.Card{&release, &review, &unassigned, &undated} {
+		require.NoError(t, db.Create(c).Error)
+	}
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?), (?, ?), (?, ?)`, release.ID, me, review.ID, me, undated.ID, me)
+
+	svc := services.NewCalendarFeedService(db, "https://nexus.example.com/")
+	svc.Now = func() time.Time { return due }
+	mine, err := svc.Feed(me, nil)
+	require.NoError(t, err)
+	again, err := svc.Feed(me, nil)
+	require.NoError(t, err)
+	require.Equal(t, mine.Token, again.Token)
+
+	body, err := svc.Render(mine.Token)
+	require.NoError(t, err)
+	ics := string(body)
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75, line)
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	require.True(t, strings.HasPrefix(unfolded, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.Equal(t, 2, strings.Count(unfolded, "BEGIN:VEVENT"))
+	require.Contains(t, unfolded, "UID:card-"+release.ID.String()+"@nexus\r\n")
+	require.Contains(t, unfolded, "DTSTART;VALUE=DATE:20240506\r\nDTEND;VALUE=DATE:20240511\r\n")
+	require.Contains(t, unfolded, `SUMMARY:Release\, v2\; final`+"\r\n")
+	require.Contains(t, unfolded, `DESCRIPTION:Status: Open\nBoard: Launch\n\nShip it\nthen celebrate\n\nhttps://nexus.example.com/board/`+boardID.String()+"/card/"+release.ID.String())
+	require.Contains(t, unfolded, "DTSTART:20240510T170000Z\r\nSUMMARY:[Done] Review "+strings.Repeat("é", 40))
+	require.NotContains(t, unfolded, "Unassigned")
+
+	boardFeed, err := svc.Feed(me, &boardID)
+	require.NoError(t, err)
+	require.NotEqual(t, mine.Token, boardFeed.Token)
+	body, err = svc.Render(boardFeed.Token + "  ")
+	require.NoError(t, err)
+	require.Contains(t, string(body), "X-WR-CALNAME:Nexus: Launch")
+	require.Contains(t, string(body), "Unassigned")
+	require.NotContains(t, string(body), "Review")
+	require.NotContains(t, string(body), "Undated")
+
+	// A regenerated token replaces the old one.
+	old := mine.Token
+	mine, err = svc.RegenerateFeed(me, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, old, mine.Token)
+	_, err = svc.Render(old)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = svc.Render(mine.Token)
+	require.NoError(t, err)
+
+	// Leaving the workspace revokes the board feed.
+	db.Exec(`DELETE FROM workspace_members WHERE user_id = ?`, me)
+	_, err = svc.Render(boardFeed.Token)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,162 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_DaysInTimezoneFiltersAndReschedule(t *testing.T) {
+	db := setupSavedFilterTestDB(t)
+	me := uuid.New()
+	wsID, boardID, otherBoard := uuid.New(), uuid.New(), uuid.New()
+	todo, done, otherCol := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name, username, email) VALUES (?, 'Me', 'me', 'me@example.com')`, me)
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, me)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Launch'), (?, ?, 'Ops')`, boardID, wsID, otherBoard, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Todo', 1), (?, ?, 'Done', 2), (?, ?, 'Todo', 1)`,
+		todo, boardID, done, boardID, otherCol, otherBoard)
+
+	at := func(month time.Month, d, hour int) *time.Time {
+		t := time.Date(2024, month, d, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	cards := map[string]*models.Card{}
+	create := func(title string, col uuid.UUID, due *time.Time) {
+		card := &models.Card{ID: uuid.New(), Title: title, ColumnID: col, Position: float64(len(cards) + 1), DueDate: due}
+		require.NoError(t, db.Create(card).Error)
+		cards[title] = card
+	}
+	create("Late night", todo, at(5, 10, 2)) // still the 9th in New York
+	create("Noon", todo, at(5, 10, 16))
+	create("Shipped", done, at(5, 12, 16))
+	create("June", todo, at(6, 3, 16))
+	create("Undated", todo, nil)
+	create("Ops", otherCol, at(5, 10, 16))
+	checklistID, itemID := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO checklists (id, card_id, title, position) VALUES (?, ?, 'Steps', 1)`, checklistID, cards["Undated"].ID)
+	db.Exec(`INSERT INTO checklist_items (id, checklist_id, title, position, due_date) VALUES (?, ?, 'Draft notes', 1, ?)`,
+		itemID, checklistID, at(5, 11, 3))
+
+	cardQuery := services.NewCardQueryService(db)
+	cardService := services.NewCardService(repository.NewCardRepository(db), nil)
+	svc := services.NewCalendarService(db, cardQuery, cardService, services.NewActivityService(db))
+	board, err := svc.GetBoard(boardID, me)
+	require.NoError(t, err)
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	may := services.CalendarOptions{
+		From: time.Date(2024, 5, 1, 0, 0, 0, 0, ny), To: time.Date(2024, 6, 1, 0, 0, 0, 0, ny), Location: ny,
+	}
+	dayTitles := func(cal *services.Calendar) map[string][]string {
+		out := map[string][]string{}
+		for _, d := range cal.Days {
+			for _, c := range d.Cards {
+				out[d.Date] = append(out[d.Date], c.Title)
+			}
+			for _, i := range d.ChecklistItems {
+				out[d.Date] = append(out[d.Date], i.Title)
+			}
+		}
+		return out
+	}
+
+	cal, err := svc.BoardCalendar(board, me, may)
+	require.NoError(t, err)
+	require.Len(t, cal.Days, 31)
+	require.Equal(t, "2024-05-01", cal.From)
+	require.Equal(t, "America/New_York", cal.Timezone)
+	require.Equal(t, map[string][]string{
+		"2024-05-09": {"Late night"},
+		"2024-05-10": {"Noon", "Draft notes"},
+		"2024-05-12": {"Shipped"},
+	}, dayTitles(cal))
+	require.Equal(t, "Undated", cal.Days[9].ChecklistItems[0].CardTitle)
+
+	may.Location = time.UTC
+	cal, err = svc.BoardCalendar(board, me, may)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Late night", "Noon"}, dayTitles(cal)["2024-05-10"])
+
+	may.Query = "column:todo"
+	cal, err = svc.BoardCalendar(board, me, may)
+	require.NoError(t, err)
+	require.NotContains(t, dayTitles(cal), "2024-05-12")
+	require.Equal(t, []string{"Draft notes"}, dayTitles(cal)["2024-05-11"])
+
+	filters := services.NewSavedFilterService(db, cardQuery)
+	name, query := "Todo", "column:todo"
+	filter, err := filters.CreateFilter(me, wsID, nil, services.SavedFilterInput{Name: &name, Query: &query})
+	require.NoError(t, err)
+	may.Query = "-board:Launch"
+	cal, err = svc.FilterCalendar(filter, me, may)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"2024-05-10": {"Ops"}}, dayTitles(cal))
+
+	for _, bad := range []services.CalendarOptions{
+		{From: may.To, To: may.From},
+		{From: may.From, To: may.From.AddDate(0, 3, 0)},
+		{From: may.From, To: may.To, Query: "due:"},
+	} {
+		_, err = svc.BoardCalendar(board, me, bad)
+		require.Error(t, err)
+	}
+
+	// A reminded card is reminded again once it moves to a new date.
+	notifications := services.NewNotificationService(db, nil, nil)
+	reminders := services.NewDueDateReminderService(db, notifications, nil, nil, 24*time.Hour)
+	soon := time.Now().Add(2 * time.Hour).UTC()
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, cards["Noon"].ID, me)
+	load := func(title string) *models.Card {
+		card, err := svc.CardForUser(cards[title].ID, me)
+		require.NoError(t, err)
+		return card
+	}
+	_, err = svc.Reschedule(load("Noon"), me, soon, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, reminders.RunOnce().NotificationsSent)
+	require.Zero(t, reminders.RunOnce().NotificationsSent, "a card is reminded once per due date")
+	late := uuid.New()
+	db.Exec(`INSERT INTO users (id, name, username, email) VALUES (?, 'Late', 'late', 'late@example.com')`, late)
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, cards["Noon"].ID, late)
+	require.Equal(t, 1, reminders.RunOnce().NotificationsSent, "members added later are still reminded")
+	require.Zero(t, reminders.RunOnce().NotificationsSent)
+	db.Exec(`DELETE FROM notifications`)
+	require.Zero(t, reminders.RunOnce().NotificationsSent, "clearing the inbox does not re-arm reminders")
+
+	current := load("Noon")
+	_, err = svc.Reschedule(current, me, soon.Add(time.Hour), current.Version-1)
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	updated, err := svc.Reschedule(current, me, soon.Add(time.Hour), current.Version)
+	require.NoError(t, err)
+	require.Equal(t, current.Version+1, updated.Version)
+	require.Equal(t, 2, reminders.RunOnce().NotificationsSent)
+
+	// Rescheduling writes only the due date, so an edit made since the card was loaded stays.
+	stale := load("Noon")
+	require.NoError(t, db.Model(&models.Card{}).Where("id = ?", stale.ID).Update("description", "edited meanwhile").Error)
+	_, err = svc.Reschedule(stale, me, soon.Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	require.Equal(t, "edited meanwhile", load("Noon").Description)
+	require.NoError(t, db.Model(&models.Card{}).Where("id = ?", stale.ID).Update("start_date", soon).Error)
+	_, err = svc.Reschedule(load("Noon"), me, soon.Add(-time.Hour), 0)
+	require.ErrorIs(t, err, services.ErrStartAfterDue)
+
+	// Reminders sent before the ledger existed are not repeated after the upgrade.
+	db.Exec(`DELETE FROM due_reminders`)
+	require.NoError(t, services.BackfillDueReminders(db, time.Now().Add(-24*time.Hour)))
+	var ledger int64
+	db.Model(&models.DueReminder{}).Count(&ledger)
+	require.Equal(t, int64(2), ledger)
+	require.Zero(t, reminders.RunOnce().NotificationsSent)
+
+	var activity models.Activity
+	require.NoError(t, db.Where("action = ? AND target_id = ?", "rescheduled_card", cards["Noon"].ID).Order("created_at DESC").First(&activity).Error)
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxBulkCards caps how many cards a single bulk request may touch.
+const MaxBulkCards = 200
+
+type BulkCardOperationType string
+
+const (
+	BulkMoveCard       BulkCardOperationType = "move"
+	BulkAddLabel       BulkCardOperationType = "add_label"
+	BulkRemoveLabel    BulkCardOperationType = "remove_label"
+	BulkAddMember      BulkCardOperationType = "add_member"
+	BulkRemoveMember   BulkCardOperationType = "remove_member"
+	BulkSetDueDate     BulkCardOperationType = "set_due_date"
+	BulkArchiveCard    BulkCardOperationType = "archive"
+	BulkRestoreCard    BulkCardOperationType = "restore"
+	BulkDeleteCard     BulkCardOperationType = "delete"
+	BulkSetCustomField BulkCardOperationType = "set_custom_field"
+)
+
+// BulkCardOperation is one operation applied to every card in a bulk request.
+// Only the parameters relevant to Type are read.
+type BulkCardOperation struct {
+	Type     BulkCardOperationType
+	ColumnID *uuid.UUID // move (required), restore (optional, defaults to the card's column)
+	LabelID  *uuid.UUID
+	UserID   *uuid.UUID
+	DueDate  *time.Time // nil clears the due date
+	FieldID  *uuid.UUID
+	Value    interface{}
+}
+
+// BulkCardFailure explains why one card blocked a bulk request.
+type BulkCardFailure struct {
+	CardID uuid.UUID `json:"card_id"`
+	Code   string    `json:"code"` // NOT_FOUND, FORBIDDEN, INVALID, CRITERIA_NOT_MET, WIP_LIMIT_EXCEEDED
+	Error  string    `json:"error"`
+}
+
+// BulkCardError is returned when any card fails its checks; nothing is written.
+type BulkCardError struct {
+	Failures []BulkCardFailure
+}
+
+func (e *BulkCardError) Error() string {
+	return fmt.Sprintf("bulk operation rejected for %d card(s)", len(e.Failures))
+}
+
+// Forbidden reports whether any failure was an access problem rather than bad input.
+func (e *BulkCardError) Forbidden() bool {
+	for _, f := range e.Failures {
+		if f.Code == "FORBIDDEN" {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidBulkOperation wraps problems with the operation itself (unknown type, missing or foreign IDs).
+var ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+
+// BulkCardResult describes what a committed bulk request changed.
+type BulkCardResult struct {
+	BoardID       uuid.UUID     `json:"board_id"`
+	Operation     string        `json:"operation"`
+	CardIDs       []uuid.UUID   `json:"card_ids"`
+	Cards         []models.Card `json:"cards,omitempty"` // omitted for delete
+	TargetBoardID *uuid.UUID    `json:"target_board_id,omitempty"`
+	// WIPExceeded lists the warn-mode WIP limits the moves or restores went over.
+	WIPExceeded []models.WIPExceeded `json:"wip_exceeded,omitempty"`
+}
+
+// BulkUpdateCards applies op to every card in one transaction. Each card must live on boardID
+// and the actor must be able to access the board of every card and every target. One activity
+// is written per card inside the transaction; automation rules run per card after commit.
+func (s *CardService) BulkUpdateCards(actorID, boardID uuid.UUID, cardIDs []uuid.UUID, op BulkCardOperation) (*BulkCardResult, error) {
+	cardIDs = uniqueUUIDs(cardIDs)
+	if len(cardIDs) == 0 {
+		return nil, fmt.Errorf("%w: card_ids is empty", ErrInvalidBulkOperation)
+	}
+	if len(cardIDs) > MaxBulkCards {
+		return nil, fmt.Errorf("%w: at most %d cards per request", ErrInvalidBulkOperation, MaxBulkCards)
+	}
+
+	result := &BulkCardResult{BoardID: boardID, Operation: string(op.Type), CardIDs: cardIDs}
+	var cards []models.Card
+
+	err := s.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		boards := repository.NewBoardRepository(tx)
+		if _, err := boards.GetBoardByID(boardID, actorID); err != nil {
+			return &BulkCardError{Failures: []BulkCardFailure{{Code: "FORBIDDEN", Error: "board not accessible"}}}
+		}
+
+		target, err := s.validateBulkOperation(tx, actorID, boardID, op)
+		if err != nil {
+			return err
+		}
+		if target != nil && target.BoardID != boardID {
+			result.TargetBoardID = &target.BoardID
+		}
+
+		if err := tx.Preload("Column").Where("id IN ?", cardIDs).Find(&cards).Error; err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]models.Card, len(cards))
+		for _, card := range cards {
+			byID[card.ID] = card
+		}
+
+		// Per-card checks run before any write so a rejected batch leaves nothing behind.
+		accessible := map[uuid.UUID]bool{boardID: true}
+		var failures []BulkCardFailure
+		ordered := make([]models.Card, 0, len(cardIDs))
+		for _, id := range cardIDs {
+			card, ok := byID[id]
+			if !ok {
+				failures = append(failures, BulkCardFailure{CardID: id, Code: "NOT_FOUND", Error: "card not found"})
+				continue
+			}
+			cardBoardID := card.Column.BoardID
+			if _, seen := accessible[cardBoardID]; !seen {
+				_, err := boards.GetBoardByID(cardBoardID, actorID)
+				accessible[cardBoardID] = err == nil
+			}
+			if !accessible[cardBoardID] {
+				failures = append(failures, BulkCardFailure{CardID: id, Code: "FORBIDDEN", Error: "card not accessible"})
+				continue
+			}
+			if cardBoardID != boardID {
+				failures = append(failures, BulkCardFailure{CardID: id, Code: "INVALID", Error: "card belongs to another board"})
+				continue
+			}
+			if card.IsTemplate {
+				failures = append(failures, BulkCardFailure{CardID: id, Code: "INVALID", Error: "card is a template"})
+				continue
+			}
+			if op.Type == BulkRestoreCard && !card.IsArchived {
+				failures = append(failures, BulkCardFailure{CardID: id, Code: "INVALID", Error: "card is not archived"})
+				continue
+			}
+			if op.Type == BulkMoveCard {
+				unmet, err := checkMoveCriteria(tx, &card, target.ID)
+				if err != nil {
+					return err
+				}
+				if len(unmet) > 0 {
+					failures = append(failures, BulkCardFailure{CardID: id, Code: "CRITERIA_NOT_MET", Error: (&CriteriaError{Unmet: unmet}).Error()})
+					continue
+				}
+			}
+			ordered = append(ordered, card)
+		}
+		if len(failures) > 0 {
+			return &BulkCardError{Failures: failures}
+		}
+		cards = ordered
+
+		txRepo := repository.NewCardRepository(tx)
+		activities := NewActivityService(tx)
+		for i := range cards {
+			action, metadata, err := s.applyBulkOperation(tx, txRepo, &cards[i], op, target, result)
+			var wipErr *repository.WIPLimitError
+			if errors.As(err, &wipErr) {
+				return &BulkCardError{Failures: []BulkCardFailure{{CardID: cards[i].ID, Code: "WIP_LIMIT_EXCEEDED", Error: wipErr.Error()}}}
+			}
+			if err != nil {
+				return fmt.Errorf("card %s: %w", cards[i].ID, err)
+			}
+			metadata["card_title"] = cards[i].Title
+			metadata["bulk"] = true
+			if err := activities.LogActivity(actorID, boardID, action, cards[i].ID, metadata); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Type != BulkDeleteCard {
+		var fresh []models.Card
+		if err := s.Repo.DB.Preload("Labels").Preload("Members").Preload("Column").
+			Where("id IN ?", cardIDs).Find(&fresh).Error; err == nil {
+			result.Cards = fresh
+		}
+	}
+
+	if op.Type != BulkDeleteCard {
+		s.Formulas.RecomputeCards(boardID, cardIDs)
+	}
+	s.evaluateBulkTriggers(cards, op, result)
+	return result, nil
+}
+
+// validateBulkOperation checks the operation parameters once, before any card is touched.
+// It returns the target column for move/restore when one was given.
+func (s *CardService) validateBulkOperation(tx *gorm.DB, actorID, boardID uuid.UUID, op BulkCardOperation) (*models.Column, error) {
+	boards := repository.NewBoardRepository(tx)
+
+	switch op.Type {
+	case BulkMoveCard, BulkRestoreCard:
+		if op.ColumnID == nil {
+			if op.Type == BulkMoveCard {
+				return nil, fmt.Errorf("%w: column_id is required", ErrInvalidBulkOperation)
+			}
+			return nil, nil
+		}
+		var column models.Column
+		if err := tx.First(&column, "id = ?", *op.ColumnID).Error; err != nil {
+			return nil, fmt.Errorf("%w: target column not found", ErrInvalidBulkOperation)
+		}
+		if column.BoardID != boardID {
+			if _, err := boards.GetBoardByID(column.BoardID, actorID); err != nil {
+				return nil, &BulkCardError{Failures: []BulkCardFailure{{Code: "FORBIDDEN", Error: "target column not accessible"}}}
+			}
+			if op.Type == BulkRestoreCard {
+				return nil, fmt.Errorf("%w: cards can only be restored on their own board", ErrInvalidBulkOperation)
+			}
+		}
+		return &column, nil
+	case BulkAddLabel, BulkRemoveLabel:
+		if op.LabelID == nil {
+			return nil, fmt.Errorf("%w: label_id is required", ErrInvalidBulkOperation)
+		}
+		var label models.Label
+		if err := tx.First(&label, "id = ? AND board_id = ?", *op.LabelID, boardID).Error; err != nil {
+			return nil, fmt.Errorf("%w: label not found on this board", ErrInvalidBulkOperation)
+		}
+	case BulkAddMember, BulkRemoveMember:
+		if op.UserID == nil {
+			return nil, fmt.Errorf("%w: user_id is required", ErrInvalidBulkOperation)
+		}
+		if op.Type == BulkAddMember {
+			if _, err := boards.GetBoardByID(boardID, *op.UserID); err != nil {
+				return nil, fmt.Errorf("%w: user is not a member of this board's workspace", ErrInvalidBulkOperation)
+			}
+		}
+	case BulkSetCustomField:
+		if op.FieldID == nil {
+			return nil, fmt.Errorf("%w: field_id is required", ErrInvalidBulkOperation)
+		}
+		var field models.CustomField
+		if err := tx.First(&field, "id = ? AND board_id = ?", *op.FieldID, boardID).Error; err != nil {
+			return nil, fmt.Errorf("%w: field not found on this board", ErrInvalidBulkOperation)
+		}
+		val, err := buildCardFieldValue(&field, uuid.Nil, op.Value)
+		if err == nil {
+			err = checkFieldValueUser(tx, &field, val)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidBulkOperation, err.Error())
+		}
+	case BulkSetDueDate, BulkArchiveCard, BulkDeleteCard:
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidBulkOperation, op.Type)
+	}
+	return nil, nil
+}
+
+// applyBulkOperation performs op on one card and returns the activity to record for it.
+// WIP limit warnings are collected on result.
+func (s *CardService) applyBulkOperation(tx *gorm.DB, repo *repository.CardRepository, card *models.Card, op BulkCardOperation, target *models.Column, result *BulkCardResult) (string, map[string]interface{}, error) {
+	switch op.Type {
+	case BulkMoveCard:
+		// Cards are appended to the target column in request order.
+		pos := repo.GetMaxPosition(target.ID)
+		if pos == 0 {
+			pos = repository.PositionGap
+		}
+		if card.ColumnID == target.ID {
+			return "moved_card", map[string]interface{}{"column_id": target.ID, "position": card.Position}, nil
+		}
+		moved, err := repo.MoveCardWithOptions(card.ID, target.ID, pos, repository.MoveCardOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		result.WIPExceeded = append(result.WIPExceeded, moved.Card.WIPExceeded...)
+		return "moved_card", map[string]interface{}{"column_id": target.ID, "from_column_id": card.ColumnID, "position": pos}, nil
+	case BulkAddLabel:
+		if err := repo.AddLabel(card.ID, *op.LabelID); err != nil {
+			return "", nil, err
+		}
+		return "added_label", map[string]interface{}{"label_id": *op.LabelID}, nil
+	case BulkRemoveLabel:
+		if err := repo.RemoveLabel(card.ID, *op.LabelID); err != nil {
+			return "", nil, err
+		}
+		return "removed_label", map[string]interface{}{"label_id": *op.LabelID}, nil
+	case BulkAddMember:
+		if err := repo.AddMember(card.ID, *op.UserID); err != nil {
+			return "", nil, err
+		}
+		return "assigned_member", map[string]interface{}{"assigned_user_id": *op.UserID}, nil
+	case BulkRemoveMember:
+		if err := repo.RemoveMember(card.ID, *op.UserID); err != nil {
+			return "", nil, err
+		}
+		return "removed_member", map[string]interface{}{"removed_user_id": *op.UserID}, nil
+	case BulkSetDueDate:
+		if err := tx.Model(&models.Card{}).Where("id = ?", card.ID).Updates(map[string]interface{}{
+			"due_date": op.DueDate,
+			"version":  gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return "", nil, err
+		}
+		return "updated_due_date", map[string]interface{}{"due_date": op.DueDate}, nil
+	case BulkArchiveCard:
+		if err := repo.Archive(card.ID); err != nil {
+			return "", nil, err
+		}
+		return "archived_card", map[string]interface{}{}, nil
+	case BulkRestoreCard:
+		columnID := card.ColumnID
+		if target != nil {
+			columnID = target.ID
+		}
+		exceeded, err := repo.Restore(card.ID, columnID)
+		if err != nil {
+			return "", nil, err
+		}
+		result.WIPExceeded = append(result.WIPExceeded, exceeded...)
+		return "restored_card", map[string]interface{}{"column_id": columnID}, nil
+	case BulkDeleteCard:
+		if err := repo.Delete(card.ID); err != nil {
+			return "", nil, err
+		}
+		return "deleted_card", map[string]interface{}{"column_id": card.ColumnID}, nil
+	case BulkSetCustomField:
+		var field models.CustomField
+		if err := tx.First(&field, "id = ?", *op.FieldID).Error; err != nil {
+			return "", nil, err
+		}
+		val, err := buildCardFieldValue(&field, card.ID, op.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := repository.NewCustomFieldRepository(tx).SetValue(val); err != nil {
+			return "", nil, err
+		}
+		return "set_custom_field", map[string]interface{}{"field_id": field.ID, "field_name": field.Name}, nil
+	}
+	return "", nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidBulkOperation, op.Type)
+}
+
+// evaluateBulkTriggers fires the same automation triggers the single-card endpoints fire, once per card.
+func (s *CardService) evaluateBulkTriggers(cards []models.Card, op BulkCardOperation, result *BulkCardResult) {
+	if s.AutomationService == nil {
+		return
+	}
+
+	var userField *models.CustomField
+	if op.Type == BulkSetCustomField {
+		var field models.CustomField
+		if err := s.Repo.DB.First(&field, "id = ?", *op.FieldID).Error; err == nil && field.Type == models.FieldTypeUser {
+			userField = &field
+		}
+	}
+
+	for _, card := range cards {
+		boardID := card.Column.BoardID
+		ctx := map[string]interface{}{"card_id": card.ID.String()}
+
+		switch op.Type {
+		case BulkMoveCard:
+			if card.ColumnID == *op.ColumnID {
+				continue
+			}
+			if result.TargetBoardID != nil {
+				boardID = *result.TargetBoardID
+			}
+			ctx["to_column_id"] = op.ColumnID.String()
+			s.AutomationService.EvaluateRules(boardID, models.TriggerCardMoved, ctx)
+		case BulkAddLabel:
+			ctx["label_id"] = op.LabelID.String()
+			s.AutomationService.EvaluateRules(boardID, models.TriggerLabelAdded, ctx)
+		case BulkAddMember:
+			ctx["user_id"] = op.UserID.String()
+			s.AutomationService.EvaluateRules(boardID, models.TriggerMemberAdded, ctx)
+		case BulkSetDueDate:
+			if op.DueDate == nil {
+				continue
+			}
+			s.AutomationService.EvaluateRules(boardID, models.TriggerDueDateSet, ctx)
+			s.AutomationService.EvaluateRules(boardID, models.TriggerCalendarSet, ctx)
+		case BulkSetCustomField:
+			if userField == nil {
+				continue
+			}
+			ctx["field_id"] = userField.ID.String()
+			ctx["user_id"] = ""
+			if userID, ok := op.Value.(string); ok {
+				ctx["user_id"] = userID
+			}
+			s.AutomationService.EvaluateRules(boardID, models.TriggerUserFieldSet, ctx)
+		}
+	}
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == uuid.Nil {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupBulkTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := SetupTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE workspaces (id TEXT PRIMARY KEY, name TEXT, owner_id TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE workspace_members (workspace_id TEXT, user_id TEXT, role TEXT, status TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE boards (id TEXT PRIMARY KEY, workspace_id TEXT, title TEXT, version INTEGER DEFAULT 1, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE activities (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		user_id TEXT NOT NULL,
+		board_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target_id TEXT,
+		metadata TEXT,
+		created_at DATETIME
+	)`).Error)
+	return db
+}
+
+func TestBulkUpdateCards_RejectsWholeBatchOnForeignCard(t *testing.T) {
+	db := setupBulkTestDB(t)
+	svc := services.NewCardService(repository.NewCardRepository(db), nil)
+
+	owner, stranger := uuid.New(), uuid.New()
+	mineWS, otherWS := uuid.New(), uuid.New()
+	mineBoard, otherBoard := uuid.New(), uuid.New()
+	mineCol, otherCol := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'mine', ?), (?, 'other', ?)`, mineWS, owner, otherWS, stranger)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Mine'), (?, ?, 'Other')`, mineBoard, mineWS, otherBoard, otherWS)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'To Do', 1), (?, ?, 'Theirs', 1)`, mineCol, mineBoard, otherCol, otherBoard)
+
+	a, b, foreign := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: a, Title: "A", ColumnID: mineCol, Position: 16384}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: b, Title: "B", ColumnID: mineCol, Position: 32768}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: foreign, Title: "X", ColumnID: otherCol, Position: 16384}).Error)
+
+	op := services.BulkCardOperation{Type: services.BulkArchiveCard}
+
+	_, err := svc.BulkUpdateCards(owner, mineBoard, []uuid.UUID{a, foreign, b}, op)
+	var bulkErr *services.BulkCardError
+	require.True(t, errors.As(err, &bulkErr))
+	require.True(t, bulkErr.Forbidden())
+	require.Len(t, bulkErr.Failures, 1)
+	require.Equal(t, foreign, bulkErr.Failures[0].CardID)
+
+	var archived int64
+	db.Model(&models.Card{}).Where("is_archived = ?", true).Count(&archived)
+	require.Zero(t, archived, "a rejected batch must not change any card")
+
+	result, err := svc.BulkUpdateCards(owner, mineBoard, []uuid.UUID{a, b, a}, op)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{a, b}, result.CardIDs)
+
+	db.Model(&models.Card{}).Where("is_archived = ?", true).Count(&archived)
+	require.EqualValues(t, 2, archived)
+
+	var activities int64
+	db.Model(&models.Activity{}).Where("action = ? AND board_id = ?", "archived_card", mineBoard).Count(&activities)
+	require.EqualValues(t, 2, activities)
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidCardQuery = errors.New("invalid card query")
+
+const (
+	DefaultCardQueryLimit = 50
+	MaxCardQueryLimit     = 100
+)
+
+// cardPriorityRankSQL orders priorities like CardPriority.Rank.
+const cardPriorityRankSQL = "CASE cards.priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END"
+
+// noDueDate stands in for a missing due date when sorting, so undated cards come last.
+var noDueDate = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// cardQuerySort is one sort order: the SQL it orders by and the same value read off a card
+// for the next page's cursor.
+type cardQuerySort struct {
+	expr        string
+	vars        []interface{}
+	defaultDesc bool
+	key         func(c *models.Card) interface{}
+	decode      func(raw json.RawMessage) (interface{}, error)
+}
+
+func decodeCursorTime(raw json.RawMessage) (interface{}, error) {
+	var t time.Time
+	err := json.Unmarshal(raw, &t)
+	return t, err
+}
+
+var cardQuerySorts = map[string]cardQuerySort{
+	"updated": {
+		expr: "cards.updated_at", defaultDesc: true,
+		key:    func(c *models.Card) interface{} { return c.UpdatedAt },
+		decode: decodeCursorTime,
+	},
+	"created": {
+		expr: "cards.created_at", defaultDesc: true,
+		key:    func(c *models.Card) interface{} { return c.CreatedAt },
+		decode: decodeCursorTime,
+	},
+	"due": {
+		expr: "COALESCE(cards.due_date, ?)", vars: []interface{}{noDueDate},
+		key: func(c *models.Card) interface{} {
+			if c.DueDate == nil {
+				return noDueDate
+			}
+			return *c.DueDate
+		},
+		decode: decodeCursorTime,
+	},
+	"priority": {
+		expr: cardPriorityRankSQL, defaultDesc: true,
+		key: func(c *models.Card) interface{} { return c.Priority.Rank() },
+		decode: func(raw json.RawMessage) (interface{}, error) {
+			var n int
+			err := json.Unmarshal(raw, &n)
+			return n, err
+		},
+	},
+	"title": {
+		expr: "cards.title",
+		key:  func(c *models.Card) interface{} { return c.Title },
+		decode: func(raw json.RawMessage) (interface{}, error) {
+			var s string
+			err := json.Unmarshal(raw, &s)
+			return s, err
+		},
+	},
+}
+
+// CardQueryPage picks the order and page of a card query. Sort is updated (default),
+// created, due, priority or title; Order is asc or desc and defaults per sort. Cursor is the
+// NextCursor of the previous page and must come with the same sort and order.
+type CardQueryPage struct {
+	Sort   string
+	Order  string
+	Cursor string
+	Limit  int
+}
+
+// CardQueryScope narrows a card query to one workspace or one board; the zero scope covers
+// every board the user can access.
+type CardQueryScope struct {
+	WorkspaceID *uuid.UUID
+	BoardID     *uuid.UUID
+}
+
+type CardQueryResult struct {
+	Cards      []models.Card `json:"cards"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// cardCursor is the last card of a page: its sort value and ID break ties.
+type cardCursor struct {
+	Sort  string          `json:"s"`
+	Desc  bool            `json:"d"`
+	Value json.RawMessage `json:"v"`
+	ID    uuid.UUID       `json:"id"`
+}
+
+type CardQueryService struct {
+	DB  *gorm.DB
+	Now func() time.Time
+}
+
+func NewCardQueryService(db *gorm.DB) *CardQueryService {
+	return &CardQueryService{DB: db, Now: time.Now}
+}
+
+// QueryCards runs a card query over every board the user can access. Parse errors come back
+// as *CardQueryError.
+func (s *CardQueryService) QueryCards(userID uuid.UUID, src string, page CardQueryPage) (*CardQueryResult, error) {
+	return s.QueryCardsIn(userID, src, CardQueryScope{}, page)
+}
+
+// QueryCardsIn runs a card query over the boards in scope that the user can access.
+func (s *CardQueryService) QueryCardsIn(userID uuid.UUID, src string, scope CardQueryScope, page CardQueryPage) (*CardQueryResult, error) {
+	query, err := ParseCardQuery(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Sort == "" {
+		page.Sort = "updated"
+	}
+	sort, ok := cardQuerySorts[page.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: sort must be updated, created, due, priority or title", ErrInvalidCardQuery)
+	}
+	desc := sort.defaultDesc
+	switch page.Order {
+	case "":
+	case "asc", "desc":
+		desc = page.Order == "desc"
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc", ErrInvalidCardQuery)
+	}
+	limit := page.Limit
+	if limit <= 0 {
+		limit = DefaultCardQueryLimit
+	}
+	if limit > MaxCardQueryLimit {
+		limit = MaxCardQueryLimit
+	}
+
+	db := s.Scoped(userID, query, scope)
+	dir, cmp := "ASC", ">"
+	if desc {
+		dir, cmp = "DESC", "<"
+	}
+	if page.Cursor != "" {
+		cursor, value, err := decodeCardCursor(page.Cursor, sort)
+		if err != nil || cursor.Sort != page.Sort || cursor.Desc != desc {
+			return nil, fmt.Errorf("%w: cursor does not belong to this sort", ErrInvalidCardQuery)
+		}
+		var vars []interface{}
+		vars = append(append(vars, sort.vars...), value)
+		vars = append(append(vars, sort.vars...), value, cursor.ID)
+		db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND cards.id %[2]s ?))", sort.expr, cmp), vars...)
+	}
+
+	var cards []models.Card
+	err = db.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  fmt.Sprintf("%s %s, cards.id %s", sort.expr, dir, dir),
+		Vars: sort.vars,
+	}}).
+		Limit(limit + 1).
+		Preload("Column").Preload("Labels").Preload("Members").
+		Find(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CardQueryResult{Cards: cards}
+	if len(cards) > limit {
+		result.Cards = cards[:limit]
+		last := &cards[limit-1]
+		value, _ := json.Marshal(sort.key(last))
+		raw, _ := json.Marshal(cardCursor{Sort: page.Sort, Desc: desc, Value: value, ID: last.ID})
+		result.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	return result, nil
+}
+
+// Scoped selects the non-template cards matching query on the boards in scope that the user
+// can access, joined to their columns and boards.
+func (s *CardQueryService) Scoped(userID uuid.UUID, query *CardQuery, scope CardQueryScope) *gorm.DB {
+	db := s.DB.Model(&models.Card{}).Select("cards.*").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("boards.deleted_at IS NULL AND boards.workspace_id IN (?)", repository.AccessibleWorkspaceIDs(s.DB, userID)).
+		Where("cards.is_template = ?", false)
+	if scope.WorkspaceID != nil {
+		db = db.Where("boards.workspace_id = ?", *scope.WorkspaceID)
+	}
+	if scope.BoardID != nil {
+		db = db.Where("boards.id = ?", *scope.BoardID)
+	}
+	return query.Apply(db, userID, s.Now())
+}
+
+func decodeCardCursor(encoded string, sort cardQuerySort) (*cardCursor, interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, err
+	}
+	var cursor cardCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, nil, err
+	}
+	value, err := sort.decode(cursor.Value)
+	return &cursor, value, err
+}
@@ -0,0 +1,510 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Card queries filter cards across boards with qualifiers:
+//
+//	label:bug member:@alice due:<7d -is:complete board:"Sprint 12" field:Priority=High
+//
+// Terms are whitespace-separated and all must match. A leading - negates a term, comma-separated
+// values match any of them (label:bug,urgent) and double quotes keep spaces, commas and
+// operators literal. Bare words match the card title or description. Names (labels, boards,
+// columns, fields, text values) compare case-insensitively. Archived cards are left out unless
+// the query mentions is:archived.
+//
+//	label:NAME | none            member:@USER | @me | none (username or email)
+//	board:TITLE   column:NAME    priority:urgent|high|medium|low|none
+//	is:complete | archived | overdue
+//	due:none | any | overdue | DATE | <DATE | <=7d | >-2w   (DATE is YYYY-MM-DD; h, d, w offsets from now)
+//	field:NAME (has a value) | field:NAME=TEXT | field:NAME>=3 | field:NAME<DATE | field:NAME=@USER
+
+const MaxCardQueryLength = 1000
+
+// CardQueryError is a query that does not parse. Position is the 1-based character offset
+// of the problem in the query.
+type CardQueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *CardQueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+var cardQueryKeys = map[string]bool{
+	"label": true, "member": true, "board": true, "column": true, "priority": true,
+	"is": true, "due": true, "field": true,
+}
+
+var cardQueryIs = map[string]bool{"complete": true, "archived": true, "overdue": true}
+
+var relativeOffset = regexp.MustCompile(`^(-?\d+)([hdw])$`)
+
+// queryChar is one byte of a value with whether it was inside quotes.
+type queryChar struct {
+	c      byte
+	quoted bool
+	pos    int
+}
+
+type queryChars []queryChar
+
+func (cs queryChars) String() string {
+	b := make([]byte, len(cs))
+	for i, c := range cs {
+		b[i] = c.c
+	}
+	return string(b)
+}
+
+// quoted reports whether every byte was inside quotes, which turns off keywords.
+func (cs queryChars) quoted() bool {
+	for _, c := range cs {
+		if !c.quoted {
+			return false
+		}
+	}
+	return len(cs) > 0
+}
+
+// split cuts at unquoted commas.
+func (cs queryChars) split() []queryChars {
+	var parts []queryChars
+	start := 0
+	for i, c := range cs {
+		if c.c == ',' && !c.quoted {
+			parts = append(parts, cs[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, cs[start:])
+}
+
+// cardQueryValue is one alternative of a term, already validated.
+type cardQueryValue struct {
+	text     string // lowercased, without a leading @
+	keyword  bool   // unquoted, so none, any and me are keywords
+	user     bool   // a username or email, or @me
+	op       string // due and field comparisons
+	field    string // field name, lowercased
+	day      *time.Time
+	offset   time.Duration
+	relative bool
+	number   *float64
+}
+
+type cardQueryTerm struct {
+	key    string // "" for free text
+	negate bool
+	values []cardQueryValue
+}
+
+// CardQuery is a parsed card query; Apply compiles it onto a cards query.
+type CardQuery struct {
+	terms []cardQueryTerm
+}
+
+type cardQueryParser struct {
+	src string
+	i   int
+}
+
+func (p *cardQueryParser) errorAt(pos int, format string, args ...interface{}) error {
+	return &CardQueryError{Position: utf8.RuneCountInString(p.src[:pos]) + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func isQuerySpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+
+// ParseCardQuery parses a query; an empty query matches every unarchived card.
+func ParseCardQuery(src string) (*CardQuery, error) {
+	if len(src) > MaxCardQueryLength {
+		return nil, &CardQueryError{Position: MaxCardQueryLength + 1, Message: fmt.Sprintf("query is longer than %d characters", MaxCardQueryLength)}
+	}
+	p := &cardQueryParser{src: src}
+	q := &CardQuery{}
+	for {
+		for p.i < len(src) && isQuerySpace(src[p.i]) {
+			p.i++
+		}
+		if p.i >= len(src) {
+			return q, nil
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		q.terms = append(q.terms, term)
+	}
+}
+
+func (p *cardQueryParser) term() (cardQueryTerm, error) {
+	var term cardQueryTerm
+	if p.src[p.i] == '-' && p.i+1 < len(p.src) && !isQuerySpace(p.src[p.i+1]) {
+		term.negate = true
+		p.i++
+	}
+	keyStart, j := p.i, p.i
+	for j < len(p.src) && (p.src[j] == '_' || (p.src[j]|0x20 >= 'a' && p.src[j]|0x20 <= 'z')) {
+		j++
+	}
+	if j == keyStart || j >= len(p.src) || p.src[j] != ':' {
+		chars, err := p.value()
+		if err != nil {
+			return term, err
+		}
+		if len(chars) == 0 {
+			return term, p.errorAt(keyStart, "empty search phrase")
+		}
+		term.values = []cardQueryValue{{text: strings.ToLower(chars.String())}}
+		return term, nil
+	}
+
+	term.key = strings.ToLower(p.src[keyStart:j])
+	if !cardQueryKeys[term.key] {
+		return term, p.errorAt(keyStart, "unknown qualifier %q", term.key)
+	}
+	p.i = j + 1
+	chars, err := p.value()
+	if err != nil {
+		return term, err
+	}
+	if len(chars) == 0 {
+		return term, p.errorAt(p.i, "missing value for %s:", term.key)
+	}
+	if term.key == "field" {
+		term.values, err = p.fieldValues(chars)
+		return term, err
+	}
+	for _, part := range chars.split() {
+		v, err := p.simpleValue(term.key, part)
+		if err != nil {
+			return term, err
+		}
+		term.values = append(term.values, v)
+	}
+	return term, nil
+}
+
+// value reads up to the next unquoted whitespace.
+func (p *cardQueryParser) value() (queryChars, error) {
+	var chars queryChars
+	inQuote, quoteAt := false, 0
+	for ; p.i < len(p.src); p.i++ {
+		c := p.src[p.i]
+		switch {
+		case c == '"':
+			inQuote, quoteAt = !inQuote, p.i
+		case isQuerySpace(c) && !inQuote:
+			return chars, nil
+		default:
+			chars = append(chars, queryChar{c: c, quoted: inQuote, pos: p.i})
+		}
+	}
+	if inQuote {
+		return nil, p.errorAt(quoteAt, "unterminated quote")
+	}
+	return chars, nil
+}
+
+func (p *cardQueryParser) posOf(chars queryChars) int {
+	if len(chars) == 0 {
+		return p.i
+	}
+	return chars[0].pos
+}
+
+func (p *cardQueryParser) simpleValue(key string, chars queryChars) (cardQueryValue, error) {
+	raw := strings.TrimSpace(chars.String())
+	pos := p.posOf(chars)
+	if raw == "" {
+		return cardQueryValue{}, p.errorAt(pos, "empty value for %s:", key)
+	}
+	v := cardQueryValue{text: strings.ToLower(raw), keyword: !chars.quoted()}
+	switch key {
+	case "member":
+		if v.keyword && v.text == "none" {
+			break
+		}
+		v.user, v.text = true, strings.TrimPrefix(v.text, "@")
+		if v.text == "" {
+			return v, p.errorAt(pos, "missing user after @")
+		}
+	case "priority":
+		if !v.keyword || !models.CardPriority(v.text).Valid() {
+			return v, p.errorAt(pos, "unknown priority %q", raw)
+		}
+	case "is":
+		if !v.keyword || !cardQueryIs[v.text] {
+			return v, p.errorAt(pos, "unknown is: value %q, expected complete, archived or overdue", raw)
+		}
+	case "due":
+		return p.dueValue(v, chars)
+	}
+	return v, nil
+}
+
+func (p *cardQueryParser) dueValue(v cardQueryValue, chars queryChars) (cardQueryValue, error) {
+	pos := p.posOf(chars)
+	if v.keyword && (v.text == "none" || v.text == "any" || v.text == "overdue") {
+		return v, nil
+	}
+	v.keyword = false
+	v.op, v.text = splitComparison(v.text)
+	if err := p.operand(&v, pos+len(v.op)); err != nil {
+		return v, err
+	}
+	switch {
+	case v.day == nil && !v.relative:
+		return v, p.errorAt(pos, "due: expects none, any, overdue, a YYYY-MM-DD date or an offset like <7d")
+	case v.relative && v.op == "":
+		return v, p.errorAt(pos, "relative due dates need <, <=, > or >=")
+	}
+	return v, nil
+}
+
+// fieldValues parses NAME[OP OPERAND[,OPERAND...]].
+func (p *cardQueryParser) fieldValues(chars queryChars) ([]cardQueryValue, error) {
+	opAt := -1
+	for i, c := range chars {
+		if !c.quoted && (c.c == '=' || c.c == '<' || c.c == '>' || c.c == '!') {
+			opAt = i
+			break
+		}
+	}
+	nameChars := chars
+	if opAt >= 0 {
+		nameChars = chars[:opAt]
+	}
+	name := strings.ToLower(strings.TrimSpace(nameChars.String()))
+	if name == "" {
+		return nil, p.errorAt(p.posOf(chars), "missing field name")
+	}
+	if opAt < 0 {
+		return []cardQueryValue{{field: name}}, nil
+	}
+	if chars[opAt].c == '!' {
+		return nil, p.errorAt(chars[opAt].pos, "use -field: to negate a field comparison")
+	}
+	op := string(chars[opAt].c)
+	rest := chars[opAt+1:]
+	if len(rest) > 0 && !rest[0].quoted && rest[0].c == '=' && op != "=" {
+		op += "="
+		rest = rest[1:]
+	}
+
+	var values []cardQueryValue
+	for _, part := range rest.split() {
+		raw := strings.TrimSpace(part.String())
+		pos := p.posOf(part)
+		if raw == "" {
+			return nil, p.errorAt(pos, "missing value after %s", op)
+		}
+		v := cardQueryValue{field: name, op: op, text: strings.ToLower(raw), keyword: !part.quoted()}
+		switch {
+		case !v.keyword:
+			// Quoted operands are always text.
+		case strings.HasPrefix(v.text, "@") && len(v.text) > 1:
+			v.user, v.text = true, strings.TrimPrefix(v.text, "@")
+		default:
+			if err := p.operand(&v, pos); err != nil {
+				return nil, err
+			}
+			if v.relative {
+				return nil, p.errorAt(pos, "field values compare with dates, not offsets")
+			}
+			v.keyword = false
+		}
+		if v.op != "=" && v.number == nil && v.day == nil {
+			return nil, p.errorAt(pos, "%s needs a number or a YYYY-MM-DD date", op)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// operand reads a number, a YYYY-MM-DD day or a relative offset into v.
+func (p *cardQueryParser) operand(v *cardQueryValue, pos int) error {
+	if v.text == "" {
+		return p.errorAt(pos, "missing value")
+	}
+	if day, err := time.Parse("2006-01-02", v.text); err == nil {
+		v.day = &day
+		return nil
+	}
+	if m := relativeOffset.FindStringSubmatch(v.text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+		v.offset, v.relative = time.Duration(n)*unit, true
+		return nil
+	}
+	if n, err := strconv.ParseFloat(v.text, 64); err == nil {
+		v.number = &n
+	}
+	return nil
+}
+
+// splitComparison takes a leading <, <=, >, >= or = off a value.
+func splitComparison(s string) (string, string) {
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(s, op) {
+			return op, s[len(op):]
+		}
+	}
+	return "", s
+}
+
+// --- Compiling ---
+
+// includesArchived reports whether the query asks about archived cards itself.
+func (q *CardQuery) includesArchived() bool {
+	for _, t := range q.terms {
+		if t.key != "is" {
+			continue
+		}
+		for _, v := range t.values {
+			if v.text == "archived" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply adds the query's conditions to db, a query over cards joined to columns and boards.
+// userID resolves @me and now anchors relative due dates.
+func (q *CardQuery) Apply(db *gorm.DB, userID uuid.UUID, now time.Time) *gorm.DB {
+	if !q.includesArchived() {
+		db = db.Where("cards.is_archived = ?", false)
+	}
+	for _, t := range q.terms {
+		var conds []string
+		var args []interface{}
+		for _, v := range t.values {
+			sql, vars := compileCardQueryValue(db.Session(&gorm.Session{NewDB: true}), t.key, v, userID, now)
+			conds = append(conds, sql)
+			args = append(args, vars...)
+		}
+		sql := "(" + strings.Join(conds, " OR ") + ")"
+		if t.negate {
+			sql = "NOT " + sql
+		}
+		db = db.Where(sql, args...)
+	}
+	return db
+}
+
+func compileCardQueryValue(db *gorm.DB, key string, v cardQueryValue, userID uuid.UUID, now time.Time) (string, []interface{}) {
+	switch key {
+	case "":
+		like := "%" + escapeLike(v.text) + "%"
+		return `(LOWER(cards.title) LIKE ? ESCAPE '\' OR LOWER(COALESCE(cards.description, '')) LIKE ? ESCAPE '\')`, []interface{}{like, like}
+	case "label":
+		if v.keyword && v.text == "none" {
+			return "cards.id NOT IN (?)", []interface{}{db.Table("card_labels").Select("card_id")}
+		}
+		return "cards.id IN (?)", []interface{}{db.Table("card_labels").Select("card_labels.card_id").
+			Joins("JOIN labels ON labels.id = card_labels.label_id").
+			Where("labels.deleted_at IS NULL AND LOWER(labels.name) = ?", v.text)}
+	case "member":
+		if v.keyword && v.text == "none" {
+			return "cards.id NOT IN (?)", []interface{}{db.Table("card_members").Select("card_id")}
+		}
+		return "cards.id IN (?)", []interface{}{db.Table("card_members").Select("card_id").
+			Where("user_id IN (?)", matchUsers(db, v, userID))}
+	case "board":
+		return "LOWER(boards.title) = ?", []interface{}{v.text}
+	case "column":
+		return "LOWER(columns.name) = ?", []interface{}{v.text}
+	case "priority":
+		return "cards.priority = ?", []interface{}{v.text}
+	case "is":
+		switch v.text {
+		case "complete":
+			return "cards.is_complete = ?", []interface{}{true}
+		case "archived":
+			return "cards.is_archived = ?", []interface{}{true}
+		}
+		return "(cards.due_date IS NOT NULL AND cards.due_date < ? AND cards.is_complete = ?)", []interface{}{now, false}
+	case "due":
+		switch {
+		case v.keyword && v.text == "none":
+			return "cards.due_date IS NULL", nil
+		case v.keyword && v.text == "any":
+			return "cards.due_date IS NOT NULL", nil
+		case v.keyword && v.text == "overdue":
+			return "(cards.due_date IS NOT NULL AND cards.due_date < ? AND cards.is_complete = ?)", []interface{}{now, false}
+		}
+		sql, args := compareTime("cards.due_date", v, now)
+		// Cards without a due date never match a comparison, so negating one includes them.
+		return "(cards.due_date IS NOT NULL AND " + sql + ")", args
+	case "field":
+		values := db.Table("card_custom_field_values").Select("card_custom_field_values.card_id").
+			Joins("JOIN custom_fields ON custom_fields.id = card_custom_field_values.custom_field_id").
+			Where("card_custom_field_values.deleted_at IS NULL AND LOWER(custom_fields.name) = ?", v.field)
+		switch {
+		case v.op == "":
+		case v.number != nil:
+			values = values.Where("card_custom_field_values.value_number "+v.op+" ?", *v.number)
+		case v.day != nil:
+			sql, args := compareTime("card_custom_field_values.value_date", v, now)
+			values = values.Where("card_custom_field_values.value_date IS NOT NULL AND "+sql, args...)
+		case v.user:
+			values = values.Where("card_custom_field_values.value_user_id IN (?)", matchUsers(db, v, userID))
+		default:
+			values = values.Where(matchFieldText(db, v.text))
+		}
+		return "cards.id IN (?)", []interface{}{values}
+	}
+	return "1 = 0", nil
+}
+
+// matchUsers selects users by username or email, or the caller for @me.
+func matchUsers(db *gorm.DB, v cardQueryValue, userID uuid.UUID) interface{} {
+	if v.keyword && v.text == "me" {
+		return []uuid.UUID{userID}
+	}
+	return db.Table("users").Select("id").Where("LOWER(username) = ? OR LOWER(email) = ?", v.text, v.text)
+}
+
+// matchFieldText compares text and dropdown values; on Postgres multi-select lists are
+// searched too.
+func matchFieldText(db *gorm.DB, text string) *gorm.DB {
+	cond := db.Where("LOWER(card_custom_field_values.value_text) = ?", text)
+	if db.Dialector.Name() == "postgres" {
+		cond = cond.Or("? IN (SELECT LOWER(unnest(card_custom_field_values.value_list)))", text)
+	}
+	return cond
+}
+
+// compareTime compares a timestamp column with a whole day or a relative offset. A bare day
+// matches anything on that day; <= and > include or skip the whole day.
+func compareTime(column string, v cardQueryValue, now time.Time) (string, []interface{}) {
+	if v.relative {
+		return column + " " + v.op + " ?", []interface{}{now.Add(v.offset)}
+	}
+	start := time.Date(v.day.Year(), v.day.Month(), v.day.Day(), 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 0, 1)
+	switch v.op {
+	case "<":
+		return column + " < ?", []interface{}{start}
+	case "<=":
+		return column + " < ?", []interface{}{end}
+	case ">":
+		return column + " >= ?", []interface{}{end}
+	case ">=":
+		return column + " >= ?", []interface{}{start}
+	}
+	return "(" + column + " >= ? AND " + column + " < ?)", []interface{}{start, end}
+}
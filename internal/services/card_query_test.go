@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCardQuery_ErrorPositions(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		position int
+	}{
+		{`label:bug colour:red`, 11},
+		{`board:"Sprint 12`, 7},
+		{`is:done`, 4},
+		{`priority:high,asap`, 15},
+		{`due:7d`, 5},
+		{`due:soon`, 5},
+		{`label:`, 7},
+		{`field:=High`, 7},
+		{`field:Stage!=Build`, 12},
+		{`field:Points>lots`, 14},
+		{`label:é due:tomorrow`, 13},
+		{`member:@`, 8},
+	} {
+		_, err := services.ParseCardQuery(tc.query)
+		var parseErr *services.CardQueryError
+		require.True(t, errors.As(err, &parseErr), tc.query)
+		require.Equal(t, tc.position, parseErr.Position, "%s: %s", tc.query, parseErr.Message)
+	}
+
+	for _, ok := range []string{
+		``, `label:bug member:@alice due:<7d -is:complete board:"Sprint 12" field:Priority=High`,
+		`due:>=2024-05-01 field:"Story Points">=3 -label:none "release notes"`, `10:30`,
+	} {
+		_, err := services.ParseCardQuery(ok)
+		require.NoError(t, err, ok)
+	}
+}
+
+func TestQueryCards_FiltersAcrossBoardsWithCursor(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, email TEXT, name TEXT, username TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+			value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	alice, bob, stranger := uuid.New(), uuid.New(), uuid.New()
+	wsID, otherWS := uuid.New(), uuid.New()
+	sprint, ops, hidden := uuid.New(), uuid.New(), uuid.New()
+	sprintCol, opsCol, hiddenCol := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?), (?, 'other', ?)`, wsID, alice, otherWS, stranger)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, bob)
+	db.Exec(`INSERT INTO users (id, name, email, username) VALUES (?, 'Alice', 'alice@example.com', 'alice'), (?, 'Bob', 'bob@example.com', 'bob')`, alice, bob)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Sprint 12'), (?, ?, 'Ops'), (?, ?, 'Hidden')`, sprint, wsID, ops, wsID, hidden, otherWS)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1), (?, ?, 'Doing', 1), (?, ?, 'Doing', 1)`,
+		sprintCol, sprint, opsCol, ops, hiddenCol, hidden)
+
+	now := time.Now()
+	soon, later, past := now.Add(48*time.Hour), now.Add(20*24*time.Hour), now.Add(-24*time.Hour)
+	cards := map[string]uuid.UUID{}
+	create := func(name string, col uuid.UUID, mutate func(*models.Card)) {
+		card := models.Card{ID: uuid.New(), Title: name, ColumnID: col, Position: float64(len(cards) + 1)}
+		if mutate != nil {
+			mutate(&card)
+		}
+		require.NoError(t, db.Create(&card).Error)
+		cards[name] = card.ID
+	}
+	create("Crash on login", sprintCol, func(c *models.Card) { c.DueDate, c.Priority = &soon, models.PriorityHigh })
+	create("Fix typo", sprintCol, func(c *models.Card) { c.DueDate, c.IsComplete = &soon, true })
+	create("Rotate keys", opsCol, func(c *models.Card) { c.DueDate, c.Description = &later, "Login secrets" })
+	create("Overdue bug", opsCol, func(c *models.Card) { c.DueDate, c.Priority = &past, models.PriorityUrgent })
+	create("Archived bug", sprintCol, func(c *models.Card) { c.IsArchived = true })
+	create("Hidden bug", hiddenCol, nil)
+
+	bug, docs := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO labels (id, board_id, name) VALUES (?, ?, 'Bug'), (?, ?, 'docs')`, bug, sprint, docs, ops)
+	db.Exec(`INSERT INTO card_labels (card_id, label_id) VALUES (?, ?), (?, ?), (?, ?)`,
+		cards["Crash on login"], bug, cards["Archived bug"], bug, cards["Rotate keys"], docs)
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?), (?, ?), (?, ?)`,
+		cards["Crash on login"], alice, cards["Fix typo"], alice, cards["Rotate keys"], bob)
+	stage, points := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO custom_fields (id, board_id, name, type) VALUES (?, ?, 'Priority', 'dropdown'), (?, ?, 'Story Points', 'number')`, stage, sprint, points, ops)
+	db.Exec(`INSERT INTO card_custom_field_values (id, card_id, custom_field_id, value_text, value_number) VALUES (?, ?, ?, 'High', 0), (?, ?, ?, '', 5), (?, ?, ?, '', 1)`,
+		uuid.New(), cards["Crash on login"], stage, uuid.New(), cards["Rotate keys"], points, uuid.New(), cards["Overdue bug"], points)
+
+	svc := services.NewCardQueryService(db)
+	titles := func(userID uuid.UUID, q string) []string {
+		result, err := svc.QueryCards(userID, q, services.CardQueryPage{Sort: "title"})
+		require.NoError(t, err, q)
+		names := []string{}
+		for _, c := range result.Cards {
+			names = append(names, c.Title)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"Crash on login"}, titles(alice, `label:bug member:@alice due:<7d -is:complete board:"Sprint 12" field:Priority=High`))
+	require.Equal(t, []string{"Crash on login"}, titles(alice, `label:BUG`))
+	require.Equal(t, []string{"Archived bug"}, titles(alice, `label:BUG is:archived,complete -is:complete`))
+	require.Equal(t, []string{"Crash on login", "Fix typo", "Rotate keys"}, titles(alice, `member:@me,bob`))
+	require.Equal(t, []string{"Rotate keys"}, titles(bob, `member:@me`))
+	require.Equal(t, []string{"Crash on login", "Fix typo"}, titles(bob, `board:"sprint 12"`))
+	require.Equal(t, []string{"Fix typo", "Overdue bug"}, titles(alice, `member:none,alice -label:bug`))
+	require.Equal(t, []string{"Overdue bug"}, titles(alice, `is:overdue`))
+	require.Equal(t, []string{"Crash on login", "Fix typo"}, titles(alice, `due:<=7d -due:overdue`))
+	require.Equal(t, []string{"Rotate keys"}, titles(alice, `field:"story points">=3`))
+	require.Equal(t, []string{"Crash on login", "Fix typo", "Overdue bug"}, titles(alice, `-field:"Story Points">=3`))
+	require.Equal(t, []string{"Crash on login", "Rotate keys"}, titles(alice, `login`))
+	require.Equal(t, []string{"Crash on login", "Overdue bug"}, titles(alice, `priority:high,urgent`))
+	require.Equal(t, []string{"Hidden bug"}, titles(stranger, `bug`))
+	require.Equal(t, []string{"Crash on login", "Fix typo"}, titles(alice, "due:"+soon.Format("2006-01-02")))
+
+	// Walk every page in priority order; ties break on ID so no card repeats.
+	var seen []string
+	page := services.CardQueryPage{Sort: "priority", Limit: 2}
+	for {
+		result, err := svc.QueryCards(alice, "", page)
+		require.NoError(t, err)
+		for _, c := range result.Cards {
+			seen = append(seen, c.Title)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+	require.Len(t, seen, 4)
+	require.Equal(t, []string{"Overdue bug", "Crash on login"}, seen[:2])
+
+	// Due order starts with the most overdue card; a cursor only works with its own order.
+	result, err := svc.QueryCards(alice, "", services.CardQueryPage{Sort: "due", Limit: 3})
+	require.NoError(t, err)
+	require.Equal(t, "Overdue bug", result.Cards[0].Title)
+	result, err = svc.QueryCards(alice, "", services.CardQueryPage{Sort: "due", Limit: 3, Cursor: result.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, result.Cards, 1)
+	require.Equal(t, "Rotate keys", result.Cards[0].Title)
+
+	_, err = svc.QueryCards(alice, "", services.CardQueryPage{Sort: "due", Order: "desc", Cursor: result.NextCursor + "x"})
+	require.ErrorIs(t, err, services.ErrInvalidCardQuery)
+	_, err = svc.QueryCards(alice, "", services.CardQueryPage{Sort: "colour"})
+	require.ErrorIs(t, err, services.ErrInvalidCardQuery)
+}
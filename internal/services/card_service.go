@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"time"
 
 	"nexus-backend/internal/models"
@@ -9,9 +10,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrStartAfterDue rejects a card date range that ends before it starts.
+var ErrStartAfterDue = errors.New("start date must not be after the due date")
+
 type CardService struct {
 	Repo              *repository.CardRepository
 	AutomationService *AutomationService
+	Formulas          *FormulaService // optional; refreshes formula fields after card changes
 }
 
 func NewCardService(repo *repository.CardRepository, automationService *AutomationService) *CardService {
@@ -35,6 +40,9 @@ func (s *CardService) CreateCard(title, description string, columnID uuid.UUID)
 	}
 
 	err := s.Repo.Create(card)
+	if err == nil {
+		s.Formulas.RecomputeCard(card.ID)
+	}
 	if err == nil && s.AutomationService != nil {
 		// Fetch full card to get BoardID
 		if fullCard, err := s.Repo.FindByIDWithChecklists(card.ID); err == nil {
@@ -49,10 +57,21 @@ func (s *CardService) CreateCard(title, description string, columnID uuid.UUID)
 }
 
 func (s *CardService) UpdateCard(id uuid.UUID, title, description string, dueDate *time.Time, isComplete *bool) (*models.Card, error) {
+	return s.UpdateCardIfVersion(id, 0, title, description, nil, dueDate, isComplete, nil)
+}
+
+// UpdateCardIfVersion applies the update only when the card is still at expectedVersion.
+// An expectedVersion of 0 skips the check. Returns repository.ErrVersionConflict on a stale write.
+func (s *CardService) UpdateCardIfVersion(id uuid.UUID, expectedVersion int, title, description string, startDate, dueDate *time.Time, isComplete *bool, priority *models.CardPriority) (*models.Card, error) {
 	card, err := s.Repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if expectedVersion == 0 {
+		expectedVersion = card.Version
+	} else if card.Version != expectedVersion {
+		return card, repository.ErrVersionConflict
+	}
 
 	if title != "" {
 		card.Title = title
@@ -64,25 +83,33 @@ func (s *CardService) UpdateCard(id uuid.UUID, title, description string, dueDat
 	// Actually, let's allow updating description to empty.
 	card.Description = description
 
+	if startDate != nil {
+		card.StartDate = startDate
+	}
 	if dueDate != nil {
 		card.DueDate = dueDate
 	}
+	if card.StartDate != nil && card.DueDate != nil && card.StartDate.After(*card.DueDate) {
+		return card, ErrStartAfterDue
+	}
 
 	if isComplete != nil {
+		if *isComplete && !card.IsComplete {
+			now := time.Now()
+			card.CompletedAt = &now
+		} else if !*isComplete {
+			card.CompletedAt = nil
+		}
 		card.IsComplete = *isComplete
 	}
 
-	err = s.Repo.Update(card)
+	if priority != nil {
+		card.Priority = *priority
+	}
 
-	if err == nil && s.AutomationService != nil && dueDate != nil {
-		// Treat due-date update as both due-date and calendar trigger.
-		if fullCard, err := s.Repo.FindByIDWithChecklists(card.ID); err == nil {
-			ctx := map[string]interface{}{
-				"card_id": card.ID.String(),
-			}
-			s.AutomationService.EvaluateRules(fullCard.Column.BoardID, models.TriggerDueDateSet, ctx)
-			s.AutomationService.EvaluateRules(fullCard.Column.BoardID, models.TriggerCalendarSet, ctx)
-		}
+	err = s.Repo.UpdateIfVersion(card, expectedVersion)
+	if err == nil {
+		s.cardUpdated(card.ID, dueDate != nil)
 	}
 
 	if err == nil && s.AutomationService != nil && isComplete != nil && *isComplete {
@@ -98,8 +125,28 @@ func (s *CardService) UpdateCard(id uuid.UUID, title, description string, dueDat
 	return card, err
 }
 
+// cardUpdated follows a committed card write: formulas are recomputed and, when the due date
+// was set, DUE_DATE_SET and CALENDAR_SET rules run.
+func (s *CardService) cardUpdated(cardID uuid.UUID, dueDateSet bool) {
+	s.Formulas.RecomputeCard(cardID)
+	if s.AutomationService == nil || !dueDateSet {
+		return
+	}
+	// Treat due-date update as both due-date and calendar trigger.
+	if fullCard, err := s.Repo.FindByIDWithChecklists(cardID); err == nil {
+		ctx := map[string]interface{}{
+			"card_id": cardID.String(),
+		}
+		s.AutomationService.EvaluateRules(fullCard.Column.BoardID, models.TriggerDueDateSet, ctx)
+		s.AutomationService.EvaluateRules(fullCard.Column.BoardID, models.TriggerCalendarSet, ctx)
+	}
+}
+
 func (s *CardService) AddLabel(cardID, labelID uuid.UUID) error {
 	err := s.Repo.AddLabel(cardID, labelID)
+	if err == nil {
+		s.Formulas.RecomputeCard(cardID)
+	}
 	if err == nil && s.AutomationService != nil {
 		if fullCard, err := s.Repo.FindByIDWithChecklists(cardID); err == nil {
 			ctx := map[string]interface{}{
@@ -113,11 +160,18 @@ func (s *CardService) AddLabel(cardID, labelID uuid.UUID) error {
 }
 
 func (s *CardService) RemoveLabel(cardID, labelID uuid.UUID) error {
-	return s.Repo.RemoveLabel(cardID, labelID)
+	err := s.Repo.RemoveLabel(cardID, labelID)
+	if err == nil {
+		s.Formulas.RecomputeCard(cardID)
+	}
+	return err
 }
 
 func (s *CardService) AddMember(cardID, userID uuid.UUID) error {
 	err := s.Repo.AddMember(cardID, userID)
+	if err == nil {
+		s.Formulas.RecomputeCard(cardID)
+	}
 	if err == nil && s.AutomationService != nil {
 		if fullCard, err := s.Repo.FindByIDWithChecklists(cardID); err == nil {
 			ctx := map[string]interface{}{
@@ -131,7 +185,11 @@ func (s *CardService) AddMember(cardID, userID uuid.UUID) error {
 }
 
 func (s *CardService) RemoveMember(cardID, userID uuid.UUID) error {
-	return s.Repo.RemoveMember(cardID, userID)
+	err := s.Repo.RemoveMember(cardID, userID)
+	if err == nil {
+		s.Formulas.RecomputeCard(cardID)
+	}
+	return err
 }
 
 func (s *CardService) DeleteCard(id uuid.UUID) error {
@@ -142,26 +200,51 @@ func (s *CardService) ArchiveCard(id uuid.UUID) error {
 	return s.Repo.Archive(id)
 }
 
-func (s *CardService) RestoreCard(id uuid.UUID, columnID uuid.UUID) error {
+// RestoreCard unarchives the card into columnID, returning any warn-mode WIP limit it
+// takes the column over.
+func (s *CardService) RestoreCard(id uuid.UUID, columnID uuid.UUID) ([]models.WIPExceeded, error) {
 	return s.Repo.Restore(id, columnID)
 }
 
 func (s *CardService) MoveCard(id uuid.UUID, newColumnID uuid.UUID, newPosition float64) (*models.Card, error) {
-	card, err := s.Repo.MoveCardTransaction(id, newColumnID, newPosition)
+	result, err := s.MoveCardWithOptions(id, newColumnID, newPosition, repository.MoveCardOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Card, nil
+}
+
+// MoveCardWithOptions moves the card and reports any renumbering of the target column.
+// Unless opts.OverrideCriteria is set, a move that fails the exit criteria of the card's
+// column or the entry criteria of the target returns a *CriteriaError.
+func (s *CardService) MoveCardWithOptions(id uuid.UUID, newColumnID uuid.UUID, newPosition float64, opts repository.MoveCardOptions) (*repository.MoveCardResult, error) {
+	if !opts.OverrideCriteria {
+		unmet, err := s.CheckMoveCriteria(id, newColumnID)
+		if err != nil {
+			return nil, err
+		}
+		if len(unmet) > 0 {
+			return nil, &CriteriaError{Unmet: unmet}
+		}
+	}
+	result, err := s.Repo.MoveCardWithOptions(id, newColumnID, newPosition, opts)
 	if err != nil {
 		return nil, err
 	}
+	card := result.Card
+	// A cross-board move brings the card under the target board's formulas.
+	s.Formulas.RecomputeCard(id)
 
 	if s.AutomationService != nil {
 		ctx := map[string]interface{}{
 			"card_id":      id.String(),
 			"to_column_id": newColumnID.String(),
 		}
-		// card.Column is preloaded in MoveCardTransaction
+		// card.Column is preloaded in MoveCardWithOptions
 		s.AutomationService.EvaluateRules(card.Column.BoardID, models.TriggerCardMoved, ctx)
 	}
 
-	return card, nil
+	return result, nil
 }
 
 // GetCardByID returns a card with all its checklists and items
@@ -174,7 +257,11 @@ func (s *CardService) CopyCard(originalCardID, targetColumnID uuid.UUID) (*model
 	pos := s.Repo.GetMaxPosition(targetColumnID)
 
 	// 2. Perform Copy
-	return s.Repo.CopyCard(originalCardID, targetColumnID, pos)
+	card, err := s.Repo.CopyCard(originalCardID, targetColumnID, pos)
+	if err == nil {
+		s.Formulas.RecomputeCard(card.ID)
+	}
+	return card, err
 }
 
 func (s *CardService) SaveCardAsTemplate(cardID uuid.UUID, templateName string) (*models.Card, error) {
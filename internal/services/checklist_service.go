@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrAssigneeNotMember = errors.New("assignee must be a member of the board")
+
+// ChecklistService handles checklist item assignment, due dates and mentions.
+type ChecklistService struct {
+	DB                  *gorm.DB
+	NotificationService *NotificationService
+}
+
+func NewChecklistService(db *gorm.DB, notificationService *NotificationService) *ChecklistService {
+	return &ChecklistService{DB: db, NotificationService: notificationService}
+}
+
+// ItemCard loads a checklist item together with the card (and column) it belongs to.
+func (s *ChecklistService) ItemCard(itemID uuid.UUID) (*models.ChecklistItem, *models.Card, error) {
+	var item models.ChecklistItem
+	if err := s.DB.First(&item, "id = ?", itemID).Error; err != nil {
+		return nil, nil, err
+	}
+	var card models.Card
+	if err := s.DB.Preload("Column").
+		Where("id = (SELECT card_id FROM checklists WHERE id = ?)", item.ChecklistID).
+		First(&card).Error; err != nil {
+		return nil, nil, err
+	}
+	return &item, &card, nil
+}
+
+// CanAccessBoard reports whether the user owns or is an accepted member of the board's workspace.
+func (s *ChecklistService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+// AssignItem sets or clears (nil) an item's assignee and notifies a new assignee.
+func (s *ChecklistService) AssignItem(itemID uuid.UUID, assigneeID *uuid.UUID, actorID uuid.UUID) (*models.ChecklistItem, *models.Card, error) {
+	item, card, err := s.ItemCard(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.CanAccessBoard(actorID, card.Column.BoardID) {
+		return nil, nil, ErrBoardAccessDenied
+	}
+	if assigneeID != nil && !s.CanAccessBoard(*assigneeID, card.Column.BoardID) {
+		return nil, nil, ErrAssigneeNotMember
+	}
+
+	previous := item.AssigneeID
+	if err := s.DB.Model(item).Update("assignee_id", assigneeID).Error; err != nil {
+		return nil, nil, err
+	}
+	item.AssigneeID = assigneeID
+
+	if assigneeID != nil && (previous == nil || *previous != *assigneeID) {
+		s.NotifyAssigned(item, card, actorID)
+	}
+	return s.reload(item.ID, card)
+}
+
+// SetItemDueDate sets or clears (nil) an item's due date. A new date re-arms its reminder.
+func (s *ChecklistService) SetItemDueDate(itemID uuid.UUID, dueDate *time.Time, actorID uuid.UUID) (*models.ChecklistItem, *models.Card, error) {
+	item, card, err := s.ItemCard(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.CanAccessBoard(actorID, card.Column.BoardID) {
+		return nil, nil, ErrBoardAccessDenied
+	}
+
+	if err := s.DB.Model(item).Updates(map[string]interface{}{
+		"due_date":             dueDate,
+		"due_reminder_sent_at": nil,
+	}).Error; err != nil {
+		return nil, nil, err
+	}
+	return s.reload(item.ID, card)
+}
+
+func (s *ChecklistService) reload(itemID uuid.UUID, card *models.Card) (*models.ChecklistItem, *models.Card, error) {
+	var item models.ChecklistItem
+	if err := s.DB.Preload("Assignee").First(&item, "id = ?", itemID).Error; err != nil {
+		return nil, nil, err
+	}
+	return &item, card, nil
+}
+
+// NotifyAssigned tells the item's assignee they were given it, unless they assigned themselves.
+func (s *ChecklistService) NotifyAssigned(item *models.ChecklistItem, card *models.Card, actorID uuid.UUID) {
+	if s.NotificationService == nil || item.AssigneeID == nil || *item.AssigneeID == actorID {
+		return
+	}
+	_, _ = s.NotificationService.CreateNotification(
+		*item.AssigneeID,
+		actorID,
+		models.NotificationAssignment,
+		"Checklist item assigned",
+		fmt.Sprintf("%s assigned you '%s' on card '%s'", s.actorName(actorID), item.Title, card.Title),
+		card.ID,
+		"CARD",
+		PrefNotifyAssignments,
+	)
+}
+
+// NotifyTitleMentions notifies workspace members mentioned in an item title.
+// Users in skip (e.g. an assignee who was just notified) are left out.
+func (s *ChecklistService) NotifyTitleMentions(item *models.ChecklistItem, card *models.Card, actorID uuid.UUID, skip map[uuid.UUID]struct{}) {
+	mentioned := ResolveBoardMentions(s.DB, card.Column.BoardID, item.Title)
+	RecordMentions(s.DB, card.ID, actorID, mentioned)
+	if s.NotificationService == nil || len(mentioned) == 0 {
+		return
+	}
+	actorName := s.actorName(actorID)
+	for recipientID := range mentioned {
+		if recipientID == actorID {
+			continue
+		}
+		if _, ok := skip[recipientID]; ok {
+			continue
+		}
+		_, _ = s.NotificationService.CreateNotification(
+			recipientID,
+			actorID,
+			models.NotificationMention,
+			"You were mentioned",
+			actorName+" mentioned you in a checklist item on card: "+card.Title,
+			card.ID,
+			"CARD",
+			PrefNotifyComments,
+		)
+	}
+}
+
+func (s *ChecklistService) actorName(actorID uuid.UUID) string {
+	return userDisplayName(s.DB, actorID)
+}
+
+// userDisplayName returns the user's name for notification text, or "Someone".
+func userDisplayName(db *gorm.DB, userID uuid.UUID) string {
+	var user models.User
+	if err := db.Select("id", "name").First(&user, "id = ?", userID).Error; err == nil && strings.TrimSpace(user.Name) != "" {
+		return user.Name
+	}
+	return "Someone"
+}
+
+// AssignedChecklistItem is an item assigned to the caller with enough card and board
+// context to link to it from a cross-board list.
+type AssignedChecklistItem struct {
+	models.ChecklistItem
+	ChecklistTitle string    `json:"checklist_title"`
+	CardID         uuid.UUID `json:"card_id"`
+	CardTitle      string    `json:"card_title"`
+	BoardID        uuid.UUID `json:"board_id"`
+	BoardTitle     string    `json:"board_title"`
+}
+
+// AssignedItems lists items assigned to the user on live cards of boards they can still access,
+// soonest due first with undated items last.
+func (s *ChecklistService) AssignedItems(userID uuid.UUID, includeCompleted bool) ([]AssignedChecklistItem, error) {
+	workspaces := repository.AccessibleWorkspaceIDs(s.DB, userID)
+
+	q := s.DB.Table("checklist_items").
+		Select(`checklist_items.*, checklists.title AS checklist_title, cards.id AS card_id, cards.title AS card_title,
+			boards.id AS board_id, boards.title AS board_title`).
+		Joins("JOIN checklists ON checklists.id = checklist_items.checklist_id AND checklists.deleted_at IS NULL").
+		Joins("JOIN cards ON cards.id = checklists.card_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id AND boards.deleted_at IS NULL").
+		Where("checklist_items.deleted_at IS NULL AND checklist_items.assignee_id = ?", userID).
+		Where("cards.is_archived = ? AND cards.is_template = ?", false, false).
+		Where("boards.workspace_id IN (?)", workspaces)
+	if !includeCompleted {
+		q = q.Where("checklist_items.is_completed = ?", false)
+	}
+
+	var items []AssignedChecklistItem
+	err := q.Order("CASE WHEN checklist_items.due_date IS NULL THEN 1 ELSE 0 END, checklist_items.due_date ASC, checklist_items.created_at ASC").
+		Scan(&items).Error
+	if items == nil {
+		items = []AssignedChecklistItem{}
+	}
+	return items, err
+}
@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupChecklistTestDB(t *testing.T) *gorm.DB {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, username TEXT, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checklists (
+		id TEXT PRIMARY KEY, card_id TEXT NOT NULL, title TEXT NOT NULL, position REAL NOT NULL,
+		version INTEGER DEFAULT 1, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checklist_items (
+		id TEXT PRIMARY KEY, checklist_id TEXT NOT NULL, title TEXT NOT NULL, is_completed INTEGER DEFAULT 0,
+		position REAL NOT NULL, assignee_id TEXT, due_date DATETIME, due_reminder_sent_at DATETIME,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE notifications (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, actor_id TEXT, type TEXT NOT NULL, title TEXT NOT NULL,
+		message TEXT NOT NULL, entity_id TEXT NOT NULL, entity_type TEXT NOT NULL, board_id TEXT,
+		is_read INTEGER DEFAULT 0, created_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE due_reminders (card_id TEXT, user_id TEXT, due_date DATETIME, created_at DATETIME, PRIMARY KEY (card_id, user_id, due_date))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checklist_templates (
+		id TEXT PRIMARY KEY, board_id TEXT, workspace_id TEXT, name TEXT NOT NULL, created_by TEXT NOT NULL,
+		created_at DATETIME, updated_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checklist_template_items (
+		id TEXT PRIMARY KEY, template_id TEXT NOT NULL, title TEXT NOT NULL, position REAL NOT NULL
+	)`).Error)
+	return db
+}
+
+func TestChecklistItems_AssignRemindAndListAcrossBoards(t *testing.T) {
+	db := setupChecklistTestDB(t)
+
+	owner, helper, outsider := uuid.New(), uuid.New(), uuid.New()
+	wsID, boardID, colID, cardID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name, username, email) VALUES (?, 'Owner', 'owner', 'owner@example.com')`, owner)
+	db.Exec(`INSERT INTO users (id, name, username, email) VALUES (?, 'Helper', 'helper', 'helper@example.com')`, helper)
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, helper)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Launch')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, boardID)
+	require.NoError(t, db.Create(&models.Card{ID: cardID, Title: "Release", ColumnID: colID, Position: 1}).Error)
+	checklist := models.Checklist{CardID: cardID, Title: "Steps", Position: 1}
+	require.NoError(t, db.Create(&checklist).Error)
+	item := models.ChecklistItem{ChecklistID: checklist.ID, Title: "Tag the build", Position: 1}
+	require.NoError(t, db.Create(&item).Error)
+
+	notifications := services.NewNotificationService(db, nil, nil)
+	svc := services.NewChecklistService(db, notifications)
+
+	_, _, err := svc.AssignItem(item.ID, &outsider, owner)
+	require.ErrorIs(t, err, services.ErrAssigneeNotMember)
+	_, _, err = svc.AssignItem(item.ID, &helper, outsider)
+	require.ErrorIs(t, err, services.ErrBoardAccessDenied)
+
+	assigned, _, err := svc.AssignItem(item.ID, &helper, owner)
+	require.NoError(t, err)
+	require.Equal(t, helper, *assigned.AssigneeID)
+
+	var assignment models.Notification
+	require.NoError(t, db.First(&assignment, "user_id = ? AND type = ?", helper, models.NotificationAssignment).Error)
+	require.Equal(t, cardID, assignment.EntityID)
+
+	due := time.Now().Add(2 * time.Hour)
+	_, _, err = svc.SetItemDueDate(item.ID, &due, owner)
+	require.NoError(t, err)
+
+	mine, err := svc.AssignedItems(helper, false)
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	require.Equal(t, "Release", mine[0].CardTitle)
+	require.Equal(t, "Launch", mine[0].BoardTitle)
+	require.Equal(t, item.ID, mine[0].ID)
+
+	reminders := services.NewDueDateReminderService(db, notifications, nil, nil, 24*time.Hour)
+	stats := reminders.RunOnce()
+	require.Equal(t, 1, stats.ItemRemindersSent)
+	stats = reminders.RunOnce()
+	require.Zero(t, stats.ItemRemindersSent, "an item is reminded once per due date")
+
+	// Mentions in a title resolve to workspace members only.
+	item.Title = "Ask @helper and @stranger"
+	svc.NotifyTitleMentions(&item, &models.Card{ID: cardID, Title: "Release", Column: models.Column{BoardID: boardID}}, owner, nil)
+	var mentions int64
+	db.Model(&models.Notification{}).Where("type = ?", models.NotificationMention).Count(&mentions)
+	require.Equal(t, int64(1), mentions)
+}
+
+func TestChecklistTemplates_ApplyByScopeAndCopy(t *testing.T) {
+	db := setupChecklistTestDB(t)
+
+	owner := uuid.New()
+	wsID, boardID, otherBoardID, colID, otherColID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Releases')`, boardID, wsID)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Support')`, otherBoardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Inbox', 1)`, otherColID, otherBoardID)
+	cardID, otherCardID := uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: cardID, Title: "v1.2", ColumnID: colID, Position: 1}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: otherCardID, Title: "Ticket", ColumnID: otherColID, Position: 1}).Error)
+
+	svc := services.NewChecklistService(db, nil)
+
+	_, err := svc.CreateTemplate(owner, &boardID, &wsID, "Both", nil)
+	require.ErrorIs(t, err, services.ErrInvalidTemplateScope)
+	_, err = svc.CreateTemplate(uuid.New(), nil, &wsID, "Sneaky", nil)
+	require.ErrorIs(t, err, services.ErrBoardAccessDenied)
+
+	release, err := svc.CreateTemplate(owner, &boardID, nil, "Release", []string{"Freeze", "Tag", "Announce"})
+	require.NoError(t, err)
+	shared, err := svc.CreateTemplate(owner, nil, &wsID, "Definition of done", []string{"Reviewed"})
+	require.NoError(t, err)
+
+	listed, err := svc.ListBoardTemplates(otherBoardID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1, "board templates stay on their board")
+	require.Equal(t, shared.ID, listed[0].ID)
+
+	checklist, err := svc.ApplyTemplate(cardID, release.ID, "")
+	require.NoError(t, err)
+	require.Equal(t, "Release", checklist.Title)
+	require.Len(t, checklist.Items, 3)
+	_, err = svc.ApplyTemplate(otherCardID, release.ID, "")
+	require.ErrorIs(t, err, services.ErrTemplateNotAvailable)
+
+	// Copying resets completion by default.
+	require.NoError(t, db.Model(&models.ChecklistItem{}).Where("checklist_id = ?", checklist.ID).Update("is_completed", true).Error)
+	copied, _, err := svc.CopyChecklist(checklist.ID, otherCardID, owner, false)
+	require.NoError(t, err)
+	require.Equal(t, otherCardID, copied.CardID)
+	var open int64
+	db.Model(&models.ChecklistItem{}).Where("checklist_id = ? AND is_completed = ?", copied.ID, false).Count(&open)
+	require.Equal(t, int64(3), open)
+
+	updated, err := svc.UpdateTemplate(release.ID, owner, nil, &[]string{"Freeze"})
+	require.NoError(t, err)
+	require.Len(t, updated.Items, 1)
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTemplateScope = errors.New("template must belong to exactly one board or workspace")
+	ErrTemplateNotAvailable = errors.New("template is not available on this board")
+	ErrInvalidTemplate      = errors.New("template needs a name and at most 100 items with non-empty titles")
+)
+
+// MaxChecklistTemplateItems caps template size so applying one stays a small write.
+const MaxChecklistTemplateItems = 100
+
+// CanAccessWorkspace reports whether the user owns or is an accepted member of the workspace.
+func (s *ChecklistService) CanAccessWorkspace(userID, workspaceID uuid.UUID) bool {
+	return canAccessWorkspace(s.DB, userID, workspaceID)
+}
+
+func canAccessWorkspace(db *gorm.DB, userID, workspaceID uuid.UUID) bool {
+	var count int64
+	db.Table("workspaces").
+		Where("id = ? AND id IN (?)", workspaceID, repository.AccessibleWorkspaceIDs(db, userID)).
+		Count(&count)
+	return count > 0
+}
+
+// canAccessTemplate checks the user can see the template's board or workspace.
+func (s *ChecklistService) canAccessTemplate(userID uuid.UUID, tmpl *models.ChecklistTemplate) bool {
+	if tmpl.BoardID != nil {
+		return s.CanAccessBoard(userID, *tmpl.BoardID)
+	}
+	if tmpl.WorkspaceID != nil {
+		return s.CanAccessWorkspace(userID, *tmpl.WorkspaceID)
+	}
+	return false
+}
+
+func normalizeTemplateItems(titles []string) ([]models.ChecklistTemplateItem, error) {
+	if len(titles) > MaxChecklistTemplateItems {
+		return nil, ErrInvalidTemplate
+	}
+	items := make([]models.ChecklistTemplateItem, 0, len(titles))
+	for i, title := range titles {
+		title = strings.TrimSpace(title)
+		if title == "" || len(title) > 500 {
+			return nil, ErrInvalidTemplate
+		}
+		items = append(items, models.ChecklistTemplateItem{
+			Title:    title,
+			Position: float64(i+1) * repository.PositionGap,
+		})
+	}
+	return items, nil
+}
+
+// CreateTemplate saves a template scoped to a board or a workspace.
+func (s *ChecklistService) CreateTemplate(actorID uuid.UUID, boardID, workspaceID *uuid.UUID, name string, titles []string) (*models.ChecklistTemplate, error) {
+	if (boardID == nil) == (workspaceID == nil) {
+		return nil, ErrInvalidTemplateScope
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 200 {
+		return nil, ErrInvalidTemplate
+	}
+	items, err := normalizeTemplateItems(titles)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := models.ChecklistTemplate{BoardID: boardID, WorkspaceID: workspaceID, Name: name, CreatedBy: actorID, Items: items}
+	if !s.canAccessTemplate(actorID, &tmpl) {
+		return nil, ErrBoardAccessDenied
+	}
+	if err := s.DB.Create(&tmpl).Error; err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// GetTemplate loads a template and its items for a user who can see it.
+func (s *ChecklistService) GetTemplate(templateID, userID uuid.UUID) (*models.ChecklistTemplate, error) {
+	var tmpl models.ChecklistTemplate
+	if err := s.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&tmpl, "id = ?", templateID).Error; err != nil {
+		return nil, err
+	}
+	if !s.canAccessTemplate(userID, &tmpl) {
+		return nil, ErrBoardAccessDenied
+	}
+	return &tmpl, nil
+}
+
+// ListBoardTemplates returns the board's own templates followed by those of its workspace.
+func (s *ChecklistService) ListBoardTemplates(boardID uuid.UUID) ([]models.ChecklistTemplate, error) {
+	var board models.Board
+	if err := s.DB.Select("id", "workspace_id").First(&board, "id = ?", boardID).Error; err != nil {
+		return nil, err
+	}
+	var templates []models.ChecklistTemplate
+	err := s.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).
+		Where("board_id = ? OR workspace_id = ?", boardID, board.WorkspaceID).
+		Order("CASE WHEN board_id IS NULL THEN 1 ELSE 0 END, name ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+func (s *ChecklistService) ListWorkspaceTemplates(workspaceID uuid.UUID) ([]models.ChecklistTemplate, error) {
+	var templates []models.ChecklistTemplate
+	err := s.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("workspace_id = ?", workspaceID).Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+// UpdateTemplate renames a template and/or replaces its items. Nil arguments are left unchanged.
+func (s *ChecklistService) UpdateTemplate(templateID, userID uuid.UUID, name *string, titles *[]string) (*models.ChecklistTemplate, error) {
+	tmpl, err := s.GetTemplate(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.ChecklistTemplateItem
+	if titles != nil {
+		if items, err = normalizeTemplateItems(*titles); err != nil {
+			return nil, err
+		}
+	}
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if trimmed == "" || len(trimmed) > 200 {
+			return nil, ErrInvalidTemplate
+		}
+		name = &trimmed
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if name != nil {
+			if err := tx.Model(tmpl).Update("name", *name).Error; err != nil {
+				return err
+			}
+		}
+		if titles == nil {
+			return nil
+		}
+		if err := tx.Where("template_id = ?", tmpl.ID).Delete(&models.ChecklistTemplateItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].TemplateID = tmpl.ID
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTemplate(templateID, userID)
+}
+
+func (s *ChecklistService) DeleteTemplate(templateID, userID uuid.UUID) error {
+	tmpl, err := s.GetTemplate(templateID, userID)
+	if err != nil {
+		return err
+	}
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", tmpl.ID).Delete(&models.ChecklistTemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tmpl).Error
+	})
+}
+
+// nextChecklistPosition returns the position after the card's last checklist.
+func nextChecklistPosition(tx *gorm.DB, cardID uuid.UUID) float64 {
+	var maxPos float64
+	tx.Model(&models.Checklist{}).Where("card_id = ?", cardID).
+		Select("COALESCE(MAX(position), 0)").Scan(&maxPos)
+	return maxPos + repository.PositionGap
+}
+
+// ApplyTemplate appends a checklist built from the template to the card. The template must
+// belong to the card's board or its workspace. An empty title uses the template name.
+// Access is not checked here so automation can apply templates; callers check the actor.
+func (s *ChecklistService) ApplyTemplate(cardID, templateID uuid.UUID, title string) (*models.Checklist, error) {
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	var board models.Board
+	if err := s.DB.Select("id", "workspace_id").First(&board, "id = ?", card.Column.BoardID).Error; err != nil {
+		return nil, err
+	}
+	var tmpl models.ChecklistTemplate
+	if err := s.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&tmpl, "id = ?", templateID).Error; err != nil {
+		return nil, err
+	}
+	onBoard := tmpl.BoardID != nil && *tmpl.BoardID == board.ID
+	inWorkspace := tmpl.WorkspaceID != nil && *tmpl.WorkspaceID == board.WorkspaceID
+	if !onBoard && !inWorkspace {
+		return nil, ErrTemplateNotAvailable
+	}
+
+	if strings.TrimSpace(title) == "" {
+		title = tmpl.Name
+	}
+	items := make([]models.ChecklistItem, 0, len(tmpl.Items))
+	for _, it := range tmpl.Items {
+		items = append(items, models.ChecklistItem{Title: it.Title, Position: it.Position})
+	}
+	return s.createChecklistWithItems(cardID, title, items)
+}
+
+// CopyChecklist copies a checklist onto another card the actor can access. Completion is reset
+// unless keepCompletion is set; assignees are kept only if they can access the target board.
+func (s *ChecklistService) CopyChecklist(sourceID, targetCardID, actorID uuid.UUID, keepCompletion bool) (*models.Checklist, *models.Card, error) {
+	var source models.Checklist
+	if err := s.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&source, "id = ?", sourceID).Error; err != nil {
+		return nil, nil, err
+	}
+	var sourceCard, target models.Card
+	if err := s.DB.Preload("Column").First(&sourceCard, "id = ?", source.CardID).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := s.DB.Preload("Column").First(&target, "id = ?", targetCardID).Error; err != nil {
+		return nil, nil, err
+	}
+	if !s.CanAccessBoard(actorID, sourceCard.Column.BoardID) || !s.CanAccessBoard(actorID, target.Column.BoardID) {
+		return nil, nil, ErrBoardAccessDenied
+	}
+
+	sameBoard := sourceCard.Column.BoardID == target.Column.BoardID
+	items := make([]models.ChecklistItem, 0, len(source.Items))
+	for _, it := range source.Items {
+		copied := models.ChecklistItem{
+			Title:    it.Title,
+			Position: it.Position,
+			DueDate:  it.DueDate,
+		}
+		if keepCompletion {
+			copied.IsCompleted = it.IsCompleted
+		}
+		if it.AssigneeID != nil && (sameBoard || s.CanAccessBoard(*it.AssigneeID, target.Column.BoardID)) {
+			copied.AssigneeID = it.AssigneeID
+		}
+		items = append(items, copied)
+	}
+
+	checklist, err := s.createChecklistWithItems(target.ID, source.Title, items)
+	if err != nil {
+		return nil, nil, err
+	}
+	return checklist, &target, nil
+}
+
+func (s *ChecklistService) createChecklistWithItems(cardID uuid.UUID, title string, items []models.ChecklistItem) (*models.Checklist, error) {
+	checklist := models.Checklist{CardID: cardID, Title: title}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		checklist.Position = nextChecklistPosition(tx, cardID)
+		if err := tx.Create(&checklist).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ChecklistID = checklist.ID
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	checklist.Items = items
+	return &checklist, nil
+}
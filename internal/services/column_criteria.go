@@ -0,0 +1,299 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxColumnCriteria caps how many criteria one column may define.
+const MaxColumnCriteria = 20
+
+var (
+	ErrInvalidCriterion       = errors.New("invalid column criterion")
+	ErrCriteriaOverrideDenied = errors.New("only workspace admins can override column criteria")
+)
+
+// UnmetCriterion is a column criterion a card fails for a particular move.
+type UnmetCriterion struct {
+	CriterionID uuid.UUID             `json:"criterion_id"`
+	ColumnID    uuid.UUID             `json:"column_id"`
+	ColumnName  string                `json:"column_name"`
+	Phase       models.CriterionPhase `json:"phase"`
+	Kind        models.CriterionKind  `json:"kind"`
+	Message     string                `json:"message"`
+}
+
+// CriteriaError rejects a move whose card does not meet the criteria of the column it
+// leaves or the column it enters.
+type CriteriaError struct {
+	Unmet []UnmetCriterion
+}
+
+func (e *CriteriaError) Error() string {
+	msgs := make([]string, len(e.Unmet))
+	for i, u := range e.Unmet {
+		msgs[i] = u.Message
+	}
+	return fmt.Sprintf("move blocked by column criteria: %s", strings.Join(msgs, "; "))
+}
+
+// ColumnCriterionInput is one criterion in a PUT /columns/:id/criteria request.
+type ColumnCriterionInput struct {
+	Phase         models.CriterionPhase `json:"phase"`
+	Kind          models.CriterionKind  `json:"kind"`
+	LabelID       *uuid.UUID            `json:"label_id"`
+	CustomFieldID *uuid.UUID            `json:"custom_field_id"`
+}
+
+type ColumnCriteriaService struct {
+	DB *gorm.DB
+}
+
+func NewColumnCriteriaService(db *gorm.DB) *ColumnCriteriaService {
+	return &ColumnCriteriaService{DB: db}
+}
+
+// GetColumn loads a column on a board the user can access.
+func (s *ColumnCriteriaService) GetColumn(columnID, userID uuid.UUID) (*models.Column, error) {
+	var column models.Column
+	if err := s.DB.First(&column, "id = ?", columnID).Error; err != nil {
+		return nil, err
+	}
+	if _, err := repository.NewBoardRepository(s.DB).GetBoardByID(column.BoardID, userID); err != nil {
+		return nil, ErrBoardAccessDenied
+	}
+	return &column, nil
+}
+
+// ListCriteria returns the column's criteria, entry criteria first.
+func (s *ColumnCriteriaService) ListCriteria(columnID uuid.UUID) ([]models.ColumnCriterion, error) {
+	criteria := []models.ColumnCriterion{}
+	err := s.DB.Where("column_id = ?", columnID).Order("phase ASC, position ASC").Find(&criteria).Error
+	return criteria, err
+}
+
+// ReplaceCriteria swaps the column's criteria for inputs. Labels and fields must belong
+// to the column's board.
+func (s *ColumnCriteriaService) ReplaceCriteria(column *models.Column, inputs []ColumnCriterionInput) ([]models.ColumnCriterion, error) {
+	if len(inputs) > MaxColumnCriteria {
+		return nil, fmt.Errorf("%w: at most %d criteria per column", ErrInvalidCriterion, MaxColumnCriteria)
+	}
+	criteria := make([]models.ColumnCriterion, 0, len(inputs))
+	for i, in := range inputs {
+		if !in.Phase.Valid() {
+			return nil, fmt.Errorf("%w: phase must be entry or exit", ErrInvalidCriterion)
+		}
+		if !in.Kind.Valid() {
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidCriterion, in.Kind)
+		}
+		criterion := models.ColumnCriterion{ColumnID: column.ID, Phase: in.Phase, Kind: in.Kind, Position: i}
+		switch in.Kind {
+		case models.CriterionLabel:
+			if in.LabelID == nil {
+				return nil, fmt.Errorf("%w: label criteria need label_id", ErrInvalidCriterion)
+			}
+			var count int64
+			s.DB.Model(&models.Label{}).Where("id = ? AND board_id = ?", *in.LabelID, column.BoardID).Count(&count)
+			if count == 0 {
+				return nil, fmt.Errorf("%w: label not found on this board", ErrInvalidCriterion)
+			}
+			criterion.LabelID = in.LabelID
+		case models.CriterionCustomField:
+			if in.CustomFieldID == nil {
+				return nil, fmt.Errorf("%w: custom_field criteria need custom_field_id", ErrInvalidCriterion)
+			}
+			var count int64
+			s.DB.Model(&models.CustomField{}).Where("id = ? AND board_id = ?", *in.CustomFieldID, column.BoardID).Count(&count)
+			if count == 0 {
+				return nil, fmt.Errorf("%w: field not found on this board", ErrInvalidCriterion)
+			}
+			criterion.CustomFieldID = in.CustomFieldID
+		}
+		criteria = append(criteria, criterion)
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("column_id = ?", column.ID).Delete(&models.ColumnCriterion{}).Error; err != nil {
+			return err
+		}
+		if len(criteria) == 0 {
+			return nil
+		}
+		return tx.Create(&criteria).Error
+	})
+	return criteria, err
+}
+
+// CheckMoveCriteria lists the criteria the card would fail by moving to targetColumnID:
+// the exit criteria of its current column and the entry criteria of the target. Moves
+// within a column are never checked.
+func (s *CardService) CheckMoveCriteria(cardID, targetColumnID uuid.UUID) ([]UnmetCriterion, error) {
+	var card models.Card
+	if err := s.Repo.DB.First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	return checkMoveCriteria(s.Repo.DB, &card, targetColumnID)
+}
+
+// CanOverrideCriteria reports whether the user may move a card past the unmet criteria:
+// only the owner or an admin of each criterion's workspace can.
+func (s *CardService) CanOverrideCriteria(userID uuid.UUID, unmet []UnmetCriterion) bool {
+	columnIDs := make([]uuid.UUID, 0, len(unmet))
+	for _, u := range unmet {
+		columnIDs = append(columnIDs, u.ColumnID)
+	}
+	var workspaceIDs []uuid.UUID
+	if err := s.Repo.DB.Table("columns").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("columns.id IN ?", columnIDs).
+		Distinct().Pluck("boards.workspace_id", &workspaceIDs).Error; err != nil || len(workspaceIDs) == 0 {
+		return false
+	}
+	var count int64
+	s.Repo.DB.Table("workspaces").
+		Where("id IN ?", workspaceIDs).
+		Where("owner_id = ? OR id IN (?)", userID,
+			s.Repo.DB.Table("workspace_members").Select("workspace_id").
+				Where("user_id = ? AND status = 'accepted' AND role IN ?", userID, []string{"owner", "admin"})).
+		Count(&count)
+	return count == int64(len(workspaceIDs))
+}
+
+func checkMoveCriteria(db *gorm.DB, card *models.Card, targetColumnID uuid.UUID) ([]UnmetCriterion, error) {
+	unmet := []UnmetCriterion{}
+	if card.ColumnID == targetColumnID {
+		return unmet, nil
+	}
+	var criteria []models.ColumnCriterion
+	if err := db.Where("(column_id = ? AND phase = ?) OR (column_id = ? AND phase = ?)",
+		card.ColumnID, models.CriterionExit, targetColumnID, models.CriterionEntry).
+		Order("phase DESC, position ASC").Find(&criteria).Error; err != nil {
+		return nil, err
+	}
+	if len(criteria) == 0 {
+		return unmet, nil
+	}
+
+	var columns []models.Column
+	if err := db.Select("id", "name").Where("id IN ?", []uuid.UUID{card.ColumnID, targetColumnID}).Find(&columns).Error; err != nil {
+		return nil, err
+	}
+	names := map[uuid.UUID]string{}
+	for _, col := range columns {
+		names[col.ID] = col.Name
+	}
+
+	for _, c := range criteria {
+		message, err := evaluateCriterion(db, card, &c)
+		if err != nil {
+			return nil, err
+		}
+		if message == "" {
+			continue
+		}
+		unmet = append(unmet, UnmetCriterion{
+			CriterionID: c.ID,
+			ColumnID:    c.ColumnID,
+			ColumnName:  names[c.ColumnID],
+			Phase:       c.Phase,
+			Kind:        c.Kind,
+			Message:     message,
+		})
+	}
+	return unmet, nil
+}
+
+// evaluateCriterion returns why the card fails c, or "" when it meets it. Labels and
+// fields linked to the same workspace definition count as the same label or field, so a
+// card from another board can meet a criterion with its shared labels and values.
+func evaluateCriterion(db *gorm.DB, card *models.Card, c *models.ColumnCriterion) (string, error) {
+	var count int64
+	switch c.Kind {
+	case models.CriterionAssignee:
+		if err := db.Table("card_members").Where("card_id = ?", card.ID).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return "needs an assignee", nil
+		}
+	case models.CriterionDueDate:
+		if card.DueDate == nil {
+			return "needs a due date", nil
+		}
+	case models.CriterionEstimate:
+		if card.EstimateMinutes == nil && card.StoryPoints == nil {
+			return "needs an estimate", nil
+		}
+	case models.CriterionChecklistsComplete:
+		if err := db.Table("checklist_items").
+			Joins("JOIN checklists ON checklists.id = checklist_items.checklist_id").
+			Where("checklists.card_id = ? AND checklist_items.is_completed = ?", card.ID, false).
+			Where("checklists.deleted_at IS NULL AND checklist_items.deleted_at IS NULL").
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return fmt.Sprintf("has %d unfinished checklist item(s)", count), nil
+		}
+	case models.CriterionDescription:
+		if strings.TrimSpace(card.Description) == "" {
+			return "needs a description", nil
+		}
+	case models.CriterionPriority:
+		if card.Priority == "" || card.Priority == models.PriorityNone {
+			return "needs a priority", nil
+		}
+	case models.CriterionLabel:
+		var label models.Label
+		if err := db.First(&label, "id = ?", c.LabelID).Error; err != nil {
+			return "", ignoreNotFound(err) // the label was deleted
+		}
+		match := db.Where("labels.id = ?", label.ID)
+		if label.WorkspaceLabelID != nil {
+			match = match.Or("labels.workspace_label_id = ?", *label.WorkspaceLabelID)
+		}
+		if err := db.Model(&models.Label{}).
+			Joins("JOIN card_labels ON card_labels.label_id = labels.id").
+			Where("card_labels.card_id = ?", card.ID).Where(match).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return fmt.Sprintf("needs label %q", label.Name), nil
+		}
+	case models.CriterionCustomField:
+		var field models.CustomField
+		if err := db.First(&field, "id = ?", c.CustomFieldID).Error; err != nil {
+			return "", ignoreNotFound(err) // the field was deleted
+		}
+		fieldIDs := db.Model(&models.CustomField{}).Select("id").Where("id = ?", field.ID)
+		if field.WorkspaceFieldID != nil {
+			fieldIDs = fieldIDs.Or("workspace_field_id = ?", *field.WorkspaceFieldID)
+		}
+		var values []models.CardCustomFieldValue
+		if err := db.Where("card_id = ? AND custom_field_id IN (?)", card.ID, fieldIDs).Find(&values).Error; err != nil {
+			return "", err
+		}
+		for i := range values {
+			if _, ok := storedFieldText(&values[i], field.ValueType()); ok && values[i].FormulaError == "" {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("needs a value for %q", field.Name), nil
+	}
+	return "", nil
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnCriteria_BlockMovesUntilMetOrOverridden(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE column_criteria (id TEXT PRIMARY KEY, column_id TEXT, phase TEXT, kind TEXT, label_id TEXT, custom_field_id TEXT, position INTEGER DEFAULT 0, created_at DATETIME)`,
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE checklists (id TEXT PRIMARY KEY, card_id TEXT, title TEXT, position REAL, version INTEGER DEFAULT 1, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE checklist_items (id TEXT PRIMARY KEY, checklist_id TEXT, title TEXT, is_completed INTEGER DEFAULT 0, position REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner, admin, member := uuid.New(), uuid.New(), uuid.New()
+	wsID, boardID := uuid.New(), uuid.New()
+	doing, review := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'admin', 'accepted'), (?, ?, 'member', 'accepted')`,
+		wsID, admin, wsID, member)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Dev')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1), (?, ?, 'Review', 2)`, doing, boardID, review, boardID)
+	cardID, other := uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: cardID, Title: "Login", ColumnID: doing, Position: 1}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: other, Title: "Logout", ColumnID: doing, Position: 2}).Error)
+
+	criteria := services.NewColumnCriteriaService(db)
+	column, err := criteria.GetColumn(review, member)
+	require.NoError(t, err)
+	_, err = criteria.GetColumn(review, uuid.New())
+	require.ErrorIs(t, err, services.ErrBoardAccessDenied)
+
+	_, err = criteria.ReplaceCriteria(column, []services.ColumnCriterionInput{{Phase: "during", Kind: models.CriterionDueDate}})
+	require.ErrorIs(t, err, services.ErrInvalidCriterion)
+	_, err = criteria.ReplaceCriteria(column, []services.ColumnCriterionInput{{Phase: models.CriterionEntry, Kind: models.CriterionLabel}})
+	require.ErrorIs(t, err, services.ErrInvalidCriterion)
+	_, err = criteria.ReplaceCriteria(column, []services.ColumnCriterionInput{
+		{Phase: models.CriterionEntry, Kind: models.CriterionAssignee},
+		{Phase: models.CriterionEntry, Kind: models.CriterionEstimate},
+		{Phase: models.CriterionEntry, Kind: models.CriterionChecklistsComplete},
+	})
+	require.NoError(t, err)
+	doingColumn, _ := criteria.GetColumn(doing, member)
+	_, err = criteria.ReplaceCriteria(doingColumn, []services.ColumnCriterionInput{{Phase: models.CriterionExit, Kind: models.CriterionDueDate}})
+	require.NoError(t, err)
+
+	svc := services.NewCardService(repository.NewCardRepository(db), nil)
+	checklist := uuid.New()
+	db.Exec(`INSERT INTO checklists (id, card_id, title, position) VALUES (?, ?, 'QA', 1)`, checklist, cardID)
+	db.Exec(`INSERT INTO checklist_items (id, checklist_id, title, is_completed, position) VALUES (?, ?, 'tests', 0, 1)`, uuid.New(), checklist)
+
+	// Every unmet criterion is reported, exit criteria first.
+	_, err = svc.MoveCard(cardID, review, 1)
+	var criteriaErr *services.CriteriaError
+	require.True(t, errors.As(err, &criteriaErr), err)
+	kinds := []models.CriterionKind{}
+	for _, u := range criteriaErr.Unmet {
+		kinds = append(kinds, u.Kind)
+	}
+	require.Equal(t, []models.CriterionKind{models.CriterionDueDate, models.CriterionAssignee, models.CriterionEstimate, models.CriterionChecklistsComplete}, kinds)
+	require.Equal(t, "Doing", criteriaErr.Unmet[0].ColumnName)
+	require.Equal(t, models.CriterionExit, criteriaErr.Unmet[0].Phase)
+	require.Equal(t, "has 1 unfinished checklist item(s)", criteriaErr.Unmet[3].Message)
+
+	// Moves within the column are never checked.
+	_, err = svc.MoveCard(cardID, doing, 5)
+	require.NoError(t, err)
+
+	// Only workspace owners and admins may override.
+	require.False(t, svc.CanOverrideCriteria(member, criteriaErr.Unmet))
+	require.True(t, svc.CanOverrideCriteria(admin, criteriaErr.Unmet))
+	require.True(t, svc.CanOverrideCriteria(owner, criteriaErr.Unmet))
+	_, err = svc.MoveCardWithOptions(other, review, 2, repository.MoveCardOptions{OverrideCriteria: true})
+	require.NoError(t, err)
+
+	// Bulk moves report the cards that fail.
+	_, err = svc.BulkUpdateCards(owner, boardID, []uuid.UUID{cardID}, services.BulkCardOperation{Type: services.BulkMoveCard, ColumnID: &review})
+	var bulkErr *services.BulkCardError
+	require.True(t, errors.As(err, &bulkErr), err)
+	require.Equal(t, "CRITERIA_NOT_MET", bulkErr.Failures[0].Code)
+
+	// Once the card meets every criterion it moves.
+	due, minutes := time.Now().Add(48*time.Hour), 90
+	db.Model(&models.Card{}).Where("id = ?", cardID).Updates(map[string]interface{}{"due_date": due, "estimate_minutes": minutes})
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, cardID, member)
+	db.Exec(`UPDATE checklist_items SET is_completed = 1 WHERE checklist_id = ?`, checklist)
+	unmet, err := svc.CheckMoveCriteria(cardID, review)
+	require.NoError(t, err)
+	require.Empty(t, unmet)
+	moved, err := svc.MoveCard(cardID, review, 3)
+	require.NoError(t, err)
+	require.Equal(t, review, moved.ColumnID)
+}
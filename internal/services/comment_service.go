@@ -1,10 +1,21 @@
 package services
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidParentComment = errors.New("parent comment must be on the same card")
+	ErrInvalidEmoji         = errors.New("reaction must be a single emoji")
 )
 
 type CommentService struct {
@@ -19,19 +30,32 @@ func NewCommentService(db *gorm.DB, activityService *ActivityService) *CommentSe
 	}
 }
 
-func (s *CommentService) CreateComment(cardID, userID uuid.UUID, content string) (*models.Comment, error) {
+// CreateComment adds a comment to a card. With a parentID it becomes a reply; replying to a
+// reply attaches to the top of that thread so threads stay one level deep.
+func (s *CommentService) CreateComment(cardID, userID uuid.UUID, content string, parentID *uuid.UUID) (*models.Comment, error) {
 	comment := models.Comment{
 		CardID:  cardID,
 		UserID:  userID,
 		Content: content,
 	}
+	if parentID != nil {
+		var parent models.Comment
+		if err := s.DB.First(&parent, "id = ?", *parentID).Error; err != nil || parent.CardID != cardID {
+			return nil, ErrInvalidParentComment
+		}
+		root := parent.ID
+		if parent.ParentID != nil {
+			root = *parent.ParentID
+		}
+		comment.ParentID = &root
+	}
 
 	if err := s.DB.Create(&comment).Error; err != nil {
 		return nil, err
 	}
 
 	// Fetch Created Comment with User
-	if err := s.DB.Preload("User").First(&comment, "id = ?", comment.ID).Error; err != nil {
+	if err := s.DB.Preload("User").Preload("Reactions").First(&comment, "id = ?", comment.ID).Error; err != nil {
 		return nil, err
 	}
 
@@ -57,7 +81,7 @@ func (s *CommentService) DeleteComment(commentID, userID uuid.UUID) error {
 
 	// Check author ownership
 	if comment.UserID == userID {
-		return s.DB.Delete(&comment).Error
+		return s.deleteWithThread(&comment)
 	}
 
 	// Not the author, check if admin or owner of the workspace
@@ -77,19 +101,40 @@ func (s *CommentService) DeleteComment(commentID, userID uuid.UUID) error {
 	if err == nil {
 		// Is workspace owner?
 		if workspaceInfo.OwnerID == userID {
-			return s.DB.Delete(&comment).Error
+			return s.deleteWithThread(&comment)
 		}
 
 		// Is workspace admin?
 		var member models.WorkspaceMember
 		if err := s.DB.Where("workspace_id = ? AND user_id = ? AND role = 'admin'", workspaceInfo.WorkspaceID, userID).First(&member).Error; err == nil {
-			return s.DB.Delete(&comment).Error
+			return s.deleteWithThread(&comment)
 		}
 	}
 
 	return gorm.ErrInvalidData // Unauthorized
 }
 
+// deleteWithThread removes a comment with its replies, reactions and edit history.
+func (s *CommentService) deleteWithThread(comment *models.Comment) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		ids := []uuid.UUID{comment.ID}
+		var replies []uuid.UUID
+		if err := tx.Model(&models.Comment{}).Where("parent_id = ?", comment.ID).Pluck("id", &replies).Error; err != nil {
+			return err
+		}
+		ids = append(ids, replies...)
+		if err := tx.Where("comment_id IN ?", ids).Delete(&models.CommentReaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN ?", ids).Delete(&models.CommentEdit{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Comment{}).Error
+	})
+}
+
+// UpdateComment replaces a comment's content, keeping the old content in its edit history.
+// Only the author may edit. Unchanged content is a no-op.
 func (s *CommentService) UpdateComment(commentID, userID uuid.UUID, content string) (*models.Comment, error) {
 	var comment models.Comment
 	if err := s.DB.First(&comment, "id = ?", commentID).Error; err != nil {
@@ -100,10 +145,159 @@ func (s *CommentService) UpdateComment(commentID, userID uuid.UUID, content stri
 		return nil, gorm.ErrInvalidData
 	}
 
-	comment.Content = content
-	if err := s.DB.Save(&comment).Error; err != nil {
-		return nil, err
+	if comment.Content != content {
+		now := time.Now()
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&models.CommentEdit{
+				CommentID:       comment.ID,
+				EditedBy:        userID,
+				PreviousContent: comment.Content,
+			}).Error; err != nil {
+				return err
+			}
+			return tx.Model(&comment).Updates(map[string]interface{}{
+				"content":   content,
+				"is_edited": true,
+				"edited_at": now,
+			}).Error
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &comment, nil
+	return s.GetComment(comment.ID)
+}
+
+// GetComment loads a comment with its author and reactions.
+func (s *CommentService) GetComment(commentID uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	err := s.DB.Preload("User").Preload("Reactions").First(&comment, "id = ?", commentID).Error
+	return &comment, err
+}
+
+// EditHistory returns a comment's previous versions, newest first.
+func (s *CommentService) EditHistory(commentID uuid.UUID) ([]models.CommentEdit, error) {
+	var edits []models.CommentEdit
+	err := s.DB.Where("comment_id = ?", commentID).Order("created_at DESC").Find(&edits).Error
+	return edits, err
+}
+
+// CommentBoard resolves the board a comment's card lives on.
+func (s *CommentService) CommentBoard(commentID uuid.UUID) (*models.Comment, uuid.UUID, error) {
+	var comment models.Comment
+	if err := s.DB.First(&comment, "id = ?", commentID).Error; err != nil {
+		return nil, uuid.Nil, err
+	}
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", comment.CardID).Error; err != nil {
+		return nil, uuid.Nil, err
+	}
+	return &comment, card.Column.BoardID, nil
+}
+
+// CanAccessBoard reports whether the user owns or is an accepted member of the board's workspace.
+func (s *CommentService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+// validEmoji accepts one emoji: a pictograph with optional variation selector, skin tone or
+// tag sequence, several of those joined by zero-width joiners, a flag (two regional indicators)
+// or a keycap. Anything else, including plain text, is rejected.
+func validEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > 32 {
+		return false
+	}
+	runes := []rune(emoji)
+	if len(runes) == 2 && isRegionalIndicator(runes[0]) && isRegionalIndicator(runes[1]) {
+		return true
+	}
+	if n := len(runes); n >= 2 && runes[n-1] == 0x20E3 && strings.ContainsRune("0123456789#*", runes[0]) &&
+		(n == 2 || (n == 3 && runes[1] == 0xFE0F)) {
+		return true
+	}
+
+	expectBase := true
+	for _, r := range runes {
+		switch {
+		case r == 0x200D: // zero-width joiner
+			if expectBase {
+				return false
+			}
+			expectBase = true
+		case r == 0xFE0E || r == 0xFE0F || (r >= 0x1F3FB && r <= 0x1F3FF) || (r >= 0xE0020 && r <= 0xE007F):
+			if expectBase {
+				return false
+			}
+		case isPictograph(r):
+			if !expectBase {
+				return false
+			}
+			expectBase = false
+		default:
+			return false
+		}
+	}
+	return !expectBase
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isPictograph approximates Unicode's Extended_Pictographic property.
+func isPictograph(r rune) bool {
+	switch {
+	case isRegionalIndicator(r):
+		return false
+	case r >= 0x1F000 && r <= 0x1FAFF,
+		r >= 0x2190 && r <= 0x21FF,
+		r >= 0x2300 && r <= 0x23FF,
+		r >= 0x25A0 && r <= 0x27BF,
+		r >= 0x2900 && r <= 0x297F,
+		r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	}
+	switch r {
+	case 0x00A9, 0x00AE, 0x203C, 0x2049, 0x2122, 0x2139, 0x24C2, 0x3030, 0x303D, 0x3297, 0x3299:
+		return true
+	}
+	return false
+}
+
+// ToggleReaction adds the user's emoji to a comment, or removes it if already there.
+// It returns whether the reaction is now present and the comment's updated counts.
+func (s *CommentService) ToggleReaction(commentID, userID uuid.UUID, emoji string) (bool, []models.ReactionCount, error) {
+	emoji = strings.TrimSpace(emoji)
+	if !validEmoji(emoji) {
+		return false, nil, ErrInvalidEmoji
+	}
+
+	added := false
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+			Delete(&models.CommentReaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+		added = true
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.CommentReaction{
+			CommentID: commentID,
+			UserID:    userID,
+			Emoji:     emoji,
+		}).Error
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	var reactions []models.CommentReaction
+	if err := s.DB.Where("comment_id = ?", commentID).Find(&reactions).Error; err != nil {
+		return added, nil, err
+	}
+	return added, models.SummarizeReactions(reactions), nil
 }
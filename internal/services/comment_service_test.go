@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestComments_RepliesEditsAndReactions(t *testing.T) {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE comments (
+		id TEXT PRIMARY KEY, card_id TEXT NOT NULL, user_id TEXT NOT NULL, parent_id TEXT, content TEXT NOT NULL,
+		is_edited INTEGER DEFAULT 0, edited_at DATETIME, created_at DATETIME, updated_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE comment_edits (
+		id TEXT PRIMARY KEY, comment_id TEXT NOT NULL, edited_by TEXT NOT NULL, previous_content TEXT NOT NULL, created_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE comment_reactions (
+		id TEXT PRIMARY KEY, comment_id TEXT NOT NULL, user_id TEXT NOT NULL, emoji TEXT NOT NULL, created_at DATETIME,
+		UNIQUE (comment_id, user_id, emoji)
+	)`).Error)
+
+	alice, bob := uuid.New(), uuid.New()
+	colID, cardID, otherCardID := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, uuid.New())
+	require.NoError(t, db.Create(&models.Card{ID: cardID, Title: "Card", ColumnID: colID, Position: 1}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: otherCardID, Title: "Other", ColumnID: colID, Position: 2}).Error)
+
+	svc := services.NewCommentService(db, services.NewActivityService(db))
+
+	root, err := svc.CreateComment(cardID, alice, "Ship it?", nil)
+	require.NoError(t, err)
+	reply, err := svc.CreateComment(cardID, bob, "Yes", &root.ID)
+	require.NoError(t, err)
+	nested, err := svc.CreateComment(cardID, alice, "Great", &reply.ID)
+	require.NoError(t, err)
+	require.Equal(t, root.ID, *nested.ParentID, "replies to replies join the root thread")
+	_, err = svc.CreateComment(otherCardID, bob, "Wrong card", &root.ID)
+	require.ErrorIs(t, err, services.ErrInvalidParentComment)
+
+	_, err = svc.UpdateComment(root.ID, bob, "hijack")
+	require.ErrorIs(t, err, gorm.ErrInvalidData)
+	edited, err := svc.UpdateComment(root.ID, alice, "Ship it today?")
+	require.NoError(t, err)
+	require.True(t, edited.IsEdited)
+	require.NotNil(t, edited.EditedAt)
+	history, err := svc.EditHistory(root.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "Ship it?", history[0].PreviousContent)
+
+	added, counts, err := svc.ToggleReaction(root.ID, alice, "🎉")
+	require.NoError(t, err)
+	require.True(t, added)
+	_, counts, err = svc.ToggleReaction(root.ID, bob, "🎉")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{alice, bob}, counts[0].UserIDs)
+	require.Equal(t, 2, counts[0].Count)
+	added, counts, err = svc.ToggleReaction(root.ID, alice, "🎉")
+	require.NoError(t, err)
+	require.False(t, added, "a second toggle removes the reaction")
+	require.Equal(t, 1, counts[0].Count)
+	for _, text := range []string{"two words", "lol", "<svg>", "🎉🎉", "🏽", "a‍👍"} {
+		_, _, err = svc.ToggleReaction(root.ID, alice, text)
+		require.ErrorIs(t, err, services.ErrInvalidEmoji, text)
+	}
+	for _, emoji := range []string{"👍🏽", "❤️", "👩‍💻", "🇳🇱", "1️⃣", "🏳️‍🌈"} {
+		for range 2 { // toggling twice leaves no reaction behind
+			_, _, err = svc.ToggleReaction(root.ID, bob, emoji)
+			require.NoError(t, err, emoji)
+		}
+	}
+
+	loaded, err := svc.GetComment(root.ID)
+	require.NoError(t, err)
+	require.Len(t, loaded.ReactionSummary, 1)
+
+	require.NoError(t, svc.DeleteComment(root.ID, alice))
+	var remaining int64
+	db.Model(&models.Comment{}).Where("card_id = ?", cardID).Count(&remaining)
+	require.Zero(t, remaining, "deleting a thread root removes its replies")
+}
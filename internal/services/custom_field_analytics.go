@@ -0,0 +1,130 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FieldGroupStat is one bucket when cards are grouped by a custom field's value.
+type FieldGroupStat struct {
+	Key    string   `json:"key"`  // option, user ID, "true"/"false", "2026-03", currency code...; "" for no value
+	Name   string   `json:"name"` // display label
+	Count  int64    `json:"count"`
+	Amount *float64 `json:"amount,omitempty"` // currency fields: total per currency code
+}
+
+// GroupCardsByField counts the active cards on boardIDs by their value for field. Cards
+// without a value are counted under "No value"; multi_select cards count once per option.
+// For a field linked to a workspace field, values of every board's copy are counted.
+func GroupCardsByField(db *gorm.DB, field *models.CustomField, boardIDs []uuid.UUID) ([]FieldGroupStat, error) {
+	stats := []FieldGroupStat{}
+	if len(boardIDs) == 0 {
+		return stats, nil
+	}
+
+	var total int64
+	if err := db.Table("cards").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Where("columns.board_id IN ? AND cards.is_archived = ?", boardIDs, false).
+		Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	fieldFilter := db.Where("card_custom_field_values.custom_field_id = ?", field.ID)
+	if field.WorkspaceFieldID != nil {
+		fieldFilter = db.Where("card_custom_field_values.custom_field_id IN (?)",
+			db.Model(&models.CustomField{}).Select("id").Where("workspace_field_id = ?", *field.WorkspaceFieldID))
+	}
+	var values []models.CardCustomFieldValue
+	if err := db.Select("card_custom_field_values.*").
+		Joins("JOIN cards ON cards.id = card_custom_field_values.card_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Where(fieldFilter).
+		Where("columns.board_id IN ? AND cards.is_archived = ?", boardIDs, false).
+		Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	buckets := map[string]*FieldGroupStat{}
+	add := func(key, name string, amount *float64) {
+		b, ok := buckets[key]
+		if !ok {
+			b = &FieldGroupStat{Key: key, Name: name}
+			buckets[key] = b
+		}
+		b.Count++
+		if amount != nil {
+			sum := *amount
+			if b.Amount != nil {
+				sum += *b.Amount
+			}
+			b.Amount = &sum
+		}
+	}
+
+	valueType := field.ValueType()
+	withValue := int64(0)
+	for i := range values {
+		v := &values[i]
+		if _, ok := storedFieldText(v, valueType); !ok || v.FormulaError != "" {
+			continue
+		}
+		withValue++
+		switch valueType {
+		case models.FieldTypeMultiSelect:
+			for _, opt := range v.ValueList {
+				add(opt, opt, nil)
+			}
+		case models.FieldTypeUser:
+			add(v.ValueUserID.String(), "", nil)
+		case models.FieldTypeCheckbox:
+			name := "No"
+			if v.ValueBool {
+				name = "Yes"
+			}
+			add(strconv.FormatBool(v.ValueBool), name, nil)
+		case models.FieldTypeDate:
+			month := v.ValueDate.UTC().Format("2006-01")
+			add(month, month, nil)
+		case models.FieldTypeCurrency:
+			amount := v.ValueNumber
+			add(v.ValueCurrency, v.ValueCurrency, &amount)
+		default:
+			text, _ := storedFieldText(v, valueType)
+			add(text, text, nil)
+		}
+	}
+
+	if field.Type == models.FieldTypeUser && len(buckets) > 0 {
+		ids := make([]string, 0, len(buckets))
+		for id := range buckets {
+			ids = append(ids, id)
+		}
+		var users []models.User
+		db.Select("id", "name").Where("id IN ?", ids).Find(&users)
+		for _, u := range users {
+			if b, ok := buckets[u.ID.String()]; ok {
+				b.Name = u.Name
+			}
+		}
+	}
+
+	for _, b := range buckets {
+		stats = append(stats, *b)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	if missing := total - withValue; missing > 0 {
+		stats = append(stats, FieldGroupStat{Key: "", Name: "No value", Count: missing})
+	}
+	return stats, nil
+}
@@ -2,35 +2,106 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"nexus-backend/internal/models"
 	"nexus-backend/internal/repository"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidCustomField = errors.New("invalid custom field")
+	ErrFieldUserNotMember = errors.New("user is not a member of this board's workspace")
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 type CustomFieldService struct {
-	Repo *repository.CustomFieldRepository
+	Repo                *repository.CustomFieldRepository
+	NotificationService *NotificationService
+	AutomationService   *AutomationService
+	Formulas            *FormulaService
 }
 
-func NewCustomFieldService(repo *repository.CustomFieldRepository) *CustomFieldService {
-	return &CustomFieldService{Repo: repo}
+func NewCustomFieldService(repo *repository.CustomFieldRepository, notificationService *NotificationService, automationService *AutomationService) *CustomFieldService {
+	return &CustomFieldService{
+		Repo:                repo,
+		NotificationService: notificationService,
+		AutomationService:   automationService,
+		Formulas:            NewFormulaService(repo.DB),
+	}
 }
 
 func (s *CustomFieldService) CreateField(boardID uuid.UUID, name string, fieldType models.CustomFieldType, options []string) (*models.CustomField, error) {
-	field := &models.CustomField{
+	if !fieldType.Valid() {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidCustomField, fieldType)
+	}
+	if fieldType == models.FieldTypeFormula {
+		return nil, fmt.Errorf("%w: formula fields need a formula", ErrInvalidCustomField)
+	}
+	if fieldType.HasOptions() && len(options) == 0 {
+		return nil, fmt.Errorf("%w: %s fields need at least one option", ErrInvalidCustomField, fieldType)
+	}
+	return s.createField(&models.CustomField{
 		BoardID: boardID,
 		Name:    name,
 		Type:    fieldType,
 		Options: options,
+	})
+}
+
+// CreateFormulaField adds a field computed from formula. The formula is compiled against
+// the board's other fields and rejected if it is invalid or part of a reference cycle.
+func (s *CustomFieldService) CreateFormulaField(boardID uuid.UUID, name, formula string) (*models.CustomField, error) {
+	formula = strings.TrimSpace(formula)
+	if formula == "" {
+		return nil, fmt.Errorf("%w: formula fields need a formula", ErrInvalidCustomField)
 	}
+	return s.createField(&models.CustomField{
+		BoardID: boardID,
+		Name:    name,
+		Type:    models.FieldTypeFormula,
+		Formula: formula,
+	})
+}
+
+// createField stores field at the end of the board's list, provided the board's formulas
+// still compile with it, and recomputes formulas that may now read it.
+func (s *CustomFieldService) createField(field *models.CustomField) (*models.CustomField, error) {
+	field.ID = uuid.New()
 
 	// Simple position logic: put at end
-	existing, _ := s.Repo.GetFieldsByBoardID(boardID)
+	existing, _ := s.Repo.GetFieldsByBoardID(field.BoardID)
 	field.Position = float64((len(existing) + 1) * 1000)
 
-	err := s.Repo.CreateField(field)
+	after, err := checkFormulaChange(s.Repo.DB, field.BoardID, *field)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range after {
+		if f.ID == field.ID {
+			field.ResultType = f.ResultType
+		}
+	}
+
+	err = s.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewCustomFieldRepository(tx).CreateField(field); err != nil {
+			return err
+		}
+		return saveFormulaDefinitions(tx, after)
+	})
+	if err == nil {
+		s.Formulas.RecomputeBoard(field.BoardID)
+	}
 	return field, err
 }
 
@@ -39,20 +110,105 @@ func (s *CustomFieldService) GetFields(boardID uuid.UUID) ([]models.CustomField,
 }
 
 func (s *CustomFieldService) DeleteField(id uuid.UUID) error {
+	field, _ := s.Repo.GetFieldByID(id)
 	// Clear dependent card values first to avoid FK constraint failures.
 	if err := s.Repo.DeleteValuesByFieldID(id); err != nil {
 		return err
 	}
-	return s.Repo.DeleteField(id)
+	if err := s.Repo.DeleteField(id); err != nil {
+		return err
+	}
+	// Formulas reading the field now show an error on every card.
+	if field != nil {
+		s.Formulas.RecomputeBoard(field.BoardID)
+	}
+	return nil
 }
 
-func (s *CustomFieldService) SetCardValue(cardID uuid.UUID, fieldID uuid.UUID, value interface{}) (*models.CardCustomFieldValue, error) {
+// SetCardValue validates and stores a card's value. When a user field changes, the new user
+// is notified and USER_FIELD_SET automation runs.
+func (s *CustomFieldService) SetCardValue(cardID uuid.UUID, fieldID uuid.UUID, value interface{}, actorID uuid.UUID) (*models.CardCustomFieldValue, error) {
 	// 1. Get Field Definition to know type
 	field, err := s.Repo.GetFieldByID(fieldID)
 	if err != nil {
 		return nil, errors.New("field not found")
 	}
 
+	val, err := buildCardFieldValue(field, cardID, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFieldValueUser(s.Repo.DB, field, val); err != nil {
+		return nil, err
+	}
+
+	var previousUser *uuid.UUID
+	if field.Type == models.FieldTypeUser {
+		var existing models.CardCustomFieldValue
+		if err := s.Repo.DB.Where("card_id = ? AND custom_field_id = ?", cardID, fieldID).First(&existing).Error; err == nil {
+			previousUser = existing.ValueUserID
+		}
+	}
+
+	if err := s.Repo.SetValue(val); err != nil {
+		return nil, err
+	}
+	if field.Type == models.FieldTypeUser && !sameUUID(previousUser, val.ValueUserID) {
+		s.userFieldChanged(field, cardID, val.ValueUserID, actorID)
+	}
+	s.Formulas.RecomputeCard(cardID)
+	return val, nil
+}
+
+// userFieldChanged notifies the newly chosen user and fires USER_FIELD_SET.
+func (s *CustomFieldService) userFieldChanged(field *models.CustomField, cardID uuid.UUID, userID *uuid.UUID, actorID uuid.UUID) {
+	var card models.Card
+	if err := s.Repo.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		log.Printf("[CustomFields] failed loading card %s after user field change: %v", cardID, err)
+		return
+	}
+
+	if s.NotificationService != nil && userID != nil && *userID != actorID {
+		_, _ = s.NotificationService.CreateNotification(
+			*userID,
+			actorID,
+			models.NotificationAssignment,
+			"Card assigned",
+			fmt.Sprintf("%s set you as '%s' on card '%s'", userDisplayName(s.Repo.DB, actorID), field.Name, card.Title),
+			card.ID,
+			"CARD",
+			PrefNotifyAssignments,
+		)
+	}
+
+	if s.AutomationService != nil {
+		ctx := map[string]interface{}{
+			"card_id":  card.ID.String(),
+			"field_id": field.ID.String(),
+			"user_id":  "",
+		}
+		if userID != nil {
+			ctx["user_id"] = userID.String()
+		}
+		s.AutomationService.EvaluateRules(card.Column.BoardID, models.TriggerUserFieldSet, ctx)
+	}
+}
+
+// checkFieldValueUser rejects user field values naming someone who cannot see the board.
+func checkFieldValueUser(db *gorm.DB, field *models.CustomField, val *models.CardCustomFieldValue) error {
+	if field.Type != models.FieldTypeUser || val.ValueUserID == nil {
+		return nil
+	}
+	if _, err := repository.NewBoardRepository(db).GetBoardByID(field.BoardID, *val.ValueUserID); err != nil {
+		return ErrFieldUserNotMember
+	}
+	return nil
+}
+
+// buildCardFieldValue converts a raw JSON value into the storage column matching the field type.
+func buildCardFieldValue(field *models.CustomField, cardID uuid.UUID, value interface{}) (*models.CardCustomFieldValue, error) {
+	fieldID := field.ID
+
 	// 2. Prepare Value Struct
 	val := &models.CardCustomFieldValue{
 		CardID:        cardID,
@@ -99,12 +255,476 @@ func (s *CustomFieldService) SetCardValue(cardID uuid.UUID, fieldID uuid.UUID, v
 			}
 			val.ValueDate = &t
 		}
+	case models.FieldTypeUser:
+		// A user ID, or nil to unassign
+		if value == nil {
+			break
+		}
+		strVal, ok := value.(string)
+		userID, err := uuid.Parse(strVal)
+		if !ok || err != nil {
+			return nil, errors.New("user field expects a user ID")
+		}
+		val.ValueUserID = &userID
+	case models.FieldTypeMultiSelect:
+		items, ok := value.([]interface{})
+		if value != nil && !ok {
+			return nil, errors.New("multi_select field expects a list of options")
+		}
+		for _, item := range items {
+			opt, ok := item.(string)
+			if !ok {
+				return nil, errors.New("multi_select options must be strings")
+			}
+			if !containsString(field.Options, opt) {
+				return nil, fmt.Errorf("%q is not an option", opt)
+			}
+			if !containsString(val.ValueList, opt) {
+				val.ValueList = append(val.ValueList, opt)
+			}
+		}
+	case models.FieldTypeURL:
+		strVal, ok := value.(string)
+		if !ok {
+			return nil, errors.New("invalid value type for url field")
+		}
+		strVal = strings.TrimSpace(strVal)
+		if strVal != "" {
+			u, err := url.Parse(strVal)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, errors.New("url must be an absolute http(s) URL")
+			}
+		}
+		val.ValueText = strVal
+	case models.FieldTypeEmail:
+		strVal, ok := value.(string)
+		if !ok {
+			return nil, errors.New("invalid value type for email field")
+		}
+		strVal = strings.TrimSpace(strVal)
+		if strVal != "" {
+			addr, err := mail.ParseAddress(strVal)
+			if err != nil || addr.Address != strVal {
+				return nil, errors.New("invalid email address")
+			}
+		}
+		val.ValueText = strVal
+	case models.FieldTypeCurrency:
+		// Expect {"amount": 12.5, "currency": "EUR"}
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("currency field expects amount and currency")
+		}
+		amount, okAmount := obj["amount"].(float64)
+		code, okCode := obj["currency"].(string)
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !okAmount || !okCode || !currencyCodePattern.MatchString(code) {
+			return nil, errors.New("currency field expects a numeric amount and a 3-letter ISO currency code")
+		}
+		val.ValueNumber = amount
+		val.ValueCurrency = code
+	case models.FieldTypeRating:
+		rating, ok := value.(float64)
+		if intVal, okInt := value.(int); okInt {
+			rating, ok = float64(intVal), true
+		}
+		if !ok || rating != float64(int(rating)) || rating < 1 || rating > models.MaxFieldRating {
+			return nil, fmt.Errorf("rating must be a whole number from 1 to %d", models.MaxFieldRating)
+		}
+		val.ValueNumber = rating
+	case models.FieldTypeFormula:
+		return nil, errors.New("formula fields are computed and cannot be set")
 	}
 
-	err = s.Repo.SetValue(val)
-	return val, err
+	return val, nil
 }
 
 func (s *CustomFieldService) GetCardValues(cardID uuid.UUID) ([]models.CardCustomFieldValue, error) {
 	return s.Repo.GetValuesByCardID(cardID)
 }
+
+func (s *CustomFieldService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.Repo.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+// FieldUpdateInput patches a field definition. OptionMapping renames options in existing
+// values (old -> new); mapping to "" clears them. With DryRun nothing is saved, so clients
+// can preview which values would be lost.
+type FieldUpdateInput struct {
+	Name          *string                 `json:"name"`
+	Type          *models.CustomFieldType `json:"type"`
+	Options       *[]string               `json:"options"`
+	Formula       *string                 `json:"formula"`
+	OptionMapping map[string]string       `json:"option_mapping"`
+	DryRun        bool                    `json:"dry_run"`
+}
+
+// FieldConversionFailure is a card value that could not be carried over; it is cleared.
+type FieldConversionFailure struct {
+	CardID uuid.UUID `json:"card_id"`
+	Value  string    `json:"value"`
+	Reason string    `json:"reason"`
+}
+
+type FieldUpdateResult struct {
+	Field          *models.CustomField      `json:"field"`
+	DryRun         bool                     `json:"dry_run"`
+	ValuesMigrated int                      `json:"values_migrated"`
+	ValuesCleared  int                      `json:"values_cleared"`
+	Failures       []FieldConversionFailure `json:"failures"`
+}
+
+// UpdateField renames a field, edits its options or formula or changes its type, rewriting
+// existing card values to match in the same transaction. Renaming a field updates the
+// formulas that reference it; changes that would break a working formula are rejected.
+func (s *CustomFieldService) UpdateField(field *models.CustomField, in FieldUpdateInput) (*FieldUpdateResult, error) {
+	if field.WorkspaceFieldID != nil {
+		return nil, fmt.Errorf("%w: %q is a workspace field", ErrSharedDefinition, field.Name)
+	}
+	return s.updateField(field, in)
+}
+
+func (s *CustomFieldService) updateField(field *models.CustomField, in FieldUpdateInput) (*FieldUpdateResult, error) {
+	oldType, oldOptions := field.Type, append([]string(nil), field.Options...)
+	oldValueType := field.ValueType()
+	updated := *field
+
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" || len(name) > 100 {
+			return nil, fmt.Errorf("%w: name must be 1-100 characters", ErrInvalidCustomField)
+		}
+		updated.Name = name
+	}
+	if in.Type != nil {
+		if !in.Type.Valid() {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidCustomField, *in.Type)
+		}
+		updated.Type = *in.Type
+	}
+	if in.Options != nil {
+		seen := map[string]bool{}
+		updated.Options = nil
+		for _, opt := range *in.Options {
+			opt = strings.TrimSpace(opt)
+			if opt == "" || seen[opt] {
+				return nil, fmt.Errorf("%w: options must be non-empty and unique", ErrInvalidCustomField)
+			}
+			seen[opt] = true
+			updated.Options = append(updated.Options, opt)
+		}
+	}
+	if updated.Type.HasOptions() {
+		if len(updated.Options) == 0 {
+			return nil, fmt.Errorf("%w: %s fields need at least one option", ErrInvalidCustomField, updated.Type)
+		}
+		for from, to := range in.OptionMapping {
+			if to != "" && !containsString(updated.Options, to) {
+				return nil, fmt.Errorf("%w: option_mapping target %q for %q is not an option", ErrInvalidCustomField, to, from)
+			}
+		}
+	} else {
+		if len(in.OptionMapping) > 0 {
+			return nil, fmt.Errorf("%w: option_mapping only applies to dropdown and multi_select fields", ErrInvalidCustomField)
+		}
+		updated.Options = nil
+	}
+	if in.Formula != nil {
+		updated.Formula = strings.TrimSpace(*in.Formula)
+	}
+	if updated.Type == models.FieldTypeFormula {
+		if updated.Formula == "" {
+			return nil, fmt.Errorf("%w: formula fields need a formula", ErrInvalidCustomField)
+		}
+	} else {
+		if updated.Formula != "" && in.Formula != nil {
+			return nil, fmt.Errorf("%w: only formula fields have a formula", ErrInvalidCustomField)
+		}
+		updated.Formula, updated.ResultType = "", ""
+	}
+
+	var formulas []models.CustomField
+	if updated.Name != field.Name || updated.Type != oldType || updated.Formula != field.Formula {
+		changed := []models.CustomField{updated}
+		if updated.Name != field.Name {
+			renamed, err := s.formulasRenaming(field, updated.Name)
+			if err != nil {
+				return nil, err
+			}
+			changed = append(changed, renamed...)
+		}
+		after, err := checkFormulaChange(s.Repo.DB, field.BoardID, changed...)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range after {
+			if f.ID == updated.ID {
+				updated.ResultType = f.ResultType
+			}
+		}
+		formulas = after
+	}
+
+	result := &FieldUpdateResult{Field: &updated, DryRun: in.DryRun, Failures: []FieldConversionFailure{}}
+	valuesChange := updated.Type != oldType || len(in.OptionMapping) > 0 ||
+		(updated.Type.HasOptions() && !sameStrings(updated.Options, oldOptions))
+
+	err := s.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		repo := repository.NewCustomFieldRepository(tx)
+		if valuesChange {
+			values, err := repo.GetValuesByFieldID(field.ID)
+			if err != nil {
+				return err
+			}
+			for i := range values {
+				v := &values[i]
+				original, _ := storedFieldText(v, oldValueType)
+				// Values of a new formula are recomputed below rather than converted.
+				keep, changed, err := false, false, error(nil)
+				if updated.Type != models.FieldTypeFormula {
+					keep, changed, err = migrateFieldValue(tx, v, oldValueType, &updated, in.OptionMapping)
+				}
+				switch {
+				case err != nil:
+					result.Failures = append(result.Failures, FieldConversionFailure{CardID: v.CardID, Value: original, Reason: err.Error()})
+				case !keep:
+					result.ValuesCleared++
+				case changed:
+					result.ValuesMigrated++
+				}
+				if in.DryRun {
+					continue
+				}
+				if err != nil || !keep {
+					if err := tx.Unscoped().Delete(&models.CardCustomFieldValue{}, "id = ?", v.ID).Error; err != nil {
+						return err
+					}
+				} else if changed {
+					if err := tx.Save(v).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if in.DryRun {
+			return nil
+		}
+		if err := tx.Model(&models.CustomField{}).Where("id = ?", field.ID).Updates(map[string]interface{}{
+			"name": updated.Name, "type": updated.Type, "options": updated.Options,
+			"formula": updated.Formula, "result_type": updated.ResultType,
+		}).Error; err != nil {
+			return err
+		}
+		return saveFormulaDefinitions(tx, formulas)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !in.DryRun {
+		*field = updated
+		s.Formulas.RecomputeBoard(field.BoardID)
+	}
+	return result, nil
+}
+
+// formulasRenaming returns the board's other formula fields that reference field, with
+// the references rewritten to newName.
+func (s *CustomFieldService) formulasRenaming(field *models.CustomField, newName string) ([]models.CustomField, error) {
+	var formulas []models.CustomField
+	if err := s.Repo.DB.Where("board_id = ? AND type = ? AND id <> ?", field.BoardID, models.FieldTypeFormula, field.ID).
+		Find(&formulas).Error; err != nil {
+		return nil, err
+	}
+	var renamed []models.CustomField
+	for _, f := range formulas {
+		if rewritten, ok := renameFormulaField(f.Formula, field.Name, newName); ok {
+			f.Formula = rewritten
+			renamed = append(renamed, f)
+		}
+	}
+	return renamed, nil
+}
+
+// MoveField sets a field's position among the board's fields, renumbering them if they
+// have become too crowded. The rebalanced positions are returned for broadcasting.
+func (s *CustomFieldService) MoveField(field *models.CustomField, position float64) ([]repository.PositionUpdate, error) {
+	var rebalanced []repository.PositionUpdate
+	err := s.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.CustomField{}).Where("id = ?", field.ID).Update("position", position).Error; err != nil {
+			return err
+		}
+		field.Position = position
+		updates, err := repository.RebalanceIfCrowded(tx, repository.CustomFieldPositionList(field.BoardID), field.ID, position)
+		rebalanced = updates
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range rebalanced {
+		if u.ID == field.ID {
+			field.Position = u.Position
+		}
+	}
+	return rebalanced, nil
+}
+
+// migrateFieldValue rewrites v for field's (possibly new) type and options by turning the
+// stored value into the input the new type expects. keep is false when the value should be
+// removed without counting as a failure (it was empty or mapped to "").
+func migrateFieldValue(db *gorm.DB, v *models.CardCustomFieldValue, from models.CustomFieldType, field *models.CustomField, mapping map[string]string) (keep bool, changed bool, err error) {
+	text, ok := storedFieldText(v, from)
+	if !ok || v.FormulaError != "" {
+		return false, false, nil
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false, false, nil
+	}
+
+	var input interface{} = text
+	switch field.Type {
+	case models.FieldTypeDropdown:
+		if to, mapped := mapping[text]; mapped {
+			if to == "" {
+				return false, false, nil
+			}
+			text, input = to, to
+		}
+		if !containsString(field.Options, text) {
+			return false, false, fmt.Errorf("%q is not an option", text)
+		}
+	case models.FieldTypeMultiSelect:
+		items := v.ValueList
+		if from != models.FieldTypeMultiSelect {
+			items = strings.Split(text, ",")
+		}
+		list := []interface{}{}
+		for _, item := range items {
+			item = strings.TrimSpace(item)
+			if to, mapped := mapping[item]; mapped {
+				item = to
+			}
+			if item != "" {
+				list = append(list, item)
+			}
+		}
+		if len(list) == 0 {
+			return false, false, nil
+		}
+		input = list
+	case models.FieldTypeNumber, models.FieldTypeRating:
+		n, err := strconv.ParseFloat(text, 64)
+		switch from {
+		case models.FieldTypeCheckbox:
+			n, err = 0, nil
+			if v.ValueBool {
+				n = 1
+			}
+		case models.FieldTypeCurrency:
+			n, err = v.ValueNumber, nil
+		}
+		if err != nil {
+			return false, false, errors.New("not a number")
+		}
+		input = n
+	case models.FieldTypeCheckbox:
+		switch strings.ToLower(text) {
+		case "true", "yes", "1", "x":
+			input = true
+		case "false", "no", "0":
+			input = false
+		default:
+			return false, false, errors.New("not a yes/no value")
+		}
+	case models.FieldTypeDate:
+		t, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			t, err = time.Parse("2006-01-02", text)
+		}
+		if err != nil {
+			return false, false, errors.New("not a date (RFC 3339 or YYYY-MM-DD)")
+		}
+		input = t.Format(time.RFC3339)
+	case models.FieldTypeCurrency:
+		// "12.50 EUR" as written by storedFieldText
+		parts := strings.Fields(text)
+		if len(parts) != 2 {
+			return false, false, errors.New("not an amount with a currency code")
+		}
+		amount, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return false, false, errors.New("not an amount with a currency code")
+		}
+		input = map[string]interface{}{"amount": amount, "currency": parts[1]}
+	}
+
+	converted, err := buildCardFieldValue(field, v.CardID, input)
+	if err == nil {
+		err = checkFieldValueUser(db, field, converted)
+	}
+	if err != nil {
+		return false, false, err
+	}
+	converted.ID, converted.CreatedAt = v.ID, v.CreatedAt
+
+	changed = converted.ValueText != v.ValueText || converted.ValueNumber != v.ValueNumber ||
+		converted.ValueBool != v.ValueBool || !sameTime(converted.ValueDate, v.ValueDate) ||
+		!sameUUID(converted.ValueUserID, v.ValueUserID) || !sameStrings(converted.ValueList, v.ValueList) ||
+		converted.ValueCurrency != v.ValueCurrency
+	*v = *converted
+	return true, changed, nil
+}
+
+// storedFieldText renders a stored value as text; ok is false when there is no value.
+func storedFieldText(v *models.CardCustomFieldValue, fieldType models.CustomFieldType) (string, bool) {
+	switch fieldType {
+	case models.FieldTypeNumber, models.FieldTypeRating:
+		return strconv.FormatFloat(v.ValueNumber, 'f', -1, 64), true
+	case models.FieldTypeCheckbox:
+		return strconv.FormatBool(v.ValueBool), true
+	case models.FieldTypeDate:
+		if v.ValueDate == nil {
+			return "", false
+		}
+		return v.ValueDate.UTC().Format(time.RFC3339), true
+	case models.FieldTypeUser:
+		if v.ValueUserID == nil {
+			return "", false
+		}
+		return v.ValueUserID.String(), true
+	case models.FieldTypeMultiSelect:
+		return strings.Join(v.ValueList, ", "), len(v.ValueList) > 0
+	case models.FieldTypeCurrency:
+		return strconv.FormatFloat(v.ValueNumber, 'f', -1, 64) + " " + v.ValueCurrency, v.ValueCurrency != ""
+	default:
+		return v.ValueText, v.ValueText != ""
+	}
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sameUUID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFieldTypes_ValidateAndGroup(t *testing.T) {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE card_custom_field_values (
+		id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+		value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+	)`).Error)
+
+	owner, member, outsider := uuid.New(), uuid.New(), uuid.New()
+	wsID, boardID, colID := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name) VALUES (?, 'Owner'), (?, 'Mia'), (?, 'Out')`, owner, member, outsider)
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, member)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Sales')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Leads', 1)`, colID, boardID)
+	cardA, cardB, cardC := uuid.New(), uuid.New(), uuid.New()
+	for i, id := range []uuid.UUID{cardA, cardB, cardC} {
+		require.NoError(t, db.Create(&models.Card{ID: id, Title: "Deal", ColumnID: colID, Position: float64(i + 1)}).Error)
+	}
+
+	svc := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	newField := func(name string, fieldType models.CustomFieldType, options ...string) *models.CustomField {
+		field := &models.CustomField{ID: uuid.New(), BoardID: boardID, Name: name, Type: fieldType, Options: options}
+		require.NoError(t, db.Create(field).Error)
+		return field
+	}
+	_, err := svc.CreateField(boardID, "Tags", models.FieldTypeMultiSelect, nil)
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+	_, err = svc.CreateField(boardID, "Odd", "colour", nil)
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+
+	owners := newField("Account owner", models.FieldTypeUser)
+	tags := newField("Tags", models.FieldTypeMultiSelect, "hot", "renewal", "eu")
+	site := newField("Website", models.FieldTypeURL)
+	contact := newField("Contact", models.FieldTypeEmail)
+	value := newField("Value", models.FieldTypeCurrency)
+	score := newField("Score", models.FieldTypeRating)
+
+	for _, tc := range []struct {
+		field *models.CustomField
+		value interface{}
+		ok    bool
+	}{
+		{owners, member.String(), true},
+		{owners, outsider.String(), false},
+		{owners, "not-a-uuid", false},
+		{tags, []interface{}{"hot", "eu", "hot"}, true},
+		{tags, []interface{}{"cold"}, false},
+		{site, "https://example.com/deal", true},
+		{site, "javascript:alert(1)", false},
+		{contact, "buyer@example.com", true},
+		{contact, "Buyer <buyer@example.com>", false},
+		{value, map[string]interface{}{"amount": 1200.5, "currency": "eur"}, true},
+		{value, map[string]interface{}{"amount": 10.0, "currency": "EURO"}, false},
+		{score, 4.0, true},
+		{score, 4.5, false},
+		{score, 6.0, false},
+	} {
+		_, err := svc.SetCardValue(cardA, tc.field.ID, tc.value, owner)
+		require.Equal(t, tc.ok, err == nil, "%s = %v: %v", tc.field.Name, tc.value, err)
+	}
+	_, err = svc.SetCardValue(cardA, owners.ID, outsider.String(), owner)
+	require.ErrorIs(t, err, services.ErrFieldUserNotMember)
+
+	values, err := svc.GetCardValues(cardA)
+	require.NoError(t, err)
+	byField := map[uuid.UUID]models.CardCustomFieldValue{}
+	for _, v := range values {
+		byField[v.CustomFieldID] = v
+	}
+	require.Equal(t, member, *byField[owners.ID].ValueUserID)
+	require.Equal(t, "Mia", byField[owners.ID].ValueUser.Name)
+	require.Equal(t, []string{"hot", "eu"}, []string(byField[tags.ID].ValueList))
+	require.Equal(t, "EUR", byField[value.ID].ValueCurrency)
+	require.Equal(t, 1200.5, byField[value.ID].ValueNumber)
+	require.Equal(t, 4.0, byField[score.ID].ValueNumber)
+
+	_, err = svc.SetCardValue(cardB, tags.ID, []interface{}{"hot"}, owner)
+	require.NoError(t, err)
+	_, err = svc.SetCardValue(cardB, value.ID, map[string]interface{}{"amount": 300.0, "currency": "EUR"}, owner)
+	require.NoError(t, err)
+	_, err = svc.SetCardValue(cardC, value.ID, map[string]interface{}{"amount": 50.0, "currency": "USD"}, owner)
+	require.NoError(t, err)
+
+	grouped, err := services.GroupCardsByField(db, tags, []uuid.UUID{boardID})
+	require.NoError(t, err)
+	require.Equal(t, []services.FieldGroupStat{
+		{Key: "hot", Name: "hot", Count: 2},
+		{Key: "eu", Name: "eu", Count: 1},
+		{Key: "", Name: "No value", Count: 1},
+	}, grouped)
+
+	grouped, err = services.GroupCardsByField(db, value, []uuid.UUID{boardID})
+	require.NoError(t, err)
+	require.Len(t, grouped, 2)
+	require.Equal(t, "EUR", grouped[0].Key)
+	require.Equal(t, 1500.5, *grouped[0].Amount)
+
+	grouped, err = services.GroupCardsByField(db, owners, []uuid.UUID{boardID})
+	require.NoError(t, err)
+	require.Equal(t, services.FieldGroupStat{Key: member.String(), Name: "Mia", Count: 1}, grouped[0])
+	require.Equal(t, int64(2), grouped[1].Count)
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCustomFieldUpdate_MigratesValues(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE card_custom_field_values (
+		id TEXT PRIMARY KEY, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+		value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+	)`).Error)
+
+	boardID, fieldID := uuid.New(), uuid.New()
+	cardA, cardB, cardC := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO custom_fields (id, board_id, name, type, options, position) VALUES (?, ?, 'Size', 'dropdown', '{S,M,L}', 1000)`, fieldID, boardID)
+	for card, value := range map[uuid.UUID]string{cardA: "S", cardB: "M", cardC: "L"} {
+		db.Exec(`INSERT INTO card_custom_field_values (id, card_id, custom_field_id, value_text) VALUES (?, ?, ?, ?)`, uuid.New(), card, fieldID, value)
+	}
+	valueOf := func(card uuid.UUID) *models.CardCustomFieldValue {
+		var v models.CardCustomFieldValue
+		if err := db.Where("card_id = ? AND custom_field_id = ?", card, fieldID).First(&v).Error; err != nil {
+			return nil
+		}
+		return &v
+	}
+
+	svc := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	field, err := svc.Repo.GetFieldByID(fieldID)
+	require.NoError(t, err)
+
+	// Rename S -> Small, drop L without a mapping; the L value is reported, not silently kept.
+	opts := []string{"Small", "M"}
+	preview, err := svc.UpdateField(field, services.FieldUpdateInput{Options: &opts, OptionMapping: map[string]string{"S": "Small"}, DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, preview.ValuesMigrated)
+	require.Equal(t, []services.FieldConversionFailure{{CardID: cardC, Value: "L", Reason: `"L" is not an option`}}, preview.Failures)
+	require.Equal(t, "L", valueOf(cardC).ValueText, "dry runs change nothing")
+	require.Equal(t, []string{"S", "M", "L"}, []string(field.Options))
+
+	_, err = svc.UpdateField(field, services.FieldUpdateInput{Options: &opts, OptionMapping: map[string]string{"S": "Nope"}})
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+
+	result, err := svc.UpdateField(field, services.FieldUpdateInput{Options: &opts, OptionMapping: map[string]string{"S": "Small", "L": ""}})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.ValuesMigrated)
+	require.Equal(t, 1, result.ValuesCleared)
+	require.Empty(t, result.Failures)
+	require.Equal(t, "Small", valueOf(cardA).ValueText)
+	require.Equal(t, "M", valueOf(cardB).ValueText)
+	require.Nil(t, valueOf(cardC))
+	stored, _ := svc.Repo.GetFieldByID(fieldID)
+	require.Equal(t, []string{"Small", "M"}, []string(stored.Options))
+
+	// dropdown -> text keeps everything; text -> number reports what doesn't parse.
+	textType, numberType := models.FieldTypeText, models.FieldTypeNumber
+	_, err = svc.UpdateField(field, services.FieldUpdateInput{Type: &textType})
+	require.NoError(t, err)
+	require.Empty(t, field.Options)
+	db.Model(&models.CardCustomFieldValue{}).Where("card_id = ?", cardB).Update("value_text", " 42.5 ")
+	name := "Estimate"
+	result, err = svc.UpdateField(field, services.FieldUpdateInput{Name: &name, Type: &numberType})
+	require.NoError(t, err)
+	require.Equal(t, "Estimate", field.Name)
+	require.Equal(t, []services.FieldConversionFailure{{CardID: cardA, Value: "Small", Reason: "not a number"}}, result.Failures)
+	require.Nil(t, valueOf(cardA))
+	require.Equal(t, 42.5, valueOf(cardB).ValueNumber)
+
+	// Moving next to a neighbour that is too close renumbers the list.
+	other := uuid.New()
+	db.Exec(`INSERT INTO custom_fields (id, board_id, name, type, position) VALUES (?, ?, 'Other', 'text', 2000)`, other, boardID)
+	rebalanced, err := svc.MoveField(field, 2000+1e-9)
+	require.NoError(t, err)
+	require.Len(t, rebalanced, 2)
+	require.Equal(t, 2*repository.PositionGap, field.Position)
+}
@@ -10,9 +10,11 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DueDateReminderService scans for cards due soon and sends reminder notifications.
+// Each recipient is reminded once per due date, as recorded in the DueReminder ledger.
 type DueDateReminderService struct {
 	DB                  *gorm.DB
 	NotificationService *NotificationService
@@ -25,6 +27,8 @@ type DueReminderRunStats struct {
 	CardsScanned      int `json:"cards_scanned"`
 	RecipientsChecked int `json:"recipients_checked"`
 	NotificationsSent int `json:"notifications_sent"`
+	ItemsScanned      int `json:"items_scanned"`
+	ItemRemindersSent int `json:"item_reminders_sent"`
 }
 
 func NewDueDateReminderService(db *gorm.DB, notificationService *NotificationService, subscriptionService *SubscriptionService, automationService *AutomationService, window time.Duration) *DueDateReminderService {
@@ -75,6 +79,12 @@ func (s *DueDateReminderService) RunOnce() DueReminderRunStats {
 	}
 	stats.CardsScanned = len(cards)
 
+	reminded, err := s.alreadyReminded(cards, now)
+	if err != nil {
+		log.Printf("[DueDateReminder] reminder ledger query failed: %v", err)
+		return stats
+	}
+
 	for _, card := range cards {
 		recipients := map[uuid.UUID]struct{}{}
 		for _, member := range card.Members {
@@ -92,7 +102,7 @@ func (s *DueDateReminderService) RunOnce() DueReminderRunStats {
 		for recipientID := range recipients {
 			stats.RecipientsChecked++
 
-			if s.reminderRecentlySent(recipientID, card.ID, now) {
+			if reminded[card.ID][recipientID] {
 				continue
 			}
 
@@ -114,6 +124,9 @@ func (s *DueDateReminderService) RunOnce() DueReminderRunStats {
 				continue
 			}
 			stats.NotificationsSent++
+			if err := s.DB.Create(&models.DueReminder{CardID: card.ID, UserID: recipientID, DueDate: *card.DueDate}).Error; err != nil {
+				log.Printf("[DueDateReminder] failed to record reminder for user %s card %s: %v", recipientID, card.ID, err)
+			}
 		}
 	}
 
@@ -121,10 +134,104 @@ func (s *DueDateReminderService) RunOnce() DueReminderRunStats {
 		log.Printf("[DueDateReminder] Sent %d due-date reminder(s)", stats.NotificationsSent)
 	}
 
+	s.remindChecklistItems(now, cutoff, &stats)
 	s.evaluateOverdueAutomations(now)
 	return stats
 }
 
+// alreadyReminded returns, per card, who has been reminded of its current due date. Entries for
+// dates that have passed are pruned first, as those cards are no longer scanned.
+func (s *DueDateReminderService) alreadyReminded(cards []models.Card, now time.Time) (map[uuid.UUID]map[uuid.UUID]bool, error) {
+	if err := s.DB.Where("due_date <= ?", now).Delete(&models.DueReminder{}).Error; err != nil {
+		return nil, err
+	}
+	reminded := map[uuid.UUID]map[uuid.UUID]bool{}
+	if len(cards) == 0 {
+		return reminded, nil
+	}
+	dueDates := make(map[uuid.UUID]time.Time, len(cards))
+	cardIDs := make([]uuid.UUID, 0, len(cards))
+	for _, card := range cards {
+		dueDates[card.ID] = *card.DueDate
+		cardIDs = append(cardIDs, card.ID)
+	}
+	var entries []models.DueReminder
+	if err := s.DB.Where("card_id IN ?", cardIDs).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.DueDate.Equal(dueDates[entry.CardID]) {
+			continue // reminded of an earlier date
+		}
+		if reminded[entry.CardID] == nil {
+			reminded[entry.CardID] = map[uuid.UUID]bool{}
+		}
+		reminded[entry.CardID][entry.UserID] = true
+	}
+	return reminded, nil
+}
+
+// BackfillDueReminders records the due-soon reminders sent since the given time against the
+// cards' current due dates, so the first run after the ledger is added does not repeat them.
+func BackfillDueReminders(db *gorm.DB, since time.Time) error {
+	var entries []models.DueReminder
+	if err := db.Table("notifications").
+		Select("DISTINCT notifications.entity_id AS card_id, notifications.user_id, cards.due_date").
+		Joins("JOIN cards ON cards.id = notifications.entity_id").
+		Where("notifications.type = ? AND notifications.created_at >= ? AND cards.due_date IS NOT NULL", models.NotificationDueSoon, since).
+		Scan(&entries).Error; err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&entries, 500).Error
+}
+
+// remindChecklistItems reminds assignees of open checklist items due within the window.
+// Each item is reminded once per due date; changing the date re-arms it.
+func (s *DueDateReminderService) remindChecklistItems(now, cutoff time.Time, stats *DueReminderRunStats) {
+	var rows []struct {
+		models.ChecklistItem
+		CardID    uuid.UUID
+		CardTitle string
+	}
+	if err := s.DB.Table("checklist_items").
+		Select("checklist_items.*, cards.id AS card_id, cards.title AS card_title").
+		Joins("JOIN checklists ON checklists.id = checklist_items.checklist_id AND checklists.deleted_at IS NULL").
+		Joins("JOIN cards ON cards.id = checklists.card_id").
+		Where("checklist_items.deleted_at IS NULL AND checklist_items.assignee_id IS NOT NULL").
+		Where("checklist_items.due_date IS NOT NULL AND checklist_items.due_date > ? AND checklist_items.due_date <= ?", now, cutoff).
+		Where("checklist_items.is_completed = ? AND checklist_items.due_reminder_sent_at IS NULL", false).
+		Where("cards.is_archived = ?", false).
+		Scan(&rows).Error; err != nil {
+		log.Printf("[DueDateReminder] checklist item query failed: %v", err)
+		return
+	}
+	stats.ItemsScanned = len(rows)
+
+	for _, row := range rows {
+		message := fmt.Sprintf("Checklist item '%s' on card '%s' is due at %s.",
+			row.Title, row.CardTitle, row.DueDate.Local().Format("Mon, 02 Jan 2006 15:04"))
+		_, err := s.NotificationService.CreateNotification(
+			*row.AssigneeID,
+			*row.AssigneeID,
+			models.NotificationItemDueSoon,
+			"Checklist Item Due Soon",
+			message,
+			row.CardID,
+			"CARD",
+			PrefNotifyDueDates,
+		)
+		if err != nil {
+			log.Printf("[DueDateReminder] failed to remind user %s of item %s: %v", *row.AssigneeID, row.ID, err)
+			continue
+		}
+		s.DB.Model(&models.ChecklistItem{}).Where("id = ?", row.ID).Update("due_reminder_sent_at", now)
+		stats.ItemRemindersSent++
+	}
+}
+
 func (s *DueDateReminderService) evaluateOverdueAutomations(now time.Time) {
 	if s.AutomationService == nil {
 		return
@@ -146,16 +253,3 @@ func (s *DueDateReminderService) evaluateOverdueAutomations(now time.Time) {
 		s.AutomationService.EvaluateRules(card.Column.BoardID, models.TriggerDueDateOverdue, ctx)
 	}
 }
-
-func (s *DueDateReminderService) reminderRecentlySent(userID, cardID uuid.UUID, now time.Time) bool {
-	var count int64
-	s.DB.Model(&models.Notification{}).
-		Where("user_id = ? AND type = ? AND entity_id = ? AND created_at >= ?",
-			userID,
-			models.NotificationDueSoon,
-			cardID,
-			now.Add(-24*time.Hour),
-		).
-		Count(&count)
-	return count > 0
-}
@@ -0,0 +1,388 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"regexp"
+	"strings"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInboundEmailDisabled  = errors.New("inbound email is not configured")
+	ErrInboundEmailTooLarge  = errors.New("email exceeds the inbound size limit")
+	ErrMalformedEmail        = errors.New("email could not be parsed")
+	ErrInvalidReplyToken     = errors.New("no valid reply address in recipients")
+	ErrInboundSenderMismatch = errors.New("sender does not match the reply address")
+	ErrInboundAutoReply      = errors.New("automatic replies are ignored")
+	ErrEmptyEmailReply       = errors.New("reply has no text above the quoted message")
+)
+
+const (
+	// MaxInboundEmailBytes bounds raw messages accepted by the webhook and SMTP listener.
+	MaxInboundEmailBytes = 10 << 20
+
+	replyAddressPrefix = "reply+"
+	replyMACSize       = 10
+
+	// ReplyAboveLine is placed in reply-enabled emails; everything from it down is dropped.
+	ReplyAboveLine = "##- Reply above this line to comment on the card -##"
+)
+
+// Base32 keeps tokens case-insensitive, since mail servers may change the case of local parts.
+var replyTokenEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ReplyAddressSigner builds and verifies reply addresses of the form
+// reply+<token>@<domain>, where the token carries the user and card IDs and an HMAC.
+type ReplyAddressSigner struct {
+	Domain string
+	secret []byte
+}
+
+func NewReplyAddressSigner(domain, secret string) *ReplyAddressSigner {
+	return &ReplyAddressSigner{Domain: strings.ToLower(strings.TrimSpace(domain)), secret: []byte(secret)}
+}
+
+// NewReplyAddressSignerFromEnv returns nil unless INBOUND_EMAIL_DOMAIN and INBOUND_EMAIL_SECRET are set.
+func NewReplyAddressSignerFromEnv() *ReplyAddressSigner {
+	domain := os.Getenv("INBOUND_EMAIL_DOMAIN")
+	secret := os.Getenv("INBOUND_EMAIL_SECRET")
+	if domain == "" || secret == "" {
+		log.Println("[EMAIL SERVICE] Inbound email not configured, notification emails have no reply address")
+		return nil
+	}
+	return NewReplyAddressSigner(domain, secret)
+}
+
+func (s *ReplyAddressSigner) mac(payload []byte) []byte {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write(payload)
+	return m.Sum(nil)[:replyMACSize]
+}
+
+// Address returns the reply address that lets userID comment on cardID.
+func (s *ReplyAddressSigner) Address(userID, cardID uuid.UUID) string {
+	payload := append(userID[:], cardID[:]...)
+	token := replyTokenEncoding.EncodeToString(append(payload, s.mac(payload)...))
+	return replyAddressPrefix + strings.ToLower(token) + "@" + s.Domain
+}
+
+// Parse verifies a reply address and returns the user and card it was issued for.
+func (s *ReplyAddressSigner) Parse(address string) (uuid.UUID, uuid.UUID, error) {
+	at := strings.LastIndex(address, "@")
+	if at < 0 || !strings.EqualFold(address[at+1:], s.Domain) {
+		return uuid.Nil, uuid.Nil, ErrInvalidReplyToken
+	}
+	local := strings.ToLower(address[:at])
+	if !strings.HasPrefix(local, replyAddressPrefix) {
+		return uuid.Nil, uuid.Nil, ErrInvalidReplyToken
+	}
+	raw, err := replyTokenEncoding.DecodeString(strings.ToUpper(local[len(replyAddressPrefix):]))
+	if err != nil || len(raw) != 32+replyMACSize {
+		return uuid.Nil, uuid.Nil, ErrInvalidReplyToken
+	}
+	payload, sig := raw[:32], raw[32:]
+	if !hmac.Equal(sig, s.mac(payload)) {
+		return uuid.Nil, uuid.Nil, ErrInvalidReplyToken
+	}
+	userID, _ := uuid.FromBytes(payload[:16])
+	cardID, _ := uuid.FromBytes(payload[16:])
+	return userID, cardID, nil
+}
+
+// InboundEmailService turns replies to notification emails into card comments.
+type InboundEmailService struct {
+	DB       *gorm.DB
+	Comments *CommentService
+	Signer   *ReplyAddressSigner
+}
+
+func NewInboundEmailService(db *gorm.DB, comments *CommentService, signer *ReplyAddressSigner) *InboundEmailService {
+	return &InboundEmailService{DB: db, Comments: comments, Signer: signer}
+}
+
+// ProcessMessage parses a raw MIME reply and posts its new text as a comment. Envelope
+// recipients (SMTP RCPT TO) are checked along with the To, Cc and delivery headers.
+// The token proves which user and card the reply belongs to; the From check stops a
+// forwarded notification from posting as its original recipient.
+func (s *InboundEmailService) ProcessMessage(raw []byte, envelopeRecipients []string) (*models.Comment, error) {
+	if s.Signer == nil {
+		return nil, ErrInboundEmailDisabled
+	}
+	if len(raw) > MaxInboundEmailBytes {
+		return nil, ErrInboundEmailTooLarge
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEmail, err)
+	}
+	if isAutoGeneratedEmail(msg.Header) {
+		return nil, ErrInboundAutoReply
+	}
+
+	userID, cardID, err := s.findReplyToken(msg.Header, envelopeRecipients)
+	if err != nil {
+		return nil, err
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, ErrInboundSenderMismatch
+	}
+	var user models.User
+	if err := s.DB.Select("id", "email").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrInvalidReplyToken
+	}
+	if !strings.EqualFold(strings.TrimSpace(user.Email), from.Address) {
+		return nil, ErrInboundSenderMismatch
+	}
+
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	if !s.Comments.CanAccessBoard(userID, card.Column.BoardID) {
+		return nil, ErrBoardAccessDenied
+	}
+
+	var body emailContent
+	if err := readEmailContent(textproto.MIMEHeader(msg.Header), msg.Body, &body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedEmail, err)
+	}
+	content := StripEmailReply(body.text())
+	if content == "" {
+		return nil, ErrEmptyEmailReply
+	}
+	return s.Comments.CreateComment(card.ID, userID, content, nil)
+}
+
+func (s *InboundEmailService) findReplyToken(header mail.Header, envelope []string) (uuid.UUID, uuid.UUID, error) {
+	for _, addr := range emailRecipients(header, envelope) {
+		if userID, cardID, err := s.Signer.Parse(addr); err == nil {
+			return userID, cardID, nil
+		}
+	}
+	return uuid.Nil, uuid.Nil, ErrInvalidReplyToken
+}
+
+// emailRecipients lists envelope recipients followed by the To, Cc and delivery headers.
+func emailRecipients(header mail.Header, envelope []string) []string {
+	candidates := make([]string, 0, len(envelope)+2)
+	for _, addr := range envelope {
+		candidates = append(candidates, strings.Trim(strings.TrimSpace(addr), "<>"))
+	}
+	for _, key := range []string{"To", "Cc", "Delivered-To", "X-Original-To"} {
+		if addrs, err := header.AddressList(key); err == nil {
+			for _, a := range addrs {
+				candidates = append(candidates, a.Address)
+			}
+		}
+	}
+	return candidates
+}
+
+// isAutoGeneratedEmail spots out-of-office and bounce messages so they don't become comments.
+func isAutoGeneratedEmail(h mail.Header) bool {
+	if v := strings.ToLower(strings.TrimSpace(h.Get("Auto-Submitted"))); v != "" && v != "no" {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(h.Get("Precedence"))) {
+	case "bulk", "junk", "list", "auto_reply":
+		return true
+	}
+	return h.Get("X-Autoreply") != "" || h.Get("X-Autorespond") != ""
+}
+
+// emailContent is what the inbound processors read from a MIME message.
+type emailContent struct {
+	Plain       string
+	HTML        string
+	Attachments []emailAttachment
+}
+
+type emailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// text returns the plain body, falling back to the HTML body converted to text.
+func (e *emailContent) text() string {
+	if strings.TrimSpace(e.Plain) != "" {
+		return e.Plain
+	}
+	return htmlToText(e.HTML)
+}
+
+// readEmailContent walks the MIME tree, keeping the first text/plain and text/html bodies
+// and collecting parts that carry a filename as attachments.
+func readEmailContent(header textproto.MIMEHeader, body io.Reader, out *emailContent) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := readEmailContent(part.Header, part, out); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if disposition == "attachment" || filename != "" || (mediaType != "text/plain" && mediaType != "text/html") {
+		if filename == "" {
+			return nil
+		}
+		data, err := io.ReadAll(io.LimitReader(body, MaxInboundEmailBytes))
+		if err != nil {
+			return err
+		}
+		out.Attachments = append(out.Attachments, emailAttachment{Filename: filename, ContentType: mediaType, Data: data})
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, MaxInboundEmailBytes))
+	if err != nil {
+		return err
+	}
+	text := decodeCharset(data, params["charset"])
+	if mediaType == "text/html" {
+		if out.HTML == "" {
+			out.HTML = text
+		}
+	} else if out.Plain == "" {
+		out.Plain = text
+	}
+	return nil
+}
+
+// newlineStripper drops line breaks so wrapped base64 bodies decode.
+type newlineStripper struct{ r io.Reader }
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// decodeCharset handles Latin-1 style charsets; anything else is treated as UTF-8.
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	return string(data)
+}
+
+var (
+	htmlQuoteBlock = regexp.MustCompile(`(?is)<blockquote.*?</blockquote>|<div class="gmail_quote".*$`)
+	htmlBreak      = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	htmlTag        = regexp.MustCompile(`<[^>]*>`)
+)
+
+func htmlToText(s string) string {
+	s = htmlQuoteBlock.ReplaceAllString(s, "")
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	return html.UnescapeString(s)
+}
+
+var (
+	replyHeaderLine    = regexp.MustCompile(`(?i)^on\b.+\bwrote:$`)
+	originalMessage    = regexp.MustCompile(`(?i)^-{2,}\s*original message\s*-{2,}$`)
+	outlookDivider     = regexp.MustCompile(`^_{20,}$`)
+	mobileSignature    = regexp.MustCompile(`(?i)^(sent from my |get outlook for )`)
+	outlookHeaderField = regexp.MustCompile(`(?i)^(sent|date|subject|to):`)
+)
+
+// StripEmailReply keeps only the new text of a reply: it stops at the reply marker, a
+// quoted-message header or a signature, and drops ">" quoted lines.
+func StripEmailReply(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	kept := make([]string, 0, len(lines))
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		unquoted := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+		if strings.Contains(unquoted, ReplyAboveLine) || isQuoteHeader(lines, i) {
+			break
+		}
+		if line == "-- " || trimmed == "--" || mobileSignature.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// isQuoteHeader detects the line a mail client puts above quoted text, including
+// "On ... wrote:" wrapped over two lines and Outlook's From:/Sent: block.
+func isQuoteHeader(lines []string, i int) bool {
+	line := strings.TrimSpace(lines[i])
+	if replyHeaderLine.MatchString(line) || originalMessage.MatchString(line) || outlookDivider.MatchString(line) {
+		return true
+	}
+	if i+1 < len(lines) && strings.HasPrefix(strings.ToLower(line), "on ") &&
+		replyHeaderLine.MatchString(line+" "+strings.TrimSpace(lines[i+1])) {
+		return true
+	}
+	if strings.HasPrefix(strings.ToLower(line), "from:") {
+		for j := i + 1; j < len(lines) && j <= i+4; j++ {
+			if outlookHeaderField.MatchString(strings.TrimSpace(lines[j])) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmailService struct {
+	services.ConsoleEmailService
+	replyTo, body string
+}
+
+func (r *recordingEmailService) SendNotificationEmailWithReplyTo(recipientEmail, subject, body, replyTo string) error {
+	r.replyTo, r.body = replyTo, body
+	return nil
+}
+
+func TestStripEmailReply(t *testing.T) {
+	cases := map[string]string{
+		"Looks good!\n\nOn Mon, 5 Jan 2026 at 10:00, Nexus <no-reply@nexus.local> wrote:\n> old text": "Looks good!",
+		"Wrapped header\n\nOn Mon, 5 Jan 2026 at 10:00, Nexus\n<no-reply@nexus.local> wrote:\n> old":  "Wrapped header",
+		"Done\n\n-- \nJane Doe\nACME":                      "Done",
+		"Ship it\r\n\r\nSent from my iPhone":               "Ship it",
+		"Inline\n> quoted\nanswer":                         "Inline\nanswer",
+		"Outlook\n\nFrom: Nexus\nSent: Monday\nSubject: x": "Outlook",
+		"Marker\n> " + services.ReplyAboveLine + "\n> hi":  "Marker",
+	}
+	for in, want := range cases {
+		require.Equal(t, want, services.StripEmailReply(in), in)
+	}
+}
+
+func TestEmailReplies_TokenSenderAndSMTPDelivery(t *testing.T) {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE comments (
+		id TEXT PRIMARY KEY, card_id TEXT NOT NULL, user_id TEXT NOT NULL, parent_id TEXT, content TEXT NOT NULL,
+		is_edited INTEGER DEFAULT 0, edited_at DATETIME, created_at DATETIME, updated_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE comment_reactions (
+		id TEXT PRIMARY KEY, comment_id TEXT NOT NULL, user_id TEXT NOT NULL, emoji TEXT NOT NULL, created_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE notifications (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, actor_id TEXT, type TEXT NOT NULL, title TEXT NOT NULL,
+		message TEXT NOT NULL, entity_id TEXT NOT NULL, entity_type TEXT NOT NULL, board_id TEXT,
+		is_read INTEGER DEFAULT 0, created_at DATETIME
+	)`).Error)
+
+	owner, outsider := uuid.New(), uuid.New()
+	wsID, boardID, colID, cardID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Owner', 'Owner@Example.com')`, owner)
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Outsider', 'out@example.com')`, outsider)
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Launch')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, boardID)
+	require.NoError(t, db.Create(&models.Card{ID: cardID, Title: "Release", ColumnID: colID, Position: 1}).Error)
+
+	signer := services.NewReplyAddressSigner("reply.nexus.test", "secret")
+	address := signer.Address(owner, cardID)
+	gotUser, gotCard, err := signer.Parse(strings.ToUpper(address[:len(address)-len("@reply.nexus.test")]) + "@reply.nexus.test")
+	require.NoError(t, err, "tokens survive case changes")
+	require.Equal(t, owner, gotUser)
+	require.Equal(t, cardID, gotCard)
+	_, _, err = services.NewReplyAddressSigner("reply.nexus.test", "other").Parse(address)
+	require.ErrorIs(t, err, services.ErrInvalidReplyToken)
+
+	// Card notifications carry the reply address.
+	mailer := &recordingEmailService{}
+	notifications := services.NewNotificationService(db, nil, mailer)
+	notifications.ReplyAddresses = signer
+	_, err = notifications.CreateNotification(owner, outsider, models.NotificationMention, "Mentioned", "You were mentioned", cardID, "CARD", "")
+	require.NoError(t, err)
+	require.Equal(t, address, mailer.replyTo)
+	require.Contains(t, mailer.body, services.ReplyAboveLine)
+
+	inbound := services.NewInboundEmailService(db, services.NewCommentService(db, services.NewActivityService(db)), signer)
+	message := func(from, to, body string, extra ...string) []byte {
+		return []byte("From: " + from + "\r\nTo: " + to + "\r\nSubject: Re: Mentioned\r\n" + strings.Join(extra, "") +
+			"Content-Type: text/plain; charset=utf-8\r\n\r\n" + body)
+	}
+
+	_, err = inbound.ProcessMessage(message("out@example.com", address, "hi"), nil)
+	require.ErrorIs(t, err, services.ErrInboundSenderMismatch)
+	_, err = inbound.ProcessMessage(message("owner@example.com", address, "Away", "Auto-Submitted: auto-replied\r\n"), nil)
+	require.ErrorIs(t, err, services.ErrInboundAutoReply)
+	_, err = inbound.ProcessMessage(message("owner@example.com", address, "> only quoted"), nil)
+	require.ErrorIs(t, err, services.ErrEmptyEmailReply)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := services.NewInboundSMTPServer("", "reply.nexus.test", func(from string, to []string, raw []byte) error {
+		_, err := inbound.ProcessMessage(raw, to)
+		return err
+	})
+	go server.Serve(ctx, ln)
+
+	reply := "Tagged v1.2, shipping now.\r\n\r\nOn Mon, Nexus <no-reply@nexus.local> wrote:\r\n> " + services.ReplyAboveLine + "\r\n"
+	require.NoError(t, smtp.SendMail(ln.Addr().String(), nil, "owner@example.com", []string{address},
+		message("Owner <owner@example.com>", "Nexus <"+address+">", reply)))
+	err = smtp.SendMail(ln.Addr().String(), nil, "x@example.com", []string{"someone@elsewhere.test"}, message("x@example.com", "someone@elsewhere.test", "spam"))
+	require.Error(t, err, "the listener does not relay")
+
+	var comments []models.Comment
+	require.NoError(t, db.Find(&comments, "card_id = ?", cardID).Error)
+	require.Len(t, comments, 1)
+	require.Equal(t, owner, comments[0].UserID)
+	require.Equal(t, "Tagged v1.2, shipping now.", comments[0].Content)
+}
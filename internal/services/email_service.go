@@ -12,6 +12,8 @@ type EmailService interface {
 	SendInvitationEmail(recipientEmail, workspaceName, inviterName, inviteLink string) error
 	SendJoinRequestApprovedEmail(recipientEmail, workspaceName string) error
 	SendNotificationEmail(recipientEmail, subject, body string) error
+	// SendNotificationEmailWithReplyTo sends a notification whose replies go to replyTo.
+	SendNotificationEmailWithReplyTo(recipientEmail, subject, body, replyTo string) error
 }
 
 // ConsoleEmailService is a mock implementation that logs emails to stdout
@@ -55,6 +57,11 @@ func (s *ConsoleEmailService) SendNotificationEmail(recipientEmail, subject, bod
 	return nil
 }
 
+func (s *ConsoleEmailService) SendNotificationEmailWithReplyTo(recipientEmail, subject, body, replyTo string) error {
+	log.Printf("[EMAIL SERVICE] Reply-To: %s", replyTo)
+	return s.SendNotificationEmail(recipientEmail, subject, body)
+}
+
 type SMTPEmailService struct {
 	Host     string
 	Port     string
@@ -89,11 +96,14 @@ func NewEmailServiceFromEnv() EmailService {
 	return NewSMTPEmailService(host, port, user, pass, from)
 }
 
-func (s *SMTPEmailService) send(recipientEmail, subject, body string) error {
+func (s *SMTPEmailService) send(recipientEmail, subject, body, replyTo string) error {
 	msg := "" +
 		fmt.Sprintf("From: %s\r\n", s.From) +
-		fmt.Sprintf("To: %s\r\n", recipientEmail) +
-		fmt.Sprintf("Subject: %s\r\n", subject) +
+		fmt.Sprintf("To: %s\r\n", recipientEmail)
+	if replyTo != "" {
+		msg += fmt.Sprintf("Reply-To: %s\r\n", replyTo)
+	}
+	msg += fmt.Sprintf("Subject: %s\r\n", subject) +
 		"MIME-Version: 1.0\r\n" +
 		"Content-Type: text/plain; charset=\"UTF-8\"\r\n" +
 		"\r\n" +
@@ -111,15 +121,19 @@ func (s *SMTPEmailService) send(recipientEmail, subject, body string) error {
 func (s *SMTPEmailService) SendInvitationEmail(recipientEmail, workspaceName, inviterName, inviteLink string) error {
 	subject := fmt.Sprintf("You've been invited to join %s on Nexus", workspaceName)
 	body := fmt.Sprintf("Hi there,\n\n%s has invited you to join the workspace '%s'.\nClick here to join: %s\n", inviterName, workspaceName, inviteLink)
-	return s.send(recipientEmail, subject, body)
+	return s.send(recipientEmail, subject, body, "")
 }
 
 func (s *SMTPEmailService) SendJoinRequestApprovedEmail(recipientEmail, workspaceName string) error {
 	subject := fmt.Sprintf("Join Request Approved for %s", workspaceName)
 	body := fmt.Sprintf("Hi there,\n\nYour request to join the workspace '%s' has been approved!\nYou can now access the workspace board.\n", workspaceName)
-	return s.send(recipientEmail, subject, body)
+	return s.send(recipientEmail, subject, body, "")
 }
 
 func (s *SMTPEmailService) SendNotificationEmail(recipientEmail, subject, body string) error {
-	return s.send(recipientEmail, subject, body)
+	return s.send(recipientEmail, subject, body, "")
+}
+
+func (s *SMTPEmailService) SendNotificationEmailWithReplyTo(recipientEmail, subject, body, replyTo string) error {
+	return s.send(recipientEmail, subject, body, replyTo)
 }
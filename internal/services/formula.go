@@ -0,0 +1,423 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// compiledFormula is a board's formula field, parsed and type-checked against the other
+// fields. Err is set when it cannot be computed; every card then shows that error.
+type compiledFormula struct {
+	Field    *models.CustomField
+	Err      error
+	root     *formulaNode
+	deps     []uuid.UUID // formula fields it reads
+	volatile bool
+}
+
+// compileFormulas compiles the formula fields among a board's fields and returns them in
+// dependency order, so each formula comes after the formulas it reads. ResultType is set on
+// the formula fields in place. Formulas that reference each other in a cycle all get an error.
+func compileFormulas(fields []models.CustomField) []*compiledFormula {
+	byName := map[string][]*models.CustomField{}
+	for i := range fields {
+		key := strings.ToLower(strings.TrimSpace(fields[i].Name))
+		byName[key] = append(byName[key], &fields[i])
+	}
+	resolve := func(name string) (*models.CustomField, error) {
+		matches := byName[strings.ToLower(name)]
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("unknown field {%s}", name)
+		case 1:
+			return matches[0], nil
+		}
+		return nil, fmt.Errorf("{%s} matches more than one field; rename one of them", name)
+	}
+
+	compiled := map[uuid.UUID]*compiledFormula{}
+	var formulas []*compiledFormula
+	for i := range fields {
+		if fields[i].Type != models.FieldTypeFormula {
+			continue
+		}
+		cf := &compiledFormula{Field: &fields[i]}
+		cf.root, cf.Err = parseFormula(cf.Field.Formula)
+		if cf.Err == nil {
+			cf.Err = cf.root.walkFields(func(n *formulaNode) error {
+				ref, err := resolve(n.str)
+				if err == nil && ref.Type == models.FieldTypeFormula {
+					cf.deps = append(cf.deps, ref.ID)
+				}
+				return err
+			})
+		}
+		compiled[cf.Field.ID] = cf
+		formulas = append(formulas, cf)
+	}
+
+	// Depth-first topological sort; a formula met again while still on the stack closes a cycle.
+	var order, stack []*compiledFormula
+	state := map[uuid.UUID]int{} // 1 = on the stack, 2 = done
+	var visit func(cf *compiledFormula)
+	visit = func(cf *compiledFormula) {
+		switch state[cf.Field.ID] {
+		case 2:
+			return
+		case 1:
+			start := len(stack) - 1
+			for stack[start] != cf {
+				start--
+			}
+			names := []string{}
+			for _, member := range stack[start:] {
+				names = append(names, member.Field.Name)
+			}
+			err := fmt.Errorf("formulas reference each other in a cycle: %s -> %s", strings.Join(names, " -> "), cf.Field.Name)
+			for _, member := range stack[start:] {
+				if member.Err == nil {
+					member.Err = err
+				}
+			}
+			return
+		}
+		state[cf.Field.ID] = 1
+		stack = append(stack, cf)
+		for _, dep := range cf.deps {
+			visit(compiled[dep])
+		}
+		stack = stack[:len(stack)-1]
+		state[cf.Field.ID] = 2
+		order = append(order, cf)
+	}
+	for _, cf := range formulas {
+		visit(cf)
+	}
+
+	for _, cf := range order {
+		if cf.Err != nil {
+			continue
+		}
+		checker := &formulaChecker{resolve: func(name string) (*models.CustomField, error) {
+			ref, err := resolve(name)
+			if err != nil {
+				return nil, err
+			}
+			if dep := compiled[ref.ID]; dep != nil {
+				if dep.Err != nil {
+					return nil, fmt.Errorf("{%s} has an error", ref.Name)
+				}
+				cf.volatile = cf.volatile || dep.volatile
+			}
+			return ref, nil
+		}}
+		if cf.Err = checker.check(cf.root); cf.Err == nil {
+			cf.volatile = cf.volatile || checker.volatile
+			cf.Field.ResultType = cf.root.typ
+		}
+	}
+	return order
+}
+
+// checkFormulaChange validates a board's formulas with changed applied (new fields are
+// appended, existing ones replaced by ID). It rejects the change when a changed formula
+// does not compile or when a formula that worked before would break. It returns the
+// board's fields after the change, with formula result types updated.
+func checkFormulaChange(db *gorm.DB, boardID uuid.UUID, changed ...models.CustomField) ([]models.CustomField, error) {
+	var before []models.CustomField
+	if err := db.Where("board_id = ?", boardID).Order("position ASC").Find(&before).Error; err != nil {
+		return nil, err
+	}
+	working := map[uuid.UUID]bool{}
+	for _, cf := range compileFormulas(append([]models.CustomField(nil), before...)) {
+		working[cf.Field.ID] = cf.Err == nil
+	}
+
+	after := append([]models.CustomField(nil), before...)
+	isChanged := map[uuid.UUID]bool{}
+	for _, c := range changed {
+		isChanged[c.ID] = true
+		replaced := false
+		for i := range after {
+			if after[i].ID == c.ID {
+				after[i], replaced = c, true
+			}
+		}
+		if !replaced {
+			after = append(after, c)
+		}
+	}
+	for _, cf := range compileFormulas(after) {
+		if cf.Err != nil && (working[cf.Field.ID] || isChanged[cf.Field.ID]) {
+			return nil, fmt.Errorf("%w: formula %q: %v", ErrInvalidCustomField, cf.Field.Name, cf.Err)
+		}
+	}
+	return after, nil
+}
+
+// saveFormulaDefinitions writes the formula and result type of every formula field in
+// fields whose stored definition differs.
+func saveFormulaDefinitions(tx *gorm.DB, fields []models.CustomField) error {
+	for _, f := range fields {
+		if f.Type != models.FieldTypeFormula {
+			continue
+		}
+		if err := tx.Model(&models.CustomField{}).
+			Where("id = ? AND (formula IS NULL OR formula <> ? OR result_type IS NULL OR result_type <> ?)", f.ID, f.Formula, f.ResultType).
+			Updates(map[string]interface{}{"formula": f.Formula, "result_type": f.ResultType}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormulaService keeps the cached values of formula fields up to date. All methods are
+// safe to call on a nil service, which does nothing.
+type FormulaService struct {
+	DB *gorm.DB
+}
+
+func NewFormulaService(db *gorm.DB) *FormulaService {
+	return &FormulaService{DB: db}
+}
+
+// RecomputeCard refreshes the formula values of one card.
+func (s *FormulaService) RecomputeCard(cardID uuid.UUID) error {
+	if s == nil {
+		return nil
+	}
+	var boardIDs []uuid.UUID
+	if err := s.DB.Table("cards").Joins("JOIN columns ON columns.id = cards.column_id").
+		Where("cards.id = ?", cardID).Pluck("columns.board_id", &boardIDs).Error; err != nil || len(boardIDs) == 0 {
+		return err
+	}
+	return s.RecomputeCards(boardIDs[0], []uuid.UUID{cardID})
+}
+
+// RecomputeBoard refreshes the formula values of every card on the board.
+func (s *FormulaService) RecomputeBoard(boardID uuid.UUID) error {
+	return s.RecomputeCards(boardID, nil)
+}
+
+// RecomputeCards refreshes the formula values of the given cards on a board (all of its
+// cards when cardIDs is nil), writing only values that changed.
+func (s *FormulaService) RecomputeCards(boardID uuid.UUID, cardIDs []uuid.UUID) error {
+	if s == nil {
+		return nil
+	}
+	err := s.recompute(boardID, cardIDs)
+	if err != nil {
+		log.Printf("[Formulas] recompute failed for board %s: %v", boardID, err)
+	}
+	return err
+}
+
+func (s *FormulaService) recompute(boardID uuid.UUID, cardIDs []uuid.UUID) error {
+	var fields []models.CustomField
+	if err := s.DB.Where("board_id = ?", boardID).Order("position ASC").Find(&fields).Error; err != nil {
+		return err
+	}
+	formulas := compileFormulas(fields)
+	if len(formulas) == 0 {
+		return nil
+	}
+	fieldsByID := map[uuid.UUID]*models.CustomField{}
+	for i := range fields {
+		fieldsByID[fields[i].ID] = &fields[i]
+	}
+
+	inputs, err := loadFormulaInputs(s.DB, boardID, cardIDs)
+	if err != nil {
+		return err
+	}
+	today := formulaDay(time.Now())
+	for _, in := range inputs {
+		in.fields, in.today = fieldsByID, today
+		for _, cf := range formulas {
+			next := computeFormulaValue(cf, in)
+			if err := storeFormulaValue(s.DB, in.values[cf.Field.ID], next); err != nil {
+				return err
+			}
+			if next != nil {
+				in.values[cf.Field.ID] = next
+			} else {
+				delete(in.values, cf.Field.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// computeFormulaValue evaluates cf for one card. It returns nil when the result is blank.
+func computeFormulaValue(cf *compiledFormula, in *formulaInputs) *models.CardCustomFieldValue {
+	val := &models.CardCustomFieldValue{CardID: in.card.ID, CustomFieldID: cf.Field.ID}
+	err := cf.Err
+	var result interface{}
+	if err == nil {
+		result, err = evalFormula(cf.root, in)
+	}
+	if err != nil {
+		val.FormulaError = err.Error()
+		if len(val.FormulaError) > 255 {
+			val.FormulaError = val.FormulaError[:252] + "..."
+		}
+		return val
+	}
+	switch r := result.(type) {
+	case formulaBlank:
+		return nil
+	case float64:
+		val.ValueNumber = r
+	case string:
+		val.ValueText = r
+	case time.Time:
+		val.ValueDate = &r
+	case bool:
+		val.ValueBool = r
+	}
+	return val
+}
+
+func storeFormulaValue(db *gorm.DB, existing, next *models.CardCustomFieldValue) error {
+	switch {
+	case existing == nil && next == nil:
+		return nil
+	case next == nil:
+		return db.Unscoped().Delete(&models.CardCustomFieldValue{}, "id = ?", existing.ID).Error
+	case existing == nil:
+		next.ID = uuid.New()
+		return db.Create(next).Error
+	}
+	if existing.ValueText == next.ValueText && existing.ValueNumber == next.ValueNumber &&
+		existing.ValueBool == next.ValueBool && sameTime(existing.ValueDate, next.ValueDate) &&
+		existing.FormulaError == next.FormulaError {
+		*next = *existing
+		return nil
+	}
+	next.ID, next.CreatedAt = existing.ID, existing.CreatedAt
+	return db.Save(next).Error
+}
+
+// loadFormulaInputs loads the cards (all of the board's when cardIDs is nil) with the
+// counts and field values formulas read, using one query per kind of input.
+func loadFormulaInputs(db *gorm.DB, boardID uuid.UUID, cardIDs []uuid.UUID) ([]*formulaInputs, error) {
+	query := db.Select("cards.*").Joins("JOIN columns ON columns.id = cards.column_id").Where("columns.board_id = ?", boardID)
+	if cardIDs != nil {
+		query = query.Where("cards.id IN ?", cardIDs)
+	}
+	var cards []models.Card
+	if err := query.Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(cards))
+	byCard := map[uuid.UUID]*formulaInputs{}
+	inputs := make([]*formulaInputs, len(cards))
+	for i := range cards {
+		ids[i] = cards[i].ID
+		inputs[i] = &formulaInputs{card: &cards[i], values: map[uuid.UUID]*models.CardCustomFieldValue{}}
+		byCard[cards[i].ID] = inputs[i]
+	}
+
+	type countRow struct {
+		CardID uuid.UUID
+		Total  int
+		Done   int
+	}
+	var rows []countRow
+	if err := db.Table("checklist_items").
+		Select("checklists.card_id AS card_id, COUNT(*) AS total, SUM(CASE WHEN checklist_items.is_completed THEN 1 ELSE 0 END) AS done").
+		Joins("JOIN checklists ON checklists.id = checklist_items.checklist_id").
+		Where("checklists.card_id IN ? AND checklists.deleted_at IS NULL AND checklist_items.deleted_at IS NULL", ids).
+		Group("checklists.card_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		byCard[r.CardID].checklistTotal, byCard[r.CardID].checklistDone = r.Total, r.Done
+	}
+	for table, target := range map[string]func(*formulaInputs) *int{
+		"card_labels":  func(in *formulaInputs) *int { return &in.labels },
+		"card_members": func(in *formulaInputs) *int { return &in.members },
+	} {
+		rows = nil
+		if err := db.Table(table).Select("card_id, COUNT(*) AS total").
+			Where("card_id IN ?", ids).Group("card_id").Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			*target(byCard[r.CardID]) = r.Total
+		}
+	}
+
+	var values []models.CardCustomFieldValue
+	if err := db.Where("card_id IN ?", ids).Find(&values).Error; err != nil {
+		return nil, err
+	}
+	for i := range values {
+		byCard[values[i].CardID].values[values[i].CustomFieldID] = &values[i]
+	}
+	return inputs, nil
+}
+
+// RecomputeDateFormulas refreshes every board whose formulas read the current date, such
+// as days_until(due_date). It returns the number of boards refreshed.
+func (s *FormulaService) RecomputeDateFormulas() int {
+	var boardIDs []uuid.UUID
+	if err := s.DB.Model(&models.CustomField{}).Distinct("board_id").
+		Where("type = ?", models.FieldTypeFormula).Pluck("board_id", &boardIDs).Error; err != nil {
+		log.Printf("[Formulas] failed listing formula boards: %v", err)
+		return 0
+	}
+
+	refreshed := 0
+	for _, boardID := range boardIDs {
+		var fields []models.CustomField
+		if err := s.DB.Where("board_id = ?", boardID).Find(&fields).Error; err != nil {
+			continue
+		}
+		for _, cf := range compileFormulas(fields) {
+			if cf.volatile {
+				if s.RecomputeBoard(boardID) == nil {
+					refreshed++
+				}
+				break
+			}
+		}
+	}
+	return refreshed
+}
+
+// Start refreshes date-dependent formulas whenever the UTC date changes, checking every interval.
+func (s *FormulaService) Start(ctx context.Context, interval time.Duration) {
+	lastDay := formulaDay(time.Now())
+	s.RecomputeDateFormulas()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[Formulas] Stopped")
+			return
+		case <-ticker.C:
+			if today := formulaDay(time.Now()); today.After(lastDay) {
+				lastDay = today
+				if n := s.RecomputeDateFormulas(); n > 0 {
+					log.Printf("[Formulas] Refreshed date formulas on %d board(s)", n)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,859 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Formula expressions are a small, side-effect free language evaluated once per card:
+//
+//	if({Estimate} > 0, round({Spent} / {Estimate} * 100), 0)
+//	days_until(due_date) <= 3 && !completed
+//	concat({Customer}, " / ", text(year(created_at)))
+//
+// Values are numbers, text, dates (whole days, UTC) and booleans. {Name} reads another
+// custom field of the board, bare identifiers read card attributes (see formulaAttributes)
+// and calls are limited to the built-ins in formulaFunctions. There are no loops, variables
+// or side effects, so evaluation always terminates.
+
+const (
+	MaxFormulaLength = 1000
+	maxFormulaDepth  = 32
+)
+
+// Static types reuse the custom field types whose value column holds them.
+const (
+	formulaNumber = models.FieldTypeNumber
+	formulaText   = models.FieldTypeText
+	formulaDate   = models.FieldTypeDate
+	formulaBool   = models.FieldTypeCheckbox
+)
+
+func formulaTypeName(t models.CustomFieldType) string {
+	if t == formulaBool {
+		return "boolean"
+	}
+	return string(t)
+}
+
+type formulaTokenKind int
+
+const (
+	tokEOF formulaTokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokField
+	tokOp
+)
+
+type formulaToken struct {
+	kind       formulaTokenKind
+	text       string // operator, identifier, field name or unquoted string
+	num        float64
+	start, end int // byte offsets in the source
+}
+
+func lexFormula(src string) ([]formulaToken, error) {
+	var toks []formulaToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isFormulaDigit(c) || (c == '.' && i+1 < len(src) && isFormulaDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isFormulaDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", src[i:j])
+			}
+			toks = append(toks, formulaToken{kind: tokNumber, num: n, text: src[i:j], start: i, end: j})
+			i = j
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, errors.New("unterminated text literal")
+			}
+			toks = append(toks, formulaToken{kind: tokString, text: b.String(), start: i, end: j + 1})
+			i = j + 1
+		case c == '{':
+			end := strings.IndexByte(src[i:], '}')
+			if end < 0 {
+				return nil, errors.New("unterminated field reference, expected }")
+			}
+			name := strings.TrimSpace(src[i+1 : i+end])
+			if name == "" {
+				return nil, errors.New("empty field reference {}")
+			}
+			toks = append(toks, formulaToken{kind: tokField, text: name, start: i, end: i + end + 1})
+			i += end + 1
+		case c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			j := i
+			for j < len(src) && (src[j] == '_' || isFormulaDigit(src[j]) || (src[j]|0x20 >= 'a' && src[j]|0x20 <= 'z')) {
+				j++
+			}
+			toks = append(toks, formulaToken{kind: tokIdent, text: src[i:j], start: i, end: j})
+			i = j
+		default:
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					toks = append(toks, formulaToken{kind: tokOp, text: two, start: i, end: i + 2})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("+-*/%<>!(),", rune(c)) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i+1)
+			}
+			toks = append(toks, formulaToken{kind: tokOp, text: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+	return append(toks, formulaToken{kind: tokEOF, start: len(src), end: len(src)}), nil
+}
+
+func isFormulaDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// formulaNode is one node of a parsed formula. op is "num", "str", "bool", "field", "attr",
+// "call", "neg", "!" or a binary operator; args holds operands or call arguments.
+type formulaNode struct {
+	op      string
+	num     float64
+	str     string // text literal, field name, attribute or function name
+	b       bool
+	args    []*formulaNode
+	fieldID uuid.UUID              // "field": resolved when compiled
+	typ     models.CustomFieldType // set by the type check
+}
+
+// Binary operators from loosest to tightest binding.
+var formulaPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type formulaParser struct {
+	toks  []formulaToken
+	pos   int
+	depth int
+}
+
+func parseFormula(src string) (*formulaNode, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("formula is empty")
+	}
+	if len(src) > MaxFormulaLength {
+		return nil, fmt.Errorf("formula is longer than %d characters", MaxFormulaLength)
+	}
+	toks, err := lexFormula(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{toks: toks}
+	root, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.start+1)
+	}
+	return root, nil
+}
+
+func (p *formulaParser) peek() formulaToken { return p.toks[p.pos] }
+
+func (p *formulaParser) isOp(text string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == text
+}
+
+func (p *formulaParser) expect(text string) error {
+	if !p.isOp(text) {
+		return p.unexpected()
+	}
+	p.pos++
+	return nil
+}
+
+func (p *formulaParser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokEOF {
+		return errors.New("formula ends unexpectedly")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.text, t.start+1)
+}
+
+func (p *formulaParser) parseBinary(level int) (*formulaNode, error) {
+	if level == len(formulaPrecedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokOp || !containsString(formulaPrecedence[level], t.text) {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaNode{op: t.text, args: []*formulaNode{left, right}}
+	}
+}
+
+func (p *formulaParser) parseUnary() (*formulaNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxFormulaDepth {
+		return nil, errors.New("formula is nested too deeply")
+	}
+
+	if p.isOp("-") || p.isOp("!") {
+		op := p.peek().text
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" {
+			op = "neg"
+		}
+		return &formulaNode{op: op, args: []*formulaNode{operand}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (*formulaNode, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokNumber:
+		p.pos++
+		return &formulaNode{op: "num", num: t.num}, nil
+	case tokString:
+		p.pos++
+		return &formulaNode{op: "str", str: t.text}, nil
+	case tokField:
+		p.pos++
+		return &formulaNode{op: "field", str: t.text}, nil
+	case tokIdent:
+		p.pos++
+		name := strings.ToLower(t.text)
+		if name == "true" || name == "false" {
+			return &formulaNode{op: "bool", b: name == "true"}, nil
+		}
+		if !p.isOp("(") {
+			return &formulaNode{op: "attr", str: name}, nil
+		}
+		p.pos++
+		call := &formulaNode{op: "call", str: name}
+		for !p.isOp(")") {
+			if len(call.args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+		}
+		p.pos++
+		return call, nil
+	case tokOp:
+		if t.text == "(" {
+			p.pos++
+			inner, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	return nil, p.unexpected()
+}
+
+// walkFields calls fn for every {Field} reference in the tree.
+func (n *formulaNode) walkFields(fn func(*formulaNode) error) error {
+	if n.op == "field" {
+		return fn(n)
+	}
+	for _, arg := range n.args {
+		if err := arg.walkFields(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameFormulaField rewrites {from} references (matched case-insensitively) to {to},
+// leaving text literals alone. ok is false when src does not reference from.
+func renameFormulaField(src, from, to string) (string, bool) {
+	toks, err := lexFormula(src)
+	if err != nil {
+		return src, false
+	}
+	var b strings.Builder
+	last, renamed := 0, false
+	for _, t := range toks {
+		if t.kind == tokField && strings.EqualFold(t.text, strings.TrimSpace(from)) {
+			b.WriteString(src[last:t.start])
+			b.WriteString("{" + to + "}")
+			last, renamed = t.end, true
+		}
+	}
+	b.WriteString(src[last:])
+	return b.String(), renamed
+}
+
+// formulaAttributes are the card attributes formulas can read.
+var formulaAttributes = map[string]models.CustomFieldType{
+	"title":              formulaText,
+	"priority":           formulaText,
+	"due_date":           formulaDate,
+	"created_at":         formulaDate,
+	"completed_at":       formulaDate,
+	"completed":          formulaBool,
+	"checklist_done":     formulaNumber,
+	"checklist_total":    formulaNumber,
+	"checklist_progress": formulaNumber, // percent, blank without checklist items
+	"label_count":        formulaNumber,
+	"member_count":       formulaNumber,
+	"story_points":       formulaNumber,
+	"estimate_minutes":   formulaNumber,
+}
+
+// formulaInputType is the type a {Field} reference reads as; ok is false for fields
+// formulas cannot use.
+func formulaInputType(f *models.CustomField) (models.CustomFieldType, bool) {
+	switch f.ValueType() {
+	case models.FieldTypeNumber, models.FieldTypeRating, models.FieldTypeCurrency:
+		return formulaNumber, true
+	case models.FieldTypeText, models.FieldTypeDropdown, models.FieldTypeURL, models.FieldTypeEmail, models.FieldTypeMultiSelect:
+		return formulaText, true
+	case models.FieldTypeDate:
+		return formulaDate, true
+	case models.FieldTypeCheckbox:
+		return formulaBool, true
+	}
+	return "", false
+}
+
+// formulaBlank is the value of an empty field or attribute. Only blank() and the untaken
+// branch of if() accept it; anything else reports which input was empty.
+type formulaBlank struct{ name string }
+
+type formulaFunc struct {
+	minArgs, maxArgs int // maxArgs < 0: no upper limit
+	// params lists the argument types; the last one repeats for variadic functions. An
+	// empty type accepts anything.
+	params   []models.CustomFieldType
+	result   models.CustomFieldType
+	volatile bool // depends on the current date
+	eval     func(args []interface{}, today time.Time) (interface{}, error)
+}
+
+var formulaFunctions = map[string]formulaFunc{
+	"today": {0, 0, nil, formulaDate, true, func(_ []interface{}, today time.Time) (interface{}, error) {
+		return today, nil
+	}},
+	"days_until": {1, 1, []models.CustomFieldType{formulaDate}, formulaNumber, true, func(a []interface{}, today time.Time) (interface{}, error) {
+		return daysBetween(today, a[0].(time.Time)), nil
+	}},
+	"days_between": {2, 2, []models.CustomFieldType{formulaDate, formulaDate}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return daysBetween(a[0].(time.Time), a[1].(time.Time)), nil
+	}},
+	"add_days": {2, 2, []models.CustomFieldType{formulaDate, formulaNumber}, formulaDate, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return addDays(a[0].(time.Time), a[1].(float64))
+	}},
+	"year": {1, 1, []models.CustomFieldType{formulaDate}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return float64(a[0].(time.Time).Year()), nil
+	}},
+	"month": {1, 1, []models.CustomFieldType{formulaDate}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return float64(a[0].(time.Time).Month()), nil
+	}},
+	"day": {1, 1, []models.CustomFieldType{formulaDate}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return float64(a[0].(time.Time).Day()), nil
+	}},
+	"round": {1, 2, []models.CustomFieldType{formulaNumber, formulaNumber}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		scale := 1.0
+		if len(a) == 2 {
+			scale = math.Pow(10, math.Round(a[1].(float64)))
+		}
+		return math.Round(a[0].(float64)*scale) / scale, nil
+	}},
+	"floor": {1, 1, []models.CustomFieldType{formulaNumber}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return math.Floor(a[0].(float64)), nil
+	}},
+	"ceil": {1, 1, []models.CustomFieldType{formulaNumber}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return math.Ceil(a[0].(float64)), nil
+	}},
+	"abs": {1, 1, []models.CustomFieldType{formulaNumber}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return math.Abs(a[0].(float64)), nil
+	}},
+	"min": {1, -1, []models.CustomFieldType{formulaNumber}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		m := a[0].(float64)
+		for _, v := range a[1:] {
+			m = math.Min(m, v.(float64))
+		}
+		return m, nil
+	}},
+	"max": {1, -1, []models.CustomFieldType{formulaNumber}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		m := a[0].(float64)
+		for _, v := range a[1:] {
+			m = math.Max(m, v.(float64))
+		}
+		return m, nil
+	}},
+	"len": {1, 1, []models.CustomFieldType{formulaText}, formulaNumber, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return float64(len([]rune(a[0].(string)))), nil
+	}},
+	"text": {1, 1, []models.CustomFieldType{""}, formulaText, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		return formatFormulaText(a[0]), nil
+	}},
+	"concat": {1, -1, []models.CustomFieldType{""}, formulaText, false, func(a []interface{}, _ time.Time) (interface{}, error) {
+		var b strings.Builder
+		for _, v := range a {
+			b.WriteString(formatFormulaText(v))
+		}
+		return b.String(), nil
+	}},
+}
+
+// formulaChecker resolves references and infers the static type of every node.
+type formulaChecker struct {
+	resolve  func(name string) (*models.CustomField, error)
+	volatile bool // reads the current date, directly or through another formula
+}
+
+func (c *formulaChecker) check(n *formulaNode) error {
+	switch n.op {
+	case "num":
+		n.typ = formulaNumber
+	case "str":
+		n.typ = formulaText
+	case "bool":
+		n.typ = formulaBool
+	case "field":
+		field, err := c.resolve(n.str)
+		if err != nil {
+			return err
+		}
+		t, ok := formulaInputType(field)
+		if !ok {
+			return fmt.Errorf("{%s} is a %s field and cannot be used in formulas", field.Name, field.Type)
+		}
+		n.fieldID, n.typ = field.ID, t
+	case "attr":
+		t, ok := formulaAttributes[n.str]
+		if !ok {
+			return fmt.Errorf("unknown card attribute %q (field names go in braces, like {%s})", n.str, n.str)
+		}
+		n.typ = t
+	case "neg", "!":
+		if err := c.check(n.args[0]); err != nil {
+			return err
+		}
+		want := formulaNumber
+		if n.op == "!" {
+			want = formulaBool
+		}
+		if n.args[0].typ != want {
+			return fmt.Errorf("%s expects a %s, not %s", strings.Replace(n.op, "neg", "-", 1), formulaTypeName(want), formulaTypeName(n.args[0].typ))
+		}
+		n.typ = want
+	case "call":
+		return c.checkCall(n)
+	default:
+		return c.checkBinary(n)
+	}
+	return nil
+}
+
+func (c *formulaChecker) checkBinary(n *formulaNode) error {
+	for _, arg := range n.args {
+		if err := c.check(arg); err != nil {
+			return err
+		}
+	}
+	l, r := n.args[0].typ, n.args[1].typ
+	switch {
+	case n.op == "&&" || n.op == "||":
+		if l == formulaBool && r == formulaBool {
+			n.typ = formulaBool
+		}
+	case n.op == "==" || n.op == "!=":
+		if l == r {
+			n.typ = formulaBool
+		}
+	case n.op == "<" || n.op == "<=" || n.op == ">" || n.op == ">=":
+		if l == r && l != formulaBool {
+			n.typ = formulaBool
+		}
+	case l == formulaNumber && r == formulaNumber:
+		n.typ = formulaNumber
+	case n.op == "+" && ((l == formulaDate && r == formulaNumber) || (l == formulaNumber && r == formulaDate)):
+		n.typ = formulaDate
+	case n.op == "-" && l == formulaDate && r == formulaNumber:
+		n.typ = formulaDate
+	case n.op == "-" && l == formulaDate && r == formulaDate:
+		n.typ = formulaNumber // whole days
+	}
+	if n.typ == "" {
+		return fmt.Errorf("operator %s cannot combine %s and %s", n.op, formulaTypeName(l), formulaTypeName(r))
+	}
+	return nil
+}
+
+func (c *formulaChecker) checkCall(n *formulaNode) error {
+	for _, arg := range n.args {
+		if err := c.check(arg); err != nil {
+			return err
+		}
+	}
+	switch n.str {
+	case "if":
+		if len(n.args) != 3 {
+			return errors.New("if() takes a condition, a value when true and a value when false")
+		}
+		if n.args[0].typ != formulaBool {
+			return fmt.Errorf("if() condition must be a boolean, not %s", formulaTypeName(n.args[0].typ))
+		}
+		if n.args[1].typ != n.args[2].typ {
+			return fmt.Errorf("if() branches must have the same type, got %s and %s", formulaTypeName(n.args[1].typ), formulaTypeName(n.args[2].typ))
+		}
+		n.typ = n.args[1].typ
+		return nil
+	case "blank":
+		if len(n.args) != 1 {
+			return errors.New("blank() takes one value")
+		}
+		n.typ = formulaBool
+		return nil
+	}
+
+	fn, ok := formulaFunctions[n.str]
+	if !ok {
+		return fmt.Errorf("unknown function %s()", n.str)
+	}
+	if len(n.args) < fn.minArgs || (fn.maxArgs >= 0 && len(n.args) > fn.maxArgs) {
+		return fmt.Errorf("%s() called with %d arguments", n.str, len(n.args))
+	}
+	for i, arg := range n.args {
+		want := fn.params[min(i, len(fn.params)-1)]
+		if want != "" && arg.typ != want {
+			return fmt.Errorf("%s() argument %d must be a %s, not %s", n.str, i+1, formulaTypeName(want), formulaTypeName(arg.typ))
+		}
+	}
+	c.volatile = c.volatile || fn.volatile
+	n.typ = fn.result
+	return nil
+}
+
+// formulaInputs is what a formula can read for one card.
+type formulaInputs struct {
+	card           *models.Card
+	checklistDone  int
+	checklistTotal int
+	labels         int
+	members        int
+	values         map[uuid.UUID]*models.CardCustomFieldValue
+	fields         map[uuid.UUID]*models.CustomField
+	today          time.Time
+}
+
+// evalFormula computes n. The result is a float64, string, time.Time, bool or formulaBlank.
+func evalFormula(n *formulaNode, in *formulaInputs) (interface{}, error) {
+	switch n.op {
+	case "num":
+		return n.num, nil
+	case "str":
+		return n.str, nil
+	case "bool":
+		return n.b, nil
+	case "field":
+		return formulaFieldValue(in, n.fieldID)
+	case "attr":
+		return formulaAttributeValue(in, n.str), nil
+	case "neg", "!":
+		v, err := evalFilled(n.args[0], in)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "!" {
+			return !v.(bool), nil
+		}
+		return -v.(float64), nil
+	case "&&", "||":
+		l, err := evalFilled(n.args[0], in)
+		if err != nil {
+			return nil, err
+		}
+		if l.(bool) == (n.op == "||") {
+			return l, nil
+		}
+		return evalFilled(n.args[1], in)
+	case "call":
+		return evalFormulaCall(n, in)
+	}
+
+	l, err := evalFilled(n.args[0], in)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalFilled(n.args[1], in)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return formulaEqual(l, r), nil
+	case "!=":
+		return !formulaEqual(l, r), nil
+	case "<", "<=", ">", ">=":
+		cmp := formulaCompare(l, r)
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		}
+		return cmp >= 0, nil
+	}
+
+	lt, lIsDate := l.(time.Time)
+	rt, rIsDate := r.(time.Time)
+	switch {
+	case lIsDate && rIsDate:
+		return daysBetween(rt, lt), nil
+	case lIsDate && n.op == "-":
+		return addDays(lt, -r.(float64))
+	case lIsDate:
+		return addDays(lt, r.(float64))
+	case rIsDate:
+		return addDays(rt, l.(float64))
+	}
+
+	a, b := l.(float64), r.(float64)
+	var out float64
+	switch n.op {
+	case "+":
+		out = a + b
+	case "-":
+		out = a - b
+	case "*":
+		out = a * b
+	case "/", "%":
+		if b == 0 {
+			return nil, errors.New("division by zero")
+		}
+		out = a / b
+		if n.op == "%" {
+			out = math.Mod(a, b)
+		}
+	}
+	if math.IsNaN(out) || math.IsInf(out, 0) {
+		return nil, errors.New("result is too large")
+	}
+	return out, nil
+}
+
+// evalFilled evaluates n and turns a blank result into an error naming the empty input.
+func evalFilled(n *formulaNode, in *formulaInputs) (interface{}, error) {
+	v, err := evalFormula(n, in)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := v.(formulaBlank); ok {
+		return nil, fmt.Errorf("%s is empty", b.name)
+	}
+	return v, nil
+}
+
+func evalFormulaCall(n *formulaNode, in *formulaInputs) (interface{}, error) {
+	switch n.str {
+	case "if":
+		cond, err := evalFilled(n.args[0], in)
+		if err != nil {
+			return nil, err
+		}
+		if cond.(bool) {
+			return evalFormula(n.args[1], in)
+		}
+		return evalFormula(n.args[2], in)
+	case "blank":
+		v, err := evalFormula(n.args[0], in)
+		if err != nil {
+			return nil, err
+		}
+		_, isBlank := v.(formulaBlank)
+		return isBlank, nil
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := evalFilled(arg, in)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return formulaFunctions[n.str].eval(args, in.today)
+}
+
+func formulaFieldValue(in *formulaInputs, fieldID uuid.UUID) (interface{}, error) {
+	field := in.fields[fieldID]
+	blank := formulaBlank{name: "{" + field.Name + "}"}
+	v := in.values[fieldID]
+	if v == nil {
+		return blank, nil
+	}
+	if v.FormulaError != "" {
+		return nil, fmt.Errorf("{%s} has an error", field.Name)
+	}
+	switch t, _ := formulaInputType(field); t {
+	case formulaNumber:
+		return v.ValueNumber, nil
+	case formulaDate:
+		if v.ValueDate == nil {
+			return blank, nil
+		}
+		return formulaDay(*v.ValueDate), nil
+	case formulaBool:
+		return v.ValueBool, nil
+	}
+	text, ok := storedFieldText(v, field.ValueType())
+	if !ok {
+		return blank, nil
+	}
+	return text, nil
+}
+
+func formulaAttributeValue(in *formulaInputs, name string) interface{} {
+	card := in.card
+	date := func(t *time.Time) interface{} {
+		if t == nil {
+			return formulaBlank{name: name}
+		}
+		return formulaDay(*t)
+	}
+	switch name {
+	case "title":
+		return card.Title
+	case "priority":
+		return string(card.Priority)
+	case "due_date":
+		return date(card.DueDate)
+	case "created_at":
+		return date(&card.CreatedAt)
+	case "completed_at":
+		return date(card.CompletedAt)
+	case "completed":
+		return card.IsComplete
+	case "checklist_done":
+		return float64(in.checklistDone)
+	case "checklist_total":
+		return float64(in.checklistTotal)
+	case "checklist_progress":
+		if in.checklistTotal == 0 {
+			return formulaBlank{name: name}
+		}
+		return float64(in.checklistDone) * 100 / float64(in.checklistTotal)
+	case "label_count":
+		return float64(in.labels)
+	case "member_count":
+		return float64(in.members)
+	case "story_points":
+		if card.StoryPoints == nil {
+			return formulaBlank{name: name}
+		}
+		return *card.StoryPoints
+	case "estimate_minutes":
+		if card.EstimateMinutes == nil {
+			return formulaBlank{name: name}
+		}
+		return float64(*card.EstimateMinutes)
+	}
+	return formulaBlank{name: name}
+}
+
+func formulaEqual(l, r interface{}) bool {
+	if lt, ok := l.(time.Time); ok {
+		return lt.Equal(r.(time.Time))
+	}
+	return l == r
+}
+
+func formulaCompare(l, r interface{}) int {
+	switch a := l.(type) {
+	case float64:
+		b := r.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case time.Time:
+		return a.Compare(r.(time.Time))
+	}
+	return strings.Compare(l.(string), r.(string))
+}
+
+// formulaDay drops the time of day so date arithmetic works in whole days.
+func formulaDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) float64 {
+	return math.Round(to.Sub(from).Hours() / 24)
+}
+
+func addDays(t time.Time, days float64) (interface{}, error) {
+	if days != math.Trunc(days) || math.Abs(days) > 100000 {
+		return nil, errors.New("dates can only move by a whole number of days")
+	}
+	return t.AddDate(0, 0, int(days)), nil
+}
+
+func formatFormulaText(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format("2006-01-02")
+	}
+	return v.(string)
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormulaFields_ComputeCacheAndRejectCycles(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (
+			id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+			value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+		`CREATE TABLE checklists (id TEXT PRIMARY KEY, card_id TEXT, title TEXT, position REAL, version INTEGER DEFAULT 1, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE checklist_items (id TEXT PRIMARY KEY, checklist_id TEXT, title TEXT, is_completed INTEGER DEFAULT 0, position REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner, boardID, colID := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, boardID)
+	created := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	cardA, cardB := uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: cardA, Title: "A", ColumnID: colID, Position: 1, CreatedAt: created, DueDate: &due}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: cardB, Title: "B", ColumnID: colID, Position: 2, CreatedAt: created}).Error)
+
+	svc := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	valueOf := func(card uuid.UUID, field *models.CustomField) *models.CardCustomFieldValue {
+		var v models.CardCustomFieldValue
+		if err := db.Where("card_id = ? AND custom_field_id = ?", card, field.ID).First(&v).Error; err != nil {
+			return nil
+		}
+		return &v
+	}
+
+	estimate, err := svc.CreateField(boardID, "Estimate", models.FieldTypeNumber, nil)
+	require.NoError(t, err)
+	spent, err := svc.CreateField(boardID, "Spent", models.FieldTypeNumber, nil)
+	require.NoError(t, err)
+
+	for formula, reason := range map[string]string{
+		`{Missing} + 1`:        "unknown field {Missing}",
+		`{Estimate} + "h"`:     "operator + cannot combine number and text",
+		`if(true, 1, "one")`:   "if() branches must have the same type",
+		`round({Estimate}`:     "formula ends unexpectedly",
+		`{Burn} * 2`:           "cycle",
+		`delete_everything(1)`: "unknown function delete_everything()",
+		`days_until(estimate)`: `unknown card attribute "estimate"`,
+		`"unterminated`:        "unterminated text literal",
+		`{Estimate} ; {Spent}`: "unexpected character",
+		`((((((((((((((((((((((((((((((((1))))))))))))))))))))))))))))))))`: "nested too deeply",
+	} {
+		_, err := svc.CreateFormulaField(boardID, "Burn", formula)
+		require.ErrorIs(t, err, services.ErrInvalidCustomField, formula)
+		require.Contains(t, err.Error(), reason, formula)
+	}
+
+	burn, err := svc.CreateFormulaField(boardID, "Burn", `round({Spent} / {Estimate} * 100)`)
+	require.NoError(t, err)
+	require.Equal(t, models.FieldTypeNumber, burn.ResultType)
+	status, err := svc.CreateFormulaField(boardID, "Status", `if({Burn} > 100, "over", concat("ok (", text({Burn}), "%)"))`)
+	require.NoError(t, err)
+	require.Equal(t, models.FieldTypeText, status.ResultType)
+	window, err := svc.CreateFormulaField(boardID, "Window", `days_between(created_at, due_date) + checklist_done * 100 + label_count`)
+	require.NoError(t, err)
+
+	_, err = svc.SetCardValue(cardA, burn.ID, 5.0, owner)
+	require.Error(t, err, "formula values cannot be set by hand")
+
+	// Values are cached when inputs change; blank inputs and bad arithmetic are per-card errors.
+	_, err = svc.SetCardValue(cardA, estimate.ID, 8.0, owner)
+	require.NoError(t, err)
+	require.Equal(t, "{Spent} is empty", valueOf(cardA, burn).FormulaError)
+	require.Equal(t, "{Burn} has an error", valueOf(cardA, status).FormulaError)
+	_, err = svc.SetCardValue(cardA, spent.ID, 10.0, owner)
+	require.NoError(t, err)
+	require.Equal(t, 125.0, valueOf(cardA, burn).ValueNumber)
+	require.Empty(t, valueOf(cardA, burn).FormulaError)
+	require.Equal(t, "over", valueOf(cardA, status).ValueText)
+
+	_, err = svc.SetCardValue(cardB, estimate.ID, 0.0, owner)
+	require.NoError(t, err)
+	_, err = svc.SetCardValue(cardB, spent.ID, 3.0, owner)
+	require.NoError(t, err)
+	require.Equal(t, "division by zero", valueOf(cardB, burn).FormulaError)
+	require.Equal(t, "due_date is empty", valueOf(cardB, window).FormulaError)
+
+	// Card attributes: whole days between dates, checklist progress and labels.
+	checklist := uuid.New()
+	db.Exec(`INSERT INTO checklists (id, card_id, title, position) VALUES (?, ?, 'QA', 1)`, checklist, cardA)
+	db.Exec(`INSERT INTO checklist_items (id, checklist_id, title, is_completed, position) VALUES (?, ?, 'a', 1, 1), (?, ?, 'b', 0, 2)`, uuid.New(), checklist, uuid.New(), checklist)
+	db.Exec(`INSERT INTO card_labels (card_id, label_id) VALUES (?, ?)`, cardA, uuid.New())
+	require.NoError(t, svc.Formulas.RecomputeCard(cardA))
+	require.Equal(t, 10.0+100+1, valueOf(cardA, window).ValueNumber)
+
+	// A cycle through another formula is rejected on save.
+	cyclic := `{Status} + 1`
+	_, err = svc.UpdateField(burn, services.FieldUpdateInput{Formula: &cyclic})
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+	require.Contains(t, err.Error(), "cycle: Burn -> Status -> Burn")
+
+	// Changing an input's type is rejected when it would break a working formula.
+	text := models.FieldTypeText
+	_, err = svc.UpdateField(estimate, services.FieldUpdateInput{Type: &text})
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+
+	// Renaming an input rewrites the formulas that reference it, but not text literals.
+	_, err = svc.UpdateField(status, services.FieldUpdateInput{Formula: ptr(`if({Burn} > 100, "{Spent} over", "ok")`)})
+	require.NoError(t, err)
+	_, err = svc.UpdateField(spent, services.FieldUpdateInput{Name: ptr("Actual")})
+	require.NoError(t, err)
+	stored, _ := svc.Repo.GetFieldByID(burn.ID)
+	require.Equal(t, `round({Actual} / {Estimate} * 100)`, stored.Formula)
+	_, err = svc.UpdateField(stored, services.FieldUpdateInput{Name: ptr("Burn %")})
+	require.NoError(t, err)
+	stored, _ = svc.Repo.GetFieldByID(status.ID)
+	require.Equal(t, `if({Burn %} > 100, "{Spent} over", "ok")`, stored.Formula)
+	require.Equal(t, "{Spent} over", valueOf(cardA, status).ValueText)
+
+	// Switching a formula's result type recomputes its cached values in the new column.
+	_, err = svc.UpdateField(status, services.FieldUpdateInput{Formula: ptr(`{Burn %} > 100`)})
+	require.NoError(t, err)
+	require.Equal(t, models.FieldTypeCheckbox, status.ResultType)
+	require.True(t, valueOf(cardA, status).ValueBool)
+	require.Empty(t, valueOf(cardA, status).ValueText)
+}
+
+func ptr[T any](v T) *T { return &v }
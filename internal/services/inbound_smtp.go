@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	smtpMaxRecipients  = 50
+	smtpCommandTimeout = 5 * time.Minute
+)
+
+// InboundSMTPServer is a minimal receive-only SMTP listener for inbound email. It accepts
+// mail for Domain only, performs no relaying and hands each message to Deliver.
+// Put it behind the public MX (or a relay) that handles TLS and spam filtering.
+type InboundSMTPServer struct {
+	Addr    string
+	Domain  string
+	Deliver func(from string, to []string, raw []byte) error
+}
+
+func NewInboundSMTPServer(addr, domain string, deliver func(from string, to []string, raw []byte) error) *InboundSMTPServer {
+	return &InboundSMTPServer{Addr: addr, Domain: strings.ToLower(domain), Deliver: deliver}
+}
+
+// ListenAndServe listens on Addr and serves until ctx is cancelled.
+func (s *InboundSMTPServer) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("[InboundSMTP] Listening on %s for *@%s", ln.Addr(), s.Domain)
+	return s.Serve(ctx, ln)
+}
+
+func (s *InboundSMTPServer) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Println("[InboundSMTP] Stopped")
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+type smtpSession struct {
+	from string
+	to   []string
+}
+
+func (s *InboundSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...interface{}) bool {
+		conn.SetWriteDeadline(time.Now().Add(smtpCommandTimeout))
+		return tp.PrintfLine(format, args...) == nil
+	}
+
+	reply("220 %s Nexus inbound ESMTP", s.Domain)
+	var sess *smtpSession
+	for {
+		conn.SetReadDeadline(time.Now().Add(smtpCommandTimeout))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			sess = nil
+			reply("250 %s", s.Domain)
+		case "EHLO":
+			sess = nil
+			reply("250-%s", s.Domain)
+			reply("250-SIZE %d", MaxInboundEmailBytes)
+			reply("250 8BITMIME")
+		case "MAIL":
+			addr, ok := smtpPathArg(arg, "FROM:")
+			if !ok {
+				reply("501 Syntax: MAIL FROM:<address>")
+				continue
+			}
+			sess = &smtpSession{from: addr}
+			reply("250 OK")
+		case "RCPT":
+			if sess == nil {
+				reply("503 MAIL first")
+				continue
+			}
+			addr, ok := smtpPathArg(arg, "TO:")
+			if !ok || addr == "" {
+				reply("501 Syntax: RCPT TO:<address>")
+				continue
+			}
+			if at := strings.LastIndex(addr, "@"); at < 0 || !strings.EqualFold(addr[at+1:], s.Domain) {
+				reply("550 Relaying denied")
+				continue
+			}
+			if len(sess.to) >= smtpMaxRecipients {
+				reply("452 Too many recipients")
+				continue
+			}
+			sess.to = append(sess.to, addr)
+			reply("250 OK")
+		case "DATA":
+			if sess == nil || len(sess.to) == 0 {
+				reply("503 RCPT first")
+				continue
+			}
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			conn.SetReadDeadline(time.Now().Add(smtpCommandTimeout))
+			data := tp.DotReader()
+			raw, err := io.ReadAll(io.LimitReader(data, MaxInboundEmailBytes+1))
+			if err != nil {
+				return
+			}
+			if len(raw) > MaxInboundEmailBytes {
+				// Drain the rest of the message so the connection stays in sync.
+				io.Copy(io.Discard, data)
+				reply("552 Message exceeds fixed maximum message size")
+			} else {
+				reply("%s", smtpDeliveryResult(s.Deliver(sess.from, sess.to, raw)))
+			}
+			sess = nil
+		case "RSET":
+			sess = nil
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "VRFY":
+			reply("252 Cannot verify user")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// smtpPathArg extracts the address from "FROM:<a@b> SIZE=..." style arguments.
+func smtpPathArg(arg, prefix string) (string, bool) {
+	arg = strings.TrimSpace(arg)
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	rest := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		addr, _, _ := strings.Cut(rest, " ")
+		return addr, addr != ""
+	}
+	end := strings.Index(rest, ">")
+	if end < 0 {
+		return "", false
+	}
+	return rest[1:end], true
+}
+
+// smtpDeliveryResult maps processing errors to SMTP replies. Rejections the sender can't fix
+// by retrying are permanent (5xx) so the reply bounces back to them.
+func smtpDeliveryResult(err error) string {
+	switch {
+	case err == nil:
+		return "250 OK: accepted"
+	case errors.Is(err, ErrInboundAutoReply):
+		return "250 OK: automatic reply ignored"
+	case errors.Is(err, ErrDuplicateInboundEmail):
+		return "250 OK: already imported"
+	case errors.Is(err, ErrInvalidReplyToken), errors.Is(err, ErrInboundSenderMismatch),
+		errors.Is(err, ErrBoardAccessDenied), errors.Is(err, ErrEmptyEmailReply),
+		errors.Is(err, ErrMalformedEmail), errors.Is(err, ErrInboundEmailTooLarge),
+		errors.Is(err, ErrInboundEmailDisabled), errors.Is(err, ErrNoBoardInbox),
+		errors.Is(err, ErrBoardInboxDisabled), errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Sprintf("550 %s", strings.Join(strings.Fields(err.Error()), " "))
+	default:
+		log.Printf("[InboundSMTP] delivery failed: %v", err)
+		return "451 Temporary failure, try again later"
+	}
+}
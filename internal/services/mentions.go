@@ -0,0 +1,148 @@
+package services
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	mentionIDPattern   = regexp.MustCompile(`mention:([0-9a-fA-F-]{36})`)
+	mentionTextPattern = regexp.MustCompile(`(?:^|\s)@([a-zA-Z0-9._-]{2,50})`)
+)
+
+// ExtractMentionUserIDs returns the distinct user IDs written as mention:<uuid> in text.
+func ExtractMentionUserIDs(text string) []uuid.UUID {
+	matches := mentionIDPattern.FindAllStringSubmatch(text, -1)
+	seen := map[uuid.UUID]struct{}{}
+	out := make([]uuid.UUID, 0, len(matches))
+	for _, m := range matches {
+		if len(m) < 2 {
+			continue
+		}
+		id, err := uuid.Parse(m[1])
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
+
+// ExtractMentionTokens returns the distinct lower-cased @handles in text.
+func ExtractMentionTokens(text string) []string {
+	matches := mentionTextPattern.FindAllStringSubmatch(text, -1)
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if len(m) < 2 {
+			continue
+		}
+		token := strings.ToLower(strings.TrimSpace(m[1]))
+		if token == "" {
+			continue
+		}
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		out = append(out, token)
+	}
+	return out
+}
+
+// ResolveBoardMentions maps the mentions in text to members of the board's workspace.
+// An @handle matches a username, a name without spaces or the local part of an email.
+// Mentions of users outside the workspace are dropped.
+func ResolveBoardMentions(db *gorm.DB, boardID uuid.UUID, text string) map[uuid.UUID]struct{} {
+	mentioned := map[uuid.UUID]struct{}{}
+	if strings.TrimSpace(text) == "" {
+		return mentioned
+	}
+
+	var board models.Board
+	if err := db.Select("id", "workspace_id").First(&board, "id = ?", boardID).Error; err != nil {
+		return mentioned
+	}
+
+	var workspaceMembers []models.WorkspaceMember
+	if err := db.Where("workspace_id = ? AND status <> ?", board.WorkspaceID, "declined").Find(&workspaceMembers).Error; err != nil {
+		return mentioned
+	}
+
+	allowed := map[uuid.UUID]struct{}{}
+	for _, m := range workspaceMembers {
+		allowed[m.UserID] = struct{}{}
+	}
+
+	for _, id := range ExtractMentionUserIDs(text) {
+		if _, ok := allowed[id]; ok {
+			mentioned[id] = struct{}{}
+		}
+	}
+
+	tokens := ExtractMentionTokens(text)
+	if len(tokens) == 0 || len(allowed) == 0 {
+		return mentioned
+	}
+
+	memberIDs := make([]uuid.UUID, 0, len(allowed))
+	for id := range allowed {
+		memberIDs = append(memberIDs, id)
+	}
+	var users []models.User
+	if err := db.Select("id", "name", "username", "email").Where("id IN ?", memberIDs).Find(&users).Error; err != nil {
+		return mentioned
+	}
+
+	tokenSet := map[string]struct{}{}
+	for _, t := range tokens {
+		tokenSet[t] = struct{}{}
+	}
+	for _, u := range users {
+		candidates := []string{
+			strings.ToLower(strings.TrimSpace(u.Username)),
+			strings.ToLower(strings.ReplaceAll(strings.TrimSpace(u.Name), " ", "")),
+		}
+		if parts := strings.Split(strings.ToLower(strings.TrimSpace(u.Email)), "@"); len(parts) > 0 {
+			candidates = append(candidates, parts[0])
+		}
+		for _, c := range candidates {
+			if c == "" {
+				continue
+			}
+			if _, ok := tokenSet[c]; ok {
+				mentioned[u.ID] = struct{}{}
+				break
+			}
+		}
+	}
+	return mentioned
+}
+
+// RecordMentions remembers which users were mentioned on a card so "My Work" can find the
+// card later; the actor's own mentions and repeats are ignored.
+func RecordMentions(db *gorm.DB, cardID, actorID uuid.UUID, mentioned map[uuid.UUID]struct{}) {
+	rows := make([]models.CardMention, 0, len(mentioned))
+	for userID := range mentioned {
+		if userID != actorID {
+			rows = append(rows, models.CardMention{CardID: cardID, UserID: userID})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		log.Printf("[Mentions] failed to record mentions on card %s: %v", cardID, err)
+	}
+}
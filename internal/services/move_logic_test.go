@@ -24,8 +24,12 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		board_id TEXT,
 		name TEXT NOT NULL,
 		position REAL NOT NULL,
+		version INTEGER DEFAULT 1,
 		created_at DATETIME,
-		updated_at DATETIME
+		updated_at DATETIME,
+		wip_limit INTEGER,
+		wip_scope TEXT DEFAULT 'cards',
+		wip_mode TEXT DEFAULT 'warn'
 	)`).Error; err != nil {
 		panic(err)
 	}
@@ -41,8 +45,18 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		archived_at DATETIME,
 		is_template INTEGER DEFAULT 0,
 		template_name TEXT,
+		version INTEGER DEFAULT 1,
+		start_date DATETIME,
 		due_date DATETIME,
 		is_complete INTEGER DEFAULT 0,
+		estimate_minutes INTEGER,
+		story_points REAL,
+		priority TEXT DEFAULT 'none',
+		last_activity_at DATETIME,
+		stale_notified_at DATETIME,
+		completed_at DATETIME,
+		sprint_id TEXT,
+		swimlane_id TEXT,
 		cover_attachment_id TEXT
 	)`).Error; err != nil {
 		panic(err)
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// My Work due buckets, in display order. Undated cards and items fall in later.
+const (
+	BucketOverdue  = "overdue"
+	BucketToday    = "today"
+	BucketThisWeek = "this_week" // after today and within the next seven days
+	BucketLater    = "later"
+)
+
+var myWorkBuckets = []string{BucketOverdue, BucketToday, BucketThisWeek, BucketLater}
+
+// Reasons a card is on someone's My Work list.
+const (
+	ReasonAssigned  = "assigned"
+	ReasonMentioned = "mentioned"
+	ReasonWatching  = "watching"
+)
+
+// MaxMyWorkCards caps the cards returned; the soonest due are kept.
+const MaxMyWorkCards = 500
+
+type MyWorkCard struct {
+	models.Card
+	BoardID    uuid.UUID `json:"board_id"`
+	BoardTitle string    `json:"board_title"`
+	Reasons    []string  `json:"reasons"`
+}
+
+type MyWorkBucket struct {
+	Key            string                  `json:"key"`
+	Cards          []MyWorkCard            `json:"cards"`
+	ChecklistItems []AssignedChecklistItem `json:"checklist_items"`
+}
+
+type MyWork struct {
+	Buckets   []MyWorkBucket `json:"buckets"`
+	Truncated bool           `json:"truncated"` // more than MaxMyWorkCards cards matched
+}
+
+type MyWorkService struct {
+	DB         *gorm.DB
+	Checklists *ChecklistService
+	Now        func() time.Time
+}
+
+func NewMyWorkService(db *gorm.DB, checklists *ChecklistService) *MyWorkService {
+	return &MyWorkService{DB: db, Checklists: checklists, Now: time.Now}
+}
+
+// MyWork gathers the open cards the user is assigned to, was mentioned on or watches, and
+// their open checklist items, across every board they can access, bucketed by due date in loc.
+// The number of queries does not grow with the number of boards.
+func (s *MyWorkService) MyWork(userID uuid.UUID, loc *time.Location) (*MyWork, error) {
+	assigned := s.DB.Table("card_members").Select("card_id").Where("user_id = ?", userID)
+	mentioned := s.DB.Table("card_mentions").Select("card_id").Where("user_id = ?", userID)
+	watching := s.DB.Table("subscriptions").Select("entity_id").Where("user_id = ? AND entity_type = ?", userID, models.SubscriptionCard)
+
+	var cards []models.Card
+	err := s.DB.Select("cards.*").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("boards.deleted_at IS NULL AND boards.workspace_id IN (?)", repository.AccessibleWorkspaceIDs(s.DB, userID)).
+		Where("cards.is_archived = ? AND cards.is_template = ? AND cards.is_complete = ?", false, false, false).
+		Where("cards.id IN (?) OR cards.id IN (?) OR cards.id IN (?)", assigned, mentioned, watching).
+		Order("CASE WHEN cards.due_date IS NULL THEN 1 ELSE 0 END, cards.due_date ASC, cards.id ASC").
+		Limit(MaxMyWorkCards + 1).
+		Preload("Column").Preload("Labels").
+		Find(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+	work := &MyWork{}
+	if len(cards) > MaxMyWorkCards {
+		cards, work.Truncated = cards[:MaxMyWorkCards], true
+	}
+
+	reasons, titles, err := s.describe(userID, cards)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.Checklists.AssignedItems(userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Now().In(loc)
+	byKey := map[string]*MyWorkBucket{}
+	for _, key := range myWorkBuckets {
+		work.Buckets = append(work.Buckets, MyWorkBucket{Key: key, Cards: []MyWorkCard{}, ChecklistItems: []AssignedChecklistItem{}})
+	}
+	for i := range work.Buckets {
+		byKey[work.Buckets[i].Key] = &work.Buckets[i]
+	}
+	for _, card := range cards {
+		bucket := byKey[dueBucket(card.DueDate, now)]
+		bucket.Cards = append(bucket.Cards, MyWorkCard{
+			Card: card, BoardID: card.Column.BoardID, BoardTitle: titles[card.Column.BoardID], Reasons: reasons[card.ID],
+		})
+	}
+	for _, item := range items {
+		bucket := byKey[dueBucket(item.DueDate, now)]
+		bucket.ChecklistItems = append(bucket.ChecklistItems, item)
+	}
+	return work, nil
+}
+
+// describe works out why each card is listed and the titles of their boards.
+func (s *MyWorkService) describe(userID uuid.UUID, cards []models.Card) (map[uuid.UUID][]string, map[uuid.UUID]string, error) {
+	reasons := map[uuid.UUID][]string{}
+	titles := map[uuid.UUID]string{}
+	if len(cards) == 0 {
+		return reasons, titles, nil
+	}
+	cardIDs := make([]uuid.UUID, len(cards))
+	boardIDs := make([]uuid.UUID, 0, len(cards))
+	for i, card := range cards {
+		cardIDs[i] = card.ID
+		boardIDs = append(boardIDs, card.Column.BoardID)
+	}
+
+	for _, source := range []struct {
+		reason string
+		query  *gorm.DB
+	}{
+		{ReasonAssigned, s.DB.Table("card_members").Select("card_id").Where("user_id = ? AND card_id IN ?", userID, cardIDs)},
+		{ReasonMentioned, s.DB.Table("card_mentions").Select("card_id").Where("user_id = ? AND card_id IN ?", userID, cardIDs)},
+		{ReasonWatching, s.DB.Table("subscriptions").Select("entity_id").
+			Where("user_id = ? AND entity_type = ? AND entity_id IN ?", userID, models.SubscriptionCard, cardIDs)},
+	} {
+		var ids []uuid.UUID
+		if err := source.query.Scan(&ids).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, id := range ids {
+			reasons[id] = append(reasons[id], source.reason)
+		}
+	}
+
+	var boards []models.Board
+	if err := s.DB.Select("id", "title").Where("id IN ?", boardIDs).Find(&boards).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, b := range boards {
+		titles[b.ID] = b.Title
+	}
+	return reasons, titles, nil
+}
+
+// dueBucket places a due date relative to now; today runs to midnight in now's location.
+func dueBucket(due *time.Time, now time.Time) string {
+	if due == nil {
+		return BucketLater
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch {
+	case due.Before(now):
+		return BucketOverdue
+	case due.Before(today.AddDate(0, 0, 1)):
+		return BucketToday
+	case due.Before(today.AddDate(0, 0, 8)):
+		return BucketThisWeek
+	}
+	return BucketLater
+}
@@ -14,6 +14,8 @@ type NotificationService struct {
 	DB           *gorm.DB
 	Hub          *realtime.Hub
 	EmailService EmailService
+	// ReplyAddresses, when set, gives card notification emails a reply address that posts a comment.
+	ReplyAddresses *ReplyAddressSigner
 }
 
 var ErrNotificationNotFound = errors.New("notification not found")
@@ -28,6 +30,8 @@ const (
 	PrefNotifyCardCreated     NotificationPreference = "notify_card_created"
 	PrefNotifyCardMoved       NotificationPreference = "notify_card_moved"
 	PrefNotifyCardArchived    NotificationPreference = "notify_card_archived"
+	PrefNotifyStaleCards      NotificationPreference = "notify_stale_cards"
+	PrefNotifyAssignments     NotificationPreference = "notify_assignments"
 )
 
 func NewNotificationService(db *gorm.DB, hub *realtime.Hub, emailService EmailService) *NotificationService {
@@ -80,7 +84,14 @@ func (s *NotificationService) CreateNotification(recipientID, actorID uuid.UUID,
 		if err := s.DB.Select("email").First(&recipient, "id = ?", recipientID).Error; err == nil && recipient.Email != "" {
 			subject := "Nexus Notification: " + title
 			body := message
-			if err := s.EmailService.SendNotificationEmail(recipient.Email, subject, body); err != nil {
+			var err error
+			if entityType == "CARD" && s.ReplyAddresses != nil {
+				body = ReplyAboveLine + "\n\n" + message + "\n\nReply to this email to add a comment to the card."
+				err = s.EmailService.SendNotificationEmailWithReplyTo(recipient.Email, subject, body, s.ReplyAddresses.Address(recipientID, entityID))
+			} else {
+				err = s.EmailService.SendNotificationEmail(recipient.Email, subject, body)
+			}
+			if err != nil {
 				log.Printf("[NotificationService] failed sending notification email to %s: %v", recipient.Email, err)
 			}
 		}
@@ -115,6 +126,10 @@ func (s *NotificationService) shouldSendEmail(userID uuid.UUID, pref Notificatio
 		return p.NotifyCardMoved
 	case PrefNotifyCardArchived:
 		return p.NotifyCardArchived
+	case PrefNotifyStaleCards:
+		return p.NotifyStaleCards
+	case PrefNotifyAssignments:
+		return p.NotifyAssignments
 	default:
 		return true
 	}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSavedFilter = errors.New("invalid saved filter")
+	ErrNotFilterOwner     = errors.New("only the filter's owner can change it")
+)
+
+type SavedFilterService struct {
+	DB    *gorm.DB
+	Cards *CardQueryService
+}
+
+func NewSavedFilterService(db *gorm.DB, cards *CardQueryService) *SavedFilterService {
+	return &SavedFilterService{DB: db, Cards: cards}
+}
+
+// SavedFilterInput carries the editable fields; nil fields are left unchanged on update.
+type SavedFilterInput struct {
+	Name   *string
+	Query  *string
+	Shared *bool
+}
+
+// CanAccessWorkspace reports whether the user owns or is an accepted member of the workspace.
+func (s *SavedFilterService) CanAccessWorkspace(userID, workspaceID uuid.UUID) bool {
+	return canAccessWorkspace(s.DB, userID, workspaceID)
+}
+
+// GetBoard loads a board the user can access.
+func (s *SavedFilterService) GetBoard(boardID, userID uuid.UUID) (*models.Board, error) {
+	board, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	if err != nil {
+		return nil, ErrBoardAccessDenied
+	}
+	return board, nil
+}
+
+// visible selects filters the user owns or that are shared with their workspace.
+func (s *SavedFilterService) visible(userID uuid.UUID) *gorm.DB {
+	return s.DB.Where("owner_id = ? OR shared = ?", userID, true)
+}
+
+// ListWorkspaceFilters returns the workspace's filters the user can see, workspace-wide
+// filters first.
+func (s *SavedFilterService) ListWorkspaceFilters(workspaceID, userID uuid.UUID) ([]models.SavedFilter, error) {
+	filters := []models.SavedFilter{}
+	err := s.visible(userID).Where("workspace_id = ?", workspaceID).
+		Order("CASE WHEN board_id IS NULL THEN 0 ELSE 1 END, name ASC").
+		Find(&filters).Error
+	return filters, err
+}
+
+// ListBoardFilters returns the board's filters followed by its workspace's workspace-wide
+// filters, as far as the user can see them.
+func (s *SavedFilterService) ListBoardFilters(board *models.Board, userID uuid.UUID) ([]models.SavedFilter, error) {
+	filters := []models.SavedFilter{}
+	err := s.visible(userID).
+		Where("board_id = ? OR (board_id IS NULL AND workspace_id = ?)", board.ID, board.WorkspaceID).
+		Order("CASE WHEN board_id IS NULL THEN 1 ELSE 0 END, name ASC").
+		Find(&filters).Error
+	return filters, err
+}
+
+// GetFilter loads a filter the user owns or can see through their workspace.
+func (s *SavedFilterService) GetFilter(id, userID uuid.UUID) (*models.SavedFilter, error) {
+	var filter models.SavedFilter
+	if err := s.DB.First(&filter, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if !s.CanAccessWorkspace(userID, filter.WorkspaceID) || (filter.OwnerID != userID && !filter.Shared) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &filter, nil
+}
+
+// CreateFilter saves a filter for the workspace, or for one of its boards when boardID is set.
+func (s *SavedFilterService) CreateFilter(ownerID, workspaceID uuid.UUID, boardID *uuid.UUID, in SavedFilterInput) (*models.SavedFilter, error) {
+	filter := &models.SavedFilter{WorkspaceID: workspaceID, BoardID: boardID, OwnerID: ownerID}
+	if in.Name == nil || in.Query == nil {
+		return nil, fmt.Errorf("%w: name and query are required", ErrInvalidSavedFilter)
+	}
+	if err := applySavedFilterInput(filter, in); err != nil {
+		return nil, err
+	}
+	if err := s.DB.Create(filter).Error; err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// UpdateFilter renames, rewrites or (un)shares a filter; only its owner may.
+func (s *SavedFilterService) UpdateFilter(filter *models.SavedFilter, userID uuid.UUID, in SavedFilterInput) error {
+	if filter.OwnerID != userID {
+		return ErrNotFilterOwner
+	}
+	if err := applySavedFilterInput(filter, in); err != nil {
+		return err
+	}
+	return s.DB.Save(filter).Error
+}
+
+func (s *SavedFilterService) DeleteFilter(filter *models.SavedFilter, userID uuid.UUID) error {
+	if filter.OwnerID != userID {
+		return ErrNotFilterOwner
+	}
+	return s.DB.Delete(filter).Error
+}
+
+// RunFilter lists the cards matching a filter within its board or workspace.
+func (s *SavedFilterService) RunFilter(filter *models.SavedFilter, userID uuid.UUID, page CardQueryPage) (*CardQueryResult, error) {
+	scope := CardQueryScope{WorkspaceID: &filter.WorkspaceID, BoardID: filter.BoardID}
+	return s.Cards.QueryCardsIn(userID, filter.Query, scope, page)
+}
+
+// applySavedFilterInput validates and copies the set fields; the query must parse.
+func applySavedFilterInput(filter *models.SavedFilter, in SavedFilterInput) error {
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" || len(name) > 100 {
+			return fmt.Errorf("%w: name must be 1-100 characters", ErrInvalidSavedFilter)
+		}
+		filter.Name = name
+	}
+	if in.Query != nil {
+		// Parse before trimming so error positions match what the user sent.
+		if _, err := ParseCardQuery(*in.Query); err != nil {
+			return err
+		}
+		filter.Query = strings.TrimSpace(*in.Query)
+	}
+	if in.Shared != nil {
+		filter.Shared = *in.Shared
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupSavedFilterTestDB(t *testing.T) *gorm.DB {
+	db := setupChecklistTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE card_mentions (card_id TEXT, user_id TEXT, created_at DATETIME, PRIMARY KEY (card_id, user_id))`,
+		`CREATE TABLE subscriptions (id TEXT PRIMARY KEY, user_id TEXT, entity_id TEXT, entity_type TEXT, created_at DATETIME)`,
+		`CREATE TABLE saved_filters (id TEXT PRIMARY KEY, workspace_id TEXT, board_id TEXT, owner_id TEXT, name TEXT, query TEXT,
+			shared INTEGER DEFAULT 0, created_at DATETIME, updated_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+func TestSavedFilters_ShareScopeAndRun(t *testing.T) {
+	db := setupSavedFilterTestDB(t)
+	alice, bob, stranger := uuid.New(), uuid.New(), uuid.New()
+	wsID, sprint, ops := uuid.New(), uuid.New(), uuid.New()
+	sprintCol, opsCol := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, alice)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, bob)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Sprint'), (?, ?, 'Ops')`, sprint, wsID, ops, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1), (?, ?, 'Doing', 1)`, sprintCol, sprint, opsCol, ops)
+	for i, c := range []models.Card{
+		{Title: "Sprint urgent", ColumnID: sprintCol, Priority: models.PriorityUrgent},
+		{Title: "Ops urgent", ColumnID: opsCol, Priority: models.PriorityUrgent},
+		{Title: "Ops calm", ColumnID: opsCol},
+	} {
+		c.ID, c.Position = uuid.New(), float64(i+1)
+		require.NoError(t, db.Create(&c).Error)
+	}
+
+	svc := services.NewSavedFilterService(db, services.NewCardQueryService(db))
+	str := func(s string) *string { return &s }
+	yes := true
+
+	_, err := svc.CreateFilter(alice, wsID, nil, services.SavedFilterInput{Name: str("Urgent")})
+	require.ErrorIs(t, err, services.ErrInvalidSavedFilter)
+	_, err = svc.CreateFilter(alice, wsID, nil, services.SavedFilterInput{Name: str("Urgent"), Query: str("priority:asap")})
+	var parseErr *services.CardQueryError
+	require.True(t, errors.As(err, &parseErr))
+
+	urgent, err := svc.CreateFilter(alice, wsID, nil, services.SavedFilterInput{Name: str(" Urgent "), Query: str("priority:urgent")})
+	require.NoError(t, err)
+	require.Equal(t, "Urgent", urgent.Name)
+	sprintOnly, err := svc.CreateFilter(alice, wsID, &sprint, services.SavedFilterInput{Name: str("Sprint urgent"), Query: str("priority:urgent"), Shared: &yes})
+	require.NoError(t, err)
+
+	// Unshared filters stay private to their owner.
+	_, err = svc.GetFilter(urgent.ID, bob)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	filters, err := svc.ListWorkspaceFilters(wsID, bob)
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	require.Equal(t, sprintOnly.ID, filters[0].ID)
+
+	require.ErrorIs(t, svc.UpdateFilter(urgent, bob, services.SavedFilterInput{Shared: &yes}), services.ErrNotFilterOwner)
+	require.NoError(t, svc.UpdateFilter(urgent, alice, services.SavedFilterInput{Shared: &yes}))
+	board, err := svc.GetBoard(ops, bob)
+	require.NoError(t, err)
+	filters, err = svc.ListBoardFilters(board, bob)
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	require.Equal(t, urgent.ID, filters[0].ID)
+
+	// A board filter only matches cards on its board.
+	run := func(id uuid.UUID) []string {
+		filter, err := svc.GetFilter(id, bob)
+		require.NoError(t, err)
+		result, err := svc.RunFilter(filter, bob, services.CardQueryPage{Sort: "title"})
+		require.NoError(t, err)
+		titles := []string{}
+		for _, c := range result.Cards {
+			titles = append(titles, c.Title)
+		}
+		return titles
+	}
+	require.Equal(t, []string{"Ops urgent", "Sprint urgent"}, run(urgent.ID))
+	require.Equal(t, []string{"Sprint urgent"}, run(sprintOnly.ID))
+
+	_, err = svc.GetFilter(urgent.ID, stranger)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	require.ErrorIs(t, svc.DeleteFilter(urgent, bob), services.ErrNotFilterOwner)
+	require.NoError(t, svc.DeleteFilter(urgent, alice))
+}
+
+func TestMyWork_BucketsAssignedMentionedAndWatched(t *testing.T) {
+	db := setupSavedFilterTestDB(t)
+	me, other := uuid.New(), uuid.New()
+	wsID, hiddenWS := uuid.New(), uuid.New()
+	boardID, hiddenBoard, colID, hiddenCol := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?), (?, 'hidden', ?)`, wsID, other, hiddenWS, other)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, me)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Launch'), (?, ?, 'Hidden')`, boardID, wsID, hiddenBoard, hiddenWS)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1), (?, ?, 'Doing', 1)`, colID, boardID, hiddenCol, hiddenBoard)
+
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, loc) // a Monday morning
+	at := func(days, hours int) *time.Time {
+		t := now.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	cards := map[string]uuid.UUID{}
+	create := func(title string, col uuid.UUID, due *time.Time, mutate func(*models.Card)) {
+		card := models.Card{ID: uuid.New(), Title: title, ColumnID: col, Position: float64(len(cards) + 1), DueDate: due}
+		if mutate != nil {
+			mutate(&card)
+		}
+		require.NoError(t, db.Create(&card).Error)
+		cards[title] = card.ID
+	}
+	create("Late report", colID, at(-1, 0), nil)
+	create("Standup notes", colID, at(0, 8), nil)
+	create("Review PR", colID, at(3, 0), nil)
+	create("Plan Q3", colID, at(30, 0), nil)
+	create("Someday", colID, nil, nil)
+	create("Done already", colID, at(0, 1), func(c *models.Card) { c.IsComplete = true })
+	create("Not mine", colID, at(0, 1), nil)
+	create("Hidden", hiddenCol, at(0, 1), nil)
+
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?), (?, ?), (?, ?), (?, ?), (?, ?)`,
+		cards["Late report"], me, cards["Plan Q3"], me, cards["Done already"], me, cards["Hidden"], me, cards["Not mine"], other)
+	services.RecordMentions(db, cards["Standup notes"], other, map[uuid.UUID]struct{}{me: {}})
+	services.RecordMentions(db, cards["Not mine"], me, map[uuid.UUID]struct{}{me: {}}) // self-mentions are not recorded
+	db.Exec(`INSERT INTO subscriptions (id, user_id, entity_id, entity_type) VALUES (?, ?, ?, 'CARD'), (?, ?, ?, 'CARD')`,
+		uuid.New(), me, cards["Review PR"], uuid.New(), me, cards["Late report"])
+	db.Exec(`INSERT INTO subscriptions (id, user_id, entity_id, entity_type) VALUES (?, ?, ?, 'BOARD')`, uuid.New(), me, boardID)
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, cards["Someday"], me)
+
+	checklist := models.Checklist{CardID: cards["Not mine"], Title: "Steps", Position: 1}
+	require.NoError(t, db.Create(&checklist).Error)
+	require.NoError(t, db.Create(&models.ChecklistItem{ChecklistID: checklist.ID, Title: "Sign off", Position: 1, AssigneeID: &me, DueDate: at(1, 0)}).Error)
+
+	svc := services.NewMyWorkService(db, services.NewChecklistService(db, nil))
+	svc.Now = func() time.Time { return now.UTC() }
+	work, err := svc.MyWork(me, loc)
+	require.NoError(t, err)
+	require.False(t, work.Truncated)
+
+	got := map[string][]string{}
+	for _, b := range work.Buckets {
+		got[b.Key] = []string{}
+		for _, c := range b.Cards {
+			require.Equal(t, boardID, c.BoardID)
+			require.Equal(t, "Launch", c.BoardTitle)
+			got[b.Key] = append(got[b.Key], c.Title)
+		}
+		for _, item := range b.ChecklistItems {
+			got[b.Key] = append(got[b.Key], "item: "+item.Title)
+		}
+	}
+	require.Equal(t, map[string][]string{
+		services.BucketOverdue:  {"Late report"},
+		services.BucketToday:    {"Standup notes"},
+		services.BucketThisWeek: {"Review PR", "item: Sign off"},
+		services.BucketLater:    {"Plan Q3", "Someday"},
+	}, got)
+	require.Equal(t, []string{services.ReasonAssigned, services.ReasonWatching}, work.Buckets[0].Cards[0].Reasons)
+	require.Equal(t, []string{services.ReasonMentioned}, work.Buckets[1].Cards[0].Reasons)
+
+	// It is still Sunday evening in UTC, so the card due this afternoon in UTC+10 is due tomorrow.
+	work, err = svc.MyWork(me, time.UTC)
+	require.NoError(t, err)
+	require.Equal(t, "Standup notes", work.Buckets[2].Cards[0].Title)
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+// SearchKind is the kind of record a search hit was found in.
+type SearchKind string
+
+const (
+	SearchCard          SearchKind = "card" // title or description
+	SearchComment       SearchKind = "comment"
+	SearchChecklistItem SearchKind = "checklist_item"
+	SearchAttachment    SearchKind = "attachment" // filename
+)
+
+// Highlight markers wrapped around matched terms before the snippet is HTML-escaped; control
+// characters cannot collide with anything a user types.
+const (
+	searchMarkStart = "\x02"
+	searchMarkStop  = "\x03"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+	snippetRadius      = 60 // characters kept either side of the first match in fallback snippets
+)
+
+// searchSource says where one kind of hit lives. Every source joins through to its card, so
+// access, archive and board filters are shared.
+type searchSource struct {
+	kind     SearchKind
+	index    string // GIN index over the source's tsvector on Postgres
+	from     string // table plus joins up to "cards"
+	text     string // SQL expression of the searchable text
+	sourceID string // SQL expression of the hit's own ID
+	config   string // Postgres text search configuration; must match the index
+	where    string // extra conditions, e.g. soft deletes
+}
+
+var searchSources = []searchSource{
+	{
+		kind:     SearchCard,
+		index:    "idx_cards_search",
+		from:     "cards",
+		text:     "coalesce(cards.title, '') || ' ' || coalesce(cards.description, '')",
+		sourceID: "cards.id",
+		config:   "english",
+	},
+	{
+		kind:     SearchComment,
+		index:    "idx_comments_search",
+		from:     "comments JOIN cards ON cards.id = comments.card_id",
+		text:     "coalesce(comments.content, '')",
+		sourceID: "comments.id",
+		config:   "english",
+	},
+	{
+		kind:     SearchChecklistItem,
+		index:    "idx_checklist_items_search",
+		from:     "checklist_items JOIN checklists ON checklists.id = checklist_items.checklist_id JOIN cards ON cards.id = checklists.card_id",
+		text:     "coalesce(checklist_items.title, '')",
+		sourceID: "checklist_items.id",
+		config:   "english",
+		where:    "checklist_items.deleted_at IS NULL AND checklists.deleted_at IS NULL",
+	},
+	{
+		// Filenames are not prose, so they are not stemmed.
+		kind:     SearchAttachment,
+		index:    "idx_attachments_search",
+		from:     "attachments JOIN cards ON cards.id = attachments.card_id",
+		text:     "coalesce(attachments.filename, '')",
+		sourceID: "attachments.id",
+		config:   "simple",
+		where:    "attachments.deleted_at IS NULL",
+	},
+}
+
+// SearchOptions narrows a search. An empty Kinds searches everything.
+type SearchOptions struct {
+	Query           string
+	BoardID         *uuid.UUID
+	Kinds           []SearchKind
+	IncludeArchived bool
+	Limit           int
+}
+
+// SearchHit is one match. SourceID is the comment, checklist item or attachment matched, or
+// the card itself. Snippet is HTML-escaped text with matched terms wrapped in <mark>.
+type SearchHit struct {
+	Kind        SearchKind `json:"kind"`
+	SourceID    uuid.UUID  `json:"source_id"`
+	CardID      uuid.UUID  `json:"card_id"`
+	CardTitle   string     `json:"card_title"`
+	IsArchived  bool       `json:"is_archived"`
+	ColumnID    uuid.UUID  `json:"column_id"`
+	ColumnName  string     `json:"column_name"`
+	BoardID     uuid.UUID  `json:"board_id"`
+	BoardTitle  string     `json:"board_title"`
+	Snippet     string     `json:"snippet"`
+	Rank        float64    `json:"rank"`
+	searchOrder int
+}
+
+type searchRow struct {
+	SourceID   uuid.UUID
+	CardID     uuid.UUID
+	CardTitle  string
+	IsArchived bool
+	ColumnID   uuid.UUID
+	ColumnName string
+	BoardID    uuid.UUID
+	BoardTitle string
+	Body       string
+	Snippet    string
+	Rank       float64
+}
+
+type SearchService struct {
+	DB *gorm.DB
+}
+
+func NewSearchService(db *gorm.DB) *SearchService {
+	return &SearchService{DB: db}
+}
+
+// EnsureSearchIndexes creates the full-text indexes on Postgres. Other databases search with
+// LIKE and need none.
+func EnsureSearchIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, src := range searchSources {
+		// The indexed expression must match the one fullText queries, or Postgres ignores it.
+		table := strings.Fields(src.from)[0]
+		stmt := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN ((%s))", src.index, table, tsvector(src))
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("create %s: %w", src.index, err)
+		}
+	}
+	return nil
+}
+
+func tsvector(src searchSource) string {
+	return fmt.Sprintf("to_tsvector('%s', %s)", src.config, src.text)
+}
+
+// Search finds cards, comments, checklist items and attachments on boards the user can access,
+// best matches first.
+func (s *SearchService) Search(userID uuid.UUID, opts SearchOptions) ([]SearchHit, error) {
+	query := strings.TrimSpace(opts.Query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+	kinds := map[SearchKind]bool{}
+	for _, k := range opts.Kinds {
+		if !isSearchKind(k) {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidSearch, k)
+		}
+		kinds[k] = true
+	}
+
+	postgres := s.DB.Dialector.Name() == "postgres"
+	terms := strings.Fields(strings.ToLower(query))
+	hits := []SearchHit{}
+	for order, src := range searchSources {
+		if len(kinds) > 0 && !kinds[src.kind] {
+			continue
+		}
+		var rows []searchRow
+		var err error
+		if postgres {
+			rows, err = s.fullText(userID, src, query, opts, limit)
+		} else {
+			rows, err = s.like(userID, src, terms, opts, limit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			hit := SearchHit{
+				Kind: src.kind, SourceID: r.SourceID, CardID: r.CardID, CardTitle: r.CardTitle,
+				IsArchived: r.IsArchived, ColumnID: r.ColumnID, ColumnName: r.ColumnName,
+				BoardID: r.BoardID, BoardTitle: r.BoardTitle, Rank: r.Rank, searchOrder: order,
+			}
+			if postgres {
+				hit.Snippet = markSnippet(r.Snippet)
+			} else {
+				hit.Snippet, hit.Rank = fallbackSnippet(r.Body, terms)
+			}
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].searchOrder < hits[j].searchOrder
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func isSearchKind(k SearchKind) bool {
+	for _, src := range searchSources {
+		if src.kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// scoped selects a source's hits on the user's boards, before the match condition.
+func (s *SearchService) scoped(userID uuid.UUID, src searchSource, opts SearchOptions) *gorm.DB {
+	q := s.DB.Table(src.from).
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("boards.deleted_at IS NULL AND boards.workspace_id IN (?)", repository.AccessibleWorkspaceIDs(s.DB, userID)).
+		Where("cards.is_template = ?", false)
+	if src.where != "" {
+		q = q.Where(src.where)
+	}
+	if !opts.IncludeArchived {
+		q = q.Where("cards.is_archived = ?", false)
+	}
+	if opts.BoardID != nil {
+		q = q.Where("boards.id = ?", *opts.BoardID)
+	}
+	return q
+}
+
+const searchColumns = "%s AS source_id, cards.id AS card_id, cards.title AS card_title, cards.is_archived AS is_archived, " +
+	"columns.id AS column_id, columns.name AS column_name, boards.id AS board_id, boards.title AS board_title"
+
+// fullText matches with the source's tsvector index, ranks with ts_rank and highlights with
+// ts_headline.
+func (s *SearchService) fullText(userID uuid.UUID, src searchSource, query string, opts SearchOptions, limit int) ([]searchRow, error) {
+	tsquery := fmt.Sprintf("websearch_to_tsquery('%s', ?)", src.config)
+	headline := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2", searchMarkStart, searchMarkStop)
+	var rows []searchRow
+	err := s.scoped(userID, src, opts).
+		Select(fmt.Sprintf(searchColumns, src.sourceID)+
+			fmt.Sprintf(", ts_rank(%s, %s) AS rank", tsvector(src), tsquery)+
+			fmt.Sprintf(", ts_headline('%s', %s, %s, ?) AS snippet", src.config, src.text, tsquery),
+			query, query, headline).
+		Where(fmt.Sprintf("%s @@ %s", tsvector(src), tsquery), query).
+		Order("rank DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// like is the fallback for databases without full-text search: every term must appear,
+// case-insensitively. Ranking and snippets are worked out afterwards from the text.
+func (s *SearchService) like(userID uuid.UUID, src searchSource, terms []string, opts SearchOptions, limit int) ([]searchRow, error) {
+	q := s.scoped(userID, src, opts).
+		Select(fmt.Sprintf(searchColumns, src.sourceID) + fmt.Sprintf(", %s AS body", src.text))
+	for _, term := range terms {
+		q = q.Where(fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '\\'", src.text), "%"+escapeLike(term)+"%")
+	}
+	var rows []searchRow
+	err := q.Order("cards.updated_at DESC").Limit(limit).Scan(&rows).Error
+	return rows, err
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+// markSnippet HTML-escapes a snippet and turns the highlight markers into <mark> tags.
+func markSnippet(snippet string) string {
+	return strings.NewReplacer(searchMarkStart, "<mark>", searchMarkStop, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// fallbackSnippet cuts the text around the first match, highlights every term in the cut and
+// ranks the text by how often the terms occur in it.
+func fallbackSnippet(body string, terms []string) (string, float64) {
+	lower := strings.ToLower(body)
+	if len(lower) != len(body) {
+		// Some runes change width when lowercased; fall back to case-sensitive offsets.
+		lower = body
+	}
+	first, occurrences := -1, 0
+	for _, term := range terms {
+		occurrences += strings.Count(lower, term)
+		if i := strings.Index(lower, term); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+
+	start, end := 0, len(body)
+	if first > snippetRadius {
+		start = first - snippetRadius
+		for start < first && !utf8.RuneStart(body[start]) {
+			start++
+		}
+	}
+	if end-first > snippetRadius*2 && first >= 0 {
+		end = first + snippetRadius*2
+		for end > first && end < len(body) && !utf8.RuneStart(body[end]) {
+			end--
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	cut, cutLower := body[start:end], lower[start:end]
+	for i := 0; i < len(cut); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(cutLower[i:], term) && len(term) > len(matched) {
+				matched = term
+			}
+		}
+		if matched == "" {
+			_, size := utf8.DecodeRuneInString(cut[i:])
+			b.WriteString(cut[i : i+size])
+			i += size
+			continue
+		}
+		b.WriteString(searchMarkStart + cut[i:i+len(matched)] + searchMarkStop)
+		i += len(matched)
+	}
+	if end < len(body) {
+		b.WriteString("...")
+	}
+	return markSnippet(b.String()), float64(occurrences) / float64(1+len(body)/200)
+}
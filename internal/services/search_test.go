@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch_LikeFallbackAcrossSources(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE comments (id TEXT PRIMARY KEY, card_id TEXT, user_id TEXT, content TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE checklists (id TEXT PRIMARY KEY, card_id TEXT, title TEXT, position REAL, version INTEGER DEFAULT 1, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE checklist_items (id TEXT PRIMARY KEY, checklist_id TEXT, title TEXT, is_completed INTEGER DEFAULT 0, position REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE attachments (id TEXT PRIMARY KEY, card_id TEXT, user_id TEXT, filename TEXT, file_path TEXT, file_type TEXT, size INTEGER, created_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner, stranger := uuid.New(), uuid.New()
+	mineWS, otherWS := uuid.New(), uuid.New()
+	mineBoard, otherBoard := uuid.New(), uuid.New()
+	mineCol, otherCol := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'mine', ?), (?, 'other', ?)`, mineWS, owner, otherWS, stranger)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Mine'), (?, ?, 'Other')`, mineBoard, mineWS, otherBoard, otherWS)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'To Do', 1), (?, ?, 'Theirs', 1)`, mineCol, mineBoard, otherCol, otherBoard)
+
+	invoice, archived, foreign := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: invoice, Title: "Send Invoice", Description: "Email the <b>invoice</b> to finance", ColumnID: mineCol, Position: 1}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: archived, Title: "Old invoice run", ColumnID: mineCol, Position: 2, IsArchived: true}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: foreign, Title: "Their invoice", ColumnID: otherCol, Position: 1}).Error)
+
+	comment, item, deletedItem, attachment := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	checklist := uuid.New()
+	db.Exec(`INSERT INTO comments (id, card_id, user_id, content) VALUES (?, ?, ?, 'Finance wants the INVOICE by Friday')`, comment, invoice, owner)
+	db.Exec(`INSERT INTO checklists (id, card_id, title, position) VALUES (?, ?, 'Steps', 1)`, checklist, invoice)
+	db.Exec(`INSERT INTO checklist_items (id, checklist_id, title, position) VALUES (?, ?, 'Attach invoice PDF', 1)`, item, checklist)
+	db.Exec(`INSERT INTO checklist_items (id, checklist_id, title, position, deleted_at) VALUES (?, ?, 'Old invoice step', 2, CURRENT_TIMESTAMP)`, deletedItem, checklist)
+	db.Exec(`INSERT INTO attachments (id, card_id, user_id, filename) VALUES (?, ?, ?, 'invoice_2024.pdf')`, attachment, invoice, owner)
+
+	svc := services.NewSearchService(db)
+
+	hits, err := svc.Search(owner, services.SearchOptions{Query: "invoice"})
+	require.NoError(t, err)
+	found := map[uuid.UUID]services.SearchKind{}
+	for _, h := range hits {
+		found[h.SourceID] = h.Kind
+		require.Equal(t, mineBoard, h.BoardID)
+		require.Equal(t, invoice, h.CardID)
+	}
+	require.Equal(t, map[uuid.UUID]services.SearchKind{
+		invoice: services.SearchCard, comment: services.SearchComment,
+		item: services.SearchChecklistItem, attachment: services.SearchAttachment,
+	}, found)
+
+	// Snippets are escaped and highlight the match whatever its case.
+	snippets := map[services.SearchKind]string{}
+	for _, h := range hits {
+		snippets[h.Kind] = h.Snippet
+	}
+	require.Equal(t, "Send <mark>Invoice</mark> Email the &lt;b&gt;<mark>invoice</mark>&lt;/b&gt; to finance", snippets[services.SearchCard])
+	require.Equal(t, "Finance wants the <mark>INVOICE</mark> by Friday", snippets[services.SearchComment])
+
+	// Every term must match, and LIKE wildcards are literal.
+	hits, err = svc.Search(owner, services.SearchOptions{Query: "invoice friday"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, comment, hits[0].SourceID)
+	hits, err = svc.Search(owner, services.SearchOptions{Query: "invoice_2"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	hits, err = svc.Search(owner, services.SearchOptions{Query: "%"})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	// Archived cards only with the flag; type filters narrow the sources.
+	hits, err = svc.Search(owner, services.SearchOptions{Query: "invoice", IncludeArchived: true, Kinds: []services.SearchKind{services.SearchCard}})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	cards := []uuid.UUID{hits[0].CardID, hits[1].CardID}
+	require.ElementsMatch(t, []uuid.UUID{invoice, archived}, cards)
+
+	// Other people's boards are never searched, even when named.
+	hits, err = svc.Search(stranger, services.SearchOptions{Query: "invoice"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, foreign, hits[0].CardID)
+	hits, err = svc.Search(stranger, services.SearchOptions{Query: "invoice", BoardID: &mineBoard})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	_, err = svc.Search(owner, services.SearchOptions{Query: "  "})
+	require.ErrorIs(t, err, services.ErrInvalidSearch)
+	_, err = svc.Search(owner, services.SearchOptions{Query: "invoice", Kinds: []services.SearchKind{"board"}})
+	require.ErrorIs(t, err, services.ErrInvalidSearch)
+	hits, err = svc.Search(owner, services.SearchOptions{Query: "invoice", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotInWorkspace     = errors.New("board is not in the shared definition's workspace")
+	ErrSharedDefinition   = errors.New("shared definitions are edited on the workspace")
+	ErrInvalidSharedLabel = errors.New("invalid label")
+)
+
+// SharedDefinitionService manages workspace fields and labels and the board copies that
+// link to them. Edits to a shared definition are applied to every linked board copy.
+type SharedDefinitionService struct {
+	DB     *gorm.DB
+	Fields *CustomFieldService
+}
+
+func NewSharedDefinitionService(db *gorm.DB, fields *CustomFieldService) *SharedDefinitionService {
+	return &SharedDefinitionService{DB: db, Fields: fields}
+}
+
+// CanAccessWorkspace reports whether the user owns or is an accepted member of the workspace.
+func (s *SharedDefinitionService) CanAccessWorkspace(userID, workspaceID uuid.UUID) bool {
+	return canAccessWorkspace(s.DB, userID, workspaceID)
+}
+
+// WorkspaceBoardIDs returns the workspace's boards; every workspace member can see them.
+func (s *SharedDefinitionService) WorkspaceBoardIDs(workspaceID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := s.DB.Model(&models.Board{}).Where("workspace_id = ?", workspaceID).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// linkedBoard loads the board and checks that it belongs to workspaceID.
+func (s *SharedDefinitionService) linkedBoard(boardID, workspaceID uuid.UUID) error {
+	var board models.Board
+	if err := s.DB.Select("id", "workspace_id").First(&board, "id = ?", boardID).Error; err != nil {
+		return err
+	}
+	if board.WorkspaceID != workspaceID {
+		return ErrNotInWorkspace
+	}
+	return nil
+}
+
+// --- Fields ---
+
+func (s *SharedDefinitionService) CreateField(workspaceID uuid.UUID, name string, fieldType models.CustomFieldType, options []string) (*models.WorkspaceField, error) {
+	// Validate through the board update path so shared and board fields follow the same rules.
+	name = strings.TrimSpace(name)
+	probe := &models.CustomField{ID: uuid.New(), Name: name, Type: models.FieldTypeText}
+	if err := s.checkField(probe, FieldUpdateInput{Name: &name, Type: &fieldType, Options: &options}); err != nil {
+		return nil, err
+	}
+	def := &models.WorkspaceField{WorkspaceID: workspaceID, Name: name, Type: fieldType, Options: options}
+	if !fieldType.HasOptions() {
+		def.Options = nil
+	}
+	if err := s.DB.Create(def).Error; err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// checkField validates a shared field edit against a detached copy of the field. Shared
+// fields cannot be formulas: a formula only makes sense among one board's fields.
+func (s *SharedDefinitionService) checkField(probe *models.CustomField, in FieldUpdateInput) error {
+	if in.Formula != nil || (in.Type != nil && *in.Type == models.FieldTypeFormula) {
+		return fmt.Errorf("%w: workspace fields cannot be formulas", ErrInvalidCustomField)
+	}
+	in.DryRun = true
+	_, err := s.Fields.updateField(probe, in)
+	return err
+}
+
+func (s *SharedDefinitionService) ListFields(workspaceID uuid.UUID) ([]models.WorkspaceField, error) {
+	var defs []models.WorkspaceField
+	err := s.DB.Where("workspace_id = ?", workspaceID).Order("name ASC").Find(&defs).Error
+	return defs, err
+}
+
+// GetField returns a shared field the user can see through its workspace.
+func (s *SharedDefinitionService) GetField(id, userID uuid.UUID) (*models.WorkspaceField, error) {
+	var def models.WorkspaceField
+	if err := s.DB.First(&def, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if !s.CanAccessWorkspace(userID, def.WorkspaceID) {
+		return nil, ErrBoardAccessDenied
+	}
+	return &def, nil
+}
+
+// SharedFieldUpdateResult sums the value migration across all linked boards.
+type SharedFieldUpdateResult struct {
+	Field          *models.WorkspaceField   `json:"field"`
+	DryRun         bool                     `json:"dry_run"`
+	BoardIDs       []uuid.UUID              `json:"board_ids"`
+	ValuesMigrated int                      `json:"values_migrated"`
+	ValuesCleared  int                      `json:"values_cleared"`
+	Failures       []FieldConversionFailure `json:"failures"`
+}
+
+// UpdateField applies a shared field edit to the definition and every linked board copy,
+// migrating their card values as a board field edit would. Every copy is checked first,
+// so a change that one board's formulas cannot take is rejected for all of them.
+func (s *SharedDefinitionService) UpdateField(def *models.WorkspaceField, in FieldUpdateInput) (*SharedFieldUpdateResult, error) {
+	probe := &models.CustomField{ID: def.ID, Name: def.Name, Type: def.Type, Options: def.Options}
+	if err := s.checkField(probe, in); err != nil {
+		return nil, err
+	}
+	var linked []models.CustomField
+	if err := s.DB.Where("workspace_field_id = ?", def.ID).Order("board_id").Find(&linked).Error; err != nil {
+		return nil, err
+	}
+
+	result := &SharedFieldUpdateResult{Field: def, DryRun: in.DryRun, BoardIDs: []uuid.UUID{}, Failures: []FieldConversionFailure{}}
+	preview := in
+	preview.DryRun = true
+	for i := range linked {
+		check := linked[i]
+		r, err := s.Fields.updateField(&check, preview)
+		if err != nil {
+			return nil, err
+		}
+		result.BoardIDs = append(result.BoardIDs, linked[i].BoardID)
+		if in.DryRun {
+			result.ValuesMigrated += r.ValuesMigrated
+			result.ValuesCleared += r.ValuesCleared
+			result.Failures = append(result.Failures, r.Failures...)
+		}
+	}
+	if in.DryRun {
+		return result, nil
+	}
+
+	for i := range linked {
+		r, err := s.Fields.updateField(&linked[i], in)
+		if err != nil {
+			return nil, err
+		}
+		result.ValuesMigrated += r.ValuesMigrated
+		result.ValuesCleared += r.ValuesCleared
+		result.Failures = append(result.Failures, r.Failures...)
+	}
+	if in.Name != nil {
+		def.Name = strings.TrimSpace(*in.Name)
+	}
+	if in.Type != nil {
+		def.Type = *in.Type
+	}
+	if in.Options != nil {
+		def.Options = nil
+		for _, opt := range *in.Options {
+			def.Options = append(def.Options, strings.TrimSpace(opt))
+		}
+	}
+	if !def.Type.HasOptions() {
+		def.Options = nil
+	}
+	if err := s.DB.Model(def).Updates(map[string]interface{}{
+		"name": def.Name, "type": def.Type, "options": def.Options,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteField removes the shared field, its board copies and their card values, and
+// returns the boards that lost a field.
+func (s *SharedDefinitionService) DeleteField(def *models.WorkspaceField) ([]uuid.UUID, error) {
+	var linked []models.CustomField
+	if err := s.DB.Where("workspace_field_id = ?", def.ID).Find(&linked).Error; err != nil {
+		return nil, err
+	}
+	boardIDs := []uuid.UUID{}
+	for _, f := range linked {
+		if err := s.Fields.DeleteField(f.ID); err != nil {
+			return nil, err
+		}
+		boardIDs = append(boardIDs, f.BoardID)
+	}
+	return boardIDs, s.DB.Delete(def).Error
+}
+
+// LinkField opts a board into a shared field; linking twice returns the existing copy.
+func (s *SharedDefinitionService) LinkField(boardID uuid.UUID, def *models.WorkspaceField) (*models.CustomField, error) {
+	if err := s.linkedBoard(boardID, def.WorkspaceID); err != nil {
+		return nil, err
+	}
+	var existing models.CustomField
+	if err := s.DB.Where("board_id = ? AND workspace_field_id = ?", boardID, def.ID).First(&existing).Error; err == nil {
+		return &existing, nil
+	}
+	return s.Fields.createField(&models.CustomField{
+		BoardID:          boardID,
+		Name:             def.Name,
+		Type:             def.Type,
+		Options:          def.Options,
+		WorkspaceFieldID: &def.ID,
+	})
+}
+
+// --- Labels ---
+
+func validateSharedLabel(name, color string) error {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(color) == "" {
+		return fmt.Errorf("%w: name and color are required", ErrInvalidSharedLabel)
+	}
+	return nil
+}
+
+func (s *SharedDefinitionService) CreateLabel(workspaceID uuid.UUID, name, color string) (*models.WorkspaceLabel, error) {
+	if err := validateSharedLabel(name, color); err != nil {
+		return nil, err
+	}
+	def := &models.WorkspaceLabel{WorkspaceID: workspaceID, Name: strings.TrimSpace(name), Color: strings.TrimSpace(color)}
+	if err := s.DB.Create(def).Error; err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+func (s *SharedDefinitionService) ListLabels(workspaceID uuid.UUID) ([]models.WorkspaceLabel, error) {
+	var defs []models.WorkspaceLabel
+	err := s.DB.Where("workspace_id = ?", workspaceID).Order("name ASC").Find(&defs).Error
+	return defs, err
+}
+
+// GetLabel returns a shared label the user can see through its workspace.
+func (s *SharedDefinitionService) GetLabel(id, userID uuid.UUID) (*models.WorkspaceLabel, error) {
+	var def models.WorkspaceLabel
+	if err := s.DB.First(&def, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if !s.CanAccessWorkspace(userID, def.WorkspaceID) {
+		return nil, ErrBoardAccessDenied
+	}
+	return &def, nil
+}
+
+// UpdateLabel renames or recolors the shared label and its board copies, returning the
+// boards whose labels changed. Empty values are left unchanged.
+func (s *SharedDefinitionService) UpdateLabel(def *models.WorkspaceLabel, name, color string) ([]uuid.UUID, error) {
+	if name = strings.TrimSpace(name); name != "" {
+		def.Name = name
+	}
+	if color = strings.TrimSpace(color); color != "" {
+		def.Color = color
+	}
+	var boardIDs []uuid.UUID
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(def).Updates(map[string]interface{}{"name": def.Name, "color": def.Color}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Label{}).Where("workspace_label_id = ?", def.ID).
+			Updates(map[string]interface{}{"name": def.Name, "color": def.Color}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Label{}).Where("workspace_label_id = ?", def.ID).Pluck("board_id", &boardIDs).Error
+	})
+	return boardIDs, err
+}
+
+// DeleteLabel removes the shared label, its board copies and their card assignments.
+func (s *SharedDefinitionService) DeleteLabel(def *models.WorkspaceLabel) ([]uuid.UUID, error) {
+	var boardIDs []uuid.UUID
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		linked := tx.Model(&models.Label{}).Select("id").Where("workspace_label_id = ?", def.ID)
+		if err := tx.Model(&models.Label{}).Where("workspace_label_id = ?", def.ID).Pluck("board_id", &boardIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM card_labels WHERE label_id IN (?)", linked).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_label_id = ?", def.ID).Delete(&models.Label{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(def).Error
+	})
+	return boardIDs, err
+}
+
+// LinkLabel opts a board into a shared label; linking twice returns the existing copy.
+func (s *SharedDefinitionService) LinkLabel(boardID uuid.UUID, def *models.WorkspaceLabel) (*models.Label, error) {
+	if err := s.linkedBoard(boardID, def.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return repository.LinkWorkspaceLabel(s.DB, boardID, def)
+}
+
+// GroupCardsByWorkspaceField counts the active cards on the workspace's boards by their
+// value for a shared field, across every board that links it.
+func (s *SharedDefinitionService) GroupCardsByWorkspaceField(def *models.WorkspaceField) ([]FieldGroupStat, error) {
+	boardIDs, err := s.WorkspaceBoardIDs(def.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return GroupCardsByField(s.DB, &models.CustomField{Type: def.Type, WorkspaceFieldID: &def.ID}, boardIDs)
+}
@@ -0,0 +1,138 @@
+package services_test
+
+import (
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedDefinitions_LinkMoveAndGroupAcrossBoards(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE workspace_fields (id TEXT PRIMARY KEY, workspace_id TEXT, name TEXT, type TEXT, options TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE workspace_labels (id TEXT PRIMARY KEY, workspace_id TEXT, name TEXT, color TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (
+			id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+			value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE checklists (id TEXT PRIMARY KEY, card_id TEXT, title TEXT, position REAL, version INTEGER DEFAULT 1, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE checklist_items (id TEXT PRIMARY KEY, checklist_id TEXT, title TEXT, is_completed INTEGER DEFAULT 0, position REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner := uuid.New()
+	wsID, otherWS := uuid.New(), uuid.New()
+	sales, support, elsewhere := uuid.New(), uuid.New(), uuid.New()
+	salesCol, supportCol, elsewhereCol := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?), (?, 'other', ?)`, wsID, owner, otherWS, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Sales'), (?, ?, 'Support'), (?, ?, 'Elsewhere')`,
+		sales, wsID, support, wsID, elsewhere, otherWS)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Open', 1), (?, ?, 'Open', 1), (?, ?, 'Open', 1)`,
+		salesCol, sales, supportCol, support, elsewhereCol, elsewhere)
+	cardA, cardB, cardC := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: cardA, Title: "A", ColumnID: salesCol, Position: 1}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: cardB, Title: "B", ColumnID: salesCol, Position: 2}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: cardC, Title: "C", ColumnID: supportCol, Position: 1}).Error)
+
+	fields := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	svc := services.NewSharedDefinitionService(db, fields)
+
+	_, err := svc.CreateField(wsID, "Priority", models.FieldTypeDropdown, nil)
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+	_, err = svc.CreateField(wsID, "Score", models.FieldTypeFormula, nil)
+	require.ErrorIs(t, err, services.ErrInvalidCustomField)
+	priority, err := svc.CreateField(wsID, "Priority", models.FieldTypeDropdown, []string{"P1", "P2"})
+	require.NoError(t, err)
+	urgent, err := svc.CreateLabel(wsID, "Urgent", "#ff0000")
+	require.NoError(t, err)
+
+	_, err = svc.LinkField(elsewhere, priority)
+	require.ErrorIs(t, err, services.ErrNotInWorkspace)
+	salesPriority, err := svc.LinkField(sales, priority)
+	require.NoError(t, err)
+	again, err := svc.LinkField(sales, priority)
+	require.NoError(t, err)
+	require.Equal(t, salesPriority.ID, again.ID)
+	supportPriority, err := svc.LinkField(support, priority)
+	require.NoError(t, err)
+	salesUrgent, err := svc.LinkLabel(sales, urgent)
+	require.NoError(t, err)
+
+	// Linked copies are edited through the workspace definition only.
+	_, err = fields.UpdateField(salesPriority, services.FieldUpdateInput{Name: ptr("Rank")})
+	require.ErrorIs(t, err, services.ErrSharedDefinition)
+
+	local := models.Label{ID: uuid.New(), BoardID: sales, Name: "Sales only", Color: "#00ff00"}
+	require.NoError(t, db.Create(&local).Error)
+	db.Exec(`INSERT INTO card_labels (card_id, label_id) VALUES (?, ?), (?, ?)`, cardA, salesUrgent.ID, cardA, local.ID)
+	for card, value := range map[uuid.UUID]string{cardA: "P1", cardB: "P2"} {
+		_, err := fields.SetCardValue(card, salesPriority.ID, value, owner)
+		require.NoError(t, err)
+	}
+	_, err = fields.SetCardValue(cardC, supportPriority.ID, "P1", owner)
+	require.NoError(t, err)
+
+	// Grouping by the shared field counts every linked board.
+	stats, err := svc.GroupCardsByWorkspaceField(priority)
+	require.NoError(t, err)
+	require.Equal(t, []services.FieldGroupStat{
+		{Key: "P1", Name: "P1", Count: 2},
+		{Key: "P2", Name: "P2", Count: 1},
+	}, stats)
+
+	// Moving within the workspace keeps the shared label and value and drops the board label.
+	_, err = repository.NewCardRepository(db).MoveCardWithOptions(cardA, supportCol, 2, repository.MoveCardOptions{})
+	require.NoError(t, err)
+	var labelIDs []uuid.UUID
+	db.Table("card_labels").Where("card_id = ?", cardA).Pluck("label_id", &labelIDs)
+	require.Len(t, labelIDs, 1)
+	var moved models.Label
+	require.NoError(t, db.First(&moved, "id = ?", labelIDs[0]).Error)
+	require.Equal(t, support, moved.BoardID)
+	require.Equal(t, urgent.ID, *moved.WorkspaceLabelID)
+	values, err := fields.GetCardValues(cardA)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, supportPriority.ID, values[0].CustomFieldID)
+	require.Equal(t, "P1", values[0].ValueText)
+
+	// Moving to another workspace clears shared labels too.
+	_, err = repository.NewCardRepository(db).MoveCardWithOptions(cardA, elsewhereCol, 1, repository.MoveCardOptions{})
+	require.NoError(t, err)
+	var count int64
+	db.Table("card_labels").Where("card_id = ?", cardA).Count(&count)
+	require.Zero(t, count)
+
+	// Workspace edits reach every linked copy and migrate their values.
+	result, err := svc.UpdateField(priority, services.FieldUpdateInput{
+		Name:          ptr("Severity"),
+		Options:       &[]string{"High", "Low"},
+		OptionMapping: map[string]string{"P1": "High", "P2": ""},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{sales, support}, result.BoardIDs)
+	require.Equal(t, 2, result.ValuesMigrated)
+	require.Equal(t, 1, result.ValuesCleared)
+	stored, err := fields.Repo.GetFieldByID(supportPriority.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Severity", stored.Name)
+	require.Equal(t, []string{"High", "Low"}, []string(stored.Options))
+
+	boards, err := svc.UpdateLabel(urgent, "", "#aa0000")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{sales, support}, boards)
+	var recolored models.Label
+	require.NoError(t, db.First(&recolored, "id = ?", salesUrgent.ID).Error)
+	require.Equal(t, "#aa0000", recolored.Color)
+}
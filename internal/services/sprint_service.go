@@ -0,0 +1,690 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSprintInvalidDates   = errors.New("sprint end date must be after its start date")
+	ErrSprintNotPlanned     = errors.New("only planned sprints can be started or deleted")
+	ErrSprintNotActive      = errors.New("only active sprints can be closed")
+	ErrSprintClosed         = errors.New("sprint is closed")
+	ErrSprintAlreadyActive  = errors.New("board already has an active sprint")
+	ErrSprintWrongBoard     = errors.New("card and sprint belong to different boards")
+	ErrInvalidCarryOver     = errors.New("invalid carry-over target")
+	ErrSprintDatesImmutable = errors.New("start date cannot change once the sprint has started")
+)
+
+// Carry-over targets for unfinished cards when a sprint closes.
+const (
+	CarryOverNextSprint = "next_sprint"
+	CarryOverBacklog    = "backlog"
+	CarryOverNone       = "none" // cards stay linked to the closed sprint
+)
+
+type SprintService struct {
+	DB                *gorm.DB
+	ActivityService   *ActivityService
+	AutomationService *AutomationService
+}
+
+func NewSprintService(db *gorm.DB, activityService *ActivityService, automationService *AutomationService) *SprintService {
+	return &SprintService{DB: db, ActivityService: activityService, AutomationService: automationService}
+}
+
+// CanAccessBoard reports whether the user owns or is an accepted member of the board's workspace.
+func (s *SprintService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+// GetSprintForUser loads a sprint the user can access.
+func (s *SprintService) GetSprintForUser(sprintID, userID uuid.UUID) (*models.Sprint, error) {
+	var sprint models.Sprint
+	if err := s.DB.First(&sprint, "id = ?", sprintID).Error; err != nil {
+		return nil, err
+	}
+	if !s.CanAccessBoard(userID, sprint.BoardID) {
+		return nil, ErrBoardAccessDenied
+	}
+	return &sprint, nil
+}
+
+func (s *SprintService) ListSprints(boardID uuid.UUID) ([]models.Sprint, error) {
+	var sprints []models.Sprint
+	err := s.DB.Where("board_id = ?", boardID).Order("start_date ASC, created_at ASC").Find(&sprints).Error
+	return sprints, err
+}
+
+// GetSprintWithCards returns the sprint with its currently assigned cards.
+func (s *SprintService) GetSprintWithCards(sprintID uuid.UUID) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := s.DB.Preload("Cards", func(db *gorm.DB) *gorm.DB {
+		return db.Where("is_archived = ?", false).Order("position ASC").Preload("Labels").Preload("Members")
+	}).First(&sprint, "id = ?", sprintID).Error
+	return &sprint, err
+}
+
+func (s *SprintService) CreateSprint(boardID, actorID uuid.UUID, name, goal string, start, end time.Time) (*models.Sprint, error) {
+	if !end.After(start) {
+		return nil, ErrSprintInvalidDates
+	}
+	sprint := models.Sprint{
+		BoardID:   boardID,
+		Name:      name,
+		Goal:      goal,
+		StartDate: start,
+		EndDate:   end,
+		State:     models.SprintPlanned,
+	}
+	if err := s.DB.Create(&sprint).Error; err != nil {
+		return nil, err
+	}
+	s.ActivityService.LogActivity(actorID, boardID, "created_sprint", sprint.ID, map[string]interface{}{
+		"sprint_name": name,
+	})
+	return &sprint, nil
+}
+
+// UpdateSprint edits name, goal and dates. Nil arguments are left unchanged.
+func (s *SprintService) UpdateSprint(sprint *models.Sprint, name, goal *string, start, end *time.Time) error {
+	if sprint.State == models.SprintClosed {
+		return ErrSprintClosed
+	}
+	newStart, newEnd := sprint.StartDate, sprint.EndDate
+	if start != nil {
+		if sprint.State != models.SprintPlanned && !start.Equal(sprint.StartDate) {
+			return ErrSprintDatesImmutable
+		}
+		newStart = *start
+	}
+	if end != nil {
+		newEnd = *end
+	}
+	if !newEnd.After(newStart) {
+		return ErrSprintInvalidDates
+	}
+
+	updates := map[string]interface{}{"start_date": newStart, "end_date": newEnd}
+	if name != nil && *name != "" {
+		updates["name"] = *name
+	}
+	if goal != nil {
+		updates["goal"] = *goal
+	}
+	if err := s.DB.Model(sprint).Updates(updates).Error; err != nil {
+		return err
+	}
+	return s.DB.First(sprint, "id = ?", sprint.ID).Error
+}
+
+// DeleteSprint removes a planned sprint and unassigns its cards.
+func (s *SprintService) DeleteSprint(sprint *models.Sprint) error {
+	if sprint.State != models.SprintPlanned {
+		return ErrSprintNotPlanned
+	}
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Card{}).Where("sprint_id = ?", sprint.ID).Update("sprint_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(sprint).Error
+	})
+}
+
+// StartSprint activates a planned sprint and snapshots its cards as the committed scope.
+func (s *SprintService) StartSprint(sprint *models.Sprint, actorID uuid.UUID) error {
+	if sprint.State != models.SprintPlanned {
+		return ErrSprintNotPlanned
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		tx.Model(&models.Sprint{}).Where("board_id = ? AND state = ?", sprint.BoardID, models.SprintActive).Count(&active)
+		if active > 0 {
+			return ErrSprintAlreadyActive
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.Sprint{}).
+			Where("id = ? AND state = ?", sprint.ID, models.SprintPlanned).
+			Updates(map[string]interface{}{"state": models.SprintActive, "started_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSprintNotPlanned
+		}
+
+		var cards []models.Card
+		if err := tx.Where("sprint_id = ? AND is_archived = ?", sprint.ID, false).Find(&cards).Error; err != nil {
+			return err
+		}
+		for _, card := range cards {
+			if err := recordSprintChange(tx, sprint.ID, card.ID, &actorID, models.SprintChangeCommitted, card.StoryPoints, nil, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.ActivityService.LogActivity(actorID, sprint.BoardID, "started_sprint", sprint.ID, map[string]interface{}{
+		"sprint_name": sprint.Name,
+	})
+	return s.DB.First(sprint, "id = ?", sprint.ID).Error
+}
+
+// AssignCard moves a card into a sprint, or out of any sprint when sprintID is nil.
+// Changes to an active sprint are written to its scope log.
+func (s *SprintService) AssignCard(cardID uuid.UUID, sprintID *uuid.UUID, actorID uuid.UUID) (*models.Card, error) {
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	if !s.CanAccessBoard(actorID, card.Column.BoardID) {
+		return nil, ErrBoardAccessDenied
+	}
+
+	var target *models.Sprint
+	if sprintID != nil {
+		target = &models.Sprint{}
+		if err := s.DB.First(target, "id = ?", *sprintID).Error; err != nil {
+			return nil, err
+		}
+		if target.BoardID != card.Column.BoardID {
+			return nil, ErrSprintWrongBoard
+		}
+		if target.State == models.SprintClosed {
+			return nil, ErrSprintClosed
+		}
+	}
+	if sameSprint(card.SprintID, sprintID) {
+		return &card, nil
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if card.SprintID != nil {
+			if err := recordIfSprintActive(tx, *card.SprintID, card.ID, &actorID, models.SprintChangeRemoved, card.StoryPoints, nil, ""); err != nil {
+				return err
+			}
+		}
+		if target != nil && target.State == models.SprintActive {
+			if err := recordSprintChange(tx, target.ID, card.ID, &actorID, models.SprintChangeAdded, card.StoryPoints, nil, ""); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Card{}).Where("id = ?", card.ID).Updates(map[string]interface{}{
+			"sprint_id": sprintID,
+			"version":   gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{"card_title": card.Title, "sprint_id": sprintID}
+	if target != nil {
+		metadata["sprint_name"] = target.Name
+	}
+	s.ActivityService.LogActivity(actorID, card.Column.BoardID, "changed_card_sprint", card.ID, metadata)
+
+	card.SprintID = sprintID
+	card.Version++
+	return &card, nil
+}
+
+// CloseSprintOptions says what happens to cards that are not complete at close-out.
+type CloseSprintOptions struct {
+	CarryOver       string // CarryOverNextSprint, CarryOverBacklog or CarryOverNone
+	NextSprintID    *uuid.UUID
+	BacklogColumnID *uuid.UUID
+}
+
+type CloseSprintResult struct {
+	Sprint           *models.Sprint `json:"sprint"`
+	CarriedOver      []uuid.UUID    `json:"carried_over"`
+	CarryOver        string         `json:"carry_over"`
+	NextSprintID     *uuid.UUID     `json:"next_sprint_id,omitempty"`
+	BacklogColumnID  *uuid.UUID     `json:"backlog_column_id,omitempty"`
+	CompletedCardIDs []uuid.UUID    `json:"completed_card_ids"`
+}
+
+// CloseSprint closes an active sprint and carries unfinished cards over as requested.
+func (s *SprintService) CloseSprint(sprint *models.Sprint, actorID uuid.UUID, opts CloseSprintOptions) (*CloseSprintResult, error) {
+	if sprint.State != models.SprintActive {
+		return nil, ErrSprintNotActive
+	}
+	if opts.CarryOver == "" {
+		opts.CarryOver = CarryOverNone
+	}
+
+	var nextSprint models.Sprint
+	var backlog models.Column
+	switch opts.CarryOver {
+	case CarryOverNextSprint:
+		if opts.NextSprintID == nil || *opts.NextSprintID == sprint.ID {
+			return nil, fmt.Errorf("%w: next_sprint_id is required", ErrInvalidCarryOver)
+		}
+		if err := s.DB.First(&nextSprint, "id = ? AND board_id = ?", *opts.NextSprintID, sprint.BoardID).Error; err != nil {
+			return nil, fmt.Errorf("%w: next sprint not found on this board", ErrInvalidCarryOver)
+		}
+		if nextSprint.State == models.SprintClosed {
+			return nil, fmt.Errorf("%w: next sprint is closed", ErrInvalidCarryOver)
+		}
+	case CarryOverBacklog:
+		if opts.BacklogColumnID == nil {
+			return nil, fmt.Errorf("%w: backlog_column_id is required", ErrInvalidCarryOver)
+		}
+		if err := s.DB.First(&backlog, "id = ? AND board_id = ?", *opts.BacklogColumnID, sprint.BoardID).Error; err != nil {
+			return nil, fmt.Errorf("%w: backlog column not found on this board", ErrInvalidCarryOver)
+		}
+	case CarryOverNone:
+	default:
+		return nil, fmt.Errorf("%w: carry_over must be next_sprint, backlog or none", ErrInvalidCarryOver)
+	}
+
+	result := &CloseSprintResult{CarryOver: opts.CarryOver, CarriedOver: []uuid.UUID{}, CompletedCardIDs: []uuid.UUID{}}
+	var moved []models.Card
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		update := tx.Model(&models.Sprint{}).
+			Where("id = ? AND state = ?", sprint.ID, models.SprintActive).
+			Updates(map[string]interface{}{"state": models.SprintClosed, "closed_at": now})
+		if update.Error != nil {
+			return update.Error
+		}
+		if update.RowsAffected == 0 {
+			return ErrSprintNotActive
+		}
+
+		var cards []models.Card
+		if err := tx.Where("sprint_id = ? AND is_archived = ?", sprint.ID, false).Order("position ASC").Find(&cards).Error; err != nil {
+			return err
+		}
+
+		repo := repository.NewCardRepository(tx)
+		for _, card := range cards {
+			if card.IsComplete {
+				result.CompletedCardIDs = append(result.CompletedCardIDs, card.ID)
+				continue
+			}
+			if opts.CarryOver == CarryOverNone {
+				continue
+			}
+
+			detail := opts.CarryOver
+			updates := map[string]interface{}{"version": gorm.Expr("version + 1")}
+			switch opts.CarryOver {
+			case CarryOverNextSprint:
+				detail = "sprint:" + nextSprint.ID.String()
+				updates["sprint_id"] = nextSprint.ID
+				if nextSprint.State == models.SprintActive {
+					if err := recordSprintChange(tx, nextSprint.ID, card.ID, &actorID, models.SprintChangeAdded, card.StoryPoints, nil, "carried over from "+sprint.Name); err != nil {
+						return err
+					}
+				}
+			case CarryOverBacklog:
+				detail = "column:" + backlog.ID.String()
+				updates["sprint_id"] = nil
+				if card.ColumnID != backlog.ID {
+					pos := repo.GetMaxPosition(backlog.ID)
+					if pos == 0 {
+						pos = repository.PositionGap
+					}
+					if _, err := repo.MoveCardWithOptions(card.ID, backlog.ID, pos, repository.MoveCardOptions{}); err != nil {
+						return err
+					}
+					moved = append(moved, card)
+				}
+			}
+			if err := tx.Model(&models.Card{}).Where("id = ?", card.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+			if err := recordSprintChange(tx, sprint.ID, card.ID, &actorID, models.SprintChangeCarriedOver, card.StoryPoints, nil, detail); err != nil {
+				return err
+			}
+			result.CarriedOver = append(result.CarriedOver, card.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CarryOver == CarryOverNextSprint {
+		result.NextSprintID = &nextSprint.ID
+	}
+	if opts.CarryOver == CarryOverBacklog {
+		result.BacklogColumnID = &backlog.ID
+	}
+
+	if s.AutomationService != nil {
+		for _, card := range moved {
+			s.AutomationService.EvaluateRules(sprint.BoardID, models.TriggerCardMoved, map[string]interface{}{
+				"card_id":      card.ID.String(),
+				"to_column_id": backlog.ID.String(),
+			})
+		}
+	}
+
+	s.ActivityService.LogActivity(actorID, sprint.BoardID, "closed_sprint", sprint.ID, map[string]interface{}{
+		"sprint_name":  sprint.Name,
+		"carry_over":   opts.CarryOver,
+		"carried_over": len(result.CarriedOver),
+		"completed":    len(result.CompletedCardIDs),
+	})
+
+	if err := s.DB.First(sprint, "id = ?", sprint.ID).Error; err != nil {
+		return nil, err
+	}
+	result.Sprint = sprint
+	return result, nil
+}
+
+func (s *SprintService) ScopeChanges(sprintID uuid.UUID) ([]models.SprintScopeChange, error) {
+	var changes []models.SprintScopeChange
+	err := s.DB.Where("sprint_id = ?", sprintID).Order("created_at ASC").Find(&changes).Error
+	return changes, err
+}
+
+// SprintTally counts cards and story points; cards without points count as zero points.
+type SprintTally struct {
+	Cards  int     `json:"cards"`
+	Points float64 `json:"points"`
+}
+
+func (t *SprintTally) add(points float64) {
+	t.Cards++
+	t.Points += points
+}
+
+type SprintReport struct {
+	SprintID       uuid.UUID                  `json:"sprint_id"`
+	State          models.SprintState         `json:"state"`
+	Committed      SprintTally                `json:"committed"`
+	Added          SprintTally                `json:"added"`
+	Removed        SprintTally                `json:"removed"`
+	Scope          SprintTally                `json:"scope"` // committed + added - removed, at current points
+	Completed      SprintTally                `json:"completed"`
+	CommittedDone  SprintTally                `json:"committed_completed"` // committed cards that were completed
+	CarriedOver    SprintTally                `json:"carried_over"`
+	CompletionRate float64                    `json:"completion_rate"` // completed / scope points (cards when no points)
+	ScopeChanges   []models.SprintScopeChange `json:"scope_changes"`
+}
+
+type BurndownPoint struct {
+	Date            string  `json:"date"`
+	ScopePoints     float64 `json:"scope_points"`
+	RemainingPoints float64 `json:"remaining_points"`
+	RemainingCards  int     `json:"remaining_cards"`
+	IdealPoints     float64 `json:"ideal_points"`
+}
+
+// sprintLedger replays a sprint's scope log into per-card state.
+type sprintLedger struct {
+	sprint    *models.Sprint
+	changes   []models.SprintScopeChange
+	cards     map[uuid.UUID]*models.Card
+	committed map[uuid.UUID]bool
+}
+
+func (s *SprintService) loadLedger(sprint *models.Sprint) (*sprintLedger, error) {
+	ledger := &sprintLedger{sprint: sprint, cards: map[uuid.UUID]*models.Card{}, committed: map[uuid.UUID]bool{}}
+	changes, err := s.ScopeChanges(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	ledger.changes = changes
+
+	ids := map[uuid.UUID]struct{}{}
+	for _, change := range changes {
+		ids[change.CardID] = struct{}{}
+		if change.Type == models.SprintChangeCommitted {
+			ledger.committed[change.CardID] = true
+		}
+	}
+
+	// A sprint that never started has no log; its scope is the cards assigned now.
+	var cards []models.Card
+	q := s.DB.Where("sprint_id = ? AND is_archived = ?", sprint.ID, false)
+	if len(ids) > 0 {
+		list := make([]uuid.UUID, 0, len(ids))
+		for id := range ids {
+			list = append(list, id)
+		}
+		q = s.DB.Where("id IN ?", list)
+	}
+	if err := q.Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	for i := range cards {
+		ledger.cards[cards[i].ID] = &cards[i]
+	}
+	if len(changes) == 0 {
+		for id := range ledger.cards {
+			ledger.committed[id] = true
+		}
+	}
+	return ledger, nil
+}
+
+// stateAt replays the log up to t and returns which cards are in scope with their points.
+func (l *sprintLedger) stateAt(t time.Time) map[uuid.UUID]float64 {
+	scope := map[uuid.UUID]float64{}
+	if len(l.changes) == 0 {
+		for id, card := range l.cards {
+			scope[id] = pointsOf(card.StoryPoints)
+		}
+		return scope
+	}
+	for _, change := range l.changes {
+		if change.CreatedAt.After(t) {
+			break
+		}
+		switch change.Type {
+		case models.SprintChangeCommitted, models.SprintChangeAdded:
+			scope[change.CardID] = pointsOf(change.Points)
+		case models.SprintChangeRemoved:
+			delete(scope, change.CardID)
+		case models.SprintChangeEstimate:
+			if _, ok := scope[change.CardID]; ok {
+				scope[change.CardID] = pointsOf(change.Points)
+			}
+		}
+	}
+	return scope
+}
+
+// completedBy reports whether the card was complete at t. A card still open today,
+// or completed after the sprint window, does not count. Cards completed before
+// CompletedAt was tracked count from their last update.
+func (l *sprintLedger) completedBy(cardID uuid.UUID, t time.Time) bool {
+	card, ok := l.cards[cardID]
+	if !ok || !card.IsComplete {
+		return false
+	}
+	completedAt := card.UpdatedAt
+	if card.CompletedAt != nil {
+		completedAt = *card.CompletedAt
+	}
+	return !completedAt.After(t)
+}
+
+// BackfillCompletedAt stamps complete cards that predate CompletedAt with their last update.
+func BackfillCompletedAt(db *gorm.DB) error {
+	return db.Model(&models.Card{}).
+		Where("is_complete = ? AND completed_at IS NULL", true).
+		UpdateColumn("completed_at", gorm.Expr("updated_at")).Error
+}
+
+// windowEnd is when the sprint stopped counting work: close time, or now while active.
+func (l *sprintLedger) windowEnd() time.Time {
+	if l.sprint.ClosedAt != nil {
+		return *l.sprint.ClosedAt
+	}
+	return time.Now()
+}
+
+func (s *SprintService) Report(sprint *models.Sprint) (*SprintReport, error) {
+	ledger, err := s.loadLedger(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SprintReport{SprintID: sprint.ID, State: sprint.State, ScopeChanges: ledger.changes}
+	if report.ScopeChanges == nil {
+		report.ScopeChanges = []models.SprintScopeChange{}
+	}
+	for _, change := range ledger.changes {
+		switch change.Type {
+		case models.SprintChangeCommitted:
+			report.Committed.add(pointsOf(change.Points))
+		case models.SprintChangeAdded:
+			report.Added.add(pointsOf(change.Points))
+		case models.SprintChangeRemoved:
+			report.Removed.add(pointsOf(change.Points))
+		case models.SprintChangeCarriedOver:
+			report.CarriedOver.add(pointsOf(change.Points))
+		}
+	}
+
+	end := ledger.windowEnd()
+	scope := ledger.stateAt(end)
+	if len(ledger.changes) == 0 {
+		for _, points := range scope {
+			report.Committed.add(points)
+		}
+	}
+	for cardID, points := range scope {
+		report.Scope.add(points)
+		if ledger.completedBy(cardID, end) {
+			report.Completed.add(points)
+			if ledger.committed[cardID] {
+				report.CommittedDone.add(points)
+			}
+		}
+	}
+
+	switch {
+	case report.Scope.Points > 0:
+		report.CompletionRate = report.Completed.Points / report.Scope.Points
+	case report.Scope.Cards > 0:
+		report.CompletionRate = float64(report.Completed.Cards) / float64(report.Scope.Cards)
+	}
+	return report, nil
+}
+
+// Burndown returns one point per day from the sprint start to its end (or today, if earlier).
+// Days are UTC calendar days; each point reflects the state at the end of that day.
+func (s *SprintService) Burndown(sprint *models.Sprint) ([]BurndownPoint, error) {
+	ledger, err := s.loadLedger(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	start := truncateToDay(sprint.StartDate)
+	last := truncateToDay(sprint.EndDate)
+	if end := truncateToDay(ledger.windowEnd()); end.Before(last) {
+		last = end
+	}
+	totalDays := truncateToDay(sprint.EndDate).Sub(start).Hours() / 24
+
+	committed := ledger.stateAt(ledger.firstChangeAt())
+	var committedPoints float64
+	for _, p := range committed {
+		committedPoints += p
+	}
+
+	points := []BurndownPoint{}
+	for day := start; !day.After(last); day = day.AddDate(0, 0, 1) {
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if ledger.sprint.ClosedAt != nil && endOfDay.After(*ledger.sprint.ClosedAt) {
+			endOfDay = *ledger.sprint.ClosedAt
+		}
+
+		scope := ledger.stateAt(endOfDay)
+		bp := BurndownPoint{Date: day.Format(TimeLogDateLayout)}
+		for cardID, p := range scope {
+			bp.ScopePoints += p
+			if !ledger.completedBy(cardID, endOfDay) {
+				bp.RemainingPoints += p
+				bp.RemainingCards++
+			}
+		}
+		if totalDays > 0 {
+			elapsed := day.Sub(start).Hours() / 24
+			bp.IdealPoints = committedPoints * (1 - elapsed/totalDays)
+			if bp.IdealPoints < 0 {
+				bp.IdealPoints = 0
+			}
+		}
+		points = append(points, bp)
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+	return points, nil
+}
+
+// firstChangeAt is the end of the commit snapshot, or now for sprints without a log.
+func (l *sprintLedger) firstChangeAt() time.Time {
+	if len(l.changes) == 0 {
+		return time.Now()
+	}
+	first := l.changes[0].CreatedAt
+	for _, change := range l.changes {
+		if change.Type != models.SprintChangeCommitted {
+			break
+		}
+		first = change.CreatedAt
+	}
+	return first
+}
+
+func recordSprintChange(tx *gorm.DB, sprintID, cardID uuid.UUID, actorID *uuid.UUID, changeType models.SprintChangeType, points, previous *float64, detail string) error {
+	return tx.Create(&models.SprintScopeChange{
+		SprintID:       sprintID,
+		CardID:         cardID,
+		ActorID:        actorID,
+		Type:           changeType,
+		Points:         points,
+		PreviousPoints: previous,
+		Detail:         detail,
+	}).Error
+}
+
+// recordIfSprintActive logs a scope change only while the sprint is running.
+func recordIfSprintActive(tx *gorm.DB, sprintID, cardID uuid.UUID, actorID *uuid.UUID, changeType models.SprintChangeType, points, previous *float64, detail string) error {
+	var count int64
+	if err := tx.Model(&models.Sprint{}).Where("id = ? AND state = ?", sprintID, models.SprintActive).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+	return recordSprintChange(tx, sprintID, cardID, actorID, changeType, points, previous, detail)
+}
+
+func pointsOf(p *float64) float64 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func sameSprint(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
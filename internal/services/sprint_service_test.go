@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupSprintTestDB(t *testing.T) *gorm.DB {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE sprints (
+		id TEXT PRIMARY KEY,
+		board_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		goal TEXT,
+		start_date DATETIME NOT NULL,
+		end_date DATETIME NOT NULL,
+		state TEXT NOT NULL DEFAULT 'planned',
+		started_at DATETIME,
+		closed_at DATETIME,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE sprint_scope_changes (
+		id TEXT PRIMARY KEY,
+		sprint_id TEXT NOT NULL,
+		card_id TEXT NOT NULL,
+		actor_id TEXT,
+		type TEXT NOT NULL,
+		points REAL,
+		previous_points REAL,
+		detail TEXT,
+		created_at DATETIME
+	)`).Error)
+	return db
+}
+
+func TestSprint_ScopeTrackingAndCloseToBacklog(t *testing.T) {
+	db := setupSprintTestDB(t)
+
+	owner := uuid.New()
+	wsID, boardID, backlogID, doingID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Board')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Backlog', 1)`, backlogID, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 2)`, doingID, boardID)
+
+	three, five, two := 3.0, 5.0, 2.0
+	doneID, openID, lateID := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: doneID, Title: "Done", ColumnID: doingID, Position: 1, StoryPoints: &three}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: openID, Title: "Open", ColumnID: doingID, Position: 2, StoryPoints: &five}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: lateID, Title: "Late", ColumnID: doingID, Position: 3, StoryPoints: &two}).Error)
+
+	activity := services.NewActivityService(db)
+	svc := services.NewSprintService(db, activity, nil)
+
+	start := time.Now().Add(-48 * time.Hour)
+	sprint, err := svc.CreateSprint(boardID, owner, "Sprint 1", "Ship it", start, start.Add(14*24*time.Hour))
+	require.NoError(t, err)
+	_, err = svc.CreateSprint(boardID, owner, "Backwards", "", start, start.Add(-time.Hour))
+	require.ErrorIs(t, err, services.ErrSprintInvalidDates)
+
+	for _, id := range []uuid.UUID{doneID, openID} {
+		_, err = svc.AssignCard(id, &sprint.ID, owner)
+		require.NoError(t, err)
+	}
+	require.NoError(t, svc.StartSprint(sprint, owner))
+	require.Equal(t, models.SprintActive, sprint.State)
+
+	second, err := svc.CreateSprint(boardID, owner, "Sprint 2", "", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.ErrorIs(t, svc.StartSprint(second, owner), services.ErrSprintAlreadyActive)
+
+	// Mid-sprint: one card joins, an estimate changes, one card is finished.
+	_, err = svc.AssignCard(lateID, &sprint.ID, owner)
+	require.NoError(t, err)
+	eight := 8.0
+	_, err = services.NewTimeTrackingService(db, activity).SetEstimate(openID, owner, nil, &eight)
+	require.NoError(t, err)
+	// Completed without a completion time, as cards were before it was tracked.
+	require.NoError(t, db.Model(&models.Card{}).Where("id = ?", doneID).
+		Updates(map[string]interface{}{"is_complete": true}).Error)
+
+	result, err := svc.CloseSprint(sprint, owner, services.CloseSprintOptions{
+		CarryOver:       services.CarryOverBacklog,
+		BacklogColumnID: &backlogID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.SprintClosed, sprint.State)
+	require.ElementsMatch(t, []uuid.UUID{openID, lateID}, result.CarriedOver)
+	require.Equal(t, []uuid.UUID{doneID}, result.CompletedCardIDs)
+
+	var moved models.Card
+	require.NoError(t, db.First(&moved, "id = ?", openID).Error)
+	require.Equal(t, backlogID, moved.ColumnID)
+	require.Nil(t, moved.SprintID)
+
+	report, err := svc.Report(sprint)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Committed.Cards)
+	require.Equal(t, 8.0, report.Committed.Points)
+	require.Equal(t, 1, report.Added.Cards)
+	require.Equal(t, 13.0, report.Scope.Points) // 3 + 8 (re-estimated) + 2
+	require.Equal(t, 3.0, report.Completed.Points)
+	require.Equal(t, 3.0, report.CommittedDone.Points)
+	require.Equal(t, 2, report.CarriedOver.Cards)
+
+	burndown, err := svc.Burndown(sprint)
+	require.NoError(t, err)
+	require.Len(t, burndown, 3) // two days ago through today
+	today := burndown[len(burndown)-1]
+	require.Equal(t, 13.0, today.ScopePoints)
+	require.Equal(t, 10.0, today.RemainingPoints)
+	require.Equal(t, 2, today.RemainingCards)
+
+	require.NoError(t, services.BackfillCompletedAt(db))
+	var done models.Card
+	require.NoError(t, db.First(&done, "id = ?", doneID).Error)
+	require.NotNil(t, done.CompletedAt)
+}
+
+func TestSprint_CrossBoardMoveLeavesSprint(t *testing.T) {
+	db := setupSprintTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (id TEXT PRIMARY KEY, card_id TEXT, custom_field_id TEXT, value TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner := uuid.New()
+	wsID, boardID, otherBoardID, doingID, otherColID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Board')`, boardID, wsID)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Other')`, otherBoardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, doingID, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Todo', 1)`, otherColID, otherBoardID)
+
+	three, five := 3.0, 5.0
+	stayID, leaveID := uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: stayID, Title: "Stay", ColumnID: doingID, Position: 1, StoryPoints: &three}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: leaveID, Title: "Leave", ColumnID: doingID, Position: 2, StoryPoints: &five}).Error)
+
+	svc := services.NewSprintService(db, services.NewActivityService(db), nil)
+	start := time.Now().Add(-24 * time.Hour)
+	sprint, err := svc.CreateSprint(boardID, owner, "Sprint 1", "", start, start.Add(14*24*time.Hour))
+	require.NoError(t, err)
+	for _, id := range []uuid.UUID{stayID, leaveID} {
+		_, err = svc.AssignCard(id, &sprint.ID, owner)
+		require.NoError(t, err)
+	}
+	require.NoError(t, svc.StartSprint(sprint, owner))
+
+	_, err = repository.NewCardRepository(db).MoveCardWithOptions(leaveID, otherColID, 1, repository.MoveCardOptions{})
+	require.NoError(t, err)
+
+	var moved models.Card
+	require.NoError(t, db.First(&moved, "id = ?", leaveID).Error)
+	require.Equal(t, otherColID, moved.ColumnID)
+	require.Nil(t, moved.SprintID)
+
+	report, err := svc.Report(sprint)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Committed.Cards)
+	require.Equal(t, 1, report.Removed.Cards)
+	require.Equal(t, 5.0, report.Removed.Points)
+	require.Equal(t, 3.0, report.Scope.Points)
+
+	burndown, err := svc.Burndown(sprint)
+	require.NoError(t, err)
+	today := burndown[len(burndown)-1]
+	require.Equal(t, 3.0, today.ScopePoints)
+	require.Equal(t, 1, today.RemainingCards)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// staleDigestMaxTitles limits how many card titles are spelled out in one digest.
+const staleDigestMaxTitles = 5
+
+// StaleCardService finds cards that have gone quiet longer than their board allows,
+// sends each assignee one digest per board and fires the CARD_STALE automation trigger
+// once per quiet spell: new activity on the card re-arms it.
+type StaleCardService struct {
+	DB                  *gorm.DB
+	NotificationService *NotificationService
+	AutomationService   *AutomationService
+}
+
+type StaleCardRunStats struct {
+	BoardsScanned int `json:"boards_scanned"`
+	StaleCards    int `json:"stale_cards"`
+	DigestsSent   int `json:"digests_sent"`
+}
+
+func NewStaleCardService(db *gorm.DB, notificationService *NotificationService, automationService *AutomationService) *StaleCardService {
+	return &StaleCardService{
+		DB:                  db,
+		NotificationService: notificationService,
+		AutomationService:   automationService,
+	}
+}
+
+func (s *StaleCardService) Start(ctx context.Context, interval time.Duration) {
+	s.backfillLastActivity()
+	s.RunOnce()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[StaleCards] Stopped")
+			return
+		case <-ticker.C:
+			s.RunOnce()
+		}
+	}
+}
+
+// backfillLastActivity seeds last_activity_at for cards that predate activity tracking.
+func (s *StaleCardService) backfillLastActivity() {
+	err := s.DB.Exec(`
+		UPDATE cards SET last_activity_at = COALESCE(
+			(SELECT MAX(a.created_at) FROM activities a WHERE a.target_id = cards.id),
+			cards.updated_at
+		)
+		WHERE last_activity_at IS NULL`).Error
+	if err != nil {
+		log.Printf("[StaleCards] backfill failed: %v", err)
+	}
+}
+
+func (s *StaleCardService) RunOnce() StaleCardRunStats {
+	stats := StaleCardRunStats{}
+	now := time.Now()
+
+	var boards []models.Board
+	if err := s.DB.Select("id", "title", "stale_after_days").
+		Where("stale_after_days > 0").
+		Find(&boards).Error; err != nil {
+		log.Printf("[StaleCards] board query failed: %v", err)
+		return stats
+	}
+	stats.BoardsScanned = len(boards)
+
+	for _, board := range boards {
+		cutoff := now.Add(-time.Duration(board.StaleAfterDays) * 24 * time.Hour)
+
+		var cards []models.Card
+		if err := s.DB.Preload("Members").
+			Where("column_id IN (SELECT id FROM columns WHERE board_id = ?)", board.ID).
+			Where("is_archived = ? AND is_template = ? AND is_complete = ?", false, false, false).
+			Where("COALESCE(last_activity_at, created_at) <= ?", cutoff).
+			Order("last_activity_at ASC").
+			Find(&cards).Error; err != nil {
+			log.Printf("[StaleCards] card query failed for board %s: %v", board.ID, err)
+			continue
+		}
+		stats.StaleCards += len(cards)
+
+		byAssignee := map[uuid.UUID][]models.Card{}
+		for _, card := range cards {
+			for _, member := range card.Members {
+				byAssignee[member.ID] = append(byAssignee[member.ID], card)
+			}
+			if card.StaleNotifiedAt == nil {
+				s.fireStaleTrigger(board, card, now)
+			}
+		}
+
+		for userID, userCards := range byAssignee {
+			if s.sendDigest(userID, board, userCards, now) {
+				stats.DigestsSent++
+			}
+		}
+	}
+
+	if stats.DigestsSent > 0 {
+		log.Printf("[StaleCards] Sent %d stale-card digest(s)", stats.DigestsSent)
+	}
+	return stats
+}
+
+func (s *StaleCardService) fireStaleTrigger(board models.Board, card models.Card, now time.Time) {
+	if s.AutomationService == nil {
+		return
+	}
+	if err := s.DB.Model(&models.Card{}).Where("id = ?", card.ID).
+		UpdateColumn("stale_notified_at", now).Error; err != nil {
+		log.Printf("[StaleCards] failed to mark card %s: %v", card.ID, err)
+		return
+	}
+	last := card.CreatedAt
+	if card.LastActivityAt != nil {
+		last = *card.LastActivityAt
+	}
+	ctx := map[string]interface{}{
+		"card_id":       card.ID.String(),
+		"column_id":     card.ColumnID.String(),
+		"days_inactive": int(now.Sub(last).Hours() / 24),
+	}
+	s.AutomationService.EvaluateRules(board.ID, models.TriggerCardStale, ctx)
+}
+
+// sendDigest notifies one assignee about their stale cards on a board, at most once a day.
+func (s *StaleCardService) sendDigest(userID uuid.UUID, board models.Board, cards []models.Card, now time.Time) bool {
+	if s.NotificationService == nil || s.digestRecentlySent(userID, board.ID, now) {
+		return false
+	}
+
+	titles := make([]string, 0, staleDigestMaxTitles)
+	for i, card := range cards {
+		if i == staleDigestMaxTitles {
+			break
+		}
+		titles = append(titles, "'"+card.Title+"'")
+	}
+	message := fmt.Sprintf("%d card(s) assigned to you on '%s' have had no activity for %d days: %s",
+		len(cards), board.Title, board.StaleAfterDays, strings.Join(titles, ", "))
+	if extra := len(cards) - len(titles); extra > 0 {
+		message += fmt.Sprintf(" and %d more", extra)
+	}
+
+	_, err := s.NotificationService.CreateNotification(
+		userID,
+		userID, // system digest with self as actor fallback
+		models.NotificationStaleCards,
+		"Stale Cards",
+		message,
+		board.ID,
+		"BOARD",
+		PrefNotifyStaleCards,
+	)
+	if err != nil {
+		log.Printf("[StaleCards] failed to notify user %s for board %s: %v", userID, board.ID, err)
+		return false
+	}
+	return true
+}
+
+func (s *StaleCardService) digestRecentlySent(userID, boardID uuid.UUID, now time.Time) bool {
+	var count int64
+	s.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND type = ? AND entity_id = ? AND created_at >= ?",
+			userID,
+			models.NotificationStaleCards,
+			boardID,
+			now.Add(-20*time.Hour), // a little under a day so a daily run never skips
+		).
+		Count(&count)
+	return count > 0
+}
@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// ruleLookups is an automation repository without rules that counts evaluations per board.
+type ruleLookups map[uuid.UUID]int
+
+func (r ruleLookups) CreateRule(*models.AutomationRule) error { return nil }
+func (r ruleLookups) GetRulesByBoard(boardID uuid.UUID) ([]models.AutomationRule, error) {
+	r[boardID]++
+	return nil, nil
+}
+func (r ruleLookups) DeleteRule(uuid.UUID) error                            { return nil }
+func (r ruleLookups) GetRuleByID(uuid.UUID) (*models.AutomationRule, error) { return nil, nil }
+func (r ruleLookups) ToggleRule(uuid.UUID) error                            { return nil }
+
+func TestStaleCardService_DigestsAssigneesOncePerDay(t *testing.T) {
+	db := SetupTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE boards (id TEXT PRIMARY KEY, workspace_id TEXT, title TEXT, stale_after_days INTEGER DEFAULT 0, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE activities (id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		user_id TEXT NOT NULL, board_id TEXT NOT NULL, action TEXT NOT NULL, target_id TEXT, metadata TEXT, created_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE notifications (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		actor_id TEXT,
+		type TEXT NOT NULL,
+		title TEXT NOT NULL,
+		message TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		entity_type TEXT NOT NULL,
+		board_id TEXT,
+		is_read INTEGER DEFAULT 0,
+		created_at DATETIME
+	)`).Error)
+
+	boardID, colID, assignee := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO boards (id, title, stale_after_days) VALUES (?, 'Ops', 3)`, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, boardID)
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Sam', 'sam@example.com')`, assignee)
+
+	old := time.Now().Add(-5 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	staleID, freshID, doneID := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: staleID, Title: "Forgotten", ColumnID: colID, Position: 1, LastActivityAt: &old}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: freshID, Title: "Active", ColumnID: colID, Position: 2, LastActivityAt: &recent}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: doneID, Title: "Shipped", ColumnID: colID, Position: 3, LastActivityAt: &old, IsComplete: true}).Error)
+	for _, id := range []uuid.UUID{staleID, freshID, doneID} {
+		db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, id, assignee)
+	}
+
+	lookups := ruleLookups{}
+	automation := services.NewAutomationService(lookups)
+	automation.SetExecutor(services.NewCardService(repository.NewCardRepository(db), nil))
+	svc := services.NewStaleCardService(db, services.NewNotificationService(db, nil, nil), automation)
+
+	stats := svc.RunOnce()
+	require.Equal(t, 1, stats.BoardsScanned)
+	require.Equal(t, 1, stats.StaleCards)
+	require.Equal(t, 1, stats.DigestsSent)
+
+	var digest models.Notification
+	require.NoError(t, db.First(&digest, "user_id = ? AND type = ?", assignee, models.NotificationStaleCards).Error)
+	require.Equal(t, boardID, digest.EntityID)
+	require.Contains(t, digest.Message, "Forgotten")
+	require.NotContains(t, digest.Message, "Active")
+
+	// The next run on the same day does not repeat the digest.
+	stats = svc.RunOnce()
+	require.Equal(t, 1, stats.StaleCards)
+	require.Zero(t, stats.DigestsSent)
+	require.Equal(t, 1, lookups[boardID], "CARD_STALE fires once while the card stays quiet")
+
+	// New activity re-arms the trigger for the next time the card goes quiet.
+	require.NoError(t, services.NewActivityService(db).LogActivity(assignee, boardID, "commented", staleID, nil))
+	db.Model(&models.Card{}).Where("id = ?", staleID).UpdateColumn("last_activity_at", old)
+	svc.RunOnce()
+	require.Equal(t, 2, lookups[boardID])
+}
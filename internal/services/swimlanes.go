@@ -0,0 +1,441 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidSwimlane = errors.New("invalid swimlane")
+
+// swimlanePriorities is the lane order in priority mode; PriorityNone is the catch-all lane.
+var swimlanePriorities = []models.CardPriority{models.PriorityUrgent, models.PriorityHigh, models.PriorityMedium, models.PriorityLow}
+
+type SwimlaneService struct {
+	DB     *gorm.DB
+	Fields *CustomFieldService // writes dropdown values so formulas follow lane moves
+}
+
+func NewSwimlaneService(db *gorm.DB, fields *CustomFieldService) *SwimlaneService {
+	return &SwimlaneService{DB: db, Fields: fields}
+}
+
+// GetBoard loads a board the user can access.
+func (s *SwimlaneService) GetBoard(boardID, userID uuid.UUID) (*models.Board, error) {
+	board, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	if err != nil {
+		return nil, ErrBoardAccessDenied
+	}
+	return board, nil
+}
+
+// BoardForColumn loads the board a column belongs to.
+func (s *SwimlaneService) BoardForColumn(columnID uuid.UUID) (*models.Board, error) {
+	var board models.Board
+	err := s.DB.Where("id = (?)", s.DB.Model(&models.Column{}).Select("board_id").Where("id = ?", columnID)).First(&board).Error
+	return &board, err
+}
+
+// --- Explicit swimlanes ---
+
+func (s *SwimlaneService) ListSwimlanes(boardID uuid.UUID) ([]models.Swimlane, error) {
+	lanes := []models.Swimlane{}
+	err := s.DB.Where("board_id = ?", boardID).Order("position ASC").Find(&lanes).Error
+	return lanes, err
+}
+
+// GetSwimlane loads a swimlane on a board the user can access.
+func (s *SwimlaneService) GetSwimlane(id, userID uuid.UUID) (*models.Swimlane, error) {
+	var lane models.Swimlane
+	if err := s.DB.First(&lane, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if _, err := s.GetBoard(lane.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return &lane, nil
+}
+
+func swimlaneName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > 100 {
+		return "", fmt.Errorf("%w: name must be 1-100 characters", ErrInvalidSwimlane)
+	}
+	return name, nil
+}
+
+// CreateSwimlane appends a lane to the board.
+func (s *SwimlaneService) CreateSwimlane(boardID uuid.UUID, name string) (*models.Swimlane, error) {
+	name, err := swimlaneName(name)
+	if err != nil {
+		return nil, err
+	}
+	var max float64
+	s.DB.Model(&models.Swimlane{}).Where("board_id = ?", boardID).Select("COALESCE(MAX(position), 0)").Scan(&max)
+	lane := &models.Swimlane{BoardID: boardID, Name: name, Position: max + repository.PositionGap}
+	return lane, s.DB.Create(lane).Error
+}
+
+// UpdateSwimlane renames and/or repositions the lane; nil leaves a value unchanged.
+func (s *SwimlaneService) UpdateSwimlane(lane *models.Swimlane, name *string, position *float64) ([]repository.PositionUpdate, error) {
+	updates := map[string]interface{}{}
+	if name != nil {
+		clean, err := swimlaneName(*name)
+		if err != nil {
+			return nil, err
+		}
+		lane.Name = clean
+		updates["name"] = clean
+	}
+	if position != nil {
+		if *position < 0 {
+			return nil, fmt.Errorf("%w: position cannot be negative", ErrInvalidSwimlane)
+		}
+		lane.Position = *position
+		updates["position"] = *position
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+	var rebalanced []repository.PositionUpdate
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(lane).Updates(updates).Error; err != nil {
+			return err
+		}
+		if position == nil {
+			return nil
+		}
+		var err error
+		rebalanced, err = repository.RebalanceIfCrowded(tx, repository.SwimlanePositionList(lane.BoardID), lane.ID, *position)
+		return err
+	})
+	for _, u := range rebalanced {
+		if u.ID == lane.ID {
+			lane.Position = u.Position
+		}
+	}
+	return rebalanced, err
+}
+
+// DeleteSwimlane removes the lane; its cards fall back to the catch-all lane.
+func (s *SwimlaneService) DeleteSwimlane(lane *models.Swimlane) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Card{}).Where("swimlane_id = ?", lane.ID).Update("swimlane_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(lane).Error
+	})
+}
+
+// CheckMode validates a swimlane mode for the board. Custom field lanes need a dropdown
+// field on the board; other modes take no field.
+func (s *SwimlaneService) CheckMode(boardID uuid.UUID, mode models.SwimlaneMode, fieldID *uuid.UUID) error {
+	if !mode.Valid() {
+		return fmt.Errorf("%w: unknown swimlane mode %q", ErrInvalidSwimlane, mode)
+	}
+	if mode != models.SwimlaneCustomField {
+		if fieldID != nil {
+			return fmt.Errorf("%w: swimlane_field_id is only used with custom_field lanes", ErrInvalidSwimlane)
+		}
+		return nil
+	}
+	if fieldID == nil {
+		return fmt.Errorf("%w: custom_field lanes need swimlane_field_id", ErrInvalidSwimlane)
+	}
+	var field models.CustomField
+	if err := s.DB.First(&field, "id = ? AND board_id = ?", *fieldID, boardID).Error; err != nil {
+		return fmt.Errorf("%w: field not found on this board", ErrInvalidSwimlane)
+	}
+	if field.Type != models.FieldTypeDropdown {
+		return fmt.Errorf("%w: only dropdown fields can define swimlanes", ErrInvalidSwimlane)
+	}
+	return nil
+}
+
+// --- Lanes ---
+
+// Lanes lists the board's lanes in display order, ending with the catch-all lane. A board
+// without swimlanes has none.
+func (s *SwimlaneService) Lanes(board *models.Board) ([]models.BoardLane, error) {
+	var lanes []models.BoardLane
+	catchAll := "No lane"
+	switch board.SwimlaneMode {
+	case models.SwimlaneExplicit:
+		swimlanes, err := s.ListSwimlanes(board.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range swimlanes {
+			lanes = append(lanes, models.BoardLane{Key: l.ID.String(), Name: l.Name})
+		}
+	case models.SwimlaneMember:
+		var users []models.User
+		if err := s.DB.Select("id", "name", "email").
+			Where("id IN (?) OR id IN (?)",
+				s.DB.Table("workspaces").Select("owner_id").Where("id = ?", board.WorkspaceID),
+				s.DB.Table("workspace_members").Select("user_id").Where("workspace_id = ? AND status = 'accepted'", board.WorkspaceID)).
+			Order("name ASC").Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			name := u.Name
+			if name == "" {
+				name = u.Email
+			}
+			lanes = append(lanes, models.BoardLane{Key: u.ID.String(), Name: name})
+		}
+		catchAll = "Unassigned"
+	case models.SwimlaneLabel:
+		var labels []models.Label
+		if err := s.DB.Where("board_id = ?", board.ID).Order("name ASC").Find(&labels).Error; err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			lanes = append(lanes, models.BoardLane{Key: l.ID.String(), Name: l.Name, Color: l.Color})
+		}
+		catchAll = "No label"
+	case models.SwimlanePriority:
+		for _, p := range swimlanePriorities {
+			lanes = append(lanes, models.BoardLane{Key: string(p), Name: strings.ToUpper(string(p[:1])) + string(p[1:])})
+		}
+		catchAll = "No priority"
+	case models.SwimlaneCustomField:
+		var field models.CustomField
+		if board.SwimlaneFieldID != nil {
+			if err := s.DB.First(&field, "id = ?", *board.SwimlaneFieldID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+		}
+		for _, option := range field.Options {
+			lanes = append(lanes, models.BoardLane{Key: option, Name: option})
+		}
+		catchAll = "No value"
+	default:
+		return nil, nil
+	}
+	return append(lanes, models.BoardLane{Key: "", Name: catchAll}), nil
+}
+
+// laneKeys returns a function giving a card's lane key. Member and label lanes need the
+// card's Members and Labels loaded; a card with several goes in the first matching lane.
+func (s *SwimlaneService) laneKeys(board *models.Board, lanes []models.BoardLane, cardIDs []uuid.UUID) (func(*models.Card) string, error) {
+	rank := make(map[string]int, len(lanes))
+	for i, l := range lanes {
+		rank[l.Key] = i
+	}
+	first := func(keys []string) string {
+		best, bestRank := "", len(lanes)
+		for _, k := range keys {
+			if r, ok := rank[k]; ok && r < bestRank {
+				best, bestRank = k, r
+			}
+		}
+		return best
+	}
+	known := func(key string) string {
+		if _, ok := rank[key]; ok {
+			return key
+		}
+		return ""
+	}
+
+	values := map[uuid.UUID]string{}
+	if board.SwimlaneMode == models.SwimlaneCustomField && board.SwimlaneFieldID != nil && len(cardIDs) > 0 {
+		var rows []models.CardCustomFieldValue
+		if err := s.DB.Select("card_id", "value_text").
+			Where("custom_field_id = ? AND card_id IN ?", *board.SwimlaneFieldID, cardIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			values[r.CardID] = r.ValueText
+		}
+	}
+
+	return func(card *models.Card) string {
+		switch board.SwimlaneMode {
+		case models.SwimlaneExplicit:
+			if card.SwimlaneID != nil {
+				return known(card.SwimlaneID.String())
+			}
+		case models.SwimlaneMember:
+			keys := make([]string, len(card.Members))
+			for i, m := range card.Members {
+				keys[i] = m.ID.String()
+			}
+			return first(keys)
+		case models.SwimlaneLabel:
+			keys := make([]string, len(card.Labels))
+			for i, l := range card.Labels {
+				keys[i] = l.ID.String()
+			}
+			return first(keys)
+		case models.SwimlanePriority:
+			if card.Priority != models.PriorityNone {
+				return known(string(card.Priority))
+			}
+		case models.SwimlaneCustomField:
+			return known(values[card.ID])
+		}
+		return ""
+	}, nil
+}
+
+// GroupColumns fills each column's Lanes with its cards, keeping every lane (even empty
+// ones) in board order. Cards need Members and Labels preloaded.
+func (s *SwimlaneService) GroupColumns(board *models.Board, columns []models.Column) ([]models.BoardLane, error) {
+	lanes, err := s.Lanes(board)
+	if err != nil || len(lanes) == 0 {
+		return lanes, err
+	}
+	var cardIDs []uuid.UUID
+	for _, col := range columns {
+		for _, card := range col.Cards {
+			cardIDs = append(cardIDs, card.ID)
+		}
+	}
+	laneKey, err := s.laneKeys(board, lanes, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range columns {
+		byKey := make(map[string][]models.Card, len(lanes))
+		for _, card := range columns[i].Cards {
+			key := laneKey(&card)
+			byKey[key] = append(byKey[key], card)
+		}
+		columns[i].Lanes = make([]models.ColumnLane, len(lanes))
+		for j, l := range lanes {
+			cards := byKey[l.Key]
+			if cards == nil {
+				cards = []models.Card{}
+			}
+			columns[i].Lanes[j] = models.ColumnLane{Key: l.Key, Cards: cards}
+		}
+	}
+	return lanes, nil
+}
+
+// CheckLane reports whether key is one of the board's lanes.
+func (s *SwimlaneService) CheckLane(board *models.Board, key string) error {
+	lanes, err := s.Lanes(board)
+	if err != nil {
+		return err
+	}
+	if len(lanes) == 0 {
+		return fmt.Errorf("%w: the board has no swimlanes", ErrInvalidSwimlane)
+	}
+	for _, l := range lanes {
+		if l.Key == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown lane %q", ErrInvalidSwimlane, key)
+}
+
+// MoveToLane puts the card in the lane by changing what the lane is derived from: the
+// explicit swimlane, the lane's member or label (replacing the one it was grouped by), the
+// priority or the dropdown value. Moving to the catch-all lane clears the members, labels
+// or value. It returns the key of the lane the card left, and whether anything changed.
+func (s *SwimlaneService) MoveToLane(board *models.Board, cardID uuid.UUID, key string) (string, bool, error) {
+	var from string
+	var changed bool
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		from, changed, err = s.MoveToLaneTx(tx, board, cardID, key)
+		return err
+	})
+	if err != nil {
+		return from, false, err
+	}
+	if changed && board.SwimlaneMode == models.SwimlaneCustomField && s.Fields != nil {
+		s.Fields.Formulas.RecomputeCard(cardID)
+	}
+	return from, changed, nil
+}
+
+// MoveToLaneTx is MoveToLane inside the caller's transaction, so a lane change can commit
+// or roll back together with a column move. Callers recompute formulas after commit.
+func (s *SwimlaneService) MoveToLaneTx(tx *gorm.DB, board *models.Board, cardID uuid.UUID, key string) (string, bool, error) {
+	in := &SwimlaneService{DB: tx, Fields: s.Fields}
+	if err := in.CheckLane(board, key); err != nil {
+		return "", false, err
+	}
+	lanes, _ := in.Lanes(board)
+	var card models.Card
+	if err := tx.Preload("Members").Preload("Labels").First(&card, "id = ?", cardID).Error; err != nil {
+		return "", false, err
+	}
+	laneKey, err := in.laneKeys(board, lanes, []uuid.UUID{cardID})
+	if err != nil {
+		return "", false, err
+	}
+	from := laneKey(&card)
+	if from == key {
+		return from, false, nil
+	}
+
+	repo := repository.NewCardRepository(tx)
+	switch board.SwimlaneMode {
+	case models.SwimlaneExplicit:
+		var laneID *uuid.UUID
+		if key != "" {
+			id := uuid.MustParse(key)
+			laneID = &id
+		}
+		err = tx.Model(&models.Card{}).Where("id = ?", cardID).
+			Updates(map[string]interface{}{"swimlane_id": laneID, "version": gorm.Expr("version + 1")}).Error
+	case models.SwimlaneMember:
+		if key == "" {
+			err = tx.Model(&models.Card{ID: cardID}).Association("Members").Clear()
+			break
+		}
+		if from != "" {
+			err = repo.RemoveMember(cardID, uuid.MustParse(from))
+		}
+		if err == nil {
+			err = repo.AddMember(cardID, uuid.MustParse(key))
+		}
+	case models.SwimlaneLabel:
+		if key == "" {
+			err = tx.Model(&models.Card{ID: cardID}).Association("Labels").Clear()
+			break
+		}
+		if from != "" {
+			err = repo.RemoveLabel(cardID, uuid.MustParse(from))
+		}
+		if err == nil {
+			err = repo.AddLabel(cardID, uuid.MustParse(key))
+		}
+	case models.SwimlanePriority:
+		priority := models.CardPriority(key)
+		if key == "" {
+			priority = models.PriorityNone
+		}
+		err = tx.Model(&models.Card{}).Where("id = ?", cardID).
+			Updates(map[string]interface{}{"priority": priority, "version": gorm.Expr("version + 1")}).Error
+	case models.SwimlaneCustomField:
+		if key == "" {
+			err = tx.Where("card_id = ? AND custom_field_id = ?", cardID, *board.SwimlaneFieldID).
+				Delete(&models.CardCustomFieldValue{}).Error
+			break
+		}
+		var field models.CustomField
+		if err = tx.First(&field, "id = ?", *board.SwimlaneFieldID).Error; err != nil {
+			break
+		}
+		var val *models.CardCustomFieldValue
+		if val, err = buildCardFieldValue(&field, cardID, key); err == nil {
+			err = repository.NewCustomFieldRepository(tx).SetValue(val)
+		}
+	}
+	if err != nil {
+		return from, false, err
+	}
+	return from, true, nil
+}
@@ -0,0 +1,178 @@
+package services_test
+
+import (
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSwimlanes_GroupAndMoveAcrossLanes(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE swimlanes (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, position REAL, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE users (id TEXT PRIMARY KEY, email TEXT, password TEXT, name TEXT, username TEXT, bio TEXT, avatar_url TEXT, language TEXT, has_completed_onboarding INTEGER, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (
+			id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+			value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	owner, dev := uuid.New(), uuid.New()
+	wsID, boardID, otherBoard := uuid.New(), uuid.New(), uuid.New()
+	todo, doing, elsewhere := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, dev)
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Olive', 'o@example.com'), (?, 'Dan', 'd@example.com')`, owner, dev)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Dev'), (?, ?, 'Ops')`, boardID, wsID, otherBoard, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'To Do', 1), (?, ?, 'Doing', 2), (?, ?, 'Queue', 1)`,
+		todo, boardID, doing, boardID, elsewhere, otherBoard)
+	cardA, cardB := uuid.New(), uuid.New()
+	require.NoError(t, db.Create(&models.Card{ID: cardA, Title: "A", ColumnID: todo, Position: 1, Priority: models.PriorityHigh}).Error)
+	require.NoError(t, db.Create(&models.Card{ID: cardB, Title: "B", ColumnID: doing, Position: 1}).Error)
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, cardA, owner)
+
+	fields := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	svc := services.NewSwimlaneService(db, fields)
+	board := &models.Board{ID: boardID, WorkspaceID: wsID, SwimlaneMode: models.SwimlaneNone}
+
+	lanes, err := svc.Lanes(board)
+	require.NoError(t, err)
+	require.Empty(t, lanes)
+	require.ErrorIs(t, svc.CheckLane(board, ""), services.ErrInvalidSwimlane)
+
+	// Explicit lanes, with the catch-all lane last.
+	board.SwimlaneMode = models.SwimlaneExplicit
+	expedite, err := svc.CreateSwimlane(boardID, "Expedite")
+	require.NoError(t, err)
+	_, err = svc.CreateSwimlane(boardID, "  ")
+	require.ErrorIs(t, err, services.ErrInvalidSwimlane)
+	from, changed, err := svc.MoveToLane(board, cardB, expedite.ID.String())
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "", from)
+
+	columns := loadBoardColumns(t, db, boardID)
+	lanes, err = svc.GroupColumns(board, columns)
+	require.NoError(t, err)
+	require.Equal(t, []models.BoardLane{{Key: expedite.ID.String(), Name: "Expedite"}, {Key: "", Name: "No lane"}}, lanes)
+	require.Len(t, columns[0].Lanes, 2)
+	require.Empty(t, columns[0].Lanes[0].Cards)
+	require.Equal(t, cardA, columns[0].Lanes[1].Cards[0].ID)
+	require.Equal(t, cardB, columns[1].Lanes[0].Cards[0].ID)
+
+	reload := func(id uuid.UUID) models.Card {
+		var card models.Card
+		require.NoError(t, db.First(&card, "id = ?", id).Error)
+		return card
+	}
+
+	// Deleting a swimlane drops its cards into the catch-all lane.
+	require.NoError(t, svc.DeleteSwimlane(expedite))
+	require.Nil(t, reload(cardB).SwimlaneID)
+
+	// Member lanes: moving across lanes swaps the assignee.
+	board.SwimlaneMode = models.SwimlaneMember
+	lanes, err = svc.Lanes(board)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Dan", "Olive", "Unassigned"}, laneNames(lanes))
+	from, changed, err = svc.MoveToLane(board, cardA, dev.String())
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, owner.String(), from)
+	var members []uuid.UUID
+	db.Table("card_members").Where("card_id = ?", cardA).Pluck("user_id", &members)
+	require.Equal(t, []uuid.UUID{dev}, members)
+	require.ErrorIs(t, svc.CheckLane(board, uuid.NewString()), services.ErrInvalidSwimlane)
+
+	// Priority lanes: the catch-all lane is priority none.
+	board.SwimlaneMode = models.SwimlanePriority
+	_, _, err = svc.MoveToLane(board, cardA, "")
+	require.NoError(t, err)
+	require.Equal(t, models.PriorityNone, reload(cardA).Priority)
+	_, _, err = svc.MoveToLane(board, cardA, string(models.PriorityUrgent))
+	require.NoError(t, err)
+	require.Equal(t, models.PriorityUrgent, reload(cardA).Priority)
+
+	// Dropdown lanes set and clear the field value.
+	text, err := fields.CreateField(boardID, "Notes", models.FieldTypeText, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, svc.CheckMode(boardID, models.SwimlaneCustomField, &text.ID), services.ErrInvalidSwimlane)
+	require.ErrorIs(t, svc.CheckMode(boardID, models.SwimlaneCustomField, nil), services.ErrInvalidSwimlane)
+	require.ErrorIs(t, svc.CheckMode(boardID, "diagonal", nil), services.ErrInvalidSwimlane)
+	stage, err := fields.CreateField(boardID, "Stage", models.FieldTypeDropdown, []string{"Design", "Build"})
+	require.NoError(t, err)
+	require.NoError(t, svc.CheckMode(boardID, models.SwimlaneCustomField, &stage.ID))
+	board.SwimlaneMode, board.SwimlaneFieldID = models.SwimlaneCustomField, &stage.ID
+	_, _, err = svc.MoveToLane(board, cardB, "Build")
+	require.NoError(t, err)
+	columns = loadBoardColumns(t, db, boardID)
+	_, err = svc.GroupColumns(board, columns)
+	require.NoError(t, err)
+	require.Equal(t, "Build", columns[1].Lanes[1].Key)
+	require.Equal(t, cardB, columns[1].Lanes[1].Cards[0].ID)
+	_, _, err = svc.MoveToLane(board, cardB, "")
+	require.NoError(t, err)
+	values, err := fields.GetCardValues(cardB)
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	// Swimlanes belong to a board, so a cross-board move clears the card's lane.
+	board.SwimlaneMode = models.SwimlaneExplicit
+	build, err := svc.CreateSwimlane(boardID, "Build")
+	require.NoError(t, err)
+	_, _, err = svc.MoveToLane(board, cardB, build.ID.String())
+	require.NoError(t, err)
+
+	// A column move and its lane change commit together, or not at all.
+	moveWithLane := func(card, column uuid.UUID, key string) error {
+		_, err := repository.NewCardRepository(db).MoveCardWithOptions(card, column, 5, repository.MoveCardOptions{
+			InTransaction: func(tx *gorm.DB) error {
+				_, _, err := svc.MoveToLaneTx(tx, board, card, key)
+				return err
+			},
+		})
+		return err
+	}
+	before := reload(cardA)
+	require.ErrorIs(t, moveWithLane(cardA, doing, uuid.NewString()), services.ErrInvalidSwimlane)
+	require.Equal(t, todo, reload(cardA).ColumnID)
+	require.Equal(t, before.Version, reload(cardA).Version)
+	require.NoError(t, moveWithLane(cardA, doing, build.ID.String()))
+	require.Equal(t, doing, reload(cardA).ColumnID)
+	require.Equal(t, build.ID, *reload(cardA).SwimlaneID)
+
+	_, err = repository.NewCardRepository(db).MoveCardWithOptions(cardB, elsewhere, 1, repository.MoveCardOptions{})
+	require.NoError(t, err)
+	require.Nil(t, reload(cardB).SwimlaneID)
+}
+
+// loadBoardColumns loads the board's columns with the relations GroupColumns reads.
+func loadBoardColumns(t *testing.T, db *gorm.DB, boardID uuid.UUID) []models.Column {
+	var columns []models.Column
+	require.NoError(t, db.Where("board_id = ?", boardID).
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_archived = ?", false).Order("position ASC").Preload("Members").Preload("Labels")
+		}).Order("position ASC").Find(&columns).Error)
+	return columns
+}
+
+func laneNames(lanes []models.BoardLane) []string {
+	names := make([]string, len(lanes))
+	for i, l := range lanes {
+		names[i] = l.Name
+	}
+	return names
+}
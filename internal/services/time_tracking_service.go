@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeLogDateLayout is the wire format of TimeLog.LoggedOn and report date filters.
+const TimeLogDateLayout = "2006-01-02"
+
+var (
+	ErrBoardAccessDenied = errors.New("board not accessible")
+	ErrTimeLogNotAuthor  = errors.New("only the author can change this time log")
+	ErrInvalidDuration   = errors.New("duration must be between 1 minute and 24 hours")
+	ErrInvalidEstimate   = errors.New("estimate must not be negative")
+)
+
+type TimeTrackingService struct {
+	DB              *gorm.DB
+	ActivityService *ActivityService
+	Formulas        *FormulaService // optional; estimates feed formula fields
+}
+
+func NewTimeTrackingService(db *gorm.DB, activityService *ActivityService) *TimeTrackingService {
+	return &TimeTrackingService{DB: db, ActivityService: activityService}
+}
+
+// CardTimeSummary is the per-card rollup of estimate and logged time.
+type CardTimeSummary struct {
+	CardID           uuid.UUID `json:"card_id"`
+	EstimateMinutes  *int      `json:"estimate_minutes"`
+	StoryPoints      *float64  `json:"story_points"`
+	LoggedMinutes    int       `json:"logged_minutes"`
+	RemainingMinutes *int      `json:"remaining_minutes"` // nil when there is no estimate
+}
+
+// cardBoardForUser loads a card and checks the user can access its board.
+func (s *TimeTrackingService) cardBoardForUser(cardID, userID uuid.UUID) (*models.Card, error) {
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	if !s.CanAccessBoard(userID, card.Column.BoardID) {
+		return nil, ErrBoardAccessDenied
+	}
+	return &card, nil
+}
+
+// CanAccessBoard reports whether the user owns or is an accepted member of the board's workspace.
+func (s *TimeTrackingService) CanAccessBoard(userID, boardID uuid.UUID) bool {
+	_, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	return err == nil
+}
+
+// SetEstimate replaces the card's estimate; nil values clear it.
+func (s *TimeTrackingService) SetEstimate(cardID, userID uuid.UUID, estimateMinutes *int, storyPoints *float64) (*models.Card, error) {
+	if (estimateMinutes != nil && *estimateMinutes < 0) || (storyPoints != nil && *storyPoints < 0) {
+		return nil, ErrInvalidEstimate
+	}
+	card, err := s.cardBoardForUser(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Card{}).Where("id = ?", cardID).Updates(map[string]interface{}{
+			"estimate_minutes": estimateMinutes,
+			"story_points":     storyPoints,
+			"version":          gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		if card.SprintID == nil || pointsOf(card.StoryPoints) == pointsOf(storyPoints) {
+			return nil
+		}
+		return recordIfSprintActive(tx, *card.SprintID, cardID, &userID, models.SprintChangeEstimate, storyPoints, card.StoryPoints, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	card.EstimateMinutes = estimateMinutes
+	card.StoryPoints = storyPoints
+	card.Version++
+	s.Formulas.RecomputeCard(cardID)
+
+	s.ActivityService.LogActivity(userID, card.Column.BoardID, "updated_estimate", cardID, map[string]interface{}{
+		"card_title":       card.Title,
+		"estimate_minutes": estimateMinutes,
+		"story_points":     storyPoints,
+	})
+	return card, nil
+}
+
+func (s *TimeTrackingService) LogTime(cardID, userID uuid.UUID, minutes int, loggedOn time.Time, note string) (*models.TimeLog, error) {
+	if minutes < 1 || minutes > 24*60 {
+		return nil, ErrInvalidDuration
+	}
+	card, err := s.cardBoardForUser(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	log := models.TimeLog{
+		CardID:          cardID,
+		UserID:          userID,
+		DurationMinutes: minutes,
+		LoggedOn:        truncateToDay(loggedOn),
+		Note:            note,
+	}
+	if err := s.DB.Create(&log).Error; err != nil {
+		return nil, err
+	}
+	if err := s.DB.Preload("User").First(&log, "id = ?", log.ID).Error; err != nil {
+		return nil, err
+	}
+
+	s.ActivityService.LogActivity(userID, card.Column.BoardID, "logged_time", cardID, map[string]interface{}{
+		"card_title":       card.Title,
+		"duration_minutes": minutes,
+		"logged_on":        log.LoggedOn.Format(TimeLogDateLayout),
+	})
+	return &log, nil
+}
+
+func (s *TimeTrackingService) ListTimeLogs(cardID, userID uuid.UUID) ([]models.TimeLog, error) {
+	if _, err := s.cardBoardForUser(cardID, userID); err != nil {
+		return nil, err
+	}
+	var logs []models.TimeLog
+	err := s.DB.Preload("User").
+		Where("card_id = ?", cardID).
+		Order("logged_on DESC, created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+// UpdateTimeLog edits a log entry. Only its author may change it, and only while they can
+// still access the card's board.
+func (s *TimeTrackingService) UpdateTimeLog(logID, userID uuid.UUID, minutes *int, loggedOn *time.Time, note *string) (*models.TimeLog, error) {
+	var log models.TimeLog
+	if err := s.DB.First(&log, "id = ?", logID).Error; err != nil {
+		return nil, err
+	}
+	if log.UserID != userID {
+		return nil, ErrTimeLogNotAuthor
+	}
+	if _, err := s.cardBoardForUser(log.CardID, userID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if minutes != nil {
+		if *minutes < 1 || *minutes > 24*60 {
+			return nil, ErrInvalidDuration
+		}
+		updates["duration_minutes"] = *minutes
+	}
+	if loggedOn != nil {
+		updates["logged_on"] = truncateToDay(*loggedOn)
+	}
+	if note != nil {
+		updates["note"] = *note
+	}
+	if len(updates) > 0 {
+		if err := s.DB.Model(&log).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.DB.Preload("User").First(&log, "id = ?", logID).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// DeleteTimeLog removes a log entry. Only its author may delete it, and only while they can
+// still access the card's board.
+func (s *TimeTrackingService) DeleteTimeLog(logID, userID uuid.UUID) error {
+	var log models.TimeLog
+	if err := s.DB.First(&log, "id = ?", logID).Error; err != nil {
+		return err
+	}
+	if log.UserID != userID {
+		return ErrTimeLogNotAuthor
+	}
+	if _, err := s.cardBoardForUser(log.CardID, userID); err != nil {
+		return err
+	}
+	return s.DB.Delete(&log).Error
+}
+
+func (s *TimeTrackingService) GetCardSummary(cardID, userID uuid.UUID) (*CardTimeSummary, error) {
+	card, err := s.cardBoardForUser(cardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var logged int64
+	if err := s.DB.Model(&models.TimeLog{}).
+		Where("card_id = ?", cardID).
+		Select("COALESCE(SUM(duration_minutes), 0)").
+		Scan(&logged).Error; err != nil {
+		return nil, err
+	}
+
+	return &CardTimeSummary{
+		CardID:           card.ID,
+		EstimateMinutes:  card.EstimateMinutes,
+		StoryPoints:      card.StoryPoints,
+		LoggedMinutes:    int(logged),
+		RemainingMinutes: RemainingMinutes(card.EstimateMinutes, int(logged)),
+	}, nil
+}
+
+// RemainingMinutes is estimate minus logged time, floored at zero. Nil without an estimate.
+func RemainingMinutes(estimate *int, logged int) *int {
+	if estimate == nil {
+		return nil
+	}
+	remaining := *estimate - logged
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// TimeLogFilter narrows board-level time reports. Zero values mean "no bound".
+type TimeLogFilter struct {
+	From   *time.Time
+	To     *time.Time // inclusive day
+	UserID *uuid.UUID
+}
+
+func (f TimeLogFilter) apply(q *gorm.DB) *gorm.DB {
+	if f.From != nil {
+		q = q.Where("time_logs.logged_on >= ?", truncateToDay(*f.From))
+	}
+	if f.To != nil {
+		q = q.Where("time_logs.logged_on < ?", truncateToDay(*f.To).AddDate(0, 0, 1))
+	}
+	if f.UserID != nil {
+		q = q.Where("time_logs.user_id = ?", *f.UserID)
+	}
+	return q
+}
+
+// BoardTimeLogs returns a board's time logs in a window with card, column and user loaded, oldest first.
+func (s *TimeTrackingService) BoardTimeLogs(boardID uuid.UUID, filter TimeLogFilter) ([]TimeLogRow, error) {
+	var rows []TimeLogRow
+	q := s.DB.Table("time_logs").
+		Select(`time_logs.id, time_logs.logged_on, time_logs.duration_minutes, time_logs.note,
+			time_logs.user_id, users.name AS user_name, users.email AS user_email,
+			cards.id AS card_id, cards.title AS card_title,
+			columns.id AS column_id, columns.name AS column_name`).
+		Joins("JOIN cards ON cards.id = time_logs.card_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("LEFT JOIN users ON users.id = time_logs.user_id").
+		Where("columns.board_id = ?", boardID)
+	err := filter.apply(q).
+		Order("time_logs.logged_on ASC, time_logs.created_at ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// TimeLogRow is a flattened time log used by reports and CSV export.
+type TimeLogRow struct {
+	ID              uuid.UUID `json:"id"`
+	LoggedOn        time.Time `json:"logged_on"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Note            string    `json:"note"`
+	UserID          uuid.UUID `json:"user_id"`
+	UserName        string    `json:"user_name"`
+	UserEmail       string    `json:"user_email"`
+	CardID          uuid.UUID `json:"card_id"`
+	CardTitle       string    `json:"card_title"`
+	ColumnID        uuid.UUID `json:"column_id"`
+	ColumnName      string    `json:"column_name"`
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeTracking_CardSummaryAndAccess(t *testing.T) {
+	db := setupBulkTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, email TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE time_logs (
+		id TEXT PRIMARY KEY,
+		card_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		duration_minutes INTEGER NOT NULL,
+		logged_on DATE NOT NULL,
+		note TEXT,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`).Error)
+
+	owner, outsider, member := uuid.New(), uuid.New(), uuid.New()
+	wsID, boardID, colID, cardID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?)`, wsID, owner)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Board')`, boardID, wsID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Doing', 1)`, colID, boardID)
+	require.NoError(t, db.Create(&models.Card{ID: cardID, Title: "Card", ColumnID: colID, Position: 1}).Error)
+
+	svc := services.NewTimeTrackingService(db, services.NewActivityService(db))
+
+	estimate := 90
+	_, err := svc.SetEstimate(cardID, owner, &estimate, nil)
+	require.NoError(t, err)
+
+	_, err = svc.LogTime(cardID, owner, 60, time.Now(), "pairing")
+	require.NoError(t, err)
+	_, err = svc.LogTime(cardID, owner, 0, time.Now(), "")
+	require.ErrorIs(t, err, services.ErrInvalidDuration)
+	_, err = svc.LogTime(cardID, outsider, 30, time.Now(), "")
+	require.ErrorIs(t, err, services.ErrBoardAccessDenied)
+
+	summary, err := svc.GetCardSummary(cardID, owner)
+	require.NoError(t, err)
+	require.Equal(t, 60, summary.LoggedMinutes)
+	require.Equal(t, 30, *summary.RemainingMinutes)
+
+	// Overrunning the estimate floors remaining time at zero.
+	_, err = svc.LogTime(cardID, owner, 45, time.Now(), "")
+	require.NoError(t, err)
+	summary, err = svc.GetCardSummary(cardID, owner)
+	require.NoError(t, err)
+	require.Equal(t, 105, summary.LoggedMinutes)
+	require.Equal(t, 0, *summary.RemainingMinutes)
+
+	// Authors lose their entries along with access to the board.
+	db.Exec(`INSERT INTO workspace_members (workspace_id, user_id, role, status) VALUES (?, ?, 'member', 'accepted')`, wsID, member)
+	entry, err := svc.LogTime(cardID, member, 15, time.Now(), "")
+	require.NoError(t, err)
+	minutes := 20
+	_, err = svc.UpdateTimeLog(entry.ID, owner, &minutes, nil, nil)
+	require.ErrorIs(t, err, services.ErrTimeLogNotAuthor, "only the author may edit")
+	require.ErrorIs(t, svc.DeleteTimeLog(entry.ID, owner), services.ErrTimeLogNotAuthor)
+	db.Exec(`DELETE FROM workspace_members WHERE user_id = ?`, member)
+	_, err = svc.UpdateTimeLog(entry.ID, member, &minutes, nil, nil)
+	require.ErrorIs(t, err, services.ErrBoardAccessDenied)
+	require.ErrorIs(t, svc.DeleteTimeLog(entry.ID, member), services.ErrBoardAccessDenied)
+}
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidTimeline   = errors.New("invalid timeline request")
+	ErrInvalidDependency = errors.New("invalid card dependency")
+)
+
+// Timeline groupings.
+const (
+	TimelineByColumn = "column"
+	TimelineByMember = "member"
+	TimelineByLabel  = "label"
+)
+
+const (
+	DefaultTimelineWindowDays = 90
+	MaxTimelineWindowDays     = 366
+	MaxTimelineCards          = 1000 // per window; narrow the window when truncated
+)
+
+// TimelineWindow is the half-open date range [From, To).
+type TimelineWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// TimelineCard is a card's bar; with only one of StartDate and DueDate it is a single point.
+type TimelineCard struct {
+	ID         uuid.UUID           `json:"id"`
+	Title      string              `json:"title"`
+	BoardID    uuid.UUID           `json:"board_id"`
+	ColumnID   uuid.UUID           `json:"column_id"`
+	StartDate  *time.Time          `json:"start_date"`
+	DueDate    *time.Time          `json:"due_date"`
+	IsComplete bool                `json:"is_complete"`
+	Priority   models.CardPriority `json:"priority"`
+	Version    int                 `json:"version"`
+}
+
+type TimelineGroup struct {
+	Key   string         `json:"key"`
+	Name  string         `json:"name"`
+	Cards []TimelineCard `json:"cards"`
+}
+
+// TimelineEdge runs from the card that must finish first to the card depending on it.
+type TimelineEdge struct {
+	From uuid.UUID `json:"from"`
+	To   uuid.UUID `json:"to"`
+}
+
+type Timeline struct {
+	TimelineWindow
+	GroupBy   string          `json:"group_by"`
+	Groups    []TimelineGroup `json:"groups"`
+	Edges     []TimelineEdge  `json:"edges"`
+	Truncated bool            `json:"truncated"`
+	Previous  *TimelineWindow `json:"previous,omitempty"` // set when cards end before From
+	Next      *TimelineWindow `json:"next,omitempty"`     // set when cards start at or after To
+}
+
+// TimelineOptions picks the window and grouping. A zero From starts a week before today (UTC);
+// a zero To ends DefaultTimelineWindowDays after From.
+type TimelineOptions struct {
+	From    time.Time
+	To      time.Time
+	GroupBy string
+}
+
+// RescheduleResult is the rescheduled card and the dependents moved along with it.
+type RescheduleResult struct {
+	Card    TimelineCard   `json:"card"`
+	Shifted []TimelineCard `json:"shifted"`
+}
+
+// TimelineService lays dated cards out over time and keeps the dependencies between them.
+type TimelineService struct {
+	DB              *gorm.DB
+	Cards           *CardQueryService
+	CardService     *CardService // recomputes formulas and runs due-date rules after a reschedule
+	ActivityService *ActivityService
+	Now             func() time.Time
+}
+
+func NewTimelineService(db *gorm.DB, cards *CardQueryService, cardService *CardService, activityService *ActivityService) *TimelineService {
+	return &TimelineService{DB: db, Cards: cards, CardService: cardService, ActivityService: activityService, Now: time.Now}
+}
+
+// GetBoard loads a board the user can access.
+func (s *TimelineService) GetBoard(boardID, userID uuid.UUID) (*models.Board, error) {
+	board, err := repository.NewBoardRepository(s.DB).GetBoardByID(boardID, userID)
+	if err != nil {
+		return nil, ErrBoardAccessDenied
+	}
+	return board, nil
+}
+
+// CardForUser loads a card, with its column, on a board the user can access.
+func (s *TimelineService) CardForUser(cardID, userID uuid.UUID) (*models.Card, error) {
+	var card models.Card
+	if err := s.DB.Preload("Column").First(&card, "id = ?", cardID).Error; err != nil {
+		return nil, err
+	}
+	if _, err := s.GetBoard(card.Column.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// BoardTimeline lays out the board's dated cards in the window.
+func (s *TimelineService) BoardTimeline(board *models.Board, userID uuid.UUID, opts TimelineOptions) (*Timeline, error) {
+	query, _ := ParseCardQuery("")
+	return s.timeline(userID, query, CardQueryScope{BoardID: &board.ID}, opts)
+}
+
+// FilterTimeline lays out the dated cards matching a saved filter within its board or workspace.
+func (s *TimelineService) FilterTimeline(filter *models.SavedFilter, userID uuid.UUID, opts TimelineOptions) (*Timeline, error) {
+	query, err := ParseCardQuery(filter.Query)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeline(userID, query, CardQueryScope{WorkspaceID: &filter.WorkspaceID, BoardID: filter.BoardID}, opts)
+}
+
+func (s *TimelineService) window(opts TimelineOptions) (TimelineWindow, error) {
+	win := TimelineWindow{From: opts.From.UTC(), To: opts.To.UTC()}
+	if opts.From.IsZero() {
+		now := s.Now().UTC()
+		win.From = time.Date(now.Year(), now.Month(), now.Day()-7, 0, 0, 0, 0, time.UTC)
+	}
+	if opts.To.IsZero() {
+		win.To = win.From.AddDate(0, 0, DefaultTimelineWindowDays)
+	}
+	if !win.To.After(win.From) {
+		return win, fmt.Errorf("%w: to must be after from", ErrInvalidTimeline)
+	}
+	if win.To.Sub(win.From) > MaxTimelineWindowDays*24*time.Hour {
+		return win, fmt.Errorf("%w: the window may span at most %d days", ErrInvalidTimeline, MaxTimelineWindowDays)
+	}
+	return win, nil
+}
+
+// timeline returns the cards whose range overlaps the window. A card's range runs from its start
+// date (or due date) to its due date (or start date); undated cards are left out.
+func (s *TimelineService) timeline(userID uuid.UUID, query *CardQuery, scope CardQueryScope, opts TimelineOptions) (*Timeline, error) {
+	win, err := s.window(opts)
+	if err != nil {
+		return nil, err
+	}
+	groupBy := opts.GroupBy
+	if groupBy == "" {
+		groupBy = TimelineByColumn
+	}
+	if groupBy != TimelineByColumn && groupBy != TimelineByMember && groupBy != TimelineByLabel {
+		return nil, fmt.Errorf("%w: group_by must be column, member or label", ErrInvalidTimeline)
+	}
+
+	q := s.Cards.Scoped(userID, query, scope).
+		Where("COALESCE(cards.start_date, cards.due_date) < ? AND COALESCE(cards.due_date, cards.start_date) >= ?", win.To, win.From).
+		Order("COALESCE(cards.start_date, cards.due_date) ASC, cards.id ASC").
+		Limit(MaxTimelineCards + 1).
+		Preload("Column")
+	switch groupBy {
+	case TimelineByMember:
+		q = q.Preload("Members")
+	case TimelineByLabel:
+		q = q.Preload("Labels")
+	}
+	var cards []models.Card
+	if err := q.Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	timeline := &Timeline{TimelineWindow: win, GroupBy: groupBy, Edges: []TimelineEdge{}}
+	if len(cards) > MaxTimelineCards {
+		cards, timeline.Truncated = cards[:MaxTimelineCards], true
+	}
+	timeline.Groups = groupTimeline(cards, groupBy)
+
+	span := win.To.Sub(win.From)
+	earlier, err := s.anyCard(s.Cards.Scoped(userID, query, scope).Where("COALESCE(cards.due_date, cards.start_date) < ?", win.From))
+	if err != nil {
+		return nil, err
+	}
+	if earlier {
+		timeline.Previous = &TimelineWindow{From: win.From.Add(-span), To: win.From}
+	}
+	later, err := s.anyCard(s.Cards.Scoped(userID, query, scope).Where("COALESCE(cards.start_date, cards.due_date) >= ?", win.To))
+	if err != nil {
+		return nil, err
+	}
+	if later {
+		timeline.Next = &TimelineWindow{From: win.To, To: win.To.Add(span)}
+	}
+
+	if len(cards) > 0 {
+		ids := make([]uuid.UUID, len(cards))
+		for i, c := range cards {
+			ids[i] = c.ID
+		}
+		var deps []models.CardDependency
+		if err := s.DB.Where("card_id IN ? OR depends_on_id IN ?", ids, ids).Order("created_at ASC").Find(&deps).Error; err != nil {
+			return nil, err
+		}
+		for _, d := range deps {
+			timeline.Edges = append(timeline.Edges, TimelineEdge{From: d.DependsOnID, To: d.CardID})
+		}
+	}
+	return timeline, nil
+}
+
+func (s *TimelineService) anyCard(q *gorm.DB) (bool, error) {
+	var ids []uuid.UUID
+	err := q.Select("cards.id").Limit(1).Scan(&ids).Error
+	return len(ids) > 0, err
+}
+
+func toTimelineCard(c *models.Card) TimelineCard {
+	return TimelineCard{
+		ID: c.ID, Title: c.Title, BoardID: c.Column.BoardID, ColumnID: c.ColumnID, StartDate: c.StartDate, DueDate: c.DueDate,
+		IsComplete: c.IsComplete, Priority: c.Priority, Version: c.Version,
+	}
+}
+
+// groupTimeline puts each card in its column's group, or in one group per member or label.
+// Columns keep board order; members and labels sort by name, with the catch-all group last.
+func groupTimeline(cards []models.Card, groupBy string) []TimelineGroup {
+	type rank struct {
+		catchAll bool
+		text     string
+		position float64
+	}
+	groups := map[string]*TimelineGroup{}
+	ranks := map[string]rank{}
+	var keys []string
+	add := func(key, name string, r rank, card TimelineCard) {
+		g, ok := groups[key]
+		if !ok {
+			g = &TimelineGroup{Key: key, Name: name}
+			groups[key], ranks[key] = g, r
+			keys = append(keys, key)
+		}
+		g.Cards = append(g.Cards, card)
+	}
+	for i := range cards {
+		c := &cards[i]
+		card := toTimelineCard(c)
+		switch groupBy {
+		case TimelineByColumn:
+			add(c.ColumnID.String(), c.Column.Name, rank{text: c.Column.BoardID.String(), position: c.Column.Position}, card)
+		case TimelineByMember:
+			if len(c.Members) == 0 {
+				add("none", "Unassigned", rank{catchAll: true}, card)
+			}
+			for _, m := range c.Members {
+				name := m.Name
+				if name == "" {
+					name = m.Username
+				}
+				add(m.ID.String(), name, rank{text: strings.ToLower(name)}, card)
+			}
+		case TimelineByLabel:
+			if len(c.Labels) == 0 {
+				add("none", "No label", rank{catchAll: true}, card)
+			}
+			for _, l := range c.Labels {
+				add(l.ID.String(), l.Name, rank{text: strings.ToLower(l.Name)}, card)
+			}
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := ranks[keys[i]], ranks[keys[j]]
+		if a.catchAll != b.catchAll {
+			return b.catchAll
+		}
+		if a.text != b.text {
+			return a.text < b.text
+		}
+		return a.position < b.position
+	})
+	out := make([]TimelineGroup, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, *groups[k])
+	}
+	return out
+}
+
+// AddDependency records that card depends on dependsOnID finishing first. Both cards must be
+// in the same workspace, and the new edge must not close a cycle.
+func (s *TimelineService) AddDependency(card *models.Card, dependsOnID, userID uuid.UUID) (*models.CardDependency, error) {
+	if dependsOnID == card.ID {
+		return nil, fmt.Errorf("%w: a card cannot depend on itself", ErrInvalidDependency)
+	}
+	other, err := s.CardForUser(dependsOnID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if other.IsTemplate || card.IsTemplate {
+		return nil, fmt.Errorf("%w: templates cannot have dependencies", ErrInvalidDependency)
+	}
+	var workspaces []uuid.UUID
+	if err := s.DB.Model(&models.Board{}).Distinct("workspace_id").
+		Where("id IN ?", []uuid.UUID{card.Column.BoardID, other.Column.BoardID}).Pluck("workspace_id", &workspaces).Error; err != nil {
+		return nil, err
+	}
+	if len(workspaces) != 1 {
+		return nil, fmt.Errorf("%w: both cards must be in the same workspace", ErrInvalidDependency)
+	}
+	prerequisites, err := walkDependencies(s.DB, dependsOnID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range prerequisites {
+		if id == card.ID {
+			return nil, fmt.Errorf("%w: that would create a dependency cycle", ErrInvalidDependency)
+		}
+	}
+
+	dep := &models.CardDependency{CardID: card.ID, DependsOnID: dependsOnID, CreatedBy: userID}
+	if err := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(dep).Error; err != nil {
+		return nil, err
+	}
+	s.ActivityService.LogActivity(userID, card.Column.BoardID, "added_dependency", card.ID, map[string]interface{}{
+		"card_title":       card.Title,
+		"depends_on_id":    other.ID,
+		"depends_on_title": other.Title,
+	})
+	return dep, nil
+}
+
+func (s *TimelineService) RemoveDependency(card *models.Card, dependsOnID, userID uuid.UUID) error {
+	result := s.DB.Where("card_id = ? AND depends_on_id = ?", card.ID, dependsOnID).Delete(&models.CardDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	s.ActivityService.LogActivity(userID, card.Column.BoardID, "removed_dependency", card.ID, map[string]interface{}{
+		"card_title":    card.Title,
+		"depends_on_id": dependsOnID,
+	})
+	return nil
+}
+
+// walkDependencies returns every card reached from start by following dependency edges, either
+// to the cards depending on it or to the cards it depends on. One query per level.
+func walkDependencies(db *gorm.DB, start uuid.UUID, dependents bool) ([]uuid.UUID, error) {
+	from, to := "card_id", "depends_on_id"
+	if dependents {
+		from, to = "depends_on_id", "card_id"
+	}
+	seen := map[uuid.UUID]bool{start: true}
+	var reached []uuid.UUID
+	for frontier := []uuid.UUID{start}; len(frontier) > 0; {
+		var next []uuid.UUID
+		if err := db.Model(&models.CardDependency{}).Where(from+" IN ?", frontier).Pluck(to, &next).Error; err != nil {
+			return nil, err
+		}
+		frontier = nil
+		for _, id := range next {
+			if !seen[id] {
+				seen[id] = true
+				reached = append(reached, id)
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return reached, nil
+}
+
+// Reschedule replaces the card's date range; a nil date clears it. With cascade, every card that
+// depends on it, directly or transitively, moves by as much as the end of the card's range moved.
+// expectedVersion guards the card itself (0 skips the check); on a conflict the result holds the
+// card's current state. Every card written gets a new version, and formulas and due-date rules
+// follow as for any other date change.
+func (s *TimelineService) Reschedule(card *models.Card, userID uuid.UUID, start, due *time.Time, cascade bool, expectedVersion int) (*RescheduleResult, error) {
+	if start != nil && due != nil && start.After(*due) {
+		return nil, ErrStartAfterDue
+	}
+	newEnd := rangeEnd(start, due)
+
+	var current models.Card
+	var shifted []models.Card
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		repo := repository.NewCardRepository(tx)
+		if err := tx.First(&current, "id = ?", card.ID).Error; err != nil {
+			return err
+		}
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return repository.ErrVersionConflict
+		}
+		oldEnd := rangeEnd(current.StartDate, current.DueDate)
+		if err := repo.UpdateIfVersion(rescheduled(&current, start, due), current.Version); err != nil {
+			return err
+		}
+		if !cascade || oldEnd == nil || newEnd == nil || newEnd.Equal(*oldEnd) {
+			return nil
+		}
+		delta := newEnd.Sub(*oldEnd)
+		ids, err := walkDependencies(tx, card.ID, true)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := tx.Preload("Column").
+			Where("id IN ? AND (start_date IS NOT NULL OR due_date IS NOT NULL)", ids).
+			Order("COALESCE(start_date, due_date) ASC, id ASC").
+			Find(&shifted).Error; err != nil {
+			return err
+		}
+		for i := range shifted {
+			c := &shifted[i]
+			if err := repo.UpdateIfVersion(rescheduled(c, shiftTime(c.StartDate, delta), shiftTime(c.DueDate, delta)), c.Version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrVersionConflict) && current.ID != uuid.Nil {
+		current.Column = card.Column
+		return &RescheduleResult{Card: toTimelineCard(&current)}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	card.StartDate, card.DueDate, card.Version = current.StartDate, current.DueDate, current.Version
+
+	result := &RescheduleResult{Card: toTimelineCard(card), Shifted: []TimelineCard{}}
+	s.CardService.cardUpdated(card.ID, due != nil)
+	s.ActivityService.LogActivity(userID, card.Column.BoardID, "rescheduled_card", card.ID, map[string]interface{}{
+		"card_title": card.Title,
+		"start_date": start,
+		"due_date":   due,
+		"shifted":    len(shifted),
+	})
+	for i := range shifted {
+		c := &shifted[i]
+		result.Shifted = append(result.Shifted, toTimelineCard(c))
+		s.CardService.cardUpdated(c.ID, c.DueDate != nil)
+		s.ActivityService.LogActivity(userID, c.Column.BoardID, "rescheduled_card", c.ID, map[string]interface{}{
+			"card_title":   c.Title,
+			"start_date":   c.StartDate,
+			"due_date":     c.DueDate,
+			"cascade_from": card.ID,
+		})
+	}
+	return result, nil
+}
+
+// rescheduled sets the card's dates.
+func rescheduled(card *models.Card, start, due *time.Time) *models.Card {
+	card.StartDate, card.DueDate = start, due
+	return card
+}
+
+func rangeEnd(start, due *time.Time) *time.Time {
+	if due != nil {
+		return due
+	}
+	return start
+}
+
+func shiftTime(t *time.Time, d time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	shifted := t.Add(d)
+	return &shifted
+}
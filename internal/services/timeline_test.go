@@ -0,0 +1,200 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeline_WindowGroupsDependenciesAndCascade(t *testing.T) {
+	db := setupSavedFilterTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE card_dependencies (id TEXT PRIMARY KEY, card_id TEXT, depends_on_id TEXT, created_by TEXT, created_at DATETIME,
+		UNIQUE (card_id, depends_on_id))`).Error)
+
+	me, alice := uuid.New(), uuid.New()
+	wsID, otherWS := uuid.New(), uuid.New()
+	boardID, otherBoard := uuid.New(), uuid.New()
+	design, build, foreign := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name, username, email) VALUES (?, 'Alice', 'alice', 'alice@example.com')`, alice)
+	db.Exec(`INSERT INTO workspaces (id, name, owner_id) VALUES (?, 'ws', ?), (?, 'other', ?)`, wsID, me, otherWS, me)
+	db.Exec(`INSERT INTO boards (id, workspace_id, title) VALUES (?, ?, 'Launch'), (?, ?, 'Elsewhere')`, boardID, wsID, otherBoard, otherWS)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'Build', 2), (?, ?, 'Design', 1), (?, ?, 'Todo', 1)`,
+		build, boardID, design, boardID, foreign, otherBoard)
+
+	day := func(month time.Month, d int) *time.Time {
+		t := time.Date(2024, month, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	cards := map[string]*models.Card{}
+	create := func(title string, col uuid.UUID, start, due *time.Time) {
+		card := &models.Card{ID: uuid.New(), Title: title, ColumnID: col, Position: float64(len(cards) + 1), StartDate: start, DueDate: due}
+		require.NoError(t, db.Create(card).Error)
+		cards[title] = card
+	}
+	create("Spec", design, day(5, 1), day(5, 5))
+	create("Build API", build, day(5, 6), day(5, 15))
+	create("Ship", build, nil, day(5, 20))
+	create("Kickoff", design, day(5, 3), nil)
+	create("Retro", build, nil, day(7, 15))
+	create("Old", build, nil, day(3, 1))
+	create("Undated", build, nil, nil)
+	create("Foreign", foreign, nil, day(5, 10))
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?)`, cards["Spec"].ID, alice)
+	ux := uuid.New()
+	db.Exec(`INSERT INTO labels (id, board_id, name) VALUES (?, ?, 'UX')`, ux, boardID)
+	db.Exec(`INSERT INTO card_labels (card_id, label_id) VALUES (?, ?)`, cards["Build API"].ID, ux)
+
+	cardQuery := services.NewCardQueryService(db)
+	cardService := services.NewCardService(repository.NewCardRepository(db), nil)
+	svc := services.NewTimelineService(db, cardQuery, cardService, services.NewActivityService(db))
+	load := func(title string) *models.Card {
+		card, err := svc.CardForUser(cards[title].ID, me)
+		require.NoError(t, err)
+		return card
+	}
+	_, err := svc.AddDependency(load("Build API"), cards["Spec"].ID, me)
+	require.NoError(t, err)
+	_, err = svc.AddDependency(load("Ship"), cards["Build API"].ID, me)
+	require.NoError(t, err)
+	_, err = svc.AddDependency(load("Ship"), cards["Build API"].ID, me) // repeated adds are a no-op
+	require.NoError(t, err)
+	_, err = svc.AddDependency(load("Spec"), cards["Ship"].ID, me)
+	require.ErrorIs(t, err, services.ErrInvalidDependency)
+	_, err = svc.AddDependency(load("Spec"), cards["Spec"].ID, me)
+	require.ErrorIs(t, err, services.ErrInvalidDependency)
+	_, err = svc.AddDependency(load("Spec"), cards["Foreign"].ID, me)
+	require.ErrorIs(t, err, services.ErrInvalidDependency)
+
+	board, err := svc.GetBoard(boardID, me)
+	require.NoError(t, err)
+	may := services.TimelineOptions{From: *day(5, 1), To: *day(6, 1)}
+	may.From, may.To = may.From.Truncate(24*time.Hour), may.To.Truncate(24*time.Hour)
+	names := func(tl *services.Timeline) map[string][]string {
+		out := map[string][]string{}
+		for _, g := range tl.Groups {
+			for _, c := range g.Cards {
+				out[g.Name] = append(out[g.Name], c.Title)
+			}
+		}
+		return out
+	}
+
+	tl, err := svc.BoardTimeline(board, me, may)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Design", "Build"}, []string{tl.Groups[0].Name, tl.Groups[1].Name})
+	require.Equal(t, map[string][]string{"Design": {"Spec", "Kickoff"}, "Build": {"Build API", "Ship"}}, names(tl))
+	require.ElementsMatch(t, []services.TimelineEdge{
+		{From: cards["Spec"].ID, To: cards["Build API"].ID},
+		{From: cards["Build API"].ID, To: cards["Ship"].ID},
+	}, tl.Edges)
+	require.NotNil(t, tl.Previous)
+	require.Equal(t, may.From, tl.Previous.To)
+	require.Equal(t, may.To, tl.Next.From)
+	require.Equal(t, may.To.Add(may.To.Sub(may.From)), tl.Next.To)
+
+	may.GroupBy = services.TimelineByMember
+	tl, err = svc.BoardTimeline(board, me, may)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", tl.Groups[0].Name)
+	require.Equal(t, map[string][]string{"Alice": {"Spec"}, "Unassigned": {"Kickoff", "Build API", "Ship"}}, names(tl))
+	may.GroupBy = services.TimelineByLabel
+	tl, err = svc.BoardTimeline(board, me, may)
+	require.NoError(t, err)
+	require.Equal(t, "UX", tl.Groups[0].Name)
+
+	filters := services.NewSavedFilterService(db, cardQuery)
+	query := "column:build"
+	filter, err := filters.CreateFilter(me, wsID, nil, services.SavedFilterInput{Name: &query, Query: &query})
+	require.NoError(t, err)
+	may.GroupBy = ""
+	tl, err = svc.FilterTimeline(filter, me, may)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"Build": {"Build API", "Ship"}}, names(tl))
+
+	for _, bad := range []services.TimelineOptions{
+		{From: may.To, To: may.From},
+		{From: may.From, To: may.From.AddDate(2, 0, 0)},
+		{GroupBy: "priority"},
+	} {
+		_, err = svc.BoardTimeline(board, me, bad)
+		require.ErrorIs(t, err, services.ErrInvalidTimeline)
+	}
+
+	// Due-date formulas follow every rescheduled card.
+	for _, stmt := range []string{
+		`CREATE TABLE custom_fields (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, type TEXT, options TEXT, position REAL DEFAULT 0, formula TEXT, result_type TEXT, workspace_field_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE card_custom_field_values (
+			id TEXT, card_id TEXT, custom_field_id TEXT, value_text TEXT, value_number REAL, value_date DATETIME, value_bool INTEGER,
+			value_user_id TEXT, value_list TEXT, value_currency TEXT, formula_error TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME
+		)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	fields := services.NewCustomFieldService(repository.NewCustomFieldRepository(db), nil, nil)
+	cardService.Formulas = fields.Formulas
+	span, err := fields.CreateFormulaField(boardID, "Span", `days_between(created_at, due_date)`)
+	require.NoError(t, err)
+	spanOf := func(title string) float64 {
+		var v models.CardCustomFieldValue
+		require.NoError(t, db.Where("card_id = ? AND custom_field_id = ?", cards[title].ID, span.ID).First(&v).Error)
+		return v.ValueNumber
+	}
+	buildSpan := spanOf("Build API")
+
+	// Moving Spec's end three days later pushes everything downstream by the same amount.
+	_, err = svc.Reschedule(load("Spec"), me, day(5, 7), day(5, 2), false, 0)
+	require.ErrorIs(t, err, services.ErrStartAfterDue)
+	spec := load("Spec")
+	version := spec.Version
+	_, err = svc.Reschedule(spec, me, day(5, 2), day(5, 8), true, version+1)
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	result, err := svc.Reschedule(spec, me, day(5, 2), day(5, 8), true, version)
+	require.NoError(t, err)
+	require.Equal(t, version+1, result.Card.Version)
+	require.Len(t, result.Shifted, 2)
+	require.Equal(t, "Build API", result.Shifted[0].Title)
+	for title, want := range map[string][2]*time.Time{
+		"Spec":      {day(5, 2), day(5, 8)},
+		"Build API": {day(5, 9), day(5, 18)},
+		"Ship":      {nil, day(5, 23)},
+		"Retro":     {nil, day(7, 15)},
+	} {
+		var got models.Card
+		require.NoError(t, db.First(&got, "id = ?", cards[title].ID).Error)
+		if want[0] == nil {
+			require.Nil(t, got.StartDate, title)
+		} else {
+			require.True(t, want[0].Equal(*got.StartDate), title)
+		}
+		require.True(t, want[1].Equal(*got.DueDate), title)
+		if title != "Retro" {
+			require.Equal(t, cards[title].Version+1, got.Version, "every rescheduled card gets a new version")
+		}
+	}
+
+	require.Equal(t, buildSpan+3, spanOf("Build API"))
+
+	// Without cascade only the card itself moves.
+	result, err = svc.Reschedule(load("Build API"), me, nil, day(6, 1), false, 0)
+	require.NoError(t, err)
+	require.Empty(t, result.Shifted)
+	require.Nil(t, result.Card.StartDate)
+
+	// The cascade is measured from the dates stored when the write happens, not the caller's copy.
+	stale := load("Build API")
+	require.NoError(t, db.Model(&models.Card{}).Where("id = ?", stale.ID).Update("due_date", day(6, 3)).Error)
+	result, err = svc.Reschedule(stale, me, nil, day(6, 5), true, 0)
+	require.NoError(t, err)
+	require.Len(t, result.Shifted, 1)
+	require.True(t, day(5, 25).Equal(*result.Shifted[0].DueDate), "Ship moves by two days")
+
+	require.NoError(t, svc.RemoveDependency(load("Ship"), cards["Build API"].ID, me))
+	require.Error(t, svc.RemoveDependency(load("Ship"), cards["Build API"].ID, me))
+}
@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"nexus-backend/internal/models"
+	"nexus-backend/internal/repository"
+	"nexus-backend/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWIPLimits_WarnAndBlockModes(t *testing.T) {
+	db := setupBulkTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE column_criteria (id TEXT PRIMARY KEY, column_id TEXT, phase TEXT, kind TEXT, label_id TEXT, custom_field_id TEXT, position INTEGER DEFAULT 0, created_at DATETIME)`,
+		`CREATE TABLE card_members (card_id TEXT, user_id TEXT)`,
+		`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT, email TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE labels (id TEXT PRIMARY KEY, board_id TEXT, name TEXT, color TEXT, workspace_label_id TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE card_labels (card_id TEXT, label_id TEXT)`,
+		`CREATE TABLE checklists (id TEXT PRIMARY KEY, card_id TEXT, title TEXT, position REAL, version INTEGER DEFAULT 1, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE checklist_items (id TEXT PRIMARY KEY, checklist_id TEXT, title TEXT, is_completed INTEGER DEFAULT 0, position REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE attachments (id TEXT PRIMARY KEY, card_id TEXT, user_id TEXT, filename TEXT, file_path TEXT, file_type TEXT, size INTEGER, created_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	boardID := uuid.New()
+	todo, doing, review := uuid.New(), uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO boards (id, title) VALUES (?, 'Dev')`, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position) VALUES (?, ?, 'To Do', 1)`, todo, boardID)
+	db.Exec(`INSERT INTO columns (id, board_id, name, position, wip_limit, wip_scope, wip_mode) VALUES (?, ?, 'Doing', 2, 2, 'cards', 'warn'), (?, ?, 'Review', 3, 1, 'member', 'block')`,
+		doing, boardID, review, boardID)
+
+	alice, bob := uuid.New(), uuid.New()
+	db.Exec(`INSERT INTO users (id, name, email) VALUES (?, 'Alice', 'a@example.com'), (?, 'Bob', 'b@example.com')`, alice, bob)
+	cards := make([]uuid.UUID, 4)
+	for i := range cards {
+		cards[i] = uuid.New()
+		require.NoError(t, db.Create(&models.Card{ID: cards[i], Title: "Task", ColumnID: todo, Position: float64(i + 1)}).Error)
+	}
+	db.Exec(`INSERT INTO card_members (card_id, user_id) VALUES (?, ?), (?, ?), (?, ?)`, cards[0], alice, cards[1], alice, cards[2], bob)
+
+	svc := services.NewCardService(repository.NewCardRepository(db), nil)
+
+	// Warn mode: the move that goes over the limit succeeds and is flagged.
+	for i, id := range cards[:2] {
+		result, err := svc.MoveCardWithOptions(id, doing, float64(i+1), repository.MoveCardOptions{})
+		require.NoError(t, err)
+		require.Empty(t, result.Card.WIPExceeded)
+	}
+	created, err := svc.CreateCard("Overflow", "", doing)
+	require.NoError(t, err)
+	require.Len(t, created.WIPExceeded, 1)
+	require.Equal(t, models.WIPExceeded{ColumnID: doing, Limit: 2, Count: 3, Scope: models.WIPScopeCards, Mode: models.WIPModeWarn}, created.WIPExceeded[0])
+
+	// Reordering within the column is not a new entry.
+	result, err := svc.MoveCardWithOptions(cards[0], doing, 10, repository.MoveCardOptions{})
+	require.NoError(t, err)
+	require.Empty(t, result.Card.WIPExceeded)
+
+	// Block mode with a per-member scope: Alice already has a card in review, Bob does not.
+	_, err = svc.MoveCardWithOptions(cards[0], review, 1, repository.MoveCardOptions{})
+	require.NoError(t, err)
+	_, err = svc.MoveCardWithOptions(cards[1], review, 2, repository.MoveCardOptions{})
+	var wipErr *repository.WIPLimitError
+	require.True(t, errors.As(err, &wipErr))
+	require.Equal(t, alice, *wipErr.Exceeded[0].UserID)
+	var stored models.Card
+	require.NoError(t, db.First(&stored, "id = ?", cards[1]).Error)
+	require.Equal(t, doing, stored.ColumnID)
+	_, err = svc.MoveCardWithOptions(cards[2], review, 2, repository.MoveCardOptions{})
+	require.NoError(t, err)
+
+	// Copies carry their members, so they count against the same limit.
+	_, err = svc.CopyCard(cards[1], review)
+	require.True(t, errors.As(err, &wipErr))
+	var count int64
+	db.Model(&models.Card{}).Where("column_id = ?", review).Count(&count)
+	require.EqualValues(t, 2, count)
+
+	// Restoring into a full column is blocked; unassigned cards are not counted per member.
+	require.NoError(t, svc.ArchiveCard(cards[1]))
+	_, err = svc.RestoreCard(cards[1], review)
+	require.True(t, errors.As(err, &wipErr))
+	require.NoError(t, db.First(&stored, "id = ?", cards[1]).Error)
+	require.True(t, stored.IsArchived)
+	exceeded, err := svc.RestoreCard(cards[1], todo)
+	require.NoError(t, err)
+	require.Empty(t, exceeded)
+	_, err = svc.MoveCardWithOptions(cards[3], review, 3, repository.MoveCardOptions{})
+	require.NoError(t, err)
+}
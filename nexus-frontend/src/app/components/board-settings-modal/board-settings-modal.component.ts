@@ -13,6 +13,7 @@ import { ToastService } from '../../services/toast.service';
 })
 export class BoardSettingsModalComponent {
     @Input({ required: true }) boardId!: string;
+    @Input({ required: true }) version!: number;
     @Input() set currentTitle(val: string) { this.title = val; }
     @Input() set currentBgColor(val: string | undefined) { if (val) this.selectedBg = val; }
     @Input() set currentBgImage(val: string | undefined) { if (val) this.selectedImage = val; }
@@ -70,11 +71,12 @@ export class BoardSettingsModalComponent {
     }
 
     save() {
-        this.boardService.updateBoard(this.boardId, {
+        this.boardService.updateBoard(this.boardId, this.version, {
             title: this.title,
             background_color: this.selectedBg,
             background_image_url: this.selectedImage
-        }).subscribe(() => {
+        }).subscribe((board) => {
+            this.version = board.version;
             this.boardService.triggerRefresh();
             this.close.emit();
         });
@@ -183,10 +185,11 @@ export class BoardSettingsModalComponent {
         }
 
         this.isSavingNote = true;
-        this.boardService.updateBoard(this.boardId, {
+        this.boardService.updateBoard(this.boardId, this.version, {
             documentation_notes: JSON.stringify(this.notes)
         }, { silent: true }).subscribe({
-            next: () => {
+            next: (board) => {
+                this.version = board.version;
                 this.isSavingNote = false;
                 this.boardService.triggerRefresh();
                 this.toast.show(successMessage, 'success');
@@ -7,6 +7,7 @@ import interactionPlugin from '@fullcalendar/interaction';
 import { CardService } from '../../../services/card.service';
 import { FocusTimeService } from '../../../services/focus-time.service';
 import { DialogService } from '../../../services/dialog.service';
+import { Card } from '../../../models/board.model';
 
 @Component({
   selector: 'app-calendar-view',
@@ -103,6 +104,7 @@ export class CalendarViewComponent {
           extendedProps: {
             kind: 'card',
             cardId: card.id,
+            card,
             listName: col.name
           }
         });
@@ -186,7 +188,7 @@ export class CalendarViewComponent {
     const kind = arg.event.extendedProps['kind'];
 
     if (kind === 'card') {
-      const cardId = arg.event.extendedProps['cardId'];
+      const card: Card = arg.event.extendedProps['card'];
       const current = arg.event.start ? this.toDateTimeLocal(arg.event.start) : '';
       const next = await this.dialogService.openPrompt({
         title: 'Edit Card Due Date/Time',
@@ -198,7 +200,8 @@ export class CalendarViewComponent {
       if (!next?.trim()) return;
       const parsed = new Date(next);
       if (isNaN(parsed.getTime())) return;
-      this.cardService.updateCard(cardId, { due_date: parsed.toISOString() }).subscribe();
+      this.cardService.updateCard(card.id, card.version, { due_date: parsed.toISOString() })
+        .subscribe(updated => card.version = updated.version);
       return;
     }
 
@@ -220,10 +223,11 @@ export class CalendarViewComponent {
   private onEventDrop(arg: EventDropArg) {
     const kind = arg.event.extendedProps['kind'];
     if (kind === 'card') {
-      const cardId = arg.event.extendedProps['cardId'];
+      const card: Card = arg.event.extendedProps['card'];
       const start = arg.event.start;
       if (!start) return;
-      this.cardService.updateCard(cardId, { due_date: start.toISOString() }).subscribe();
+      this.cardService.updateCard(card.id, card.version, { due_date: start.toISOString() })
+        .subscribe(updated => card.version = updated.version);
       return;
     }
 
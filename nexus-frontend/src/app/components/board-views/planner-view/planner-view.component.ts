@@ -340,7 +340,8 @@ export class PlannerViewComponent {
       nextDue.setFullYear(targetDate.getFullYear(), targetDate.getMonth(), targetDate.getDate());
       const iso = nextDue.toISOString();
       this.dueOverrides.update(map => ({ ...map, [entry.card.id]: iso }));
-      this.cardService.updateCard(entry.card.id, { due_date: iso }).subscribe();
+      this.cardService.updateCard(entry.card.id, entry.card.version, { due_date: iso })
+        .subscribe(updated => entry.card.version = updated.version);
       return;
     }
 
@@ -364,13 +365,15 @@ export class PlannerViewComponent {
     if (isNaN(next.getTime())) return;
     const iso = next.toISOString();
     this.dueOverrides.update(map => ({ ...map, [entry.card.id]: iso }));
-    this.cardService.updateCard(entry.card.id, { due_date: iso }).subscribe();
+    this.cardService.updateCard(entry.card.id, entry.card.version, { due_date: iso })
+        .subscribe(updated => entry.card.version = updated.version);
   }
 
   toggleComplete(entry: CardEntry, event: Event) {
     const checked = (event.target as HTMLInputElement).checked;
     this.completeOverrides.update(map => ({ ...map, [entry.card.id]: checked }));
-    this.cardService.updateCard(entry.card.id, { is_complete: checked }).subscribe();
+    this.cardService.updateCard(entry.card.id, entry.card.version, { is_complete: checked })
+      .subscribe(updated => entry.card.version = updated.version);
   }
 
   saveFocusTime() {
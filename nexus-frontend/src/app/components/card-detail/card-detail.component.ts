@@ -952,7 +952,10 @@ export class CardDetailComponent implements OnInit, OnDestroy, OnChanges {
     checklists[prevIndex].position = newPosition;
     moveItemInArray(checklists, prevIndex, currentIndex);
 
-    this.cardService.moveChecklist(movedChecklist.id, newPosition).subscribe();
+    this.cardService.moveChecklist(movedChecklist.id, movedChecklist.version, newPosition).subscribe({
+      next: (moved) => movedChecklist.version = moved.version,
+      error: () => this.loadCard(this.card()!.id, true)
+    });
   }
 
   dropItem(event: CdkDragDrop<ChecklistItem[]>, targetChecklistId: string) {
@@ -1101,8 +1104,9 @@ export class CardDetailComponent implements OnInit, OnDestroy, OnChanges {
     if (!currentCard) return;
 
     // Use any for payload to bypass strict type if needed, or update service
-    this.cardService.updateCard(currentCard.id, { due_date: date } as any).subscribe(() => {
-      this.loadCard(currentCard.id);
+    this.cardService.updateCard(currentCard.id, currentCard.version, { due_date: date } as any).subscribe({
+      next: () => this.loadCard(currentCard.id),
+      error: () => this.loadCard(currentCard.id)
     });
   }
 
@@ -1139,8 +1143,9 @@ export class CardDetailComponent implements OnInit, OnDestroy, OnChanges {
     // Optimistic update
     this.card.set({ ...currentCard, is_complete: isComplete });
 
-    this.cardService.updateCard(currentCard.id, { is_complete: isComplete }).subscribe(() => {
-      this.loadCard(currentCard.id, true);
+    this.cardService.updateCard(currentCard.id, currentCard.version, { is_complete: isComplete }).subscribe({
+      next: () => this.loadCard(currentCard.id, true),
+      error: () => this.loadCard(currentCard.id, true)
     });
   }
 
@@ -1349,7 +1354,10 @@ export class CardDetailComponent implements OnInit, OnDestroy, OnChanges {
     this.descriptionPreview.set(false);
     this.closeDescriptionMenu();
 
-    this.cardService.updateCard(currentCard.id, { description: this.descriptionDraft }).subscribe();
+    this.cardService.updateCard(currentCard.id, currentCard.version, { description: this.descriptionDraft }).subscribe({
+      next: (updated) => this.card.update(c => c ? { ...c, version: updated.version } : c),
+      error: () => this.loadCard(currentCard.id, true)
+    });
   }
 
   @HostListener('document:click', ['$event'])
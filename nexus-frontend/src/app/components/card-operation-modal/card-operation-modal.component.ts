@@ -156,7 +156,7 @@ export class CardOperationModalComponent {
     submit() {
         this.isSubmitting.set(true);
         if (this.action === 'move') {
-            this.boardService.moveCard(this.card.id, {
+            this.boardService.moveCard(this.card.id, this.card.version, {
                 column_id: this.selectedColumnId,
                 position: this.selectedPosition
             }).subscribe({
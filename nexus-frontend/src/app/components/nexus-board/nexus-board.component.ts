@@ -551,7 +551,7 @@ export class NexusBoardComponent implements OnInit, OnDestroy {
       moveItemInArray(event.container.data, event.previousIndex, event.currentIndex);
       const newPos = this.calculateMidpoint(event.container.data, event.currentIndex);
       const card = event.container.data[event.currentIndex];
-      this.boardService.moveCard(card.id, { column_id: newColumnId, position: newPos }).subscribe({
+      this.boardService.moveCard(card.id, card.version, { column_id: newColumnId, position: newPos }).subscribe({
         next: () => {
           this.onDragEnded();
           // Controlled refresh after API confirms
@@ -577,7 +577,7 @@ export class NexusBoardComponent implements OnInit, OnDestroy {
         // Restoring from archive requires explicit unarchive semantics on backend.
         this.boardService.restoreCard(card.id, newColumnId).subscribe({
           next: () => {
-            this.boardService.moveCard(card.id, {
+            this.boardService.moveCard(card.id, card.version, {
               column_id: newColumnId,
               position: newPos
             }).subscribe({
@@ -600,7 +600,7 @@ export class NexusBoardComponent implements OnInit, OnDestroy {
           }
         });
       } else {
-        this.boardService.moveCard(card.id, {
+        this.boardService.moveCard(card.id, card.version, {
           column_id: newColumnId,
           position: newPos
         }).subscribe({
@@ -689,7 +689,12 @@ export class NexusBoardComponent implements OnInit, OnDestroy {
 
     const newPos = this.calculateMidpoint(columns, event.currentIndex);
     const column = columns[event.currentIndex];
-    this.boardService.moveColumn(column.id, newPos).subscribe();
+    this.boardService.moveColumn(column.id, column.version, newPos).subscribe({
+      next: (moved) => column.version = moved.version,
+      error: () => {
+        if (this.currentBoardId) this.refreshBoard(this.currentBoardId);
+      }
+    });
   }
 
   private calculateMidpoint(items: any[], index: number): number {
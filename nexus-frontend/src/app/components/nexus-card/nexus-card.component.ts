@@ -54,10 +54,10 @@ export class NexusCardComponent {
     // without Signal input or Service update.
     // For MVP, we'll just wait for API.
 
-    this.boardService.updateCard(this.card.id, { title: cleanTitle }).subscribe({
-      next: () => {
+    this.boardService.updateCard(this.card.id, this.card.version, { title: cleanTitle }).subscribe({
+      next: (updated) => {
         // ideally update local state or signal
-        this.card = { ...this.card, title: cleanTitle }; // Local mutation for responsiveness
+        this.card = { ...this.card, title: cleanTitle, version: updated.version }; // Local mutation for responsiveness
         this.isEditing = false;
       },
       error: () => {
@@ -122,9 +122,9 @@ export class NexusColumnComponent {
       return;
     }
 
-    this.boardService.updateColumn(this.column.id, newName).subscribe({
-      next: () => {
-        this.column = { ...this.column, name: newName };
+    this.boardService.updateColumn(this.column.id, this.column.version, newName).subscribe({
+      next: (updated) => {
+        this.column = { ...this.column, name: newName, version: updated.version };
         this.isEditingName = false;
       },
       error: () => this.cancelNameEdit()
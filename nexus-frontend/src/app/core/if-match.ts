@@ -0,0 +1,7 @@
+import { HttpHeaders } from '@angular/common/http';
+
+// Versioned writes (board, column and card edits, moves) must name the version they were made
+// against; the API answers a missing header with 428 and a stale one with 412.
+export function ifMatch(version: number): { headers: HttpHeaders } {
+  return { headers: new HttpHeaders({ 'If-Match': `"${version}"` }) };
+}
@@ -14,6 +14,7 @@ export interface Checklist {
     card_id: string;
     items: ChecklistItem[];
     position: number;
+    version: number;
     created_at?: string;
     updated_at?: string;
 }
@@ -66,6 +67,7 @@ export interface Card {
     due_date?: string;
     is_complete: boolean;
     position: number;
+    version: number;
     checklists?: Checklist[];
     comments?: Comment[];
     attachments?: Attachment[];
@@ -81,6 +83,7 @@ export interface Column {
     position: number;
     cards: Card[];
     card_count: number;
+    version: number;
     created_at?: string;
     updated_at?: string;
 }
@@ -106,6 +109,7 @@ export interface BoardResponse {
         background_image_url?: string;
         documentation_notes?: string;
         custom_fields?: CustomField[];
+        version: number;
     };
     columns: Column[];
     user_role: string;
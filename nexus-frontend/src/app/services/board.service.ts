@@ -4,6 +4,7 @@ import { Observable, Subject, catchError, map, retry, tap, throwError } from 'rx
 import { BoardResponse, Column, Card } from '../models/board.model';
 import { ToastService } from './toast.service';
 import { API_BASE_URL, BACKEND_BASE_URL, toBackendUrl } from '../core/runtime-config';
+import { ifMatch } from '../core/if-match';
 
 @Injectable({
   providedIn: 'root'
@@ -249,10 +250,11 @@ export class BoardService {
 
   updateBoard(
     boardId: string,
+    version: number,
     payload: { title?: string; background_color?: string; background_image_url?: string; documentation_notes?: string },
     options?: { silent?: boolean }
   ): Observable<any> {
-    return this.http.patch<any>(`${this.apiUrl}/boards/${boardId}`, payload).pipe(
+    return this.http.patch<any>(`${this.apiUrl}/boards/${boardId}`, payload, ifMatch(version)).pipe(
       tap(() => {
         if (!options?.silent) {
           this.toast.show('Board updated', 'success');
@@ -297,15 +299,15 @@ export class BoardService {
     );
   }
 
-  updateColumn(columnId: string, name: string): Observable<any> {
-    return this.http.patch<any>(`${this.apiUrl}/columns/${columnId}`, { name }).pipe(
+  updateColumn(columnId: string, version: number, name: string): Observable<Column> {
+    return this.http.patch<Column>(`${this.apiUrl}/columns/${columnId}`, { name }, ifMatch(version)).pipe(
       tap(() => this.toast.show('Column updated', 'success')),
       catchError(err => this.handleError('Failed to update column', err))
     );
   }
 
-  moveColumn(columnId: string, position: number): Observable<void> {
-    return this.http.patch<void>(`${this.apiUrl}/columns/${columnId}/move`, { position }).pipe(
+  moveColumn(columnId: string, version: number, position: number): Observable<Column> {
+    return this.http.patch<Column>(`${this.apiUrl}/columns/${columnId}/move`, { position }, ifMatch(version)).pipe(
       catchError(err => this.handleError('Failed to move column', err))
     );
   }
@@ -322,15 +324,15 @@ export class BoardService {
     );
   }
 
-  updateCard(cardId: string, payload: { title?: string }): Observable<Card> {
-    return this.http.patch<Card>(`${this.apiUrl}/cards/${cardId}`, payload).pipe(
+  updateCard(cardId: string, version: number, payload: { title?: string }): Observable<Card> {
+    return this.http.patch<Card>(`${this.apiUrl}/cards/${cardId}`, payload, ifMatch(version)).pipe(
       tap(() => this.toast.show('Card updated', 'success')),
       catchError(err => this.handleError('Failed to update card', err))
     );
   }
 
-  moveCard(cardId: string, payload: { column_id?: string; position: number }) {
-    return this.http.patch(`${this.apiUrl}/cards/${cardId}/move`, payload).pipe(
+  moveCard(cardId: string, version: number, payload: { column_id?: string; position: number }) {
+    return this.http.patch<{ moved_card: Card }>(`${this.apiUrl}/cards/${cardId}/move`, payload, ifMatch(version)).pipe(
       retry(2), // Retry moves for resilience
       catchError(err => {
         this.toast.show('Failed to move card', 'error');
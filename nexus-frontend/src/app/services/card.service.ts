@@ -3,6 +3,7 @@ import { HttpClient } from '@angular/common/http';
 import { Observable, catchError, throwError, tap } from 'rxjs';
 import { ToastService } from './toast.service';
 import { API_BASE_URL } from '../core/runtime-config';
+import { ifMatch } from '../core/if-match';
 
 @Injectable({
     providedIn: 'root'
@@ -20,13 +21,13 @@ export class CardService {
     }
 
     // Update card (title, description, due_date, is_complete)
-    updateCard(id: string, payload: {
+    updateCard(id: string, version: number, payload: {
         title?: string;
         description?: string;
         due_date?: string | null;
         is_complete?: boolean;
     }): Observable<any> {
-        return this.http.patch<any>(`${this.apiUrl}/cards/${id}`, payload).pipe(
+        return this.http.patch<any>(`${this.apiUrl}/cards/${id}`, payload, ifMatch(version)).pipe(
             tap(() => this.toast.show('Card updated', 'success')),
             catchError(err => this.handleError('Failed to update card', err))
         );
@@ -54,8 +55,8 @@ export class CardService {
         );
     }
 
-    moveChecklist(id: string, position: number): Observable<any> {
-        return this.http.patch<any>(`${this.apiUrl}/checklists/${id}/move`, { position }).pipe(
+    moveChecklist(id: string, version: number, position: number): Observable<any> {
+        return this.http.patch<any>(`${this.apiUrl}/checklists/${id}/move`, { position }, ifMatch(version)).pipe(
             catchError(err => this.handleError('Failed to move checklist', err))
         );
     }